@@ -0,0 +1,111 @@
+"""Bulk operations on filtered memories (see app/services/bulk.py)"""
+
+from fastapi import APIRouter, Depends, HTTPException
+from sqlalchemy.orm import Session
+
+from ..core.confirmation import ConfirmationError, confirmations
+from ..core.database import get_db
+from ..core.permissions import check_access
+from ..models.memory import Memory
+from ..models.schemas import (
+    BulkOperationResponse,
+    BulkRequest,
+    DeleteMemoriesRequest,
+    MoveMemoriesRequest,
+    RetagMemoriesRequest,
+)
+from ..services.bulk import (
+    bulk_scope,
+    delete_memories,
+    filter_memories,
+    move_memories,
+    retag_memories,
+)
+from .memories import get_agent_id, get_namespace
+
+router = APIRouter()
+
+
+def _prepare(
+    operation: str, request: BulkRequest, db: Session, namespace: str, agent_id: str | None
+) -> tuple[list[Memory], BulkOperationResponse | None]:
+    """Matching memories the agent may change, plus the preview response for a dry run.
+    A real run must redeem the preview's token: 428 without one, 409 if it is stale."""
+    memories = [
+        memory
+        for memory in filter_memories(db, namespace, request)
+        if check_access(agent_id, memory, "write")
+    ]
+    params = request.model_dump(mode="json", exclude={"dry_run", "confirmation_token"})
+    scope = bulk_scope(namespace, params, memories)
+
+    if request.dry_run:
+        return memories, BulkOperationResponse(
+            dry_run=True,
+            count=len(memories),
+            memory_ids=[memory.id for memory in memories],
+            confirmation_token=confirmations.issue(operation, scope),
+        )
+
+    if not request.confirmation_token:
+        raise HTTPException(
+            status_code=428,
+            detail="Preview with dry_run first and pass its confirmation_token to proceed",
+        )
+    try:
+        confirmations.redeem(request.confirmation_token, operation, scope)
+    except ConfirmationError as e:
+        raise HTTPException(status_code=409, detail=str(e)) from e
+    return memories, None
+
+
+def _done(memory_ids: list[str]) -> BulkOperationResponse:
+    return BulkOperationResponse(dry_run=False, count=len(memory_ids), memory_ids=memory_ids)
+
+
+@router.post("/memories/bulk/delete", response_model=BulkOperationResponse)
+async def bulk_delete(
+    request: DeleteMemoriesRequest,
+    db: Session = Depends(get_db),
+    namespace: str = Depends(get_namespace),
+    agent_id: str | None = Depends(get_agent_id),
+) -> BulkOperationResponse:
+    """Delete every memory matching the filter; preview first, then confirm with the token"""
+    memories, preview = _prepare("delete_memories", request, db, namespace, agent_id)
+    if preview is not None:
+        return preview
+    memory_ids = [memory.id for memory in memories]
+    await delete_memories(db, memories, agent_id)
+    return _done(memory_ids)
+
+
+@router.post("/memories/bulk/move", response_model=BulkOperationResponse)
+async def bulk_move(
+    request: MoveMemoriesRequest,
+    db: Session = Depends(get_db),
+    namespace: str = Depends(get_namespace),
+    agent_id: str | None = Depends(get_agent_id),
+) -> BulkOperationResponse:
+    """Move every memory matching the filter to new_category (its new first tag)"""
+    memories, preview = _prepare("move_memories", request, db, namespace, agent_id)
+    if preview is not None:
+        return preview
+    memory_ids = [memory.id for memory in memories]
+    move_memories(db, memories, request.new_category, agent_id)
+    return _done(memory_ids)
+
+
+@router.post("/memories/bulk/retag", response_model=BulkOperationResponse)
+async def bulk_retag(
+    request: RetagMemoriesRequest,
+    db: Session = Depends(get_db),
+    namespace: str = Depends(get_namespace),
+    agent_id: str | None = Depends(get_agent_id),
+) -> BulkOperationResponse:
+    """Add and remove tags on every memory matching the filter"""
+    memories, preview = _prepare("retag_memories", request, db, namespace, agent_id)
+    if preview is not None:
+        return preview
+    memory_ids = [memory.id for memory in memories]
+    retag_memories(db, memories, request.add_tags, request.remove_tags, agent_id)
+    return _done(memory_ids)
@@ -0,0 +1,62 @@
+"""Category description and suggestion endpoints (see app/services/categories.py)"""
+
+from typing import Any
+
+from fastapi import APIRouter, Depends, HTTPException
+from sqlalchemy.orm import Session
+
+from ..core.database import get_db
+from ..models.schemas import CategoryDescriptionRequest, CategorySuggestRequest
+from ..services.categories import (
+    delete_category_description,
+    describe_category,
+    list_categories,
+    suggest_categories,
+)
+from .memories import get_namespace
+
+router = APIRouter()
+
+
+@router.get("/categories")
+async def get_categories(
+    db: Session = Depends(get_db),
+    namespace: str = Depends(get_namespace),
+) -> dict[str, Any]:
+    """Categories in use or described, with memory counts"""
+    categories = list_categories(db, namespace)
+    return {"namespace": namespace, "total": len(categories), "categories": categories}
+
+
+@router.put("/categories/{name}")
+async def put_category_description(
+    name: str,
+    request: CategoryDescriptionRequest,
+    db: Session = Depends(get_db),
+    namespace: str = Depends(get_namespace),
+) -> dict[str, Any]:
+    """Describe what belongs in a category; the description is embedded for suggestions"""
+    category = await describe_category(db, namespace, name, request.description.strip())
+    return category.to_dict()
+
+
+@router.delete("/categories/{name}")
+async def delete_category(
+    name: str,
+    db: Session = Depends(get_db),
+    namespace: str = Depends(get_namespace),
+) -> dict[str, Any]:
+    """Remove a category's description (its memories are not touched)"""
+    if not delete_category_description(db, namespace, name):
+        raise HTTPException(status_code=404, detail=f"Category '{name}' has no description")
+    return {"deleted": name}
+
+
+@router.post("/categories/suggest")
+async def suggest_category(
+    request: CategorySuggestRequest,
+    db: Session = Depends(get_db),
+    namespace: str = Depends(get_namespace),
+) -> dict[str, Any]:
+    """Existing categories that best fit a new memory"""
+    return await suggest_categories(db, namespace, request.value, request.limit)
@@ -1,34 +1,49 @@
 """Dashboard API for memory management"""
 
-from fastapi import APIRouter, Depends, HTTPException, Request
+from datetime import datetime
+from pathlib import Path
+
+from fastapi import APIRouter, Depends, HTTPException, Query, Request
 from fastapi.responses import HTMLResponse
 from fastapi.templating import Jinja2Templates
 from sqlalchemy.orm import Session
 
+from ..core.config import settings
 from ..core.database import get_db
+from ..core.events import MEMORY_DELETED, MEMORY_UPDATED, MemoryEvent, event_bus
 from ..models.memory import Memory
+from ..models.schemas import MemoryUpdate, SearchRequest
+from ..services.counts import dashboard_counts, tag_counts
+from ..services.operation_log import memory_snapshot
 
 router = APIRouter()
-templates = Jinja2Templates(directory="app/templates")
+templates = Jinja2Templates(directory=str(Path(__file__).resolve().parent.parent / "templates"))
 
 
 @router.get("/dashboard", response_class=HTMLResponse)
-async def dashboard(request: Request, db: Session = Depends(get_db)):
+async def dashboard(
+    request: Request,
+    namespace: str | None = Query(None, description="Namespace to show"),
+    db: Session = Depends(get_db),
+):
     """Memory management dashboard"""
+    namespace = namespace or settings.namespace
+
     # Get all memories with basic stats
-    memories = db.query(Memory).order_by(Memory.updated_at.desc()).all()
+    memories = (
+        db.query(Memory)
+        .filter(Memory.namespace == namespace)
+        .order_by(Memory.updated_at.desc())
+        .all()
+    )
 
     # Calculate stats
-    total_memories = len(memories)
-    memories_with_embeddings = sum(1 for m in memories if m.has_embedding)
-    ai_processed = sum(1 for m in memories if m.is_ai_processed)
-
-    stats = {
-        "total_memories": total_memories,
-        "memories_with_embeddings": memories_with_embeddings,
-        "ai_processed": ai_processed,
-        "pending_processing": total_memories - ai_processed,
-    }
+    stats = dashboard_counts(db, namespace)
+    total_memories = stats["total_memories"]
+    stats["embedding_coverage"] = (
+        round(stats["memories_with_embeddings"] / total_memories * 100) if total_memories else 0
+    )
+    stats["pending_processing"] = total_memories - stats["ai_processed"]
 
     return templates.TemplateResponse(
         "dashboard.html",
@@ -36,6 +51,8 @@ async def dashboard(request: Request, db: Session = Depends(get_db)):
             "request": request,
             "memories": memories,
             "stats": stats,
+            "tags": tag_counts(db, namespace, limit=30),
+            "namespace": namespace,
         },
     )
 
@@ -49,10 +66,64 @@ async def delete_memory_api(memory_id: str, db: Session = Depends(get_db)):
 
     db.delete(memory)
     db.commit()
+    await event_bus.publish(
+        MemoryEvent(MEMORY_DELETED, memory, session=db, details={"summary": memory.summary})
+    )
 
     return {"success": True, "message": f"Memory {memory_id} deleted successfully"}
 
 
+@router.put("/dashboard/memories/{memory_id}")
+async def update_memory_api(
+    memory_id: str, memory_update: MemoryUpdate, db: Session = Depends(get_db)
+):
+    """Edit a memory's content via dashboard (embedding is regenerated)"""
+    memory = db.query(Memory).filter(Memory.id == memory_id).first()
+    if not memory:
+        raise HTTPException(status_code=404, detail="Memory not found")
+
+    if memory_update.value and memory_update.value != memory.value:
+        before = memory_snapshot(memory)
+        memory.value = memory_update.value
+        memory.updated_at = datetime.utcnow()
+        db.commit()
+        await event_bus.publish(
+            MemoryEvent(MEMORY_UPDATED, memory, session=db, details={"before": before})
+        )
+        db.refresh(memory)
+
+    return {"success": True, "memory": memory.to_dict()}
+
+
+@router.get("/dashboard/api/search")
+async def search_memories_api(
+    q: str = Query(..., min_length=1, description="Search query"),
+    search_type: str = Query("hybrid", description="fts5, semantic or hybrid"),
+    namespace: str | None = Query(None),
+    db: Session = Depends(get_db),
+):
+    """Server-side search for the dashboard (returns matching IDs in rank order)"""
+    from ..services.search import search_service
+
+    request = SearchRequest(
+        query=q,
+        namespace=namespace or settings.namespace,
+        search_type=search_type,
+        include_pending=True,
+        include_archived=True,
+        limit=100,
+    )
+    response = await search_service.search_memories(request, db)
+
+    return {
+        "results": [
+            {"id": result.memory.id, "score": result.score} for result in response.results
+        ],
+        "search_type": response.search_type,
+        "execution_time_ms": response.execution_time_ms,
+    }
+
+
 @router.get("/dashboard/api/memories")
 async def get_memories_api(db: Session = Depends(get_db)):
     """Get all memories for dashboard API"""
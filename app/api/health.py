@@ -2,15 +2,20 @@
 Basic status and system information
 """
 
+import time
 from datetime import datetime
 from typing import Any
 
-from fastapi import APIRouter, Depends
+from fastapi import APIRouter, Depends, Query
 from sqlalchemy import text
 from sqlalchemy.orm import Session
 
 from ..core.config import settings
 from ..core.database import check_fts5_support, get_db
+from ..core.tracing import trace_recorder
+from ..models.memory import Memory
+from ..services.counts import count_memories, count_with_embeddings
+from ..services.embedding import embedding_service
 
 router = APIRouter()
 
@@ -68,3 +73,97 @@ async def detailed_health_check(db: Session = Depends(get_db)) -> dict[str, Any]
             "data_dir": settings.data_dir,
         },
     }
+
+
+@router.get("/diagnostics")
+async def diagnostics(
+    limit: int = Query(20, ge=1, le=100, description="Number of recent traces to return"),
+) -> dict[str, Any]:
+    """Per-request timing breakdowns recorded in debug mode"""
+    return {
+        "debug_enabled": settings.debug,
+        "timestamp": datetime.utcnow().isoformat(),
+        "summary": trace_recorder.summary(),
+        "recent_requests": trace_recorder.recent(limit),
+    }
+
+
+def _check(name: str, func) -> dict[str, Any]:
+    """Run a self-test check and time it"""
+    start_time = time.perf_counter()
+    try:
+        status, message = func()
+    except Exception as e:
+        status, message = "fail", f"{type(e).__name__}: {e}"
+    return {
+        "name": name,
+        "status": status,
+        "message": message,
+        "duration_ms": round((time.perf_counter() - start_time) * 1000, 2),
+    }
+
+
+async def _check_embedding_api() -> dict[str, Any]:
+    """Verify the embedding API is reachable with a real request"""
+    start_time = time.perf_counter()
+    if not embedding_service.enabled:
+        status, message = "skip", "Embedding service disabled"
+    else:
+        vector = await embedding_service.generate_embedding("mory health check")
+        if vector is None:
+            status, message = "fail", "Embedding API call failed"
+        else:
+            status, message = "pass", f"Embedding API reachable ({len(vector)} dimensions)"
+    return {
+        "name": "embedding_api",
+        "status": status,
+        "message": message,
+        "duration_ms": round((time.perf_counter() - start_time) * 1000, 2),
+    }
+
+
+@router.get("/health/selftest")
+async def self_test(
+    check_embedding: bool = Query(False, description="Also call the embedding API"),
+    db: Session = Depends(get_db),
+) -> dict[str, Any]:
+    """Structured pass/fail self-test of the server's dependencies"""
+
+    def database_connectivity():
+        db.execute(text("SELECT 1"))
+        return "pass", "Database responds"
+
+    def database_write():
+        # Flush acquires the write lock; the probe row is rolled back
+        probe = Memory(value="mory self-test probe")
+        db.add(probe)
+        db.flush()
+        db.rollback()
+        return "pass", "Database accepts writes"
+
+    def vector_store():
+        total = count_memories(db)
+        embedded = count_with_embeddings(db)
+        message = f"{embedded}/{total} memories have embeddings"
+        if not settings.is_semantic_available:
+            return "skip", f"Semantic search unavailable; {message}"
+        if total and not embedded:
+            return "warn", message
+        return "pass", message
+
+    checks = [
+        _check("database_connectivity", database_connectivity),
+        _check("database_write", database_write),
+        _check("vector_store", vector_store),
+    ]
+
+    if check_embedding:
+        checks.append(await _check_embedding_api())
+
+    failed = [check["name"] for check in checks if check["status"] == "fail"]
+    return {
+        "status": "fail" if failed else "pass",
+        "timestamp": datetime.utcnow().isoformat(),
+        "failed_checks": failed,
+        "checks": checks,
+    }
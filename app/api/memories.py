@@ -1,13 +1,33 @@
 """Memory CRUD API endpoints"""
 
+import hmac
+import logging
 from datetime import datetime, timedelta
+from typing import Any
 
-from fastapi import APIRouter, Depends, HTTPException, Query
+from fastapi import APIRouter, Depends, Header, HTTPException, Query
+from sqlalchemy import func, text
+from sqlalchemy.exc import OperationalError
 from sqlalchemy.orm import Session
 
+from ..core.config import settings
+from ..core.confirmation import ConfirmationError, confirmations
 from ..core.database import get_db
+from ..core.events import MEMORY_DELETED, MEMORY_SAVED, MEMORY_UPDATED, MemoryEvent, event_bus
+from ..core.limits import (
+    PayloadTooLargeError,
+    RateLimitExceededError,
+    limit_counters,
+    limit_value,
+    write_rate_limiter,
+)
+from ..core.logging_config import current_request_id
+from ..core.permissions import check_access, read_filter, readable, readable_page
+from ..core.scheduler import parse_interval
+from ..core.tracing import trace_span
 from ..models.memory import Memory
 from ..models.schemas import (
+    ChosenResultRequest,
     MemoryCreate,
     MemoryListResponse,
     MemoryListSummaryResponse,
@@ -16,29 +36,155 @@ from ..models.schemas import (
     MemorySummaryResponse,
     MemoryUpdate,
     MessageResponse,
+    RecentMemoriesResponse,
+    RecentMemoryGroup,
+    ReviewAutoTagsRequest,
+    SaveUrlRequest,
     SearchRequest,
     SearchResponse,
+    SummarizeCategoryRequest,
+    SummarizeCategoryResponse,
 )
-from ..services.embedding import embedding_service
+from ..services.archive import set_archived
+from ..services.auto_tag import review_auto_tags
+from ..services.canvas import CANVAS_LIMIT, MAX_CANVAS_LIMIT, build_canvas, canvas_memories
+from ..services.content_types import detect_content_type
+from ..services.counts import count_memories, tag_counts
+from ..services.file_store import DEFAULT_CATEGORY
+from ..services.jobs import scheduler
+from ..services.operation_log import memory_snapshot, record_operation
+from ..services.redaction import RedactionError, RedactionResult, redaction_service
+from ..services.report import DEFAULT_DAYS, build_report
+from ..services.search_log import log_search, record_choice, search_analytics
+from ..services.stats import stats_history
+from ..services.subscribers import register_subscribers
+from ..services.suggest import suggest
 from ..services.summarization import summarization_service
+from ..services.surfacing import mark_surfaced, pick_memories_to_surface
+from ..services.templates import TemplateError, describe_templates, validate_fields
+from ..services.web_clip import WebClipError, web_clipper
 
 router = APIRouter()
+logger = logging.getLogger(__name__)
+
+register_subscribers()
+
+
+def get_namespace(x_mory_namespace: str | None = Header(None)) -> str:
+    """Namespace (profile) for the request: X-Mory-Namespace header or configured default"""
+    return x_mory_namespace or settings.namespace
+
+
+def get_agent_id(x_mory_agent: str | None = Header(None)) -> str | None:
+    """Calling agent: X-Mory-Agent header or configured MORY_AGENT_ID"""
+    return x_mory_agent or settings.agent_id
+
+
+# Fields PUT /memories/{id} can change without re-processing the content
+# (request field -> Memory attribute)
+METADATA_FIELDS = {
+    "remind_at": "remind_at",
+    "metadata": "metadata_dict",
+    "confidence": "confidence",
+    "template": "template",
+    "content_type": "content_type",
+}
+
+
+def _template_fields(template: str, metadata: dict[str, Any] | None) -> dict[str, Any]:
+    """Validated metadata for a templated memory (422 when it does not fit)"""
+    try:
+        return validate_fields(template, metadata)
+    except TemplateError as e:
+        raise HTTPException(status_code=422, detail=str(e)) from e
+
+
+def _forbidden(memory_id: str, agent_id: str | None) -> HTTPException:
+    return HTTPException(
+        status_code=403,
+        detail=f"Agent '{agent_id}' is not allowed to modify memory '{memory_id}'",
+    )
+
+
+def _enforce_write_limits(value: str, agent_id: str | None) -> str:
+    """Apply the per-minute write limit and maximum value length"""
+    try:
+        value, truncated = limit_value(value, settings.max_value_length, settings.oversize_policy)
+        write_rate_limiter.check(agent_id or "anonymous", settings.write_rate_limit_per_minute)
+    except RateLimitExceededError as e:
+        raise HTTPException(status_code=429, detail=str(e)) from e
+    except PayloadTooLargeError as e:
+        raise HTTPException(status_code=413, detail=str(e)) from e
+
+    if truncated:
+        logger.warning(f"Memory value truncated to {settings.max_value_length} characters")
+    return value
+
+
+def _redact(
+    value: str, db: Session, agent_id: str | None, memory_id: str | None = None
+) -> RedactionResult:
+    """Run the redaction pipeline, logging and rejecting sensitive content as configured"""
+    try:
+        return redaction_service.apply(value)
+    except RedactionError as e:
+        record_operation(
+            db,
+            "redaction_rejected",
+            memory_id=memory_id,
+            agent_id=agent_id,
+            details={"rules": e.matches},
+        )
+        db.commit()
+        raise HTTPException(status_code=422, detail=str(e)) from e
+
+
+def _log_redaction(
+    db: Session, redaction: RedactionResult, memory_id: str, agent_id: str | None
+) -> None:
+    if redaction.redacted:
+        logger.info(f"Redacted {sum(redaction.matches.values())} match(es) in {memory_id}")
+        record_operation(
+            db,
+            "redaction",
+            memory_id=memory_id,
+            agent_id=agent_id,
+            details={"rules": redaction.matches},
+        )
 
 
 @router.post("/memories", response_model=MemoryResponse, status_code=201)
-async def save_memory(memory_data: MemoryCreate, db: Session = Depends(get_db)) -> MemoryResponse:
+async def save_memory(
+    memory_data: MemoryCreate,
+    db: Session = Depends(get_db),
+    namespace: str = Depends(get_namespace),
+    agent_id: str | None = Depends(get_agent_id),
+    x_mory_tool: str | None = Header(None),
+) -> MemoryResponse:
     """Save a new memory - simplified AI-driven schema (Issue #112)"""
-    import traceback
-    import uuid
-
-    request_id = str(uuid.uuid4())[:8]
+    request_id = current_request_id()
     errors = []  # Track non-fatal errors
+    memory_data.value = _enforce_write_limits(memory_data.value, agent_id)
+    redaction = _redact(memory_data.value, db, agent_id)
+    memory_data.value = redaction.text
+    if memory_data.template:
+        memory_data.metadata = _template_fields(memory_data.template, memory_data.metadata)
 
     try:
         # Create new memory (each save creates a new memory in simplified schema)
         new_memory = Memory(
             value=memory_data.value,
+            namespace=namespace,
+            owner=agent_id,
+            source=memory_data.source or (f"mcp:{x_mory_tool}" if x_mory_tool else "api"),
+            remind_at=memory_data.remind_at,
+            confidence=memory_data.confidence,
+            template=memory_data.template,
+            content_type=memory_data.content_type or detect_content_type(memory_data.value),
+            # Saves made by the assistant (via MCP tools) wait for human approval
+            review_status="pending" if settings.require_approval and x_mory_tool else "approved",
         )
+        new_memory.metadata_dict = memory_data.metadata or {}
 
         # Generate AI summary and tags if enabled (Issue #112)
         if summarization_service.enabled:
@@ -79,8 +225,7 @@ async def save_memory(memory_data: MemoryCreate, db: Session = Depends(get_db))
                 new_memory.ai_processed_at = datetime.utcnow()
             except Exception as e:
                 # If AI processing fails, continue without AI enhancements
-                error_msg = f"AI processing failed: {str(e)} (request_id: {request_id})"
-                print(error_msg)
+                logger.warning(f"AI processing failed: {str(e)}")
                 errors.append(
                     {
                         "stage": "ai_processing",
@@ -93,9 +238,12 @@ async def save_memory(memory_data: MemoryCreate, db: Session = Depends(get_db))
 
         # Database save operation
         try:
-            db.add(new_memory)
-            db.commit()
-            db.refresh(new_memory)
+            with trace_span("db_write"):
+                db.add(new_memory)
+                db.flush()
+                _log_redaction(db, redaction, new_memory.id, agent_id)
+                db.commit()
+                db.refresh(new_memory)
         except Exception as e:
             db.rollback()
             raise HTTPException(
@@ -110,32 +258,16 @@ async def save_memory(memory_data: MemoryCreate, db: Session = Depends(get_db))
                 },
             ) from e
 
-        # Generate vector embedding automatically (Issue #112 enhancement)
-        if embedding_service.enabled:
-            try:
-                embedding_generated = await embedding_service.generate_embedding_for_memory(
-                    new_memory
-                )
-                if embedding_generated:
-                    db.commit()
-                    db.refresh(new_memory)
-            except Exception as e:
-                error_msg = f"Embedding generation failed: {str(e)} (request_id: {request_id})"
-                print(error_msg)
-                errors.append(
-                    {
-                        "stage": "embedding_generation",
-                        "error": str(e),
-                        "error_type": type(e).__name__,
-                        "recoverable": True,
-                    }
-                )
+        # Embedding, operation log and webhooks subscribe to this event
+        await event_bus.publish(
+            MemoryEvent(MEMORY_SAVED, new_memory, session=db, agent_id=agent_id)
+        )
 
         # Add warnings to response if there were non-fatal errors
         response = MemoryResponse.model_validate(new_memory)
         if errors:
             # Add warning header for partial failures
-            print(f"Memory saved with warnings (request_id: {request_id}): {errors}")
+            logger.warning(f"Memory saved with warnings: {errors}")
 
         return response
 
@@ -145,8 +277,7 @@ async def save_memory(memory_data: MemoryCreate, db: Session = Depends(get_db))
     except Exception as e:
         # Catch any unexpected errors
         db.rollback()
-        error_trace = traceback.format_exc()
-        print(f"Unexpected error saving memory (request_id: {request_id}): {error_trace}")
+        logger.exception("Unexpected error saving memory")
 
         raise HTTPException(
             status_code=500,
@@ -162,29 +293,70 @@ async def save_memory(memory_data: MemoryCreate, db: Session = Depends(get_db))
         ) from e
 
 
+@router.post("/memories/url", response_model=MemoryResponse, status_code=201)
+async def save_url(
+    request: SaveUrlRequest,
+    db: Session = Depends(get_db),
+    namespace: str = Depends(get_namespace),
+    agent_id: str | None = Depends(get_agent_id),
+    x_mory_tool: str | None = Header(None),
+) -> MemoryResponse:
+    """Fetch a web page and save its title, a summary and the URL as a bookmark memory"""
+    url = request.url.split("#", 1)[0]
+    source = f"url:{url}"
+    # Saving the same link again returns the bookmark saved the first time
+    existing = (
+        db.query(Memory).filter(Memory.namespace == namespace, Memory.source == source).first()
+    )
+    if existing is not None:
+        return MemoryResponse.model_validate(existing)
+
+    try:
+        with trace_span("fetch_url"):
+            article = await web_clipper.fetch(url)
+    except WebClipError as e:
+        raise HTTPException(status_code=422, detail=str(e)) from e
+
+    content = article.text or article.description
+    try:
+        summary = await summarization_service.generate_summary(content) if content else ""
+    except Exception as e:
+        logger.warning(f"Summarizing {url} failed: {e}")
+        summary = article.description
+    metadata = {
+        "url": article.url,
+        "title": article.title,
+        "site_name": article.site_name,
+        "description": article.description,
+        "fetched_at": datetime.utcnow().isoformat(),
+    }
+    memory_data = MemoryCreate(
+        value="\n\n".join(part for part in (article.title, summary, article.url) if part),
+        metadata={key: value for key, value in metadata.items() if value},
+        source=source,
+        template="bookmark",
+    )
+    return await save_memory(memory_data, db, namespace, agent_id, x_mory_tool)
+
+
 @router.get("/memories/stats", response_model=MemoryStatsResponse)
-async def get_memory_stats(db: Session = Depends(get_db)) -> MemoryStatsResponse:
+async def get_memory_stats(
+    db: Session = Depends(get_db), namespace: str = Depends(get_namespace)
+) -> MemoryStatsResponse:
     """Get memory statistics - simplified AI-driven schema (Issue #112)"""
     # Basic counts
-    total_memories = db.query(Memory).count()
+    total_memories = count_memories(db, namespace)
 
     # Recent memories (last 24 hours)
     yesterday = datetime.utcnow() - timedelta(days=1)
-    recent_memories = db.query(Memory).filter(Memory.created_at >= yesterday).count()
+    recent_memories = (
+        db.query(func.count(Memory.id))
+        .filter(Memory.namespace == namespace, Memory.created_at >= yesterday)
+        .scalar()
+    )
 
     # AI-generated tags count
-    all_tags = []
-    memories_with_tags = db.query(Memory.tags).filter(Memory.tags != "[]").all()
-    for (tags_json,) in memories_with_tags:
-        try:
-            import json
-
-            tags = json.loads(tags_json)
-            all_tags.extend(tags)
-        except json.JSONDecodeError:
-            continue
-
-    total_tags = len(set(all_tags))
+    total_tags = len(tag_counts(db, namespace))
 
     return MemoryStatsResponse(
         total_memories=total_memories,
@@ -198,24 +370,570 @@ async def get_memory_stats(db: Session = Depends(get_db)) -> MemoryStatsResponse
             "supports_fts": True,
             "supports_semantic": False,  # Will be updated when semantic search is implemented
             "ai_driven": True,  # New: Indicates AI-driven tag and summary generation
+            "namespace": namespace,
         },
+        limits=limit_counters(),
+        jobs=scheduler.report(),
+    )
+
+
+@router.get("/memories/stats/history")
+async def get_stats_history(
+    days: int = Query(DEFAULT_DAYS, ge=1, le=3650, description="Number of days to return"),
+    db: Session = Depends(get_db),
+    namespace: str = Depends(get_namespace),
+) -> dict[str, Any]:
+    """Daily totals and activity (searches, embeddings generated), oldest first"""
+    rows = stats_history(db, namespace, days)
+    return {"namespace": namespace, "days": [row.to_dict() for row in rows]}
+
+
+@router.get("/memories/report")
+async def get_memory_report(
+    days: int = Query(DEFAULT_DAYS, ge=1, le=3650, description="Period for new memories"),
+    db: Session = Depends(get_db),
+    namespace: str = Depends(get_namespace),
+    agent_id: str | None = Depends(get_agent_id),
+) -> dict[str, Any]:
+    """Markdown digest: growth, top categories and tags, coverage, largest, highlights
+
+    The report aggregates the whole namespace, so under a restrictive permission hook only
+    agents allowed to read every memory in it get one.
+    """
+    visible = read_filter(agent_id)
+    if visible is not None and not all(
+        visible(memory) for memory in db.query(Memory).filter(Memory.namespace == namespace)
+    ):
+        raise HTTPException(
+            status_code=403,
+            detail=f"Agent '{agent_id}' may not read every memory in '{namespace}'",
+        )
+    return {"namespace": namespace, "days": days, "markdown": build_report(db, namespace, days)}
+
+
+@router.get("/memories/canvas")
+async def get_memory_canvas(
+    category: str | None = Query(None, description="Only memories in this category"),
+    tag: str | None = Query(None, description="Only memories with this tag"),
+    limit: int = Query(CANVAS_LIMIT, ge=1, le=MAX_CANVAS_LIMIT),
+    db: Session = Depends(get_db),
+    namespace: str = Depends(get_namespace),
+    agent_id: str | None = Depends(get_agent_id),
+) -> dict[str, Any]:
+    """Obsidian canvas (JSON Canvas) of the matching memories, grouped by category"""
+    if not category and not tag:
+        raise HTTPException(status_code=400, detail="Give a category or a tag")
+    memories = canvas_memories(db, namespace, category, tag, limit, read_filter(agent_id))
+    return {"namespace": namespace, "count": len(memories), "canvas": build_canvas(memories)}
+
+
+@router.get("/templates")
+async def list_templates() -> dict[str, Any]:
+    """Memory templates and their declared fields"""
+    return {"templates": describe_templates()}
+
+
+@router.get("/memories/recent", response_model=RecentMemoriesResponse)
+async def list_recent_memories(
+    window: str = Query("24h", description='How far back to look, e.g. "24h" or "7d"'),
+    limit: int = Query(100, ge=1, le=500, description="Maximum memories"),
+    db: Session = Depends(get_db),
+    namespace: str = Depends(get_namespace),
+    agent_id: str | None = Depends(get_agent_id),
+) -> RecentMemoriesResponse:
+    """Memories created or updated within the window, grouped by first tag"""
+    try:
+        since = datetime.utcnow() - timedelta(seconds=parse_interval(window))
+    except ValueError as e:
+        raise HTTPException(status_code=422, detail=str(e)) from e
+
+    query = (
+        db.query(Memory)
+        .filter(
+            Memory.namespace == namespace,
+            Memory.updated_at >= since,
+            Memory.review_status == "approved",
+            Memory.archived_at.is_(None),
+        )
+        .order_by(Memory.updated_at.desc())
+    )
+    memories, _ = readable_page(query, agent_id, limit=limit)
+
+    # Groups keep the order of their most recent memory
+    groups: dict[str, list[MemorySummaryResponse]] = {}
+    for memory in memories:
+        category = memory.tags_list[0] if memory.tags_list else DEFAULT_CATEGORY
+        groups.setdefault(category, []).append(MemorySummaryResponse.model_validate(memory))
+
+    return RecentMemoriesResponse(
+        window=window,
+        since=since,
+        total=len(memories),
+        groups=[RecentMemoryGroup(category=c, memories=m) for c, m in groups.items()],
+    )
+
+
+@router.post("/memories/surface", response_model=MemoryListResponse)
+async def surface_memories(
+    count: int = Query(3, ge=1, le=20, description="Number of memories to surface"),
+    min_idle_days: float = Query(1.0, ge=0, description="Skip memories seen more recently"),
+    tag: str | None = Query(None, description="Only memories with this tag"),
+    db: Session = Depends(get_db),
+    namespace: str = Depends(get_namespace),
+    agent_id: str | None = Depends(get_agent_id),
+) -> MemoryListResponse:
+    """Memories due for review, favouring long-unseen and rarely read ones
+
+    Surfaced memories count as read, so they are not picked again right away.
+    """
+    memories = pick_memories_to_surface(
+        db, namespace, count, min_idle_days, tag, visible=read_filter(agent_id)
+    )
+    mark_surfaced(db, memories)
+    return MemoryListResponse(
+        memories=[MemoryResponse.model_validate(memory) for memory in memories],
+        total=len(memories),
+    )
+
+
+@router.get("/memories/suggest")
+async def suggest_completions(
+    prefix: str = Query(..., min_length=1, description="Beginning of a tag or summary"),
+    limit: int = Query(10, ge=1, le=50, description="Maximum suggestions of each kind"),
+    db: Session = Depends(get_db),
+    namespace: str = Depends(get_namespace),
+    agent_id: str | None = Depends(get_agent_id),
+) -> dict[str, Any]:
+    """Tags and memory summaries starting with a prefix, for autocomplete"""
+    return suggest(db, prefix, namespace, limit, read_filter(agent_id))
+
+
+@router.get("/memories/pending", response_model=MemoryListResponse)
+async def list_pending_memories(
+    limit: int = Query(100, ge=1, le=300, description="Maximum number of memories to return"),
+    db: Session = Depends(get_db),
+    namespace: str = Depends(get_namespace),
+    agent_id: str | None = Depends(get_agent_id),
+) -> MemoryListResponse:
+    """List memories awaiting human approval, oldest first"""
+    query = db.query(Memory).filter(
+        Memory.namespace == namespace, Memory.review_status == "pending"
+    )
+    memories, total = readable_page(
+        query.order_by(Memory.created_at.asc()), agent_id, limit=limit
+    )
+
+    return MemoryListResponse(
+        memories=[MemoryResponse.model_validate(memory) for memory in memories],
+        total=total,
+    )
+
+
+@router.get("/memories/auto-tagged", response_model=MemoryListResponse)
+async def list_auto_tagged_memories(
+    limit: int = Query(100, ge=1, le=300, description="Maximum number of memories to return"),
+    db: Session = Depends(get_db),
+    namespace: str = Depends(get_namespace),
+    agent_id: str | None = Depends(get_agent_id),
+) -> MemoryListResponse:
+    """List memories whose machine-generated tags have not been reviewed, oldest first"""
+    query = db.query(Memory).filter(Memory.namespace == namespace, Memory.auto_tags.is_not(None))
+    memories, total = readable_page(
+        query.order_by(Memory.created_at.asc()), agent_id, limit=limit
+    )
+
+    return MemoryListResponse(
+        memories=[MemoryResponse.model_validate(memory) for memory in memories],
+        total=total,
+    )
+
+
+@router.post("/memories/{memory_id}/auto-tags", response_model=MemoryResponse)
+async def review_memory_auto_tags(
+    memory_id: str,
+    request: ReviewAutoTagsRequest,
+    db: Session = Depends(get_db),
+    namespace: str = Depends(get_namespace),
+    agent_id: str | None = Depends(get_agent_id),
+) -> MemoryResponse:
+    """Keep some or all machine-generated tags and remove the others"""
+    memory = (
+        db.query(Memory).filter(Memory.id == memory_id, Memory.namespace == namespace).first()
+    )
+    if not memory:
+        raise HTTPException(status_code=404, detail=f"Memory with ID '{memory_id}' not found")
+    if memory.auto_tags is None:
+        raise HTTPException(
+            status_code=409, detail=f"Memory '{memory_id}' has no auto tags to review"
+        )
+
+    dropped = review_auto_tags(memory, request.keep)
+    db.commit()
+    db.refresh(memory)
+    await event_bus.publish(
+        MemoryEvent(
+            MEMORY_UPDATED,
+            memory,
+            session=db,
+            agent_id=agent_id,
+            details={"kept": memory.tags_list, "dropped": dropped},
+            operation="auto_tags_reviewed",
+        )
     )
 
+    return MemoryResponse.model_validate(memory)
+
+
+@router.get("/memories/reminders", response_model=MemoryListResponse)
+async def list_due_reminders(
+    include_upcoming_hours: int = Query(
+        0, ge=0, le=24 * 30, description="Also include reminders due within this many hours"
+    ),
+    db: Session = Depends(get_db),
+    namespace: str = Depends(get_namespace),
+    agent_id: str | None = Depends(get_agent_id),
+) -> MemoryListResponse:
+    """List approved, unarchived memories whose reminder time has passed, oldest first"""
+    due_before = datetime.utcnow() + timedelta(hours=include_upcoming_hours)
+    query = db.query(Memory).filter(
+        Memory.namespace == namespace,
+        Memory.review_status == "approved",
+        Memory.archived_at.is_(None),
+        Memory.remind_at.is_not(None),
+        Memory.remind_at <= due_before,
+    )
+    memories = readable(query.order_by(Memory.remind_at.asc()).all(), agent_id)
+
+    return MemoryListResponse(
+        memories=[MemoryResponse.model_validate(memory) for memory in memories],
+        total=len(memories),
+    )
+
+
+@router.post("/memories/{memory_id}/acknowledge", response_model=MemoryResponse)
+async def acknowledge_reminder(
+    memory_id: str,
+    db: Session = Depends(get_db),
+    namespace: str = Depends(get_namespace),
+    agent_id: str | None = Depends(get_agent_id),
+) -> MemoryResponse:
+    """Mark a reminder as handled so it is no longer returned as due"""
+    memory = (
+        db.query(Memory).filter(Memory.id == memory_id, Memory.namespace == namespace).first()
+    )
+    if not memory:
+        raise HTTPException(status_code=404, detail=f"Memory with ID '{memory_id}' not found")
+    if memory.remind_at is None:
+        raise HTTPException(status_code=409, detail=f"Memory '{memory_id}' has no reminder")
+
+    details = {"remind_at": memory.remind_at.isoformat()}
+    memory.remind_at = None
+    db.commit()
+    db.refresh(memory)
+    await event_bus.publish(
+        MemoryEvent(
+            MEMORY_UPDATED,
+            memory,
+            session=db,
+            agent_id=agent_id,
+            details=details,
+            operation="reminder_acknowledged",
+        )
+    )
+
+    return MemoryResponse.model_validate(memory)
+
+
+def _get_pending_memory(
+    db: Session, memory_id: str, namespace: str, agent_id: str | None
+) -> Memory:
+    memory = _get_writable_memory(db, memory_id, namespace, agent_id)
+    if memory.review_status != "pending":
+        raise HTTPException(
+            status_code=409, detail=f"Memory '{memory_id}' is not awaiting approval"
+        )
+    return memory
+
+
+def verify_approver(x_mory_approval_token: str | None = Header(None)) -> None:
+    """Require X-Mory-Approval-Token to approve, unless MORY_TOOL_APPROVAL lets anyone
+
+    Approval exists so a human reviews what the assistant saved, and the assistant can reach
+    the REST API too, so only a credential it does not hold marks the caller as a human.
+    """
+    if settings.tool_approval:
+        return
+    if not settings.approval_token or not hmac.compare_digest(
+        x_mory_approval_token or "", settings.approval_token
+    ):
+        raise HTTPException(
+            status_code=403,
+            detail="Pending memories must be approved by a human: use mory-cli approve, or "
+            "send X-Mory-Approval-Token matching MORY_APPROVAL_TOKEN; set "
+            "MORY_TOOL_APPROVAL=true to allow MCP tools to approve",
+        )
+
+
+@router.post(
+    "/memories/{memory_id}/approve",
+    response_model=MemoryResponse,
+    dependencies=[Depends(verify_approver)],
+)
+async def approve_memory(
+    memory_id: str,
+    db: Session = Depends(get_db),
+    namespace: str = Depends(get_namespace),
+    agent_id: str | None = Depends(get_agent_id),
+) -> MemoryResponse:
+    """Approve a pending memory, making it permanent"""
+    memory = _get_pending_memory(db, memory_id, namespace, agent_id)
+
+    memory.review_status = "approved"
+    db.commit()
+    db.refresh(memory)
+    await event_bus.publish(
+        MemoryEvent(MEMORY_UPDATED, memory, session=db, agent_id=agent_id, operation="approved")
+    )
+
+    return MemoryResponse.model_validate(memory)
+
+
+@router.post("/memories/{memory_id}/reject", response_model=MessageResponse)
+async def reject_memory(
+    memory_id: str,
+    db: Session = Depends(get_db),
+    namespace: str = Depends(get_namespace),
+    agent_id: str | None = Depends(get_agent_id),
+) -> MessageResponse:
+    """Reject a pending memory, discarding it"""
+    memory = _get_pending_memory(db, memory_id, namespace, agent_id)
+
+    db.delete(memory)
+    db.commit()
+    await event_bus.publish(
+        MemoryEvent(MEMORY_DELETED, memory, session=db, agent_id=agent_id, operation="rejected")
+    )
+
+    return MessageResponse(
+        message=f"Memory '{memory_id}' rejected and discarded", data={"rejected_id": memory_id}
+    )
+
+
+@router.post("/memories/summarize", response_model=SummarizeCategoryResponse)
+async def summarize_category(
+    request: SummarizeCategoryRequest,
+    db: Session = Depends(get_db),
+    namespace: str = Depends(get_namespace),
+    agent_id: str | None = Depends(get_agent_id),
+) -> SummarizeCategoryResponse:
+    """Condense all memories with a tag into one summary memory and archive the originals"""
+    from ..services.search import tags_filter
+
+    condition, params = tags_filter("memories.tags", [request.tag])
+    query = db.query(Memory).filter(
+        Memory.namespace == namespace,
+        Memory.review_status == "approved",
+        Memory.archived_at.is_(None),
+        text(condition).bindparams(**params),
+    )
+    if request.older_than_days is not None:
+        cutoff = datetime.utcnow() - timedelta(days=request.older_than_days)
+        query = query.filter(Memory.updated_at < cutoff)
+    memories = [
+        memory
+        for memory in query.order_by(Memory.created_at.asc()).limit(request.limit).all()
+        if check_access(agent_id, memory, "write")
+    ]
+    if len(memories) < 2:
+        raise HTTPException(
+            status_code=404,
+            detail=f"Fewer than two memories tagged '{request.tag}' to summarize",
+        )
+
+    source_ids = [memory.id for memory in memories]
+    scope = {
+        "namespace": namespace,
+        "tag": request.tag,
+        "older_than_days": request.older_than_days,
+        "memories": [[memory.id, memory.updated_at.isoformat()] for memory in memories],
+    }
+    if request.dry_run:
+        summary = await summarization_service.condense([memory.value for memory in memories])
+        token = confirmations.issue("summarize_category", scope, {"summary": summary})
+        return SummarizeCategoryResponse(
+            tag=request.tag,
+            dry_run=True,
+            source_ids=source_ids,
+            summary=summary,
+            confirmation_token=token,
+        )
+
+    # Archiving a whole category needs the token from a preview of exactly these memories
+    if not request.confirmation_token:
+        raise HTTPException(
+            status_code=428,
+            detail="Preview with dry_run first and pass its confirmation_token to proceed",
+        )
+    try:
+        preview = confirmations.redeem(request.confirmation_token, "summarize_category", scope)
+    except ConfirmationError as e:
+        raise HTTPException(status_code=409, detail=str(e)) from e
+    summary = preview["summary"]
+
+    # The summary is new content written by the LLM, so it is redacted like any other save
+    redaction = _redact(_enforce_write_limits(summary, agent_id), db, agent_id)
+    value = redaction.text
+    condensed = Memory(
+        value=value,
+        namespace=namespace,
+        owner=agent_id,
+        source=f"summary:{request.tag}",
+        summary=await summarization_service.generate_summary(value),
+        ai_processed_at=datetime.utcnow(),
+    )
+    condensed.tags_list = [request.tag, "summary"]
+    db.add(condensed)
+    db.flush()
+    _log_redaction(db, redaction, condensed.id, agent_id)
+
+    archived = [
+        set_archived(db, memory, True, agent_id=agent_id, details={"summary_id": condensed.id})
+        for memory in memories
+    ]
+    db.commit()
+    db.refresh(condensed)
+    logger.info(f"Condensed {len(memories)} memories tagged '{request.tag}' into {condensed.id}")
+
+    await event_bus.publish(
+        MemoryEvent(
+            MEMORY_SAVED,
+            condensed,
+            session=db,
+            agent_id=agent_id,
+            details={"source_ids": source_ids},
+        )
+    )
+    for event in archived:
+        await event_bus.publish(event)
+
+    return SummarizeCategoryResponse(
+        tag=request.tag,
+        dry_run=False,
+        source_ids=source_ids,
+        summary=summary,
+        memory=MemoryResponse.model_validate(condensed),
+    )
+
+
+def _record_access(db: Session, memory: Memory) -> None:
+    """Count a read for the auto-archive policy without touching updated_at"""
+    db.query(Memory).filter(Memory.id == memory.id).update(
+        {
+            Memory.access_count: Memory.access_count + 1,
+            Memory.last_accessed_at: datetime.utcnow(),
+            Memory.updated_at: Memory.updated_at,
+        },
+        synchronize_session=False,
+    )
+    db.commit()
+    db.refresh(memory)
+
+
+def _get_writable_memory(
+    db: Session, memory_id: str, namespace: str, agent_id: str | None
+) -> Memory:
+    memory = (
+        db.query(Memory).filter(Memory.id == memory_id, Memory.namespace == namespace).first()
+    )
+    if not memory:
+        raise HTTPException(status_code=404, detail=f"Memory with ID '{memory_id}' not found")
+    if not check_access(agent_id, memory, "write"):
+        raise _forbidden(memory_id, agent_id)
+    return memory
+
+
+@router.post("/memories/{memory_id}/archive", response_model=MemoryResponse)
+async def archive_memory(
+    memory_id: str,
+    db: Session = Depends(get_db),
+    namespace: str = Depends(get_namespace),
+    agent_id: str | None = Depends(get_agent_id),
+) -> MemoryResponse:
+    """Move a memory to the archive tier (hidden unless include_archived=true)"""
+    memory = _get_writable_memory(db, memory_id, namespace, agent_id)
+    if not memory.is_archived:
+        event = set_archived(db, memory, True, agent_id=agent_id)
+        db.commit()
+        db.refresh(memory)
+        await event_bus.publish(event)
+    return MemoryResponse.model_validate(memory)
+
+
+@router.post("/memories/{memory_id}/unarchive", response_model=MemoryResponse)
+async def unarchive_memory(
+    memory_id: str,
+    db: Session = Depends(get_db),
+    namespace: str = Depends(get_namespace),
+    agent_id: str | None = Depends(get_agent_id),
+) -> MemoryResponse:
+    """Restore an archived memory to default list and search results"""
+    memory = _get_writable_memory(db, memory_id, namespace, agent_id)
+    if memory.is_archived:
+        event = set_archived(db, memory, False, agent_id=agent_id)
+        db.commit()
+        db.refresh(memory)
+        await event_bus.publish(event)
+    return MemoryResponse.model_validate(memory)
+
+
+@router.post("/memories/{memory_id}/verify", response_model=MemoryResponse)
+async def verify_memory(
+    memory_id: str,
+    db: Session = Depends(get_db),
+    namespace: str = Depends(get_namespace),
+    agent_id: str | None = Depends(get_agent_id),
+) -> MemoryResponse:
+    """Confirm a tentative fact: marks it verified with full confidence"""
+    memory = _get_writable_memory(db, memory_id, namespace, agent_id)
+    if not memory.verified:
+        details = {"previous_confidence": memory.confidence}
+        memory.verified = True
+        memory.verified_at = datetime.utcnow()
+        memory.confidence = 1.0
+        db.commit()
+        db.refresh(memory)
+        await event_bus.publish(
+            MemoryEvent(
+                MEMORY_UPDATED,
+                memory,
+                session=db,
+                agent_id=agent_id,
+                details=details,
+                operation="verified",
+            )
+        )
+    return MemoryResponse.model_validate(memory)
+
 
 @router.get("/memories/{memory_id}", response_model=MemoryResponse)
 async def get_memory(
     memory_id: str,
     db: Session = Depends(get_db),
+    namespace: str = Depends(get_namespace),
+    agent_id: str | None = Depends(get_agent_id),
 ) -> MemoryResponse:
     """Get memory by ID - simplified AI-driven schema (Issue #112)"""
-    memory = db.query(Memory).filter(Memory.id == memory_id).first()
+    memory = (
+        db.query(Memory).filter(Memory.id == memory_id, Memory.namespace == namespace).first()
+    )
 
-    if not memory:
+    if not memory or not check_access(agent_id, memory, "read"):
         raise HTTPException(
             status_code=404,
             detail=f"Memory with ID '{memory_id}' not found",
         )
 
+    _record_access(db, memory)
     return MemoryResponse.model_validate(memory)
 
 
@@ -224,16 +942,21 @@ async def get_memory(
 async def get_memory_detail(
     memory_id: str,
     db: Session = Depends(get_db),
+    namespace: str = Depends(get_namespace),
+    agent_id: str | None = Depends(get_agent_id),
 ) -> MemoryResponse:
     """Get full memory details by ID - simplified AI-driven schema (Issue #112)"""
-    memory = db.query(Memory).filter(Memory.id == memory_id).first()
+    memory = (
+        db.query(Memory).filter(Memory.id == memory_id, Memory.namespace == namespace).first()
+    )
 
-    if not memory:
+    if not memory or not check_access(agent_id, memory, "read"):
         raise HTTPException(
             status_code=404,
             detail=f"Memory with ID '{memory_id}' not found",
         )
 
+    _record_access(db, memory)
     return MemoryResponse.model_validate(memory)
 
 
@@ -245,16 +968,23 @@ async def list_memories(
     include_full_text: bool = Query(
         False, description="Include full content (backward compatibility)"
     ),
+    owner: str | None = Query(None, description="Only list memories saved by this agent"),
+    include_pending: bool = Query(False, description="Include memories awaiting approval"),
+    include_archived: bool = Query(False, description="Include archived memories"),
     db: Session = Depends(get_db),
+    namespace: str = Depends(get_namespace),
+    agent_id: str | None = Depends(get_agent_id),
 ):
     """List memories with optimized responses - simplified AI-driven schema (Issue #112)"""
-    query = db.query(Memory)
-
-    # Get total count
-    total = query.count()
-
-    # Apply pagination and ordering
-    memories = query.order_by(Memory.updated_at.desc()).offset(offset).limit(limit).all()
+    query = db.query(Memory).filter(Memory.namespace == namespace)
+    if owner:
+        query = query.filter(Memory.owner == owner)
+    if not include_pending:
+        query = query.filter(Memory.review_status == "approved")
+    if not include_archived:
+        query = query.filter(Memory.archived_at.is_(None))
+    query = query.order_by(Memory.updated_at.desc())
+    memories, total = readable_page(query, agent_id, offset, limit)
 
     # Return different response based on include_full_text parameter
     if include_full_text:
@@ -275,8 +1005,17 @@ async def list_memories(
 
             summary_memory = MemorySummaryResponse(
                 id=str(memory.id),
+                namespace=memory.namespace,
+                owner=memory.owner,
+                source=memory.source,
+                confidence=memory.confidence,
+                verified=memory.verified,
+                review_status=memory.review_status,
+                archived_at=memory.archived_at,
                 tags=memory.tags_list or [],
                 summary=str(summary) if summary else None,
+                template=memory.template,
+                rendered=memory.rendered,
                 created_at=memory.created_at,
                 updated_at=memory.updated_at,
                 has_embedding=memory.has_embedding,
@@ -294,9 +1033,13 @@ async def list_memories(
 async def delete_memory(
     memory_id: str,
     db: Session = Depends(get_db),
+    namespace: str = Depends(get_namespace),
+    agent_id: str | None = Depends(get_agent_id),
 ) -> MessageResponse:
     """Delete memory by ID - simplified AI-driven schema (Issue #112)"""
-    memory = db.query(Memory).filter(Memory.id == memory_id).first()
+    memory = (
+        db.query(Memory).filter(Memory.id == memory_id, Memory.namespace == namespace).first()
+    )
 
     if not memory:
         raise HTTPException(
@@ -304,8 +1047,20 @@ async def delete_memory(
             detail=f"Memory with ID '{memory_id}' not found",
         )
 
+    if not check_access(agent_id, memory, "write"):
+        raise _forbidden(memory_id, agent_id)
+
     db.delete(memory)
     db.commit()
+    await event_bus.publish(
+        MemoryEvent(
+            MEMORY_DELETED,
+            memory,
+            session=db,
+            agent_id=agent_id,
+            details={"summary": memory.summary},
+        )
+    )
 
     return MessageResponse(
         message=f"Memory '{memory_id}' deleted successfully", data={"deleted_id": memory.id}
@@ -317,16 +1072,17 @@ async def update_memory(
     memory_id: str,
     memory_update: MemoryUpdate,
     db: Session = Depends(get_db),
+    namespace: str = Depends(get_namespace),
+    agent_id: str | None = Depends(get_agent_id),
 ) -> MemoryResponse:
     """Update memory by ID - simplified AI-driven schema (Issue #112)"""
-    import traceback
-    import uuid
-
-    request_id = str(uuid.uuid4())[:8]
+    request_id = current_request_id()
     errors = []  # Track non-fatal errors
 
     try:
-        memory = db.query(Memory).filter(Memory.id == memory_id).first()
+        memory = (
+            db.query(Memory).filter(Memory.id == memory_id, Memory.namespace == namespace).first()
+        )
 
         if not memory:
             raise HTTPException(
@@ -340,10 +1096,44 @@ async def update_memory(
                 },
             )
 
-        # Update value (only field that can be updated in simplified schema)
+        if not check_access(agent_id, memory, "write"):
+            raise _forbidden(memory_id, agent_id)
+
+        before = memory_snapshot(memory)
         update_data = memory_update.model_dump(exclude_unset=True)
+        template = update_data.get("template", memory.template)
+        if template and ("template" in update_data or "metadata" in update_data):
+            metadata = update_data.get("metadata", memory.metadata_dict)
+            update_data["metadata"] = _template_fields(template, metadata)
+        if "content_type" in update_data and not update_data["content_type"]:
+            # null asks for detection from the (new) value
+            content = update_data.get("value", memory.value)
+            update_data["content_type"] = detect_content_type(content)
+        fields = {name: update_data[name] for name in METADATA_FIELDS if name in update_data}
+        for name, field_value in fields.items():
+            setattr(memory, METADATA_FIELDS[name], field_value)
+        if fields and "value" not in update_data:
+            # Metadata-only change: no AI re-processing, and the embedding is kept
+            db.commit()
+            db.refresh(memory)
+            await event_bus.publish(
+                MemoryEvent(
+                    MEMORY_UPDATED,
+                    memory,
+                    session=db,
+                    agent_id=agent_id,
+                    details={"before": before},
+                )
+            )
+
+        # Content changes re-run AI processing
         if "value" in update_data:
-            memory.value = update_data["value"]
+            value = _enforce_write_limits(update_data["value"], agent_id)
+            redaction = _redact(value, db, agent_id, memory_id)
+            memory.value = redaction.text
+            if "content_type" not in update_data:
+                memory.content_type = detect_content_type(memory.value)
+            _log_redaction(db, redaction, memory_id, agent_id)
 
             # Re-process with AI when value changes
             if summarization_service.enabled:
@@ -378,8 +1168,7 @@ async def update_memory(
 
                     memory.ai_processed_at = datetime.utcnow()
                 except Exception as e:
-                    error_msg = f"AI re-processing failed: {str(e)} (request_id: {request_id})"
-                    print(error_msg)
+                    logger.warning(f"AI re-processing failed: {str(e)}")
                     errors.append(
                         {
                             "stage": "ai_reprocessing",
@@ -389,29 +1178,12 @@ async def update_memory(
                         }
                     )
 
-            # Regenerate vector embedding when content changes
-            if embedding_service.enabled:
-                try:
-                    await embedding_service.generate_embedding_for_memory(memory)
-                except Exception as e:
-                    error_msg = (
-                        f"Embedding regeneration failed: {str(e)} (request_id: {request_id})"
-                    )
-                    print(error_msg)
-                    errors.append(
-                        {
-                            "stage": "embedding_regeneration",
-                            "error": str(e),
-                            "error_type": type(e).__name__,
-                            "recoverable": True,
-                        }
-                    )
-
             # Database update operation
             try:
-                memory.updated_at = datetime.utcnow()
-                db.commit()
-                db.refresh(memory)
+                with trace_span("db_write"):
+                    memory.updated_at = datetime.utcnow()
+                    db.commit()
+                    db.refresh(memory)
             except Exception as e:
                 db.rollback()
                 raise HTTPException(
@@ -427,10 +1199,21 @@ async def update_memory(
                     },
                 ) from e
 
+            # Subscribers regenerate the embedding for the new content
+            await event_bus.publish(
+                MemoryEvent(
+                    MEMORY_UPDATED,
+                    memory,
+                    session=db,
+                    agent_id=agent_id,
+                    details={"before": before},
+                )
+            )
+
         # Add warnings to response if there were non-fatal errors
         response = MemoryResponse.model_validate(memory)
         if errors:
-            print(f"Memory updated with warnings (request_id: {request_id}): {errors}")
+            logger.warning(f"Memory updated with warnings: {errors}")
 
         return response
 
@@ -440,8 +1223,7 @@ async def update_memory(
     except Exception as e:
         # Catch any unexpected errors
         db.rollback()
-        error_trace = traceback.format_exc()
-        print(f"Unexpected error updating memory (request_id: {request_id}): {error_trace}")
+        logger.exception("Unexpected error updating memory")
 
         raise HTTPException(
             status_code=500,
@@ -462,11 +1244,54 @@ async def update_memory(
 async def search_memories(
     search_request: SearchRequest,
     db: Session = Depends(get_db),
+    namespace: str = Depends(get_namespace),
+    agent_id: str | None = Depends(get_agent_id),
 ) -> SearchResponse:
     """Advanced memory search with FTS5 and semantic search support"""
-    from ..services.search import search_service
+    from ..services.search import SearchQueryError, search_service
+
+    if not search_request.namespace:
+        search_request = search_request.model_copy(update={"namespace": namespace})
 
     try:
-        return await search_service.search_memories(search_request, db)
+        # Results the permission hook hides from this agent are left out before paginating
+        response = await search_service.search_memories(
+            search_request, db, read_filter(agent_id)
+        )
+    except SearchQueryError as e:
+        raise HTTPException(status_code=400, detail=str(e)) from e
     except Exception as e:
         raise HTTPException(status_code=500, detail=f"Search failed: {str(e)}") from e
+
+    if settings.query_log:
+        try:
+            response.query_id = log_search(db, namespace, agent_id, response).id
+        except OperationalError as e:
+            db.rollback()
+            logger.warning(f"Search not logged: {e}")
+    return response
+
+
+@router.post("/memories/search/{query_id}/chosen")
+async def report_chosen_result(
+    query_id: int,
+    request: ChosenResultRequest,
+    db: Session = Depends(get_db),
+    namespace: str = Depends(get_namespace),
+) -> dict[str, Any]:
+    """Record which result of a logged search was used"""
+    entry = record_choice(db, query_id, request.memory_id, namespace)
+    if entry is None:
+        raise HTTPException(status_code=404, detail=f"Search {query_id} is not in the query log")
+    return entry.to_dict()
+
+
+@router.get("/memories/search/analytics")
+async def get_search_analytics(
+    days: int = Query(30, ge=1, le=3650, description="Period to analyze"),
+    limit: int = Query(10, ge=1, le=100, description="Queries per list"),
+    db: Session = Depends(get_db),
+    namespace: str = Depends(get_namespace),
+) -> dict[str, Any]:
+    """Most frequent and zero-result queries from the query log"""
+    return {"enabled": settings.query_log, **search_analytics(db, namespace, days, limit)}
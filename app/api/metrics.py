@@ -0,0 +1,49 @@
+"""Metrics endpoints for Mory Server
+Prometheus scrape target and JSON view for the MCP get_metrics tool
+"""
+
+from pathlib import Path
+from typing import Any
+
+from fastapi import APIRouter, Depends
+from fastapi.responses import PlainTextResponse
+from sqlalchemy.orm import Session
+
+from ..core.config import settings
+from ..core.database import get_db
+from ..core.metrics import metrics
+from ..services.counts import count_memories, count_with_embeddings
+
+router = APIRouter()
+
+
+def _collect_gauges(db: Session) -> dict[str, tuple[str, float]]:
+    """Point-in-time values computed at scrape time"""
+    total = count_memories(db)
+    with_embeddings = count_with_embeddings(db)
+
+    db_size = 0
+    url = settings.sqlite_url
+    if url.startswith("sqlite:///"):
+        db_path = Path(url.removeprefix("sqlite:///"))
+        if db_path.exists():
+            db_size = db_path.stat().st_size
+
+    return {
+        "mory_memories": ("Number of stored memories", total),
+        "mory_memories_with_embedding": ("Memories that have an embedding", with_embeddings),
+        "mory_database_size_bytes": ("Size of the SQLite database file", db_size),
+    }
+
+
+@router.get("/metrics", response_class=PlainTextResponse)
+async def prometheus_metrics(db: Session = Depends(get_db)) -> str:
+    """Prometheus text exposition format"""
+    return metrics.render_prometheus(_collect_gauges(db))
+
+
+@router.get("/api/metrics")
+async def metrics_json(db: Session = Depends(get_db)) -> dict[str, Any]:
+    """Metrics as JSON"""
+    gauges = {name: value for name, (_help, value) in _collect_gauges(db).items()}
+    return {"gauges": gauges, **metrics.snapshot()}
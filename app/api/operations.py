@@ -0,0 +1,26 @@
+"""Operation log endpoints"""
+
+from datetime import datetime
+from typing import Any
+
+from fastapi import APIRouter, Depends, Query
+from sqlalchemy.orm import Session
+
+from ..core.database import get_db
+from ..services.operation_log import search_operations
+
+router = APIRouter()
+
+
+@router.get("/operations/search")
+async def search_operation_log(
+    q: str = Query(..., min_length=1, description="Words in the memory content or ID"),
+    operation: str | None = Query(None, description='Only this operation, e.g. "deleted"'),
+    since: datetime | None = Query(None, description="Only entries at or after this UTC time"),
+    until: datetime | None = Query(None, description="Only entries at or before this UTC time"),
+    limit: int = Query(20, ge=1, le=100, description="Maximum results"),
+    db: Session = Depends(get_db),
+) -> dict[str, Any]:
+    """Search the before/after snapshots recorded in the operation log"""
+    entries = search_operations(db, q, operation, since, until, limit)
+    return {"query": q, "total": len(entries), "results": [entry.to_dict() for entry in entries]}
@@ -0,0 +1,42 @@
+"""Sync endpoints used by another Mory instance (see app/services/sync.py)"""
+
+import hmac
+from datetime import datetime
+from typing import Any
+
+from fastapi import APIRouter, Depends, Header, HTTPException, Query
+from sqlalchemy.orm import Session
+
+from ..core.config import settings
+from ..core.database import get_db
+from ..models.schemas import SyncApplyRequest
+from ..services.sync import apply_changes, export_changes
+
+router = APIRouter()
+
+
+def verify_sync_token(x_mory_sync_token: str | None = Header(None)) -> None:
+    """Require X-Mory-Sync-Token when MORY_SYNC_TOKEN is set"""
+    if settings.sync_token and not hmac.compare_digest(
+        x_mory_sync_token or "", settings.sync_token
+    ):
+        raise HTTPException(status_code=401, detail="Invalid sync token")
+
+
+@router.get("/sync/changes", dependencies=[Depends(verify_sync_token)])
+async def get_changes(
+    since: datetime | None = Query(None, description="Only changes after this UTC time"),
+    db: Session = Depends(get_db),
+) -> dict[str, Any]:
+    """Memories changed and deleted since the given time"""
+    return export_changes(db, since)
+
+
+@router.post("/sync/apply", dependencies=[Depends(verify_sync_token)])
+async def apply_peer_changes(
+    request: SyncApplyRequest,
+    db: Session = Depends(get_db),
+) -> dict[str, Any]:
+    """Apply changes exported by a peer"""
+    result = await apply_changes(db, request.changes, request.since, peer=request.peer)
+    return result.to_dict()
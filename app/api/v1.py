@@ -0,0 +1,37 @@
+"""Versioned REST API (/v1) for shell scripts and other non-MCP clients
+Shares handlers, validation and storage with the /api memory endpoints
+"""
+
+from fastapi import APIRouter, Depends, Query
+from sqlalchemy.orm import Session
+
+from ..core.database import get_db
+from ..models.schemas import SearchRequest, SearchResponse
+from .memories import get_agent_id, get_namespace, search_memories
+from .memories import router as memories_router
+
+router = APIRouter()
+
+# /v1/memories, /v1/memories/{id}, ... are the same handlers as /api/memories
+router.include_router(memories_router)
+
+# /v1/search: POST takes a SearchRequest body, GET takes query parameters (curl friendly)
+router.add_api_route("/search", search_memories, methods=["POST"], response_model=SearchResponse)
+
+
+@router.get("/search", response_model=SearchResponse)
+async def search_memories_get(
+    q: str = Query(..., min_length=1, description="Search query"),
+    search_type: str = Query("hybrid", description="fts5, semantic or hybrid"),
+    limit: int = Query(20, ge=1, le=100, description="Maximum results"),
+    tags: list[str] | None = Query(None, description="Filter by tags"),
+    advanced: bool = Query(False, description="Interpret FTS5 operators in the query"),
+    db: Session = Depends(get_db),
+    namespace: str = Depends(get_namespace),
+    agent_id: str | None = Depends(get_agent_id),
+) -> SearchResponse:
+    """Search memories with query parameters"""
+    request = SearchRequest(
+        query=q, search_type=search_type, limit=limit, tags=tags, advanced=advanced
+    )
+    return await search_memories(request, db=db, namespace=namespace, agent_id=agent_id)
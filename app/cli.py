@@ -0,0 +1,682 @@
+"""mory-cli: manage memories from a terminal
+Works directly against the configured SQLite database (no MCP or HTTP server needed)
+"""
+
+import argparse
+import asyncio
+import json
+import sys
+from collections.abc import Callable
+from datetime import datetime
+from pathlib import Path
+
+import httpx
+from sqlalchemy.orm import Session
+
+from .core.config import settings
+from .core.events import MEMORY_DELETED, MEMORY_SAVED, MEMORY_UPDATED, MemoryEvent, event_bus
+from .core.fileutil import atomic_write_text
+from .models.memory import Memory
+from .models.schemas import SearchRequest
+from .services.importers import IMPORTERS, ImportFormatError, ImportResult, import_memories
+from .services.stats import search_counter
+from .services.subscribers import register_subscribers
+
+
+def _preview(text: str | None, length: int = 60) -> str:
+    text = (text or "").replace("\n", " ")
+    return text if len(text) <= length else text[: length - 3] + "..."
+
+
+def _find(db: Session, memory_id: str, namespace: str) -> Memory | None:
+    return db.query(Memory).filter(Memory.id == memory_id, Memory.namespace == namespace).first()
+
+
+def cmd_save(db: Session, args: argparse.Namespace) -> int:
+    """Save a new memory (value "-" reads from stdin)"""
+    value = sys.stdin.read() if args.value == "-" else args.value
+    if not value.strip():
+        print("❌ Memory value cannot be empty", file=sys.stderr)
+        return 1
+
+    memory = Memory(
+        value=value.strip(), namespace=args.namespace, owner=settings.agent_id, source="cli"
+    )
+    if args.tags:
+        memory.tags_list = args.tags
+    if args.meta:
+        invalid = [item for item in args.meta if "=" not in item]
+        if invalid:
+            print(f"❌ Metadata must be KEY=VALUE: {', '.join(invalid)}", file=sys.stderr)
+            return 1
+        memory.metadata_dict = dict(item.split("=", 1) for item in args.meta)
+    db.add(memory)
+    db.commit()
+    db.refresh(memory)
+    asyncio.run(event_bus.publish(MemoryEvent(MEMORY_SAVED, memory, session=db)))
+
+    print(memory.id)
+    return 0
+
+
+def cmd_get(db: Session, args: argparse.Namespace) -> int:
+    """Print a memory as JSON"""
+    memory = _find(db, args.memory_id, args.namespace)
+    if not memory:
+        print(f"❌ Memory '{args.memory_id}' not found", file=sys.stderr)
+        return 1
+
+    print(json.dumps(memory.to_dict(), indent=2, ensure_ascii=False))
+    return 0
+
+
+def cmd_list(db: Session, args: argparse.Namespace) -> int:
+    """List memories, newest first"""
+    query = db.query(Memory).filter(Memory.namespace == args.namespace)
+    if not args.include_pending:
+        query = query.filter(Memory.review_status == "approved")
+    if not args.include_archived:
+        query = query.filter(Memory.archived_at.is_(None))
+
+    memories = query.order_by(Memory.updated_at.desc()).offset(args.offset).limit(args.limit)
+    for memory in memories:
+        updated = memory.updated_at.strftime("%Y-%m-%d %H:%M") if memory.updated_at else "-"
+        print(f"{memory.id}  {updated}  {_preview(memory.summary or memory.value)}")
+    return 0
+
+
+def cmd_search(db: Session, args: argparse.Namespace) -> int:
+    """Search memories with the same engine as the API"""
+    from .services.search import search_service
+
+    request = SearchRequest(
+        query=args.query,
+        namespace=args.namespace,
+        include_pending=args.include_pending,
+        include_archived=args.include_archived,
+        limit=args.limit,
+        search_type=args.type,
+        language=args.language,
+    )
+    response = asyncio.run(search_service.search_memories(request, db))
+
+    for result in response.results:
+        memory = result.memory
+        print(f"{memory.id}  {result.score:.3f}  {_preview(memory.summary or memory.value)}")
+    elapsed_ms = response.execution_time_ms
+    print(f"({response.total} results, {response.search_type}, {elapsed_ms:.0f}ms)")
+    return 0
+
+
+def cmd_delete(db: Session, args: argparse.Namespace, input_fn: Callable = input) -> int:
+    """Delete a memory after confirmation"""
+    memory = _find(db, args.memory_id, args.namespace)
+    if not memory:
+        print(f"❌ Memory '{args.memory_id}' not found", file=sys.stderr)
+        return 1
+
+    if not args.yes:
+        answer = input_fn(f"Delete {memory.id} ({_preview(memory.value, 40)})? [y/N] ")
+        if answer.strip().lower() not in ("y", "yes"):
+            print("Aborted")
+            return 1
+
+    db.delete(memory)
+    db.commit()
+    asyncio.run(
+        event_bus.publish(
+            MemoryEvent(MEMORY_DELETED, memory, session=db, details={"summary": memory.summary})
+        )
+    )
+    print(f"🗑️  Deleted {args.memory_id}")
+    return 0
+
+
+def _find_pending(db: Session, args: argparse.Namespace) -> Memory | None:
+    memory = _find(db, args.memory_id, args.namespace)
+    if not memory:
+        print(f"❌ Memory '{args.memory_id}' not found", file=sys.stderr)
+    elif memory.review_status != "pending":
+        print(f"❌ Memory '{args.memory_id}' is not awaiting approval", file=sys.stderr)
+        memory = None
+    return memory
+
+
+def cmd_approve(db: Session, args: argparse.Namespace) -> int:
+    """Approve a pending memory saved by the assistant"""
+    memory = _find_pending(db, args)
+    if not memory:
+        return 1
+
+    memory.review_status = "approved"
+    db.commit()
+    db.refresh(memory)
+    asyncio.run(
+        event_bus.publish(MemoryEvent(MEMORY_UPDATED, memory, session=db, operation="approved"))
+    )
+    print(f"✅ Approved {args.memory_id}")
+    return 0
+
+
+def cmd_reject(db: Session, args: argparse.Namespace) -> int:
+    """Reject a pending memory, discarding it"""
+    memory = _find_pending(db, args)
+    if not memory:
+        return 1
+
+    db.delete(memory)
+    db.commit()
+    asyncio.run(
+        event_bus.publish(MemoryEvent(MEMORY_DELETED, memory, session=db, operation="rejected"))
+    )
+    print(f"🗑️  Rejected {args.memory_id}")
+    return 0
+
+
+def cmd_export(db: Session, args: argparse.Namespace) -> int:
+    """Export memories as a JSON array"""
+    query = db.query(Memory)
+    if not args.all_namespaces:
+        query = query.filter(Memory.namespace == args.namespace)
+
+    payload = json.dumps(
+        [memory.to_dict() for memory in query.order_by(Memory.created_at.asc())],
+        indent=2,
+        ensure_ascii=False,
+    )
+
+    if args.output:
+        atomic_write_text(args.output, payload + "\n")
+        print(f"✅ Exported to {args.output}", file=sys.stderr)
+    else:
+        print(payload)
+    return 0
+
+
+def _report_import_progress(done: int, total: int, result: ImportResult) -> None:
+    print(
+        f"⏳ {done}/{total}: {result.created} created, {result.updated} updated, "
+        f"{result.unchanged} unchanged, {result.skipped} skipped",
+        file=sys.stderr,
+    )
+
+
+def cmd_import(db: Session, args: argparse.Namespace) -> int:
+    """Import memories from another tool's export (Ctrl-C cancels without saving)"""
+    try:
+        items = list(IMPORTERS[args.format](args.path))
+    except (ImportFormatError, OSError) as e:
+        print(f"❌ Import failed: {e}", file=sys.stderr)
+        return 1
+
+    try:
+        result = asyncio.run(
+            import_memories(
+                db,
+                items,
+                args.namespace,
+                dry_run=args.dry_run,
+                progress=_report_import_progress,
+            )
+        )
+    except KeyboardInterrupt:
+        print("❌ Import cancelled; nothing was saved", file=sys.stderr)
+        return 1
+    print(json.dumps(result.to_dict(), indent=2, ensure_ascii=False))
+    if result.error_report:
+        print(
+            f"⚠️  {result.skipped + result.failed} items not imported: {result.error_report}",
+            file=sys.stderr,
+        )
+    return 0
+
+
+def cmd_notion_sync(db: Session, args: argparse.Namespace) -> int:
+    """Import pages edited in a Notion database since the last run"""
+    from .services.importers import NotionImporter
+
+    database_id = args.database or settings.notion_database_id
+    if not database_id:
+        print("❌ Pass --database or set MORY_NOTION_DATABASE_ID", file=sys.stderr)
+        return 1
+    importer = NotionImporter(database_id)
+    try:
+        result = asyncio.run(
+            importer.sync(db, args.namespace, dry_run=args.dry_run, full=args.full)
+        )
+    except (ValueError, httpx.HTTPError) as e:
+        print(f"❌ Notion import failed: {e}", file=sys.stderr)
+        return 1
+
+    print(json.dumps(result.to_dict(), indent=2, ensure_ascii=False))
+    return 0
+
+
+def cmd_mail_sync(db: Session, args: argparse.Namespace) -> int:
+    """Import new messages from an IMAP folder"""
+    import imaplib
+
+    from .services.importers import MailImporter
+
+    try:
+        result = asyncio.run(
+            MailImporter(args.folder).sync(
+                db, args.namespace, dry_run=args.dry_run, full=args.full
+            )
+        )
+    except (ValueError, OSError, imaplib.IMAP4.error) as e:
+        print(f"❌ Mail import failed: {e}", file=sys.stderr)
+        return 1
+
+    print(json.dumps(result.to_dict(), indent=2, ensure_ascii=False))
+    return 0
+
+
+def cmd_sync(db: Session, args: argparse.Namespace) -> int:
+    """Two-way sync with another Mory server"""
+    from .services.sync import SyncClient
+
+    client = SyncClient(args.peer, token=args.token or settings.sync_token)
+    try:
+        result = asyncio.run(client.sync(db))
+    except httpx.HTTPError as e:
+        print(f"❌ Sync with {args.peer} failed: {e}", file=sys.stderr)
+        return 1
+
+    print(json.dumps(result, indent=2, ensure_ascii=False))
+    return 0
+
+
+def cmd_git_snapshot(db: Session, args: argparse.Namespace) -> int:
+    """Write every memory to the MORY_GIT_DIR repository and commit"""
+    from .services.git_store import GitError, git_mirror
+
+    mirror = git_mirror()
+    if mirror is None:
+        print("❌ MORY_GIT_DIR is not set", file=sys.stderr)
+        return 1
+    try:
+        count = mirror.snapshot(db)
+    except (GitError, OSError) as e:
+        print(f"❌ Snapshot failed: {e}", file=sys.stderr)
+        return 1
+
+    print(f"✅ Committed {count} memories to {mirror.repo_dir}")
+    return 0
+
+
+def cmd_files_sync(db: Session, args: argparse.Namespace) -> int:
+    """Load edits made to the markdown files (MORY_STORAGE_BACKEND=files)"""
+    from .services.file_store import file_store
+
+    store = file_store()
+    if store is None:
+        print("❌ MORY_STORAGE_BACKEND is not files", file=sys.stderr)
+        return 1
+
+    if args.export:
+        count = store.export_all(db)
+        print(f"✅ Wrote {count} memories to {store.root}")
+        return 0
+
+    result = asyncio.run(store.sync_to_database(db))
+    print(json.dumps(result.to_dict(), indent=2, ensure_ascii=False))
+    return 0
+
+
+def cmd_compact(db: Session, args: argparse.Namespace) -> int:
+    """Run WAL checkpoint, VACUUM and ANALYZE and report the space reclaimed"""
+    from sqlalchemy.exc import OperationalError
+
+    from .core.database import compact_database
+
+    db.close()  # VACUUM needs the database free of open transactions
+    try:
+        report = compact_database(db.get_bind())
+    except OperationalError as e:
+        print(f"❌ Compaction failed: {e.orig}", file=sys.stderr)
+        return 1
+
+    print(
+        f"✅ {report['size_before']:,} -> {report['size_after']:,} bytes "
+        f"({report['reclaimed']:,} reclaimed)"
+    )
+    return 0
+
+
+def cmd_detect_languages(db: Session, args: argparse.Namespace) -> int:
+    """Record the language of memories saved before languages were detected"""
+    from .services.language import backfill_languages
+
+    updated = backfill_languages(db)
+    print(f"✅ Detected the language of {updated} memories")
+    return 0
+
+
+def cmd_export_audit(db: Session, args: argparse.Namespace) -> int:
+    """Export the hash-chained operation log for a time range"""
+    from .services.operation_log import audit_entries, audit_record, export_audit
+
+    records = [audit_record(entry) for entry in audit_entries(db, args.since, args.until)]
+    payload = export_audit(records, args.format)
+    if args.output:
+        atomic_write_text(args.output, payload)
+        print(f"✅ Exported {len(records)} operations to {args.output}", file=sys.stderr)
+        if records:
+            print(f"   Last hash: {records[-1]['hash']}", file=sys.stderr)
+    else:
+        print(payload, end="")
+    return 0
+
+
+def cmd_verify_audit(db: Session, args: argparse.Namespace) -> int:
+    """Check the hash chain of an audit export, or of the whole operation log"""
+    from .services.operation_log import audit_entries, audit_record, load_audit, verify_chain
+
+    if args.path:
+        path = Path(args.path)
+        try:
+            text = path.read_text(encoding="utf-8")
+        except OSError as e:
+            print(f"❌ Cannot read {path}: {e}", file=sys.stderr)
+            return 1
+        records = load_audit(text, "csv" if path.suffix.lower() == ".csv" else "jsonl")
+    else:
+        records = [audit_record(entry) for entry in audit_entries(db)]
+
+    problems = verify_chain(records)
+    for problem in problems:
+        print(f"❌ {problem}", file=sys.stderr)
+    if problems:
+        return 1
+    print(f"✅ Hash chain intact ({len(records)} operations)")
+    return 0
+
+
+def cmd_report(db: Session, args: argparse.Namespace) -> int:
+    """Write the markdown digest report"""
+    from .services.report import build_report
+
+    report = build_report(db, args.namespace, args.days)
+    if args.output:
+        atomic_write_text(args.output, report)
+        print(f"✅ Report written to {args.output}")
+    else:
+        print(report, end="")
+    return 0
+
+
+def cmd_snapshot(db: Session, args: argparse.Namespace) -> int:
+    """Write the whole data directory (database, files, manifest) to one tar.gz"""
+    from .services.snapshot import SnapshotError, create_snapshot, default_snapshot_path
+
+    database = db.get_bind().url.database
+    db.close()
+    output = args.output or default_snapshot_path(settings.data_dir)
+    try:
+        manifest = create_snapshot(
+            settings.data_dir, None if database in (None, ":memory:") else database, output
+        )
+    except (SnapshotError, OSError) as e:
+        print(f"❌ Snapshot failed: {e}", file=sys.stderr)
+        return 1
+
+    print(
+        f"✅ {len(manifest['files'])} files ({manifest.get('memories', 0)} memories, "
+        f"{manifest.get('embeddings', 0)} embeddings) written to {output}"
+    )
+    return 0
+
+
+def cmd_restore(args: argparse.Namespace) -> int:
+    """Restore a snapshot into an empty data directory"""
+    from .services.snapshot import SnapshotError, restore_snapshot
+
+    data_dir = args.data_dir or settings.data_dir
+    try:
+        manifest = restore_snapshot(args.archive, data_dir)
+    except (SnapshotError, OSError) as e:
+        print(f"❌ Restore failed: {e}", file=sys.stderr)
+        return 1
+
+    print(
+        f"✅ Restored {len(manifest['files'])} files ({manifest.get('memories', 0)} memories) "
+        f"from the {manifest['created_at']} snapshot into {data_dir}"
+    )
+    return 0
+
+
+def cmd_tui(db: Session, args: argparse.Namespace) -> int:
+    """Open the interactive memory browser"""
+    try:
+        from .tui import run_tui
+    except ImportError:
+        print("❌ The TUI requires curses (Windows: pip install windows-curses)", file=sys.stderr)
+        return 1
+
+    return run_tui(db, args.namespace)
+
+
+def _parse_listen(listen: str) -> tuple[str, int]:
+    """Parse "host:port" or ":port" (binds to localhost)"""
+    host, _, port = listen.rpartition(":")
+    return host or "127.0.0.1", int(port)
+
+
+def cmd_web(db: Session, args: argparse.Namespace) -> int:
+    """Serve the dashboard on its own, reading the database directly"""
+    import uvicorn
+    from fastapi import FastAPI
+    from fastapi.responses import RedirectResponse
+
+    from .api.dashboard import router as dashboard_router
+
+    try:
+        host, port = _parse_listen(args.listen)
+    except ValueError:
+        print(f"❌ Invalid listen address: {args.listen}", file=sys.stderr)
+        return 1
+
+    web_app = FastAPI(title="Mory Dashboard")
+    web_app.include_router(dashboard_router)
+    web_app.add_api_route("/", lambda: RedirectResponse("/dashboard"), include_in_schema=False)
+
+    print(f"🌐 Dashboard: http://{host}:{port}/dashboard", file=sys.stderr)
+    uvicorn.run(web_app, host=host, port=port)
+    return 0
+
+
+COMMANDS: dict[str, Callable[[Session, argparse.Namespace], int]] = {
+    "save": cmd_save,
+    "get": cmd_get,
+    "list": cmd_list,
+    "search": cmd_search,
+    "delete": cmd_delete,
+    "approve": cmd_approve,
+    "reject": cmd_reject,
+    "export": cmd_export,
+    "import": cmd_import,
+    "notion-sync": cmd_notion_sync,
+    "mail-sync": cmd_mail_sync,
+    "sync": cmd_sync,
+    "git-snapshot": cmd_git_snapshot,
+    "files-sync": cmd_files_sync,
+    "compact": cmd_compact,
+    "detect-languages": cmd_detect_languages,
+    "export-audit": cmd_export_audit,
+    "verify-audit": cmd_verify_audit,
+    "snapshot": cmd_snapshot,
+    "report": cmd_report,
+    "tui": cmd_tui,
+    "web": cmd_web,
+}
+
+
+def build_parser() -> argparse.ArgumentParser:
+    parser = argparse.ArgumentParser(prog="mory-cli", description="Manage Mory memories")
+    parser.add_argument(
+        "--namespace", default=settings.namespace, help="Memory namespace (default: %(default)s)"
+    )
+    subparsers = parser.add_subparsers(dest="command", required=True)
+
+    save = subparsers.add_parser("save", help="Save a new memory")
+    save.add_argument("value", help='Memory content ("-" to read from stdin)')
+    save.add_argument("--tags", nargs="*", help="Tags to attach")
+    save.add_argument(
+        "--meta", action="append", metavar="KEY=VALUE", help="Metadata field (repeatable)"
+    )
+
+    get = subparsers.add_parser("get", help="Show a memory as JSON")
+    get.add_argument("memory_id")
+
+    list_parser = subparsers.add_parser("list", help="List recent memories")
+    list_parser.add_argument("--limit", type=int, default=20)
+    list_parser.add_argument("--offset", type=int, default=0)
+    list_parser.add_argument("--include-pending", action="store_true")
+    list_parser.add_argument("--include-archived", action="store_true")
+
+    search = subparsers.add_parser("search", help="Search memories")
+    search.add_argument("query")
+    search.add_argument("--limit", type=int, default=10)
+    search.add_argument("--type", choices=["hybrid", "fts5", "semantic"], default="hybrid")
+    search.add_argument("--include-pending", action="store_true")
+    search.add_argument("--include-archived", action="store_true")
+    search.add_argument("--language", choices=["ja", "zh", "ko", "en"])
+
+    delete = subparsers.add_parser("delete", help="Delete a memory")
+    delete.add_argument("memory_id")
+    delete.add_argument("-y", "--yes", action="store_true", help="Do not ask for confirmation")
+
+    approve = subparsers.add_parser("approve", help="Approve a pending memory")
+    approve.add_argument("memory_id")
+
+    reject = subparsers.add_parser("reject", help="Reject and discard a pending memory")
+    reject.add_argument("memory_id")
+
+    export = subparsers.add_parser("export", help="Export memories as JSON")
+    export.add_argument("-o", "--output", help="Write to a file instead of stdout")
+    export.add_argument("--all-namespaces", action="store_true")
+
+    import_parser = subparsers.add_parser(
+        "import", help="Import memories from another tool's export (re-running updates them)"
+    )
+    import_parser.add_argument("format", choices=sorted(IMPORTERS))
+    import_parser.add_argument(
+        "path", nargs="?", help="Export file, directory or zip archive (apple-notes: optional)"
+    )
+    import_parser.add_argument(
+        "--dry-run", action="store_true", help="Only report what would be created or updated"
+    )
+
+    notion_sync = subparsers.add_parser(
+        "notion-sync", help="Import pages edited in a Notion database since the last run"
+    )
+    notion_sync.add_argument("--database", help="Database ID (default: MORY_NOTION_DATABASE_ID)")
+    notion_sync.add_argument("--dry-run", action="store_true", help="Only report the changes")
+    notion_sync.add_argument("--full", action="store_true", help="Import every page again")
+
+    mail_sync = subparsers.add_parser(
+        "mail-sync", help="Import new messages from an IMAP folder (e.g. a Gmail label)"
+    )
+    mail_sync.add_argument("--folder", help="Folder or label (default: MORY_IMAP_FOLDER)")
+    mail_sync.add_argument("--dry-run", action="store_true", help="Only report the changes")
+    mail_sync.add_argument("--full", action="store_true", help="Import every matching message")
+
+    sync = subparsers.add_parser("sync", help="Two-way sync with another Mory server")
+    sync.add_argument("peer", help="Peer base URL, e.g. http://laptop:8080 (or an SSH tunnel)")
+    sync.add_argument("--token", help="Peer's MORY_SYNC_TOKEN (default: local setting)")
+
+    subparsers.add_parser(
+        "git-snapshot", help="Commit all memories to the MORY_GIT_DIR repository"
+    )
+
+    files_sync = subparsers.add_parser(
+        "files-sync", help="Load edits made to markdown memory files into the database"
+    )
+    files_sync.add_argument(
+        "--export", action="store_true", help="Rewrite every memory file from the database"
+    )
+
+    subparsers.add_parser(
+        "compact", help="Shrink the database file (WAL checkpoint, VACUUM, ANALYZE)"
+    )
+
+    subparsers.add_parser(
+        "detect-languages", help="Detect the language of memories saved by older versions"
+    )
+
+    export_audit = subparsers.add_parser(
+        "export-audit", help="Export the hash-chained operation log (JSON lines or CSV)"
+    )
+    export_audit.add_argument(
+        "--since", type=datetime.fromisoformat, help="Only operations at or after (UTC)"
+    )
+    export_audit.add_argument(
+        "--until", type=datetime.fromisoformat, help="Only operations at or before (UTC)"
+    )
+    export_audit.add_argument("--format", choices=["jsonl", "csv"], default="jsonl")
+    export_audit.add_argument("-o", "--output", help="Write to a file instead of stdout")
+
+    verify_audit = subparsers.add_parser(
+        "verify-audit", help="Check that operations were not altered or removed"
+    )
+    verify_audit.add_argument(
+        "path", nargs="?", help="Audit export to check (default: the operation log itself)"
+    )
+
+    snapshot = subparsers.add_parser(
+        "snapshot", help="Save the database and data files to one tar.gz for disaster recovery"
+    )
+    snapshot.add_argument(
+        "-o", "--output", help="Archive path (default: <data dir>/snapshots/mory_snapshot_*.tar.gz)"
+    )
+
+    restore = subparsers.add_parser(
+        "restore", help="Restore a snapshot into an empty data directory"
+    )
+    restore.add_argument("archive", help="Snapshot tar.gz written by mory-cli snapshot")
+    restore.add_argument("--data-dir", help="Directory to restore into (default: MORY_DATA_DIR)")
+
+    report = subparsers.add_parser(
+        "report", help="Markdown digest: growth, top categories and tags, largest, highlights"
+    )
+    report.add_argument("--days", type=int, default=30, help="Recent period (default: 30)")
+    report.add_argument("-o", "--output", help="Write to a file instead of stdout")
+
+    subparsers.add_parser("tui", help="Browse, search and edit memories interactively")
+
+    web = subparsers.add_parser("web", help="Serve the web dashboard")
+    web.add_argument(
+        "--listen", default=":7777", help="Address to listen on (default: %(default)s)"
+    )
+
+    return parser
+
+
+def main(
+    argv: list[str] | None = None, session_factory: Callable[[], Session] | None = None
+) -> int:
+    """Command line entry point for mory-cli"""
+    args = build_parser().parse_args(argv)
+    if args.command == "restore":
+        # Before opening the database, which would create it in the directory being restored
+        return cmd_restore(args)
+    register_subscribers()
+
+    if session_factory is None:
+        from .core.database import SessionLocal, create_tables
+
+        create_tables()
+        session_factory = SessionLocal
+
+    db = session_factory()
+    try:
+        return COMMANDS[args.command](db, args)
+    finally:
+        # Searches made by search and tui are counted in memory until flushed
+        search_counter.flush(db)
+        db.close()
+
+
+if __name__ == "__main__":
+    sys.exit(main())
@@ -0,0 +1,130 @@
+"""Python client for the Mory REST API (/v1)
+For scripts and other tools that want memories without going through MCP:
+
+    from app.client import MoryClient
+
+    with MoryClient("http://localhost:8080", namespace="blog") as mory:
+        for result in mory.search("idea", tags=["blog-idea"]).results:
+            print(result.memory.id, result.memory.value)
+
+Responses are the server's own pydantic models, so fields match the API exactly.
+"""
+
+from typing import Any
+
+import httpx
+
+from .models.schemas import MemoryListResponse, MemoryResponse, SearchRequest, SearchResponse
+
+
+class MoryAPIError(Exception):
+    """The server answered with an error status"""
+
+    def __init__(self, status_code: int, detail: Any):
+        super().__init__(f"HTTP {status_code}: {detail}")
+        self.status_code = status_code
+        self.detail = detail
+
+
+class MemoryNotFoundError(MoryAPIError):
+    """No memory with that ID in the client's namespace"""
+
+
+class MoryClient:
+    """Thin wrapper over /v1 with typed responses"""
+
+    def __init__(
+        self,
+        base_url: str = "http://localhost:8080",
+        namespace: str | None = None,
+        agent_id: str | None = None,
+        timeout: float = 30.0,
+        http_client: httpx.Client | None = None,
+    ):
+        """
+        Args:
+            base_url: Where the Mory server listens
+            namespace: Namespace (profile) to read and write (server default if None)
+            agent_id: Recorded as the owner of saved memories
+            timeout: Seconds per request
+            http_client: Client to send requests with instead of a new one (e.g. in tests)
+
+        """
+        self.headers: dict[str, str] = {}
+        if namespace:
+            self.headers["X-Mory-Namespace"] = namespace
+        if agent_id:
+            self.headers["X-Mory-Agent"] = agent_id
+        self._owns_client = http_client is None
+        self._http = http_client or httpx.Client(base_url=base_url, timeout=timeout)
+
+    def __enter__(self) -> "MoryClient":
+        return self
+
+    def __exit__(self, *exc_info: Any) -> None:
+        self.close()
+
+    def close(self) -> None:
+        if self._owns_client:
+            self._http.close()
+
+    def _request(self, method: str, path: str, **kwargs: Any) -> Any:
+        response = self._http.request(method, f"/v1{path}", headers=self.headers, **kwargs)
+        if response.status_code >= 400:
+            try:
+                detail = response.json().get("detail", response.text)
+            except ValueError:
+                detail = response.text
+            error = MemoryNotFoundError if response.status_code == 404 else MoryAPIError
+            raise error(response.status_code, detail)
+        return response.json()
+
+    def save(self, value: str, **fields: Any) -> MemoryResponse:
+        """Save a memory; fields are the optional MemoryCreate fields (metadata, remind_at, ...)"""
+        data = self._request("POST", "/memories", json={"value": value, **fields})
+        return MemoryResponse.model_validate(data)
+
+    def get(self, memory_id: str) -> MemoryResponse:
+        return MemoryResponse.model_validate(self._request("GET", f"/memories/{memory_id}"))
+
+    def list(
+        self,
+        limit: int = 100,
+        offset: int = 0,
+        owner: str | None = None,
+        include_pending: bool = False,
+        include_archived: bool = False,
+    ) -> MemoryListResponse:
+        """One page of memories, newest first"""
+        params: dict[str, Any] = {
+            "limit": limit,
+            "offset": offset,
+            "include_full_text": True,
+            "include_pending": include_pending,
+            "include_archived": include_archived,
+        }
+        if owner:
+            params["owner"] = owner
+        return MemoryListResponse.model_validate(self._request("GET", "/memories", params=params))
+
+    def update(self, memory_id: str, **fields: Any) -> MemoryResponse:
+        """Change the given MemoryUpdate fields (value, metadata, confidence, ...)"""
+        data = self._request("PUT", f"/memories/{memory_id}", json=fields)
+        return MemoryResponse.model_validate(data)
+
+    def delete(self, memory_id: str) -> None:
+        self._request("DELETE", f"/memories/{memory_id}")
+
+    def archive(self, memory_id: str) -> MemoryResponse:
+        data = self._request("POST", f"/memories/{memory_id}/archive")
+        return MemoryResponse.model_validate(data)
+
+    def unarchive(self, memory_id: str) -> MemoryResponse:
+        data = self._request("POST", f"/memories/{memory_id}/unarchive")
+        return MemoryResponse.model_validate(data)
+
+    def search(self, query: str, **options: Any) -> SearchResponse:
+        """Search memories; options are SearchRequest fields (search_type, limit, tags, ...)"""
+        request = SearchRequest(query=query, **options)
+        body = request.model_dump(mode="json", exclude_unset=True)
+        return SearchResponse.model_validate(self._request("POST", "/search", json=body))
@@ -0,0 +1,54 @@
+"""Size budget for MCP tool responses
+Keeps one list or search call from filling the model's context: long text fields are
+shortened first, then trailing items are dropped with a hint to refine the query.
+"""
+
+import json
+from typing import Any
+
+from .config import settings
+from .i18n import translate
+
+# Text fields are never cut shorter than this, whatever the budget
+MIN_FIELD_CHARS = 200
+TRUNCATION_MARK = "…"
+
+
+def _size(result: dict[str, Any]) -> int:
+    # Measured the way tool handlers serialize their output
+    return len(json.dumps(result, indent=2))
+
+
+def _shorten(value: Any, limit: int) -> Any:
+    if isinstance(value, str) and len(value) > limit:
+        return value[:limit] + TRUNCATION_MARK
+    if isinstance(value, dict):
+        return {key: _shorten(item, limit) for key, item in value.items()}
+    if isinstance(value, list):
+        return [_shorten(item, limit) for item in value]
+    return value
+
+
+def fit_to_budget(
+    result: dict[str, Any], items_key: str, budget: int | None = None
+) -> dict[str, Any]:
+    """Shrink a list/search response to about budget characters of JSON
+
+    Adds a "hint" saying how many results were left out. A budget of 0 disables this.
+    """
+    budget = settings.response_budget if budget is None else budget
+    items = result.get(items_key)
+    if budget <= 0 or not isinstance(items, list) or _size(result) <= budget:
+        return result
+
+    # Share the budget between items so one long memory cannot crowd out the rest
+    limit = max(MIN_FIELD_CHARS, budget // (2 * len(items)))
+    shown = [_shorten(item, limit) for item in items]
+    fitted = {**result, items_key: shown}
+    while len(shown) > 1 and _size(fitted) > budget:
+        shown.pop()
+
+    remaining = max(result.get("total") or 0, len(items)) - len(shown)
+    if remaining > 0:
+        fitted["hint"] = translate("more_results", count=remaining)
+    return fitted
@@ -0,0 +1,87 @@
+"""Claude Desktop integration helpers
+Locates claude_desktop_config.json and registers the Mory MCP server in it
+"""
+
+import json
+import os
+import platform
+import shutil
+import sys
+from datetime import datetime
+from pathlib import Path
+
+from .fileutil import atomic_write_text
+
+# Project root (contains mcp_main.py)
+PROJECT_ROOT = Path(__file__).resolve().parent.parent.parent
+
+CONFIG_FILENAME = "claude_desktop_config.json"
+
+
+def claude_desktop_server_entry(port: int = 8080) -> dict:
+    """Build the mcpServers entry that launches the Mory MCP bridge"""
+    return {
+        "command": sys.executable,
+        "args": [str(PROJECT_ROOT / "mcp_main.py")],
+        "env": {"MORY_API_URL": f"http://localhost:{port}"},
+    }
+
+
+def claude_desktop_snippet(port: int = 8080) -> str:
+    """Render the Claude Desktop configuration snippet to paste"""
+    return json.dumps({"mcpServers": {"mory": claude_desktop_server_entry(port)}}, indent=2)
+
+
+def default_config_path(system: str | None = None) -> Path:
+    """Return the Claude Desktop config file location for the current OS"""
+    system = system or platform.system()
+    home = Path.home()
+
+    if system == "Darwin":
+        return home / "Library" / "Application Support" / "Claude" / CONFIG_FILENAME
+    if system == "Windows":
+        appdata = os.environ.get("APPDATA") or str(home / "AppData" / "Roaming")
+        return Path(appdata) / "Claude" / CONFIG_FILENAME
+    # Linux and other Unix-likes follow XDG
+    config_home = os.environ.get("XDG_CONFIG_HOME") or str(home / ".config")
+    return Path(config_home) / "Claude" / CONFIG_FILENAME
+
+
+def install_server(
+    config_path: Path | None = None, port: int = 8080, server_name: str = "mory"
+) -> tuple[Path, Path | None]:
+    """Insert or update the Mory entry in the Claude Desktop config
+
+    Args:
+        config_path: Config file to edit (defaults to the per-OS location)
+        port: Port the Mory API server listens on
+        server_name: Key under mcpServers
+
+    Returns:
+        Tuple of (config path, backup path or None if the file did not exist)
+
+    Raises:
+        ValueError: If the existing config file is not valid JSON
+
+    """
+    path = config_path or default_config_path()
+    config: dict = {}
+    backup_path = None
+
+    if path.exists():
+        try:
+            config = json.loads(path.read_text(encoding="utf-8") or "{}")
+        except json.JSONDecodeError as e:
+            raise ValueError(f"{path} is not valid JSON ({e}); fix it before installing") from e
+        if not isinstance(config, dict):
+            raise ValueError(f"{path} must contain a JSON object")
+
+        timestamp = datetime.now().strftime("%Y%m%d_%H%M%S")
+        backup_path = path.with_name(f"{path.name}.backup_{timestamp}")
+        shutil.copy2(path, backup_path)
+
+    servers = config.setdefault("mcpServers", {})
+    servers[server_name] = claude_desktop_server_entry(port)
+
+    atomic_write_text(path, json.dumps(config, indent=2, ensure_ascii=False) + "\n")
+    return path, backup_path
@@ -2,11 +2,16 @@
 Supports environment variables and .env files
 """
 
+import asyncio
+import logging
 from pathlib import Path
+from typing import Any
 
 from pydantic import Field
 from pydantic_settings import BaseSettings
 
+logger = logging.getLogger(__name__)
+
 
 class Settings(BaseSettings):
     """Application settings with environment variable support"""
@@ -16,9 +21,81 @@ class Settings(BaseSettings):
     port: int = Field(default=8080, alias="MORY_PORT")
     debug: bool = Field(default=False, alias="MORY_DEBUG")
 
+    # Logging configuration
+    log_level: str = Field(default="INFO", alias="MORY_LOG_LEVEL")
+    log_format: str = Field(default="text", alias="MORY_LOG_FORMAT")  # text or json
+    log_file: str | None = Field(default=None, alias="MORY_LOG_FILE")
+
+    # Webhooks: JSON list of URLs notified on save/update/delete, HMAC-signed with the secret
+    webhook_urls: list[str] = Field(default_factory=list, alias="MORY_WEBHOOK_URLS")
+    webhook_secret: str | None = Field(default=None, alias="MORY_WEBHOOK_SECRET")
+    webhook_max_retries: int = Field(default=3, alias="MORY_WEBHOOK_MAX_RETRIES")
+    webhook_retry_backoff: float = Field(default=1.0, alias="MORY_WEBHOOK_RETRY_BACKOFF")
+    webhook_timeout: float = Field(default=5.0, alias="MORY_WEBHOOK_TIMEOUT")
+
+    # save_url: also fetch pages on loopback, private and link-local addresses (intranet pages)
+    web_clip_allow_private: bool = Field(default=False, alias="MORY_WEB_CLIP_ALLOW_PRIVATE")
+
+    # Optional gRPC listener (0 disables; requires the grpc extra)
+    grpc_port: int = Field(default=0, alias="MORY_GRPC_PORT")
+
+    # Web dashboard (/dashboard)
+    dashboard_enabled: bool = Field(default=True, alias="MORY_DASHBOARD_ENABLED")
+
+    # Metrics endpoints (/metrics and /api/metrics)
+    metrics_enabled: bool = Field(default=True, alias="MORY_METRICS_ENABLED")
+
+    # MCP server configuration
+    mcp_server_name: str = Field(default="mory", alias="MORY_MCP_SERVER_NAME")
+
+    # Default memory namespace (profile) when a request does not specify one
+    namespace: str = Field(default="default", alias="MORY_NAMESPACE")
+
+    # Agent ID recorded as the owner of saved memories (shared database setups)
+    agent_id: str | None = Field(default=None, alias="MORY_AGENT_ID")
+
+    # Write protection: saves/updates per minute per agent and max value length (0 disables)
+    write_rate_limit_per_minute: int = Field(default=60, alias="MORY_WRITE_RATE_LIMIT")
+    max_value_length: int = Field(default=20000, alias="MORY_MAX_VALUE_LENGTH")
+    oversize_policy: str = Field(default="reject", alias="MORY_OVERSIZE_POLICY")  # or truncate
+
+    # Stage memories saved through MCP tools as "pending" until a human approves them
+    require_approval: bool = Field(default=False, alias="MORY_REQUIRE_APPROVAL")
+    # Let MCP tools approve pending memories (only for clients that confirm each tool call)
+    tool_approval: bool = Field(default=False, alias="MORY_TOOL_APPROVAL")
+    # Otherwise approving over the REST API needs this in X-Mory-Approval-Token
+    approval_token: str | None = Field(default=None, alias="MORY_APPROVAL_TOKEN")
+
+    # Redaction of sensitive content on save (credit cards, API keys, ...)
+    redaction_enabled: bool = Field(default=False, alias="MORY_REDACTION_ENABLED")
+    redaction_mode: str = Field(default="mask", alias="MORY_REDACTION_MODE")  # mask or reject
+    # Extra rules as JSON: {"rule_name": "regex", ...}
+    redaction_rules: dict[str, str] = Field(default_factory=dict, alias="MORY_REDACTION_RULES")
+
     # Database configuration
     data_dir: str = Field(default="data", alias="MORY_DATA_DIR")
     database_url: str = Field(default="", alias="MORY_DATABASE_URL")
+    allow_multiple_instances: bool = Field(default=False, alias="MORY_ALLOW_MULTIPLE_INSTANCES")
+    # Concurrency: seconds SQLite waits on a locked database, connection pool limits and
+    # how often a unit of work is retried (with jittered backoff) when still locked
+    db_busy_timeout: float = Field(default=20.0, alias="MORY_DB_BUSY_TIMEOUT")
+    db_pool_size: int = Field(default=5, alias="MORY_DB_POOL_SIZE")
+    db_max_overflow: int = Field(default=10, alias="MORY_DB_MAX_OVERFLOW")
+    db_busy_retries: int = Field(default=3, alias="MORY_DB_BUSY_RETRIES")
+
+    # Storage backend: "sqlite", or "files" to also keep each memory as an editable
+    # markdown file under MORY_FILES_DIR (default: <data_dir>/memories)
+    storage_backend: str = Field(default="sqlite", alias="MORY_STORAGE_BACKEND")
+    files_dir: str | None = Field(default=None, alias="MORY_FILES_DIR")
+    # Folders whose hand-written notes are never loaded (Obsidian/Templater templates)
+    files_ignore_dirs: list[str] = Field(
+        default_factory=lambda: ["templates", "_templates"], alias="MORY_FILES_IGNORE_DIRS"
+    )
+
+    # Git versioning: commit each change to memories/<id>.json in this repository
+    git_dir: str | None = Field(default=None, alias="MORY_GIT_DIR")
+    git_push: bool = Field(default=False, alias="MORY_GIT_PUSH")
+    git_remote: str = Field(default="origin", alias="MORY_GIT_REMOTE")
 
     # OpenAI configuration (for semantic search)
     openai_api_key: str | None = Field(default=None, alias="OPENAI_API_KEY")
@@ -35,7 +112,91 @@ class Settings(BaseSettings):
 
     # Search configuration
     semantic_search_enabled: bool = Field(default=True, alias="MORY_SEMANTIC_SEARCH_ENABLED")
+    # FTS5 full-text index (off by default: its tokenizer does not segment Japanese)
+    fts5_enabled: bool = Field(default=False, alias="MORY_FTS5_ENABLED")
+    # Column weights for bm25() and LIKE fallback ranking; unlisted columns weigh 1.0
+    fts_weights: dict[str, float] = Field(
+        default_factory=lambda: {"tags": 3.0, "summary": 2.0, "value": 1.0},
+        alias="MORY_FTS_WEIGHTS",
+    )
+    # Strip English suffixes from query words so "networking" also finds "networks"
+    search_stemming: bool = Field(default=False, alias="MORY_SEARCH_STEMMING")
+    # Query words ignored by keyword and FTS5 search, e.g. ["the", "a", "について"]
+    search_stopwords: list[str] = Field(default_factory=list, alias="MORY_SEARCH_STOPWORDS")
+    # Words searched as one another, e.g. {"k8s": "kubernetes", "js": ["javascript"]}
+    search_synonyms: dict[str, str | list[str]] = Field(
+        default_factory=dict, alias="MORY_SEARCH_SYNONYMS"
+    )
     hybrid_search_weight: float = Field(default=0.7, alias="MORY_HYBRID_SEARCH_WEIGHT")
+    # Per-query score normalization before hybrid combination: minmax, zscore or none
+    score_normalization: str = Field(default="minmax", alias="MORY_SCORE_NORMALIZATION")
+    semantic_similarity_threshold: float = Field(default=0.1, alias="MORY_SEMANTIC_THRESHOLD")
+    # Longer texts are embedded in chunks of at most this many characters (kept well under
+    # the embedding model's token limit, which CJK text reaches at fewer characters)
+    embedding_chunk_size: int = Field(default=4000, alias="MORY_EMBEDDING_CHUNK_SIZE")
+    max_search_results: int = Field(default=100, alias="MORY_MAX_SEARCH_RESULTS")
+    # Tag memories saved without tags: tags of similar memories, else extracted keywords
+    auto_tag: bool = Field(default=False, alias="MORY_AUTO_TAG")
+    # Log search queries, hit counts and reported choices for search analytics
+    query_log: bool = Field(default=False, alias="MORY_QUERY_LOG")
+
+    # Approximate size limit (characters of JSON) of list/search tool output, 0 disables
+    response_budget: int = Field(default=20000, alias="MORY_RESPONSE_BUDGET")
+    # Minutes a working-memory note lives within its MCP session, 0 keeps it until the end
+    working_memory_ttl: int = Field(default=0, alias="MORY_WORKING_MEMORY_TTL")
+
+    # Language of MCP tool messages: en or ja
+    locale: str = Field(default="en", alias="MORY_LOCALE")
+
+    # Seconds to wait for in-flight requests when shutting down
+    shutdown_timeout: float = Field(default=10.0, alias="MORY_SHUTDOWN_TIMEOUT")
+
+    # Extra memory templates (see app/services/templates.py), name -> definition
+    memory_templates: dict[str, dict[str, Any]] = Field(
+        default_factory=dict, alias="MORY_MEMORY_TEMPLATES"
+    )
+
+    # Scheduled jobs: name -> interval, e.g. {"backup": "24h", "weekly_review": "7d"}
+    jobs: dict[str, str] = Field(default_factory=dict, alias="MORY_JOBS")
+    backup_keep: int = Field(default=7, alias="MORY_BACKUP_KEEP")
+    pending_retention_days: int = Field(default=30, alias="MORY_PENDING_RETENTION_DAYS")
+    # Off-site copies of each backup, e.g. [{"type": "s3", "bucket": "...", ...}]
+    backup_targets: list[dict[str, Any]] = Field(
+        default_factory=list, alias="MORY_BACKUP_TARGETS"
+    )
+    backup_encryption_key: str | None = Field(default=None, alias="MORY_BACKUP_ENCRYPTION_KEY")
+
+    # Sync with other Mory instances (mory-cli sync, or the "sync" job for MORY_SYNC_PEERS)
+    sync_peers: list[str] = Field(default_factory=list, alias="MORY_SYNC_PEERS")
+    sync_token: str | None = Field(default=None, alias="MORY_SYNC_TOKEN")
+
+    # Notion import (mory-cli notion-sync, or the "notion_sync" job): integration token,
+    # database to import and which of its properties hold the category, tags and key
+    notion_token: str | None = Field(default=None, alias="MORY_NOTION_TOKEN")
+    notion_database_id: str | None = Field(default=None, alias="MORY_NOTION_DATABASE_ID")
+    notion_properties: dict[str, str] = Field(
+        default_factory=lambda: {"category": "Category", "tags": "Tags"},
+        alias="MORY_NOTION_PROPERTIES",
+    )
+
+    # Email ingestion (mory-cli mail-sync, or the "mail_sync" job): IMAP account, the
+    # folder/label to read and an IMAP search filter such as FLAGGED (starred)
+    imap_host: str | None = Field(default=None, alias="MORY_IMAP_HOST")
+    imap_port: int = Field(default=993, alias="MORY_IMAP_PORT")
+    imap_username: str | None = Field(default=None, alias="MORY_IMAP_USERNAME")
+    imap_password: str | None = Field(default=None, alias="MORY_IMAP_PASSWORD")
+    imap_folder: str = Field(default="Mory", alias="MORY_IMAP_FOLDER")
+    imap_search: str = Field(default="ALL", alias="MORY_IMAP_SEARCH")
+
+    # Auto-archive: memories untouched for this many days (0 disables), optionally only
+    # those read at most MORY_ARCHIVE_MAX_ACCESS_COUNT times; runs as the auto_archive job
+    archive_after_days: int = Field(default=0, alias="MORY_ARCHIVE_AFTER_DAYS")
+    archive_max_access_count: int | None = Field(
+        default=None, alias="MORY_ARCHIVE_MAX_ACCESS_COUNT"
+    )
+
+    # Hot reload of .env (seconds between checks, 0 disables)
+    config_reload_interval: float = Field(default=2.0, alias="MORY_CONFIG_RELOAD_INTERVAL")
 
     model_config = {
         "env_file": ".env",
@@ -65,3 +226,91 @@ class Settings(BaseSettings):
 
 # Global settings instance
 settings = Settings()
+
+# Settings that can be changed at runtime by editing .env
+HOT_RELOAD_FIELDS = (
+    "hybrid_search_weight",
+    "semantic_similarity_threshold",
+    "max_search_results",
+    "query_log",
+    "auto_tag",
+    "fts_weights",
+    "search_stemming",
+    "search_stopwords",
+    "search_synonyms",
+    "score_normalization",
+    "obsidian_vault_path",
+    "files_ignore_dirs",
+    "locale",
+    "response_budget",
+)
+
+
+def reload_settings() -> dict[str, Any]:
+    """Re-read environment and .env, applying hot-reloadable settings in place
+
+    Returns:
+        Mapping of changed setting names to their new values
+
+    """
+    fresh = Settings()
+    changes = {
+        name: getattr(fresh, name)
+        for name in HOT_RELOAD_FIELDS
+        if getattr(fresh, name) != getattr(settings, name)
+    }
+    if changes:
+        # Single dict update so readers never observe a partially applied config
+        settings.__dict__.update(changes)
+    return changes
+
+
+class ConfigWatcher:
+    """Poll the .env file and hot-reload tunable settings when it changes"""
+
+    def __init__(self, path: str | Path = ".env", interval: float = 2.0):
+        self.path = Path(path)
+        self.interval = interval
+        self._mtime = self._current_mtime()
+        self._task: asyncio.Task | None = None
+
+    def _current_mtime(self) -> float | None:
+        try:
+            return self.path.stat().st_mtime
+        except OSError:
+            return None
+
+    def check(self) -> dict[str, Any]:
+        """Reload settings if the watched file changed since the last check"""
+        mtime = self._current_mtime()
+        if mtime == self._mtime:
+            return {}
+        self._mtime = mtime
+        return reload_settings()
+
+    async def _run(self) -> None:
+        while True:
+            await asyncio.sleep(self.interval)
+            try:
+                changes = self.check()
+            except Exception as e:
+                # An invalid .env must not take the running server down
+                logger.warning(f"Config reload failed, keeping previous settings: {e}")
+                continue
+            if changes:
+                logger.info(f"Config reloaded: {', '.join(sorted(changes))}")
+
+    def start(self) -> None:
+        """Start polling in the background"""
+        if self.interval > 0 and self._task is None:
+            self._task = asyncio.create_task(self._run())
+
+    async def stop(self) -> None:
+        """Stop polling"""
+        if self._task is not None:
+            self._task.cancel()
+            try:
+                await self._task
+            except asyncio.CancelledError:
+                pass
+            self._task = None
@@ -0,0 +1,77 @@
+"""Two-phase confirmation for destructive bulk operations
+A preview issues a single-use token bound to the operation, its parameters and the
+memories it would touch; the operation only runs when called again with that token,
+and refuses if the matching memories changed in between.
+"""
+
+import hashlib
+import json
+import secrets
+import threading
+import time
+from dataclasses import dataclass, field
+from typing import Any
+
+# Seconds a preview's confirmation token stays valid
+CONFIRMATION_TTL = 600
+
+
+class ConfirmationError(Exception):
+    """The confirmation token is unknown, expired, or for a different operation or scope"""
+
+
+@dataclass
+class PendingConfirmation:
+    operation: str
+    fingerprint: str
+    expires_at: float
+    data: dict[str, Any] = field(default_factory=dict)  # What the preview computed
+
+
+def fingerprint(scope: Any) -> str:
+    """Stable digest of an operation's parameters and affected memory IDs"""
+    payload = json.dumps(scope, sort_keys=True, ensure_ascii=False, default=str)
+    return hashlib.sha256(payload.encode("utf-8")).hexdigest()
+
+
+class ConfirmationStore:
+    """Tokens issued by previews, kept in memory until used or expired"""
+
+    def __init__(self, ttl: float = CONFIRMATION_TTL):
+        self.ttl = ttl
+        self._pending: dict[str, PendingConfirmation] = {}
+        self._lock = threading.Lock()
+
+    def issue(self, operation: str, scope: Any, data: dict[str, Any] | None = None) -> str:
+        """Token confirming `operation` on exactly this scope"""
+        token = secrets.token_urlsafe(16)
+        with self._lock:
+            self._expire()
+            self._pending[token] = PendingConfirmation(
+                operation, fingerprint(scope), time.monotonic() + self.ttl, data or {}
+            )
+        return token
+
+    def redeem(self, token: str, operation: str, scope: Any) -> dict[str, Any]:
+        """Use up a token and return the preview's data, or raise ConfirmationError"""
+        with self._lock:
+            self._expire()
+            pending = self._pending.pop(token, None)
+        if pending is None or pending.operation != operation:
+            raise ConfirmationError(
+                "Unknown or expired confirmation token; run the preview (dry_run) again"
+            )
+        if pending.fingerprint != fingerprint(scope):
+            raise ConfirmationError(
+                "The matching memories changed since the preview; run the preview again"
+            )
+        return pending.data
+
+    def _expire(self) -> None:
+        now = time.monotonic()
+        for token in [t for t, p in self._pending.items() if p.expires_at <= now]:
+            del self._pending[token]
+
+
+# Shared by the API handlers of all bulk operations
+confirmations = ConfirmationStore()
@@ -2,20 +2,55 @@
 SQLite with SQLAlchemy for Mory Server
 """
 
-from sqlalchemy import create_engine, event, text
+import asyncio
+import functools
+import inspect as pyinspect
+import logging
+import os
+import random
+import time
+from collections.abc import Callable, Iterator
+from contextlib import contextmanager
+from typing import Any, TypeVar
+
+from sqlalchemy import create_engine, event, inspect, text
+from sqlalchemy.engine import Engine
+from sqlalchemy.exc import OperationalError
 from sqlalchemy.ext.declarative import declarative_base
-from sqlalchemy.orm import sessionmaker
+from sqlalchemy.orm import Session, sessionmaker
 from sqlalchemy.pool import StaticPool
 
 from .config import settings
 
+logger = logging.getLogger(__name__)
+
+# First retry waits about this long; each further attempt doubles it (plus jitter)
+BUSY_RETRY_BASE_DELAY = 0.05
+
+F = TypeVar("F", bound=Callable[..., Any])
+
+
+def build_engine(url: str) -> Engine:
+    """Engine with a connection per thread for files, one shared connection for :memory:"""
+    connect_args = {"check_same_thread": False, "timeout": settings.db_busy_timeout}
+    if url.endswith(":memory:"):
+        return create_engine(
+            url, poolclass=StaticPool, connect_args=connect_args, echo=settings.debug
+        )
+    # Concurrent requests sharing one connection interleave their transactions, so file
+    # databases get a pool and rely on WAL + busy_timeout to serialise writers
+    return create_engine(
+        url,
+        pool_size=settings.db_pool_size,
+        max_overflow=settings.db_max_overflow,
+        pool_pre_ping=True,
+        connect_args=connect_args,
+        echo=settings.debug,
+    )
+
+
 # SQLAlchemy setup
-engine = create_engine(
-    settings.sqlite_url,
-    poolclass=StaticPool,
-    connect_args={"check_same_thread": False, "timeout": 20},
-    echo=settings.debug,
-)
+engine = build_engine(settings.sqlite_url)
 
 
 # Enable SQLite optimizations and FTS5
@@ -34,6 +69,9 @@ def set_sqlite_pragma(dbapi_connection, connection_record):
     # Enable foreign key constraints
     cursor.execute("PRAGMA foreign_keys=ON")
 
+    # Wait for competing writers instead of failing with "database is locked"
+    cursor.execute(f"PRAGMA busy_timeout={int(settings.db_busy_timeout * 1000)}")
+
     cursor.close()
 
 
@@ -44,6 +82,53 @@ SessionLocal = sessionmaker(autocommit=False, autoflush=False, bind=engine)
 Base = declarative_base()
 
 
+def is_busy_error(error: Exception) -> bool:
+    """SQLITE_BUSY / SQLITE_LOCKED surfaced through SQLAlchemy"""
+    message = str(getattr(error, "orig", error)).lower()
+    return isinstance(error, OperationalError) and (
+        "database is locked" in message or "database is busy" in message
+    )
+
+
+def _busy_delay(attempt: int) -> float:
+    return BUSY_RETRY_BASE_DELAY * (2**attempt) * (1 + random.random())
+
+
+def retry_on_busy(func: F) -> F:
+    """Re-run a whole unit of work when SQLite stays locked past busy_timeout
+
+    The wrapped function must open (or roll back) its own session state, since a
+    failed flush discards the transaction. Works for sync and async functions.
+    """
+    if pyinspect.iscoroutinefunction(func):
+
+        @functools.wraps(func)
+        async def async_wrapper(*args, **kwargs):
+            for attempt in range(settings.db_busy_retries + 1):
+                try:
+                    return await func(*args, **kwargs)
+                except OperationalError as e:
+                    if not is_busy_error(e) or attempt == settings.db_busy_retries:
+                        raise
+                    logger.warning(f"{func.__qualname__}: database busy, retrying ({e.orig})")
+                    await asyncio.sleep(_busy_delay(attempt))
+
+        return async_wrapper  # type: ignore[return-value]
+
+    @functools.wraps(func)
+    def wrapper(*args, **kwargs):
+        for attempt in range(settings.db_busy_retries + 1):
+            try:
+                return func(*args, **kwargs)
+            except OperationalError as e:
+                if not is_busy_error(e) or attempt == settings.db_busy_retries:
+                    raise
+                logger.warning(f"{func.__qualname__}: database busy, retrying ({e.orig})")
+                time.sleep(_busy_delay(attempt))
+
+    return wrapper  # type: ignore[return-value]
+
+
 def get_db():
     """Database dependency for FastAPI"""
     db = SessionLocal()
@@ -58,19 +143,103 @@ def create_tables(engine_override=None):
     db_engine = engine_override if engine_override else engine
     Base.metadata.create_all(bind=db_engine)
 
+    # Bring databases created by older versions up to the current schema
+    added_columns = ensure_columns(db_engine)
+    if added_columns:
+        logger.info(f"Added columns to existing database: {', '.join(added_columns)}")
+
     # Initialize FTS5 search functionality if available
     if check_fts5_support(db_engine):
         create_fts5_table(db_engine)
-        print("✅ FTS5 search enabled")
+        logger.info("FTS5 search enabled")
     else:
-        print("⚠️  FTS5 not available, falling back to LIKE search")
+        logger.warning("FTS5 not available, falling back to LIKE search")
+
+
+def ensure_columns(engine_override=None) -> list[str]:
+    """Add model columns and indexes missing from existing tables
+
+    create_all() only creates missing tables, so databases created before a
+    column was introduced are upgraded with ALTER TABLE ADD COLUMN.
+    New columns must be nullable or carry a server_default.
+    """
+    db_engine = engine_override if engine_override else engine
+    inspector = inspect(db_engine)
+    added = []
+
+    with db_engine.begin() as conn:
+        for table in Base.metadata.sorted_tables:
+            if not inspector.has_table(table.name):
+                continue
+
+            existing = {column["name"] for column in inspector.get_columns(table.name)}
+            missing = [column for column in table.columns if column.name not in existing]
+            for column in missing:
+                column_type = column.type.compile(dialect=db_engine.dialect)
+                ddl = f"ALTER TABLE {table.name} ADD COLUMN {column.name} {column_type}"
+                if column.server_default is not None:
+                    default = column.server_default.arg
+                    default_sql = f"'{default}'" if isinstance(default, str) else str(default)
+                    ddl += f" DEFAULT {default_sql}"
+                conn.execute(text(ddl))
+                added.append(f"{table.name}.{column.name}")
+
+            for index in table.indexes:
+                index.create(bind=conn, checkfirst=True)
+
+    return added
+
+
+def checkpoint_and_close(engine_override=None) -> None:
+    """Checkpoint the WAL into the main database file and close all connections"""
+    db_engine = engine_override if engine_override else engine
+    try:
+        with db_engine.connect() as conn:
+            conn.execute(text("PRAGMA wal_checkpoint(TRUNCATE)"))
+    except Exception as e:
+        logger.warning(f"WAL checkpoint failed: {e}")
+    db_engine.dispose()
+
+
+def _database_files_size(db_engine) -> int:
+    """Bytes used by the database file plus its WAL and shared-memory files"""
+    database = db_engine.url.database
+    if not database or database == ":memory:":
+        return 0
+    return sum(
+        os.path.getsize(path)
+        for path in (database, f"{database}-wal", f"{database}-shm")
+        if os.path.exists(path)
+    )
+
+
+def compact_database(engine_override=None) -> dict[str, int]:
+    """Checkpoint the WAL, VACUUM and ANALYZE; returns sizes in bytes"""
+    db_engine = engine_override if engine_override else engine
+    size_before = _database_files_size(db_engine)
+
+    # VACUUM cannot run inside a transaction
+    with db_engine.connect().execution_options(isolation_level="AUTOCOMMIT") as conn:
+        conn.execute(text("PRAGMA wal_checkpoint(TRUNCATE)"))
+        conn.execute(text("VACUUM"))
+        conn.execute(text("ANALYZE"))
+        conn.execute(text("PRAGMA wal_checkpoint(TRUNCATE)"))
+
+    size_after = _database_files_size(db_engine)
+    logger.info(f"Database compacted: {size_before} -> {size_after} bytes")
+    return {
+        "size_before": size_before,
+        "size_after": size_after,
+        "reclaimed": max(size_before - size_after, 0),
+    }
 
 
 def check_fts5_support(engine_override=None) -> bool:
-    """Check if SQLite FTS5 extension is available"""
-    # Temporarily disable FTS5 to use optimized LIKE search
-    # TODO: Enable FTS5 when SQLite build supports it
-    return False
+    """Check if FTS5 search is enabled and the SQLite build provides it"""
+    # Off by default: the unicode61 tokenizer does not segment Japanese, where the
+    # LIKE search matches substrings
+    if not settings.fts5_enabled:
+        return False
 
     db_engine = engine_override if engine_override else engine
     try:
@@ -82,88 +251,130 @@ def check_fts5_support(engine_override=None) -> bool:
         return False
 
 
+# Indexed columns, in the order bm25() takes their weights
+FTS_COLUMNS = ("value", "summary", "tags")
+
+FTS_TRIGGERS = ("memories_fts_insert", "memories_fts_update", "memories_fts_delete")
+
+# Bulk loads of at least this many rows rebuild the FTS5 index once instead of per row
+BULK_FTS_THRESHOLD = 1000
+
+
 def create_fts5_table(engine_override=None):
-    """Create FTS5 virtual table for full-text search"""
+    """Create the FTS5 index over memories and the triggers keeping it in sync"""
     db_engine = engine_override if engine_override else engine
+    columns = ", ".join(FTS_COLUMNS)
     try:
-        with db_engine.connect() as conn:
-            # Create FTS5 virtual table with Japanese tokenizer support
+        with db_engine.begin() as conn:
+            existing = conn.execute(
+                text("SELECT sql FROM sqlite_master WHERE type = 'table' AND name = 'memories_fts'")
+            ).scalar()
+            # Tables from before the simplified schema indexed category/key columns
+            if existing and "content_rowid" not in existing:
+                for trigger in FTS_TRIGGERS:
+                    conn.execute(text(f"DROP TRIGGER IF EXISTS {trigger}"))
+                conn.execute(text("DROP TABLE memories_fts"))
+                existing = None
+
+            # External content table: rows are read from memories by rowid
             conn.execute(
-                text("""
+                text(f"""
                 CREATE VIRTUAL TABLE IF NOT EXISTS memories_fts USING fts5(
-                    id UNINDEXED,
-                    category,
-                    key,
-                    value,
-                    tags,
+                    {columns},
                     content='memories',
+                    content_rowid='rowid',
                     tokenize='unicode61 remove_diacritics 2'
                 )
             """)
             )
+            _create_fts_triggers(conn)
 
-            # Create triggers for automatic synchronization
-            conn.execute(
-                text("""
-                CREATE TRIGGER IF NOT EXISTS memories_fts_insert
-                AFTER INSERT ON memories
-                BEGIN
-                    INSERT INTO memories_fts(id, category, key, value, tags)
-                    VALUES (new.id, new.category, new.key, new.value, new.tags);
-                END
-            """)
-            )
+            if existing is None:
+                conn.execute(text("INSERT INTO memories_fts(memories_fts) VALUES ('rebuild')"))
+        return True
+    except Exception as e:
+        logger.error(f"Failed to create FTS5 table: {e}")
+        return False
 
-            conn.execute(
-                text("""
-                CREATE TRIGGER IF NOT EXISTS memories_fts_update
-                AFTER UPDATE ON memories
-                BEGIN
-                    UPDATE memories_fts
-                    SET category = new.category,
-                        key = new.key,
-                        value = new.value,
-                        tags = new.tags
-                    WHERE id = new.id;
-                END
-            """)
-            )
 
-            conn.execute(
-                text("""
-                CREATE TRIGGER IF NOT EXISTS memories_fts_delete
-                AFTER DELETE ON memories
-                BEGIN
-                    DELETE FROM memories_fts WHERE id = old.id;
-                END
-            """)
-            )
+def _create_fts_triggers(conn) -> None:
+    """Triggers mirroring inserts, updates and deletes on memories into memories_fts"""
+    columns = ", ".join(FTS_COLUMNS)
+    new_values = ", ".join(f"new.{column}" for column in FTS_COLUMNS)
+    old_values = ", ".join(f"old.{column}" for column in FTS_COLUMNS)
+    conn.execute(
+        text(f"""
+        CREATE TRIGGER IF NOT EXISTS memories_fts_insert
+        AFTER INSERT ON memories
+        BEGIN
+            INSERT INTO memories_fts(rowid, {columns}) VALUES (new.rowid, {new_values});
+        END
+    """)
+    )
 
-            conn.commit()
-            return True
-    except Exception as e:
-        print(f"Failed to create FTS5 table: {e}")
-        return False
+    conn.execute(
+        text(f"""
+        CREATE TRIGGER IF NOT EXISTS memories_fts_update
+        AFTER UPDATE ON memories
+        BEGIN
+            INSERT INTO memories_fts(memories_fts, rowid, {columns})
+            VALUES ('delete', old.rowid, {old_values});
+            INSERT INTO memories_fts(rowid, {columns}) VALUES (new.rowid, {new_values});
+        END
+    """)
+    )
+
+    conn.execute(
+        text(f"""
+        CREATE TRIGGER IF NOT EXISTS memories_fts_delete
+        AFTER DELETE ON memories
+        BEGIN
+            INSERT INTO memories_fts(memories_fts, rowid, {columns})
+            VALUES ('delete', old.rowid, {old_values});
+        END
+    """)
+    )
 
 
 def rebuild_fts5_index(engine_override=None):
     """Rebuild FTS5 index with all existing memories"""
     db_engine = engine_override if engine_override else engine
     try:
-        with db_engine.connect() as conn:
-            # Clear existing FTS5 data
-            conn.execute(text("DELETE FROM memories_fts"))
-
-            # Populate FTS5 table with existing data
-            conn.execute(
-                text("""
-                INSERT INTO memories_fts(id, category, key, value, tags)
-                SELECT id, category, key, value, tags FROM memories
-            """)
-            )
-
-            conn.commit()
-            return True
+        with db_engine.begin() as conn:
+            conn.execute(text("INSERT INTO memories_fts(memories_fts) VALUES ('rebuild')"))
+        return True
     except Exception as e:
-        print(f"Failed to rebuild FTS5 index: {e}")
+        logger.error(f"Failed to rebuild FTS5 index: {e}")
         return False
+
+
+@contextmanager
+def bulk_load(db: Session, rows: int) -> Iterator[None]:
+    """Write many rows in one transaction, committed on exit
+
+    Loads of BULK_FTS_THRESHOLD rows or more drop the FTS5 triggers and rebuild the index
+    once at the end instead of updating it per row. synchronous is left as is: under
+    WAL + NORMAL a single commit does not fsync anyway.
+    """
+    deferred = (
+        rows >= BULK_FTS_THRESHOLD
+        and db.execute(
+            text("SELECT 1 FROM sqlite_master WHERE type = 'table' AND name = 'memories_fts'")
+        ).first()
+        is not None
+    )
+    if deferred:
+        for trigger in FTS_TRIGGERS:
+            db.execute(text(f"DROP TRIGGER IF EXISTS {trigger}"))
+    try:
+        yield
+        db.flush()  # Insert the rows before the triggers come back
+    except BaseException:  # Also Ctrl-C: a cancelled load leaves nothing behind
+        db.rollback()
+        raise
+    finally:
+        # Also after a rollback: the driver may have committed the DROPs on their own
+        if deferred:
+            _create_fts_triggers(db)
+            db.execute(text("INSERT INTO memories_fts(memories_fts) VALUES ('rebuild')"))
+        db.commit()
@@ -0,0 +1,302 @@
+"""Configuration diagnostics for Mory Server
+Validates storage paths, database access and integrations before serving
+"""
+
+import shutil
+import sqlite3
+import tempfile
+from dataclasses import dataclass
+from pathlib import Path
+
+from ..services.backup_targets import validate_target
+from ..services.templates import template_problem
+from .config import Settings, settings
+from .i18n import DEFAULT_LOCALE, LOCALES
+from .scheduler import parse_interval
+
+
+@dataclass
+class CheckResult:
+    """Outcome of a single configuration check"""
+
+    name: str
+    ok: bool
+    message: str
+    severity: str = "error"  # error: blocks startup, warning: degraded feature
+
+    @property
+    def is_error(self) -> bool:
+        return not self.ok and self.severity == "error"
+
+
+def check_data_dir(config: Settings) -> CheckResult:
+    """Check that the data directory exists (or can be created) and is writable"""
+    data_path = Path(config.data_dir)
+    try:
+        data_path.mkdir(parents=True, exist_ok=True)
+        with tempfile.NamedTemporaryFile(dir=data_path, prefix=".mory_write_test"):
+            pass
+    except OSError as e:
+        return CheckResult(
+            "data_dir",
+            False,
+            f"Data directory '{data_path}' is not writable ({e}). "
+            "Set MORY_DATA_DIR to a writable directory.",
+        )
+    return CheckResult("data_dir", True, f"Data directory '{data_path.resolve()}' is writable")
+
+
+def check_database(config: Settings) -> CheckResult:
+    """Check that the SQLite database file can be opened"""
+    url = config.sqlite_url
+    if not url.startswith("sqlite:///"):
+        return CheckResult(
+            "database", True, f"Non-file database URL '{url}', skipped", severity="warning"
+        )
+
+    db_path = url.removeprefix("sqlite:///")
+    try:
+        conn = sqlite3.connect(db_path, timeout=5)
+        try:
+            conn.execute("SELECT 1")
+        finally:
+            conn.close()
+    except sqlite3.Error as e:
+        return CheckResult(
+            "database",
+            False,
+            f"Cannot open SQLite database '{db_path}' ({e}). "
+            "Check file permissions or set MORY_DATABASE_URL.",
+        )
+    return CheckResult("database", True, f"SQLite database '{db_path}' opens")
+
+
+def check_obsidian_vault(config: Settings) -> CheckResult:
+    """Check that the configured Obsidian vault exists"""
+    if not config.obsidian_vault_path:
+        return CheckResult("obsidian_vault", True, "Obsidian vault not configured")
+
+    vault = Path(config.obsidian_vault_path).expanduser()
+    if not vault.is_dir():
+        return CheckResult(
+            "obsidian_vault",
+            False,
+            f"Obsidian vault '{vault}' does not exist or is not a directory. "
+            "Fix MORY_OBSIDIAN_VAULT_PATH or remove it.",
+        )
+    return CheckResult("obsidian_vault", True, f"Obsidian vault '{vault}' found")
+
+
+def check_search_settings(config: Settings) -> CheckResult:
+    """Check that search tunables are in range"""
+    if not 0.0 <= config.hybrid_search_weight <= 1.0:
+        return CheckResult(
+            "search_settings",
+            False,
+            f"MORY_HYBRID_SEARCH_WEIGHT must be between 0.0 and 1.0 "
+            f"(got {config.hybrid_search_weight})",
+        )
+    if config.embedding_chunk_size < 1:
+        return CheckResult(
+            "search_settings",
+            False,
+            f"MORY_EMBEDDING_CHUNK_SIZE must be positive (got {config.embedding_chunk_size})",
+        )
+    from ..services.search import NORMALIZATION_METHODS
+    from .database import FTS_COLUMNS  # Importing database opens the engine
+
+    if config.score_normalization not in NORMALIZATION_METHODS:
+        return CheckResult(
+            "search_settings",
+            False,
+            f"MORY_SCORE_NORMALIZATION must be one of {', '.join(NORMALIZATION_METHODS)} "
+            f"(got '{config.score_normalization}')",
+        )
+
+    unknown = set(config.fts_weights) - set(FTS_COLUMNS)
+    if unknown:
+        return CheckResult(
+            "search_settings",
+            False,
+            f"MORY_FTS_WEIGHTS has unknown columns {sorted(unknown)} "
+            f"(expected: {', '.join(FTS_COLUMNS)})",
+        )
+    return CheckResult("search_settings", True, "Search settings are valid")
+
+
+def check_jobs(config: Settings) -> CheckResult:
+    """Check that scheduled job intervals parse"""
+    for name, interval in config.jobs.items():
+        try:
+            parse_interval(interval)
+        except ValueError as e:
+            return CheckResult("jobs", False, f"MORY_JOBS entry '{name}': {e}")
+    enabled = ", ".join(config.jobs) or "none"
+    return CheckResult("jobs", True, f"Scheduled jobs: {enabled}")
+
+
+def check_storage_backend(config: Settings) -> CheckResult:
+    """Check the storage backend name and that the files directory is writable"""
+    if config.storage_backend not in ("sqlite", "files"):
+        return CheckResult(
+            "storage_backend",
+            False,
+            f"MORY_STORAGE_BACKEND must be 'sqlite' or 'files' (got '{config.storage_backend}')",
+        )
+    if config.storage_backend == "sqlite":
+        return CheckResult("storage_backend", True, "Storing memories in SQLite")
+
+    files_dir = Path(config.files_dir or Path(config.data_dir) / "memories")
+    try:
+        files_dir.mkdir(parents=True, exist_ok=True)
+    except OSError as e:
+        return CheckResult(
+            "storage_backend", False, f"Memory files directory '{files_dir}' is unusable ({e})"
+        )
+    return CheckResult("storage_backend", True, f"Storing memories as markdown in {files_dir}")
+
+
+def check_git_dir(config: Settings) -> CheckResult:
+    """Check that git is available when MORY_GIT_DIR is set"""
+    if not config.git_dir:
+        return CheckResult("git", True, "Git versioning disabled")
+    if shutil.which("git") is None:
+        return CheckResult(
+            "git",
+            False,
+            "MORY_GIT_DIR is set but git is not installed; changes will not be versioned",
+            severity="warning",
+        )
+    push = f", pushing to {config.git_remote}" if config.git_push else ""
+    return CheckResult("git", True, f"Versioning memories in {config.git_dir}{push}")
+
+
+def check_backup_targets(config: Settings) -> CheckResult:
+    """Check off-site backup target definitions and encryption support"""
+    if not config.backup_targets:
+        return CheckResult("backup_targets", True, "No off-site backup targets")
+
+    for index, target in enumerate(config.backup_targets):
+        problem = validate_target(target)
+        if problem:
+            return CheckResult(
+                "backup_targets", False, f"MORY_BACKUP_TARGETS[{index}]: {problem}"
+            )
+
+    if config.backup_encryption_key:
+        try:
+            import cryptography  # noqa: F401
+        except ImportError:
+            return CheckResult(
+                "backup_targets",
+                False,
+                "MORY_BACKUP_ENCRYPTION_KEY is set but cryptography is not installed "
+                "(pip install 'mory-server[backup]')",
+            )
+    encrypted = "encrypted" if config.backup_encryption_key else "unencrypted"
+    return CheckResult(
+        "backup_targets",
+        True,
+        f"{len(config.backup_targets)} off-site backup target(s), {encrypted}",
+    )
+
+
+def check_memory_templates(config: Settings) -> CheckResult:
+    """Check MORY_MEMORY_TEMPLATES definitions"""
+    for name, template in config.memory_templates.items():
+        problem = template_problem(name, template)
+        if problem:
+            return CheckResult("memory_templates", False, f"MORY_MEMORY_TEMPLATES: {problem}")
+    return CheckResult(
+        "memory_templates", True, f"{len(config.memory_templates)} custom memory template(s)"
+    )
+
+
+def check_locale(config: Settings) -> CheckResult:
+    """Check that MORY_LOCALE has a message catalog"""
+    if config.locale not in LOCALES:
+        return CheckResult(
+            "locale",
+            False,
+            f"MORY_LOCALE={config.locale} is not supported ({', '.join(LOCALES)}); "
+            f"tool messages fall back to {DEFAULT_LOCALE}",
+            severity="warning",
+        )
+    return CheckResult("locale", True, f"Tool messages in {config.locale}")
+
+
+def check_openai(config: Settings, live: bool = False) -> CheckResult:
+    """Check the OpenAI API key, optionally with a live request"""
+    if not config.semantic_search_enabled:
+        return CheckResult("openai", True, "Semantic search disabled", severity="warning")
+
+    if not config.openai_api_key:
+        return CheckResult(
+            "openai",
+            False,
+            "OPENAI_API_KEY is not set; semantic search and AI summaries are disabled. "
+            "Set the key or MORY_SEMANTIC_SEARCH_ENABLED=false.",
+            severity="warning",
+        )
+
+    if not live:
+        return CheckResult("openai", True, "OpenAI API key configured (not verified)")
+
+    try:
+        import openai
+
+        client = openai.OpenAI(api_key=config.openai_api_key)
+        client.models.retrieve(config.openai_model)
+    except Exception as e:
+        return CheckResult(
+            "openai",
+            False,
+            f"OpenAI API check failed for model '{config.openai_model}': {e}",
+            severity="warning",
+        )
+    return CheckResult("openai", True, f"OpenAI API reachable, model '{config.openai_model}' OK")
+
+
+def run_config_checks(config: Settings | None = None, live: bool = False) -> list[CheckResult]:
+    """Run all configuration checks"""
+    config = config or settings
+    data_dir_result = check_data_dir(config)
+    results = [data_dir_result]
+
+    # Opening the database is pointless if the data directory is unusable
+    if data_dir_result.ok or config.database_url:
+        results.append(check_database(config))
+
+    results.extend(
+        [
+            check_obsidian_vault(config),
+            check_search_settings(config),
+            check_jobs(config),
+            check_storage_backend(config),
+            check_backup_targets(config),
+            check_git_dir(config),
+            check_memory_templates(config),
+            check_locale(config),
+            check_openai(config, live=live),
+        ]
+    )
+    return results
+
+
+def format_report(results: list[CheckResult]) -> str:
+    """Format check results as a human readable report"""
+    lines = ["Mory configuration check", ""]
+    for result in results:
+        if result.ok:
+            icon = "✅"
+        elif result.severity == "warning":
+            icon = "⚠️ "
+        else:
+            icon = "❌"
+        lines.append(f"{icon} {result.name}: {result.message}")
+
+    errors = sum(1 for r in results if r.is_error)
+    warnings = sum(1 for r in results if not r.ok and r.severity == "warning")
+    lines.append("")
+    lines.append(f"{errors} error(s), {warnings} warning(s)")
+    return "\n".join(lines)
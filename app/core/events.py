@@ -0,0 +1,82 @@
+"""In-process event bus for memory lifecycle events
+Subsystems (embeddings, webhooks, operation log, importers) subscribe instead of
+being called directly from the API handlers.
+"""
+
+import inspect
+import logging
+from collections.abc import Awaitable, Callable
+from dataclasses import dataclass, field
+from typing import Any
+
+logger = logging.getLogger(__name__)
+
+MEMORY_SAVED = "saved"
+MEMORY_UPDATED = "updated"
+MEMORY_DELETED = "deleted"
+MEMORY_IMPORTED = "imported"
+
+EVENT_TYPES = (MEMORY_SAVED, MEMORY_UPDATED, MEMORY_DELETED, MEMORY_IMPORTED)
+
+
+@dataclass
+class MemoryEvent:
+    """Something happened to a memory"""
+
+    type: str
+    memory: Any
+    session: Any = None  # Database session the change was committed with
+    agent_id: str | None = None
+    details: dict[str, Any] = field(default_factory=dict)
+    # Operation log name when more specific than the type ("archived", "approved", ...)
+    operation: str | None = None
+
+
+Handler = Callable[[MemoryEvent], Awaitable[None] | None]
+
+
+class EventBus:
+    """Dispatches events to subscribers in registration order"""
+
+    def __init__(self) -> None:
+        self._handlers: dict[str, list[Handler]] = {}
+
+    def subscribe(self, event_type: str, handler: Handler) -> None:
+        if event_type not in EVENT_TYPES:
+            raise ValueError(f"Unknown event type: {event_type}")
+        handlers = self._handlers.setdefault(event_type, [])
+        if handler not in handlers:
+            handlers.append(handler)
+
+    def unsubscribe(self, event_type: str, handler: Handler) -> None:
+        handlers = self._handlers.get(event_type, [])
+        if handler in handlers:
+            handlers.remove(handler)
+
+    def on(self, *event_types: str) -> Callable[[Handler], Handler]:
+        """Decorator subscribing a handler to one or more event types"""
+
+        def decorator(handler: Handler) -> Handler:
+            for event_type in event_types:
+                self.subscribe(event_type, handler)
+            return handler
+
+        return decorator
+
+    def handlers(self, event_type: str) -> list[Handler]:
+        return list(self._handlers.get(event_type, []))
+
+    async def publish(self, event: MemoryEvent) -> None:
+        """Run every handler; a failing handler is logged and does not stop the others"""
+        for handler in self.handlers(event.type):
+            try:
+                result = handler(event)
+                if inspect.isawaitable(result):
+                    await result
+            except Exception:
+                name = getattr(handler, "__qualname__", repr(handler))
+                logger.exception(f"Event handler {name} failed for {event.type}")
+
+
+# Global event bus instance
+event_bus = EventBus()
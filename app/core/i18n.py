@@ -0,0 +1,129 @@
+"""Message catalogs for text shown to users by MCP tools
+MORY_LOCALE picks the catalog; unknown locales and missing keys fall back to English.
+"""
+
+from .config import settings
+
+DEFAULT_LOCALE = "en"
+
+MESSAGES: dict[str, dict[str, str]] = {
+    "en": {
+        "shutting_down": "Mory is shutting down",
+        "unknown_tool": "Unknown tool: {name}",
+        "invalid_arguments": "Invalid arguments for {name}",
+        "memory_not_found": "Memory with key '{key}' not found",
+        "memory_not_found_in_category": "Memory with key '{key}' in category '{category}' not found",
+        "reminder_acknowledged": "Reminder for {memory_id} acknowledged",
+        "summary_preview": "Preview: {count} memories would be archived",
+        "confirm_with_token": "To proceed, call again with dry_run=false and confirmation_token={token}",
+        "summary_saved": "Saved summary {memory_id}; archived {count} memories",
+        "preview.delete_memories": "Preview: {count} memories would be deleted",
+        "preview.move_memories": "Preview: {count} memories would be moved to '{category}'",
+        "preview.retag_memories": "Preview: {count} memories would be retagged",
+        "done.delete_memories": "Deleted {count} memories",
+        "done.move_memories": "Moved {count} memories to '{category}'",
+        "done.retag_memories": "Retagged {count} memories",
+        "canvas_written": "Wrote {path} with {count} memories and {edges} links",
+        "vault_not_configured": "No Obsidian vault configured (set MORY_OBSIDIAN_VAULT_PATH)",
+        "more_results": "{count} more results, refine your query",
+        "http_error": "HTTP {status}: {detail}",
+        "server_unreachable": "Mory API server is not reachable at {url}",
+        "context_set": "Searches in this session now prefer memories related to: {context}",
+        "context_cleared": "Session context cleared",
+        "working_memory_not_found": "Working-memory note '{id}' not found (it may have expired)",
+        "failed.save_memory": "Failed to save memory: {error}",
+        "failed.save_url": "Failed to save URL: {error}",
+        "failed.get_memory": "Failed to get memory: {error}",
+        "failed.list_memories": "Failed to list memories: {error}",
+        "failed.search_memories": "Failed to search memories: {error}",
+        "failed.list_pending": "Failed to list pending memories: {error}",
+        "failed.approve_memory": "Failed to approve memory: {error}",
+        "failed.reject_memory": "Failed to reject memory: {error}",
+        "failed.verify_memory": "Failed to verify memory: {error}",
+        "failed.list_templates": "Failed to list templates: {error}",
+        "failed.surface_memory": "Failed to surface memories: {error}",
+        "failed.get_recent_memories": "Failed to get recent memories: {error}",
+        "failed.suggest_keys": "Failed to get suggestions: {error}",
+        "failed.promote_to_long_term": "Failed to promote working memory: {error}",
+        "failed.suggest_category": "Failed to suggest a category: {error}",
+        "failed.describe_category": "Failed to describe category: {error}",
+        "failed.report_search_result": "Failed to report search result: {error}",
+        "failed.get_search_analytics": "Failed to get search analytics: {error}",
+        "failed.search_operations": "Failed to search operations: {error}",
+        "failed.get_due_reminders": "Failed to get due reminders: {error}",
+        "failed.acknowledge_reminder": "Failed to acknowledge reminder: {error}",
+        "failed.summarize_category": "Failed to summarize category: {error}",
+        "failed.delete_memories": "Failed to delete memories: {error}",
+        "failed.move_memories": "Failed to move memories: {error}",
+        "failed.retag_memories": "Failed to retag memories: {error}",
+        "failed.get_diagnostics": "Failed to get diagnostics: {error}",
+        "failed.get_report": "Failed to build report: {error}",
+        "failed.generate_obsidian_canvas": "Failed to generate canvas: {error}",
+        "failed.get_metrics": "Failed to get metrics: {error}",
+        "failed.health_check": "Failed to run health check: {error}",
+    },
+    "ja": {
+        "shutting_down": "Moryはシャットダウン中です",
+        "unknown_tool": "不明なツールです: {name}",
+        "invalid_arguments": "{name} の引数が正しくありません",
+        "memory_not_found": "キー '{key}' のメモリが見つかりません",
+        "memory_not_found_in_category": "カテゴリ '{category}' にキー '{key}' のメモリが見つかりません",
+        "reminder_acknowledged": "{memory_id} のリマインダーを完了にしました",
+        "summary_preview": "プレビュー: {count} 件のメモリがアーカイブされます",
+        "confirm_with_token": "実行するには dry_run=false と confirmation_token={token} を指定して再度呼び出してください",
+        "summary_saved": "要約 {memory_id} を保存し、{count} 件のメモリをアーカイブしました",
+        "preview.delete_memories": "プレビュー: {count} 件のメモリが削除されます",
+        "preview.move_memories": "プレビュー: {count} 件のメモリが '{category}' に移動されます",
+        "preview.retag_memories": "プレビュー: {count} 件のメモリのタグが変更されます",
+        "done.delete_memories": "{count} 件のメモリを削除しました",
+        "done.move_memories": "{count} 件のメモリを '{category}' に移動しました",
+        "done.retag_memories": "{count} 件のメモリのタグを変更しました",
+        "canvas_written": "{path} を作成しました ({count} 件のメモリ, {edges} 本のリンク)",
+        "vault_not_configured": "Obsidian の Vault が設定されていません (MORY_OBSIDIAN_VAULT_PATH を設定してください)",
+        "more_results": "他に {count} 件あります。検索条件を絞り込んでください",
+        "http_error": "HTTPエラー {status}: {detail}",
+        "server_unreachable": "Mory APIサーバー ({url}) に接続できません",
+        "context_set": "このセッションの検索では次に関連するメモリを優先します: {context}",
+        "context_cleared": "セッションのコンテキストを解除しました",
+        "working_memory_not_found": "作業メモリ '{id}' が見つかりません（期限切れの可能性があります）",
+        "failed.save_memory": "メモリの保存に失敗しました: {error}",
+        "failed.save_url": "URLの保存に失敗しました: {error}",
+        "failed.get_memory": "メモリの取得に失敗しました: {error}",
+        "failed.list_memories": "メモリの一覧取得に失敗しました: {error}",
+        "failed.search_memories": "メモリの検索に失敗しました: {error}",
+        "failed.list_pending": "承認待ちメモリの取得に失敗しました: {error}",
+        "failed.approve_memory": "メモリの承認に失敗しました: {error}",
+        "failed.reject_memory": "メモリの却下に失敗しました: {error}",
+        "failed.verify_memory": "メモリの検証に失敗しました: {error}",
+        "failed.list_templates": "テンプレートの取得に失敗しました: {error}",
+        "failed.surface_memory": "復習メモリの取得に失敗しました: {error}",
+        "failed.get_recent_memories": "最近のメモリの取得に失敗しました: {error}",
+        "failed.suggest_keys": "候補の取得に失敗しました: {error}",
+        "failed.promote_to_long_term": "作業メモリの保存に失敗しました: {error}",
+        "failed.suggest_category": "カテゴリの提案に失敗しました: {error}",
+        "failed.describe_category": "カテゴリの説明の設定に失敗しました: {error}",
+        "failed.report_search_result": "検索結果の報告に失敗しました: {error}",
+        "failed.get_search_analytics": "検索分析の取得に失敗しました: {error}",
+        "failed.search_operations": "操作ログの検索に失敗しました: {error}",
+        "failed.get_due_reminders": "リマインダーの取得に失敗しました: {error}",
+        "failed.acknowledge_reminder": "リマインダーの完了に失敗しました: {error}",
+        "failed.summarize_category": "カテゴリの要約に失敗しました: {error}",
+        "failed.delete_memories": "メモリの一括削除に失敗しました: {error}",
+        "failed.move_memories": "メモリの一括移動に失敗しました: {error}",
+        "failed.retag_memories": "メモリのタグの一括変更に失敗しました: {error}",
+        "failed.get_diagnostics": "診断情報の取得に失敗しました: {error}",
+        "failed.get_report": "レポートの作成に失敗しました: {error}",
+        "failed.generate_obsidian_canvas": "キャンバスの作成に失敗しました: {error}",
+        "failed.get_metrics": "メトリクスの取得に失敗しました: {error}",
+        "failed.health_check": "ヘルスチェックに失敗しました: {error}",
+    },
+}
+
+LOCALES = tuple(MESSAGES)
+
+
+def translate(key: str, locale: str | None = None, **values: object) -> str:
+    """Message for key in the configured locale, formatted with values"""
+    catalog = MESSAGES.get(locale or settings.locale, MESSAGES[DEFAULT_LOCALE])
+    template = catalog.get(key) or MESSAGES[DEFAULT_LOCALE][key]
+    return template.format(**values)
@@ -0,0 +1,87 @@
+"""Single-instance locking on the data directory
+Prevents two Mory servers from writing the same database concurrently
+"""
+
+import os
+from pathlib import Path
+from typing import IO
+
+LOCK_FILENAME = "mory.lock"
+
+
+class InstanceLockedError(RuntimeError):
+    """Raised when another process already holds the data directory lock"""
+
+
+class InstanceLock:
+    """Advisory lock file held for the lifetime of the server process
+
+    The OS releases the lock when the process exits, so a crashed server
+    never leaves a stale lock behind.
+    """
+
+    def __init__(self, data_dir: str | Path):
+        self.path = Path(data_dir) / LOCK_FILENAME
+        self._file: IO[str] | None = None
+
+    @property
+    def held(self) -> bool:
+        return self._file is not None
+
+    def acquire(self) -> None:
+        """Acquire the lock or raise InstanceLockedError"""
+        if self._file is not None:
+            return
+
+        self.path.parent.mkdir(parents=True, exist_ok=True)
+        lock_file = open(self.path, "a+", encoding="utf-8")
+        try:
+            _lock(lock_file)
+        except OSError as e:
+            lock_file.seek(0)
+            owner = lock_file.read().strip() or "unknown"
+            lock_file.close()
+            raise InstanceLockedError(
+                f"Another Mory server (pid {owner}) is already using '{self.path.parent}'. "
+                "Stop it first, or set MORY_ALLOW_MULTIPLE_INSTANCES=true "
+                "(or pass --force) to override."
+            ) from e
+
+        lock_file.seek(0)
+        lock_file.truncate()
+        lock_file.write(str(os.getpid()))
+        lock_file.flush()
+        self._file = lock_file
+
+    def release(self) -> None:
+        """Release the lock if held"""
+        if self._file is None:
+            return
+        try:
+            self._file.seek(0)
+            self._file.truncate()
+            _unlock(self._file)
+        finally:
+            self._file.close()
+            self._file = None
+
+
+if os.name == "nt":
+    import msvcrt
+
+    def _lock(lock_file: IO[str]) -> None:
+        lock_file.seek(0)
+        msvcrt.locking(lock_file.fileno(), msvcrt.LK_NBLCK, 1)
+
+    def _unlock(lock_file: IO[str]) -> None:
+        lock_file.seek(0)
+        msvcrt.locking(lock_file.fileno(), msvcrt.LK_UNLCK, 1)
+
+else:
+    import fcntl
+
+    def _lock(lock_file: IO[str]) -> None:
+        fcntl.flock(lock_file.fileno(), fcntl.LOCK_EX | fcntl.LOCK_NB)
+
+    def _unlock(lock_file: IO[str]) -> None:
+        fcntl.flock(lock_file.fileno(), fcntl.LOCK_UN)
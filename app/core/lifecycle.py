@@ -0,0 +1,47 @@
+"""Lifecycle helpers for graceful shutdown
+Tracks in-flight operations so shutdown can drain them before closing storage
+"""
+
+import asyncio
+from collections.abc import Iterator
+from contextlib import contextmanager
+
+
+class InFlightTracker:
+    """Count running operations and signal when none remain"""
+
+    def __init__(self) -> None:
+        self._count = 0
+        self._idle = asyncio.Event()
+        self._idle.set()
+        self.draining = False
+
+    @property
+    def count(self) -> int:
+        return self._count
+
+    @contextmanager
+    def track(self) -> Iterator[None]:
+        """Mark an operation as in flight for the duration of the block"""
+        self._count += 1
+        self._idle.clear()
+        try:
+            yield
+        finally:
+            self._count -= 1
+            if self._count == 0:
+                self._idle.set()
+
+    async def drain(self, timeout: float) -> bool:
+        """Stop accepting new operations and wait for running ones
+
+        Returns:
+            True if all operations finished within the timeout
+
+        """
+        self.draining = True
+        try:
+            await asyncio.wait_for(self._idle.wait(), timeout)
+            return True
+        except TimeoutError:
+            return False
@@ -0,0 +1,91 @@
+"""Write rate limiting and payload size limits
+Protects the database from runaway agents saving large volumes of memories
+"""
+
+import threading
+import time
+from collections import deque
+
+from .metrics import metrics
+
+# Outcomes counted under mory_limit_events_total
+LIMIT_EVENTS = ("rate_limited", "truncated", "rejected_too_large")
+
+
+class RateLimitExceededError(Exception):
+    """Raised when a client exceeds its per-minute write allowance"""
+
+
+class PayloadTooLargeError(ValueError):
+    """Raised when a memory value exceeds the maximum length"""
+
+
+class RateLimiter:
+    """Sliding one-minute window of write timestamps per client"""
+
+    def __init__(self, window_seconds: float = 60.0):
+        self.window_seconds = window_seconds
+        self._lock = threading.Lock()
+        self._events: dict[str, deque[float]] = {}
+
+    def check(self, key: str, limit: int) -> None:
+        """Record a write for the key, raising if it exceeds the limit (0 disables)"""
+        if limit <= 0:
+            return
+
+        now = time.monotonic()
+        with self._lock:
+            events = self._events.setdefault(key, deque())
+            while events and now - events[0] >= self.window_seconds:
+                events.popleft()
+            if len(events) >= limit:
+                retry_after = self.window_seconds - (now - events[0])
+                metrics.inc("mory_limit_events_total", {"event": "rate_limited"})
+                raise RateLimitExceededError(
+                    f"Write limit of {limit}/min exceeded for '{key}', retry in {retry_after:.0f}s"
+                )
+            events.append(now)
+
+    def reset(self) -> None:
+        with self._lock:
+            self._events.clear()
+
+
+def limit_value(value: str, max_length: int, policy: str = "reject") -> tuple[str, bool]:
+    """Enforce the maximum value length
+
+    Args:
+        value: Memory content
+        max_length: Maximum number of characters (0 disables)
+        policy: "reject" raises, "truncate" cuts the value down to max_length
+
+    Returns:
+        Tuple of (value to store, whether it was truncated)
+
+    Raises:
+        PayloadTooLargeError: If the value is too long and policy is "reject"
+
+    """
+    if max_length <= 0 or len(value) <= max_length:
+        return value, False
+
+    if policy == "truncate":
+        metrics.inc("mory_limit_events_total", {"event": "truncated"})
+        return value[:max_length], True
+
+    metrics.inc("mory_limit_events_total", {"event": "rejected_too_large"})
+    raise PayloadTooLargeError(
+        f"Memory value is {len(value)} characters; the limit is {max_length}"
+    )
+
+
+def limit_counters() -> dict[str, int]:
+    """Counts of limit enforcement events since startup"""
+    return {
+        event: int(metrics.get("mory_limit_events_total", {"event": event}))
+        for event in LIMIT_EVENTS
+    }
+
+
+# Global write rate limiter
+write_rate_limiter = RateLimiter()
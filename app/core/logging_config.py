@@ -0,0 +1,86 @@
+"""Logging configuration for Mory Server
+Level, text/JSON format and an optional log file are driven by settings
+"""
+
+import json
+import logging
+import sys
+import uuid
+from contextvars import ContextVar
+from datetime import UTC, datetime
+from pathlib import Path
+
+from .config import settings
+
+# Correlation ID of the request currently being handled
+request_id_var: ContextVar[str | None] = ContextVar("request_id", default=None)
+
+TEXT_FORMAT = "%(asctime)s - %(name)s - %(levelname)s - [%(request_id)s] %(message)s"
+
+
+def new_request_id() -> str:
+    """Generate a short request ID for log correlation"""
+    return uuid.uuid4().hex[:8]
+
+
+def current_request_id() -> str:
+    """Return the active request ID, generating one if none is set"""
+    return request_id_var.get() or new_request_id()
+
+
+class RequestIdFilter(logging.Filter):
+    """Attach the current request ID to every log record"""
+
+    def filter(self, record: logging.LogRecord) -> bool:
+        record.request_id = request_id_var.get() or "-"
+        return True
+
+
+class JsonFormatter(logging.Formatter):
+    """Format log records as single-line JSON objects"""
+
+    def format(self, record: logging.LogRecord) -> str:
+        payload = {
+            "timestamp": datetime.fromtimestamp(record.created, tz=UTC).isoformat(),
+            "level": record.levelname,
+            "logger": record.name,
+            "message": record.getMessage(),
+            "request_id": getattr(record, "request_id", "-"),
+        }
+        if record.exc_info:
+            payload["exception"] = self.formatException(record.exc_info)
+        return json.dumps(payload, ensure_ascii=False)
+
+
+def setup_logging(
+    level: str | None = None, log_format: str | None = None, log_file: str | None = None
+) -> None:
+    """Configure the root logger
+
+    Args:
+        level: Log level name (defaults to MORY_LOG_LEVEL)
+        log_format: "text" or "json" (defaults to MORY_LOG_FORMAT)
+        log_file: Optional file to log to in addition to stderr (defaults to MORY_LOG_FILE)
+
+    """
+    level = (level or settings.log_level).upper()
+    log_format = (log_format or settings.log_format).lower()
+    log_file = log_file or settings.log_file
+
+    # stderr only: stdout carries the MCP stdio protocol
+    handlers: list[logging.Handler] = [logging.StreamHandler(sys.stderr)]
+    if log_file:
+        Path(log_file).parent.mkdir(parents=True, exist_ok=True)
+        handlers.append(logging.FileHandler(log_file, encoding="utf-8"))
+
+    formatter = JsonFormatter() if log_format == "json" else logging.Formatter(TEXT_FORMAT)
+    request_filter = RequestIdFilter()
+
+    root = logging.getLogger()
+    for handler in list(root.handlers):
+        root.removeHandler(handler)
+    for handler in handlers:
+        handler.setFormatter(formatter)
+        handler.addFilter(request_filter)
+        root.addHandler(handler)
+    root.setLevel(level)
@@ -0,0 +1,142 @@
+"""In-process metrics registry for Mory Server
+Counters and latency summaries rendered in Prometheus text format
+"""
+
+import threading
+from typing import Any
+
+LabelKey = tuple[tuple[str, str], ...]
+
+
+def _label_key(labels: dict[str, str] | None) -> LabelKey:
+    return tuple(sorted((labels or {}).items()))
+
+
+def _escape(value: str) -> str:
+    return value.replace("\\", "\\\\").replace('"', '\\"').replace("\n", "\\n")
+
+
+def _format_labels(key: LabelKey) -> str:
+    if not key:
+        return ""
+    return "{" + ",".join(f'{name}="{_escape(value)}"' for name, value in key) + "}"
+
+
+class MetricsRegistry:
+    """Thread-safe registry of counters and summaries"""
+
+    def __init__(self) -> None:
+        self._lock = threading.Lock()
+        self._help: dict[str, tuple[str, str]] = {}  # name -> (type, help)
+        self._counters: dict[str, dict[LabelKey, float]] = {}
+        self._summaries: dict[str, dict[LabelKey, list[float]]] = {}  # [count, sum]
+
+    def counter(self, name: str, help_text: str) -> None:
+        """Declare a counter"""
+        with self._lock:
+            self._help[name] = ("counter", help_text)
+            self._counters.setdefault(name, {})
+
+    def summary(self, name: str, help_text: str) -> None:
+        """Declare a summary (count and sum of observations)"""
+        with self._lock:
+            self._help[name] = ("summary", help_text)
+            self._summaries.setdefault(name, {})
+
+    def inc(self, name: str, labels: dict[str, str] | None = None, value: float = 1.0) -> None:
+        """Increment a counter"""
+        key = _label_key(labels)
+        with self._lock:
+            series = self._counters.setdefault(name, {})
+            series[key] = series.get(key, 0.0) + value
+
+    def observe(self, name: str, value: float, labels: dict[str, str] | None = None) -> None:
+        """Record an observation in a summary"""
+        key = _label_key(labels)
+        with self._lock:
+            series = self._summaries.setdefault(name, {})
+            entry = series.setdefault(key, [0.0, 0.0])
+            entry[0] += 1
+            entry[1] += value
+
+    def get(self, name: str, labels: dict[str, str] | None = None) -> float:
+        """Current value of a counter"""
+        with self._lock:
+            return self._counters.get(name, {}).get(_label_key(labels), 0.0)
+
+    def snapshot(self) -> dict[str, Any]:
+        """JSON-friendly view of all metrics"""
+        with self._lock:
+            counters = {
+                name: [{"labels": dict(key), "value": value} for key, value in series.items()]
+                for name, series in self._counters.items()
+            }
+            summaries = {
+                name: [
+                    {
+                        "labels": dict(key),
+                        "count": int(count),
+                        "sum": round(total, 6),
+                        "avg": round(total / count, 6) if count else 0.0,
+                    }
+                    for key, (count, total) in series.items()
+                ]
+                for name, series in self._summaries.items()
+            }
+        return {"counters": counters, "summaries": summaries}
+
+    def render_prometheus(self, gauges: dict[str, tuple[str, float]] | None = None) -> str:
+        """Render metrics in Prometheus text exposition format
+
+        Args:
+            gauges: Point-in-time values computed at scrape time, name -> (help, value)
+
+        """
+        lines: list[str] = []
+        with self._lock:
+            for name, series in self._counters.items():
+                metric_type, help_text = self._help.get(name, ("counter", ""))
+                lines.append(f"# HELP {name} {help_text}")
+                lines.append(f"# TYPE {name} {metric_type}")
+                for key, value in series.items():
+                    lines.append(f"{name}{_format_labels(key)} {value:g}")
+
+            for name, series in self._summaries.items():
+                metric_type, help_text = self._help.get(name, ("summary", ""))
+                lines.append(f"# HELP {name} {help_text}")
+                lines.append(f"# TYPE {name} {metric_type}")
+                for key, (count, total) in series.items():
+                    labels = _format_labels(key)
+                    lines.append(f"{name}_count{labels} {count:g}")
+                    lines.append(f"{name}_sum{labels} {total:g}")
+
+        for name, (help_text, value) in (gauges or {}).items():
+            lines.append(f"# HELP {name} {help_text}")
+            lines.append(f"# TYPE {name} gauge")
+            lines.append(f"{name} {value:g}")
+
+        return "\n".join(lines) + "\n"
+
+    def reset(self) -> None:
+        """Clear all recorded values (declarations are kept)"""
+        with self._lock:
+            for series in self._counters.values():
+                series.clear()
+            for series in self._summaries.values():
+                series.clear()
+
+
+# Global metrics registry
+metrics = MetricsRegistry()
+
+metrics.counter("mory_http_requests_total", "HTTP requests by method, route and status")
+metrics.counter("mory_tool_calls_total", "MCP tool calls by tool name")
+metrics.counter("mory_tool_errors_total", "MCP tool calls that returned an error status")
+metrics.counter("mory_embedding_api_calls_total", "OpenAI embedding API calls by outcome")
+metrics.counter("mory_summary_api_calls_total", "OpenAI summary API calls by outcome")
+metrics.counter("mory_webhook_deliveries_total", "Webhook deliveries by outcome")
+metrics.counter(
+    "mory_limit_events_total", "Writes rate limited, truncated or rejected as too large"
+)
+metrics.summary("mory_search_duration_seconds", "Search latency by search type")
+metrics.summary("mory_http_request_duration_seconds", "HTTP request latency by route")
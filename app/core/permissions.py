@@ -0,0 +1,72 @@
+"""Access control for memories shared between several agents
+A permission hook decides whether an agent may read or modify a memory
+"""
+
+from collections.abc import Callable, Iterable
+from typing import Any, Literal
+
+from sqlalchemy.orm import Query
+
+Action = Literal["read", "write"]
+
+# (agent_id, memory, action) -> allowed
+PermissionHook = Callable[[str | None, Any, Action], bool]
+
+
+def default_permission_hook(agent_id: str | None, memory: Any, action: Action) -> bool:
+    """Default policy: everyone may read, only the owner may modify
+
+    Memories without an owner (saved before scoping was enabled, or by an
+    anonymous client) and requests without an agent ID are unrestricted.
+    """
+    if action == "read":
+        return True
+    owner = getattr(memory, "owner", None)
+    return owner is None or agent_id is None or owner == agent_id
+
+
+_permission_hook: PermissionHook = default_permission_hook
+
+
+def set_permission_hook(hook: PermissionHook | None) -> None:
+    """Install a custom permission hook (None restores the default policy)"""
+    global _permission_hook
+    _permission_hook = hook or default_permission_hook
+
+
+def reads_restricted() -> bool:
+    """Whether the active hook may hide memories from readers (the default never does)"""
+    return _permission_hook is not default_permission_hook
+
+
+def check_access(agent_id: str | None, memory: Any, action: Action) -> bool:
+    """Ask the active permission hook whether the agent may perform the action"""
+    return _permission_hook(agent_id, memory, action)
+
+
+def read_filter(agent_id: str | None) -> Callable[[Any], bool] | None:
+    """Predicate for memories the agent may read, or None when every memory is readable"""
+    if not reads_restricted():
+        return None
+    return lambda memory: check_access(agent_id, memory, "read")
+
+
+def readable(memories: Iterable[Any], agent_id: str | None) -> list[Any]:
+    """The memories the agent may read"""
+    visible = read_filter(agent_id)
+    return [memory for memory in memories if visible is None or visible(memory)]
+
+
+def readable_page(
+    query: Query, agent_id: str | None, offset: int = 0, limit: int | None = None
+) -> tuple[list[Any], int]:
+    """A page of the query's memories the agent may read, and how many it may read in all
+
+    The default policy pages in SQL; a restrictive hook is applied before paginating so
+    hidden memories neither take up the page nor count towards the total.
+    """
+    if not reads_restricted():
+        return query.offset(offset).limit(limit).all(), query.order_by(None).count()
+    memories = readable(query.all(), agent_id)
+    end = None if limit is None else offset + limit
+    return memories[offset:end], len(memories)
@@ -0,0 +1,167 @@
+"""Lightweight scheduler for recurring maintenance jobs
+Jobs register a coroutine; MORY_JOBS enables them with an interval such as "24h"
+"""
+
+import asyncio
+import json
+import logging
+import re
+import time
+from collections.abc import Awaitable, Callable
+from dataclasses import asdict, dataclass
+from datetime import UTC, datetime
+from pathlib import Path
+from typing import Any
+
+from .fileutil import atomic_write_text
+
+logger = logging.getLogger(__name__)
+
+JobFunc = Callable[[], Awaitable[str | None]]
+
+_UNITS = {"s": 1, "m": 60, "h": 3600, "d": 86400, "w": 604800}
+
+
+def parse_interval(value: str) -> float:
+    """Parse an interval like "30m", "24h" or "7d" into seconds"""
+    match = re.fullmatch(r"\s*(\d+(?:\.\d+)?)\s*([smhdw])\s*", value)
+    if not match:
+        raise ValueError(f"Invalid interval '{value}' (expected e.g. 30m, 24h, 7d)")
+    return float(match.group(1)) * _UNITS[match.group(2)]
+
+
+@dataclass
+class JobStatus:
+    """Last-run information for a job"""
+
+    interval_seconds: float
+    last_run_at: float | None = None
+    last_status: str | None = None  # "ok" or "error"
+    last_message: str | None = None
+    last_duration_ms: float | None = None
+    run_count: int = 0
+
+    @property
+    def next_run_at(self) -> float:
+        return (self.last_run_at or 0.0) + self.interval_seconds
+
+    def to_dict(self) -> dict[str, Any]:
+        def _iso(ts: float | None) -> str | None:
+            return datetime.fromtimestamp(ts, tz=UTC).isoformat() if ts else None
+
+        return {
+            "interval_seconds": self.interval_seconds,
+            "last_run_at": _iso(self.last_run_at),
+            "last_status": self.last_status,
+            "last_message": self.last_message,
+            "last_duration_ms": self.last_duration_ms,
+            "run_count": self.run_count,
+            "next_run_at": _iso(self.next_run_at),
+        }
+
+
+class Scheduler:
+    """Runs enabled jobs when their interval has elapsed
+
+    Last-run times are persisted to a state file so schedules survive restarts.
+    """
+
+    def __init__(self, state_path: Path | None = None, tick_seconds: float = 30.0):
+        self.state_path = state_path
+        self.tick_seconds = tick_seconds
+        self._jobs: dict[str, JobFunc] = {}
+        self.status: dict[str, JobStatus] = {}
+        self._task: asyncio.Task | None = None
+
+    def register(self, name: str, func: JobFunc) -> None:
+        """Make a job available (it only runs once enabled via configure)"""
+        self._jobs[name] = func
+
+    @property
+    def registered(self) -> list[str]:
+        return sorted(self._jobs)
+
+    def configure(self, intervals: dict[str, str]) -> None:
+        """Enable jobs with their intervals, e.g. {"backup": "24h"}"""
+        saved = self._load_state()
+        self.status = {}
+        for name, interval in intervals.items():
+            if name not in self._jobs:
+                logger.warning(f"Unknown job '{name}' in MORY_JOBS (available: {self.registered})")
+                continue
+            status = JobStatus(interval_seconds=parse_interval(interval))
+            if name in saved:
+                status.last_run_at = saved[name].get("last_run_at")
+                status.last_status = saved[name].get("last_status")
+                status.last_message = saved[name].get("last_message")
+                status.run_count = saved[name].get("run_count", 0)
+            self.status[name] = status
+
+    async def run_job(self, name: str) -> JobStatus:
+        """Run a job now and record the outcome"""
+        status = self.status.get(name) or JobStatus(interval_seconds=0)
+        self.status.setdefault(name, status)
+
+        start = time.perf_counter()
+        try:
+            message = await self._jobs[name]()
+            status.last_status = "ok"
+            status.last_message = message
+            logger.info(f"Job {name} finished: {message or 'ok'}")
+        except Exception as e:
+            status.last_status = "error"
+            status.last_message = str(e)
+            logger.exception(f"Job {name} failed")
+        status.last_run_at = time.time()
+        status.last_duration_ms = round((time.perf_counter() - start) * 1000, 1)
+        status.run_count += 1
+        self._save_state()
+        return status
+
+    async def run_due(self) -> list[str]:
+        """Run every enabled job whose interval has elapsed"""
+        now = time.time()
+        due = [name for name, status in self.status.items() if status.next_run_at <= now]
+        for name in due:
+            await self.run_job(name)
+        return due
+
+    def report(self) -> dict[str, dict[str, Any]]:
+        return {name: status.to_dict() for name, status in self.status.items()}
+
+    async def _loop(self) -> None:
+        while True:
+            await self.run_due()
+            await asyncio.sleep(self.tick_seconds)
+
+    def start(self) -> None:
+        if self.status and self._task is None:
+            self._task = asyncio.get_running_loop().create_task(self._loop())
+            logger.info(f"⏰ Scheduler started: {', '.join(self.status)}")
+
+    async def stop(self) -> None:
+        if self._task is not None:
+            self._task.cancel()
+            try:
+                await self._task
+            except asyncio.CancelledError:
+                pass
+            self._task = None
+
+    def _load_state(self) -> dict[str, dict[str, Any]]:
+        if not self.state_path or not self.state_path.exists():
+            return {}
+        try:
+            return json.loads(self.state_path.read_text(encoding="utf-8"))
+        except (OSError, json.JSONDecodeError):
+            logger.warning(f"Ignoring unreadable scheduler state {self.state_path}")
+            return {}
+
+    def _save_state(self) -> None:
+        if not self.state_path:
+            return
+        state = {name: asdict(status) for name, status in self.status.items()}
+        try:
+            atomic_write_text(self.state_path, json.dumps(state, indent=2))
+        except OSError as e:
+            logger.warning(f"Could not save scheduler state: {e}")
@@ -0,0 +1,109 @@
+"""Per-request timing traces for debug mode
+Records how long each stage (DB query, embedding, FTS, ...) of a request takes
+"""
+
+import time
+from collections import deque
+from collections.abc import Iterator
+from contextlib import contextmanager
+from contextvars import ContextVar, Token
+from dataclasses import dataclass, field
+from datetime import UTC, datetime
+from typing import Any
+
+
+@dataclass
+class RequestTrace:
+    """Timing breakdown for a single request"""
+
+    request_id: str
+    method: str
+    path: str
+    started_at: float = field(default_factory=time.time)
+    total_ms: float = 0.0
+    status_code: int | None = None
+    spans: dict[str, float] = field(default_factory=dict)  # stage name -> total ms
+
+    def add(self, name: str, elapsed_ms: float) -> None:
+        self.spans[name] = self.spans.get(name, 0.0) + elapsed_ms
+
+    def to_dict(self) -> dict[str, Any]:
+        return {
+            "request_id": self.request_id,
+            "method": self.method,
+            "path": self.path,
+            "started_at": datetime.fromtimestamp(self.started_at, tz=UTC).isoformat(),
+            "status_code": self.status_code,
+            "total_ms": round(self.total_ms, 2),
+            "spans": {name: round(ms, 2) for name, ms in self.spans.items()},
+        }
+
+
+_current_trace: ContextVar[RequestTrace | None] = ContextVar("current_trace", default=None)
+
+
+class TraceRecorder:
+    """Keeps the most recent request traces in memory"""
+
+    def __init__(self, max_traces: int = 100):
+        self._traces: deque[RequestTrace] = deque(maxlen=max_traces)
+
+    def start(self, request_id: str, method: str, path: str) -> Token:
+        """Begin tracing the current request"""
+        return _current_trace.set(RequestTrace(request_id, method, path))
+
+    def finish(self, token: Token, status_code: int | None = None) -> RequestTrace | None:
+        """Finish the current trace and store it"""
+        trace = _current_trace.get()
+        _current_trace.reset(token)
+        if trace is None:
+            return None
+        trace.total_ms = (time.time() - trace.started_at) * 1000
+        trace.status_code = status_code
+        self._traces.append(trace)
+        return trace
+
+    def recent(self, limit: int = 20) -> list[dict[str, Any]]:
+        """Most recent traces, newest first"""
+        return [trace.to_dict() for trace in list(self._traces)[::-1][:limit]]
+
+    def summary(self) -> dict[str, dict[str, float]]:
+        """Aggregate span timings across stored traces"""
+        stats: dict[str, dict[str, float]] = {}
+        for trace in self._traces:
+            for name, elapsed_ms in [("total", trace.total_ms), *trace.spans.items()]:
+                entry = stats.setdefault(name, {"count": 0, "total_ms": 0.0, "max_ms": 0.0})
+                entry["count"] += 1
+                entry["total_ms"] += elapsed_ms
+                entry["max_ms"] = max(entry["max_ms"], elapsed_ms)
+
+        return {
+            name: {
+                "count": int(entry["count"]),
+                "avg_ms": round(entry["total_ms"] / entry["count"], 2),
+                "max_ms": round(entry["max_ms"], 2),
+            }
+            for name, entry in stats.items()
+        }
+
+    def clear(self) -> None:
+        self._traces.clear()
+
+
+@contextmanager
+def trace_span(name: str) -> Iterator[None]:
+    """Time a stage of the current request (no-op when not tracing)"""
+    trace = _current_trace.get()
+    if trace is None:
+        yield
+        return
+
+    start = time.perf_counter()
+    try:
+        yield
+    finally:
+        trace.add(name, (time.perf_counter() - start) * 1000)
+
+
+# Global trace recorder instance
+trace_recorder = TraceRecorder()
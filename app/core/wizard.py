@@ -0,0 +1,97 @@
+"""Interactive first-run setup for Mory Server
+Generates a .env file and the Claude Desktop MCP configuration snippet
+"""
+
+from collections.abc import Callable
+from pathlib import Path
+
+from .claude_desktop import claude_desktop_snippet
+from .fileutil import atomic_write_text
+
+
+class SetupWizard:
+    """Ask the user for core settings and write them to a .env file"""
+
+    def __init__(
+        self,
+        env_path: str | Path = ".env",
+        input_fn: Callable[[str], str] = input,
+        output_fn: Callable[[str], None] = print,
+    ):
+        self.env_path = Path(env_path)
+        self._input = input_fn
+        self._print = output_fn
+
+    def ask(self, prompt: str, default: str = "") -> str:
+        suffix = f" [{default}]" if default else ""
+        answer = self._input(f"{prompt}{suffix}: ").strip()
+        return answer or default
+
+    def ask_yes_no(self, prompt: str, default: bool = True) -> bool:
+        hint = "Y/n" if default else "y/N"
+        answer = self._input(f"{prompt} ({hint}): ").strip().lower()
+        if not answer:
+            return default
+        return answer in ("y", "yes")
+
+    def collect(self) -> dict[str, str]:
+        """Prompt for settings and return them as environment variables"""
+        values: dict[str, str] = {}
+
+        values["MORY_DATA_DIR"] = self.ask("Data directory", "data")
+        if self.ask_yes_no(
+            "Also keep each memory as an editable markdown file (files backend)?", default=False
+        ):
+            values["MORY_STORAGE_BACKEND"] = "files"
+            default_dir = str(Path(values["MORY_DATA_DIR"]) / "memories")
+            values["MORY_FILES_DIR"] = self.ask("Markdown files directory", default_dir)
+        else:
+            values["MORY_STORAGE_BACKEND"] = "sqlite"
+        port = self.ask("Server port", "8080")
+        while not port.isdigit():
+            self._print("Port must be a number")
+            port = self.ask("Server port", "8080")
+        values["MORY_PORT"] = port
+
+        vault = self.ask("Obsidian vault path (leave empty to skip)")
+        if vault:
+            vault_path = Path(vault).expanduser()
+            if not vault_path.is_dir():
+                self._print(f"⚠️  '{vault_path}' does not exist yet, saving it anyway")
+            values["MORY_OBSIDIAN_VAULT_PATH"] = str(vault_path)
+
+        if self.ask_yes_no("Enable semantic search (requires an OpenAI API key)?"):
+            values["MORY_SEMANTIC_SEARCH_ENABLED"] = "true"
+            api_key = self.ask("OpenAI API key")
+            if api_key:
+                values["OPENAI_API_KEY"] = api_key
+            else:
+                self._print("⚠️  No API key given, semantic search stays inactive until set")
+        else:
+            values["MORY_SEMANTIC_SEARCH_ENABLED"] = "false"
+
+        return values
+
+    def write(self, values: dict[str, str]) -> Path:
+        """Write settings to the .env file"""
+        lines = ["# Generated by mory-server init"]
+        lines.extend(f"{key}={value}" for key, value in values.items())
+        atomic_write_text(self.env_path, "\n".join(lines) + "\n")
+        return self.env_path
+
+    def run(self) -> int:
+        """Run the wizard end to end"""
+        self._print("🦔 Mory setup")
+        if self.env_path.exists() and not self.ask_yes_no(
+            f"{self.env_path} already exists. Overwrite?", default=False
+        ):
+            self._print("❌ Setup cancelled")
+            return 1
+
+        values = self.collect()
+        path = self.write(values)
+        self._print(f"✅ Configuration written to {path}")
+        self._print("")
+        self._print("Add this to your Claude Desktop config (claude_desktop_config.json):")
+        self._print(claude_desktop_snippet(int(values.get("MORY_PORT", "8080"))))
+        return 0
@@ -0,0 +1,61 @@
+"""Working memory for MCP sessions
+Short-lived notes an assistant keeps while working on a task. They live in the MCP
+server's memory only, per session, and reach the long-term store only when promoted.
+"""
+
+import itertools
+from dataclasses import asdict, dataclass, field
+from datetime import datetime, timedelta
+from typing import Any
+
+# Oldest notes are dropped beyond this many per session
+MAX_ITEMS = 100
+
+
+@dataclass
+class WorkingItem:
+    """One working-memory note"""
+
+    id: str
+    value: str
+    tags: list[str] = field(default_factory=list)
+    created_at: datetime = field(default_factory=datetime.utcnow)
+
+    def to_dict(self) -> dict[str, Any]:
+        return {**asdict(self), "created_at": self.created_at.isoformat()}
+
+
+class WorkingMemory:
+    """Notes of one session, newest last; expired notes vanish after ttl minutes (0: never)"""
+
+    def __init__(self, ttl: int = 0, capacity: int = MAX_ITEMS) -> None:
+        self.ttl = ttl
+        self.capacity = capacity
+        self._items: dict[str, WorkingItem] = {}
+        self._ids = itertools.count(1)
+
+    def _expire(self) -> None:
+        if self.ttl <= 0:
+            return
+        cutoff = datetime.utcnow() - timedelta(minutes=self.ttl)
+        for item in [item for item in self._items.values() if item.created_at < cutoff]:
+            del self._items[item.id]
+
+    def add(self, value: str, tags: list[str] | None = None) -> WorkingItem:
+        self._expire()
+        item = WorkingItem(id=f"wm_{next(self._ids)}", value=value, tags=list(tags or []))
+        self._items[item.id] = item
+        while len(self._items) > self.capacity:
+            del self._items[next(iter(self._items))]
+        return item
+
+    def items(self) -> list[WorkingItem]:
+        self._expire()
+        return list(self._items.values())
+
+    def get(self, item_id: str) -> WorkingItem | None:
+        self._expire()
+        return self._items.get(item_id)
+
+    def remove(self, item_id: str) -> WorkingItem | None:
+        return self._items.pop(item_id, None)
@@ -0,0 +1,189 @@
+"""Optional gRPC listener for programmatic access (see app/proto/mory.proto)
+Requests are served by the same handlers as the REST API, so validation,
+limits, redaction and permissions behave identically.
+
+Requires grpcio and grpcio-tools (which compiles the proto at startup):
+pip install 'grpcio>=1.60.0' 'grpcio-tools>=1.60.0'
+"""
+
+import json
+import logging
+import sys
+from collections.abc import Callable
+from pathlib import Path
+from typing import Any
+
+from fastapi import HTTPException
+from pydantic import ValidationError
+from sqlalchemy.orm import Session
+
+from .api import memories as memories_api
+from .core.config import settings
+from .models.schemas import MemoryCreate, MemoryResponse, SearchRequest
+
+logger = logging.getLogger(__name__)
+
+PROTO_DIR = Path(__file__).resolve().parent / "proto"
+
+
+def load_protos() -> tuple[Any, Any]:
+    """Compile mory.proto at runtime, returning (messages, services) modules"""
+    import grpc
+
+    if str(PROTO_DIR) not in sys.path:
+        sys.path.append(str(PROTO_DIR))
+    return grpc.protos_and_services("mory.proto")
+
+
+def _status_code(http_status: int):
+    import grpc
+
+    return {
+        403: grpc.StatusCode.PERMISSION_DENIED,
+        404: grpc.StatusCode.NOT_FOUND,
+        409: grpc.StatusCode.FAILED_PRECONDITION,
+        413: grpc.StatusCode.INVALID_ARGUMENT,
+        422: grpc.StatusCode.INVALID_ARGUMENT,
+        429: grpc.StatusCode.RESOURCE_EXHAUSTED,
+    }.get(http_status, grpc.StatusCode.INTERNAL)
+
+
+class MemoryServicer:
+    """Implements mory.v1.MemoryService on top of the REST handlers"""
+
+    def __init__(self, protos: Any, session_factory: Callable[[], Session] | None = None):
+        from .core.database import SessionLocal
+
+        self.protos = protos
+        self.session_factory = session_factory or SessionLocal
+
+    def _memory(self, memory: MemoryResponse):
+        return self.protos.Memory(
+            id=memory.id,
+            namespace=memory.namespace,
+            owner=memory.owner or "",
+            value=memory.value,
+            summary=memory.summary or "",
+            tags=memory.tags,
+            created_at=memory.created_at.isoformat(),
+            updated_at=memory.updated_at.isoformat(),
+            has_embedding=memory.has_embedding,
+            processing_status=memory.processing_status,
+            review_status=memory.review_status,
+            metadata_json=json.dumps(memory.metadata, ensure_ascii=False),
+            source=memory.source or "",
+        )
+
+    async def _call(self, context, handler, *args, **kwargs):
+        """Run a REST handler with a fresh session, mapping errors to gRPC status codes"""
+        db = self.session_factory()
+        try:
+            return await handler(*args, db=db, **kwargs)
+        except HTTPException as e:
+            detail = e.detail if isinstance(e.detail, str) else str(e.detail)
+            await context.abort(_status_code(e.status_code), detail)
+        except ValidationError as e:
+            import grpc
+
+            await context.abort(grpc.StatusCode.INVALID_ARGUMENT, str(e))
+        finally:
+            db.close()
+
+    async def SaveMemory(self, request, context):  # noqa: N802
+        import grpc
+
+        try:
+            metadata = json.loads(request.metadata_json) if request.metadata_json else None
+            memory_data = MemoryCreate(value=request.value, metadata=metadata, source="grpc")
+        except (ValidationError, json.JSONDecodeError) as e:
+            await context.abort(grpc.StatusCode.INVALID_ARGUMENT, str(e))
+
+        memory = await self._call(
+            context,
+            memories_api.save_memory,
+            memory_data,
+            namespace=request.namespace or settings.namespace,
+            agent_id=request.agent_id or settings.agent_id,
+            x_mory_tool=None,
+        )
+        return self._memory(memory)
+
+    async def GetMemory(self, request, context):  # noqa: N802
+        memory = await self._call(
+            context,
+            memories_api.get_memory,
+            request.id,
+            namespace=request.namespace or settings.namespace,
+            agent_id=settings.agent_id,
+        )
+        return self._memory(memory)
+
+    async def ListMemories(self, request, context):  # noqa: N802
+        result = await self._call(
+            context,
+            memories_api.list_memories,
+            limit=request.limit or 100,
+            offset=request.offset,
+            include_full_text=True,
+            owner=None,
+            include_pending=request.include_pending,
+            include_archived=request.include_archived,
+            namespace=request.namespace or settings.namespace,
+        )
+        return self.protos.ListMemoriesResponse(
+            memories=[self._memory(memory) for memory in result.memories], total=result.total
+        )
+
+    async def DeleteMemory(self, request, context):  # noqa: N802
+        await self._call(
+            context,
+            memories_api.delete_memory,
+            request.id,
+            namespace=request.namespace or settings.namespace,
+            agent_id=request.agent_id or settings.agent_id,
+        )
+        return self.protos.DeleteMemoryResponse(deleted_id=request.id)
+
+    async def Search(self, request, context):  # noqa: N802
+        import grpc
+
+        try:
+            search_request = SearchRequest(
+                query=request.query,
+                search_type=request.search_type or "hybrid",
+                limit=request.limit or 20,
+                tags=list(request.tags) or None,
+                include_pending=request.include_pending,
+                include_archived=request.include_archived,
+            )
+        except ValidationError as e:
+            await context.abort(grpc.StatusCode.INVALID_ARGUMENT, str(e))
+
+        response = await self._call(
+            context,
+            memories_api.search_memories,
+            search_request,
+            namespace=request.namespace or settings.namespace,
+            agent_id=settings.agent_id,
+        )
+        for result in response.results:
+            yield self.protos.SearchResult(
+                memory=self._memory(result.memory),
+                score=result.score,
+                search_type=result.search_type,
+            )
+
+
+async def start_grpc_server(
+    port: int, host: str | None = None, session_factory: Callable[[], Session] | None = None
+):
+    """Start the gRPC listener; returns the running grpc.aio.Server"""
+    import grpc
+
+    protos, services = load_protos()
+    server = grpc.aio.server()
+    services.add_MemoryServiceServicer_to_server(MemoryServicer(protos, session_factory), server)
+    bound_port = server.add_insecure_port(f"{host or settings.host}:{port}")
+    await server.start()
+    logger.info(f"🔌 gRPC listening on {host or settings.host}:{bound_port}")
+    return server
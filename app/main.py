@@ -2,14 +2,38 @@
 Personal Memory Server with REST API
 """
 
-from fastapi import FastAPI
+import logging
+import time
+
+from fastapi import FastAPI, Request
 from fastapi.middleware.cors import CORSMiddleware
+from fastapi.responses import JSONResponse
+from sqlalchemy.exc import OperationalError
 
+from .api.bulk import router as bulk_router
+from .api.categories import router as categories_router
 from .api.dashboard import router as dashboard_router
 from .api.health import router as health_router
 from .api.memories import router as memories_router
-from .core.config import settings
-from .core.database import create_tables
+from .api.metrics import router as metrics_router
+from .api.operations import router as operations_router
+from .api.sync import router as sync_router
+from .api.v1 import router as v1_router
+from .core.config import ConfigWatcher, settings
+from .core.database import SessionLocal, checkpoint_and_close, create_tables, is_busy_error
+from .core.diagnostics import format_report, run_config_checks
+from .core.instance_lock import InstanceLock
+from .core.lifecycle import InFlightTracker
+from .core.logging_config import new_request_id, request_id_var, setup_logging
+from .core.metrics import metrics
+from .core.tracing import trace_recorder
+from .services.file_store import file_store
+from .services.jobs import scheduler
+from .services.stats import search_counter
+from .services.webhooks import webhook_dispatcher
+
+setup_logging()
+logger = logging.getLogger(__name__)
 
 # Create FastAPI application
 app = FastAPI(
@@ -29,29 +53,165 @@ app.add_middleware(
     allow_headers=["*"],
 )
 
+# In-flight HTTP requests, drained before the database is closed
+in_flight = InFlightTracker()
+
+# Guards the data directory against a second server process
+instance_lock = InstanceLock(settings.data_dir)
+
+
+@app.middleware("http")
+async def request_id_middleware(request: Request, call_next):
+    """Propagate X-Request-ID into log records and the response"""
+    if in_flight.draining:
+        return JSONResponse(status_code=503, content={"detail": "Server is shutting down"})
+
+    request_id = request.headers.get("X-Request-ID") or new_request_id()
+    token = request_id_var.set(request_id)
+    # Debug mode records a per-request timing breakdown
+    trace_token = (
+        trace_recorder.start(request_id, request.method, request.url.path)
+        if settings.debug
+        else None
+    )
+    status_code = 500
+    start_time = time.perf_counter()
+    try:
+        with in_flight.track():
+            response = await call_next(request)
+        status_code = response.status_code
+    finally:
+        if trace_token is not None:
+            trace_recorder.finish(trace_token, status_code)
+        request_id_var.reset(token)
+        _record_request_metrics(request, status_code, time.perf_counter() - start_time)
+    response.headers["X-Request-ID"] = request_id
+    return response
+
+
+@app.exception_handler(OperationalError)
+async def database_busy_handler(request: Request, exc: OperationalError):
+    """Answer 503 + Retry-After when SQLite stays locked, so clients can retry"""
+    if not is_busy_error(exc):
+        raise exc
+    logger.warning(f"Database busy for {request.method} {request.url.path}: {exc.orig}")
+    return JSONResponse(
+        status_code=503,
+        content={"detail": "Database is busy, please retry"},
+        headers={"Retry-After": "1"},
+    )
+
+
+def _record_request_metrics(request: Request, status_code: int, elapsed: float) -> None:
+    """Count requests per route template and per MCP tool"""
+    route = request.scope.get("route")
+    path = getattr(route, "path", "unmatched")
+    metrics.inc(
+        "mory_http_requests_total",
+        {"method": request.method, "route": path, "status": str(status_code)},
+    )
+    metrics.observe("mory_http_request_duration_seconds", elapsed, {"route": path})
+
+    tool = request.headers.get("X-Mory-Tool")
+    if tool:
+        metrics.inc("mory_tool_calls_total", {"tool": tool})
+        if status_code >= 400:
+            metrics.inc("mory_tool_errors_total", {"tool": tool})
+
+
+# Watches .env for tunable setting changes while the server runs
+config_watcher = ConfigWatcher(".env", interval=settings.config_reload_interval)
+
 # Include routers
 app.include_router(health_router, prefix="/api", tags=["health"])
 app.include_router(memories_router, prefix="/api", tags=["memories"])
-app.include_router(dashboard_router, tags=["dashboard"])
+app.include_router(categories_router, prefix="/api", tags=["categories"])
+app.include_router(bulk_router, prefix="/api", tags=["bulk"])
+app.include_router(operations_router, prefix="/api", tags=["operations"])
+app.include_router(sync_router, prefix="/api", tags=["sync"])
+app.include_router(v1_router, prefix="/v1", tags=["v1"])
+if settings.dashboard_enabled:
+    app.include_router(dashboard_router, tags=["dashboard"])
+if settings.metrics_enabled:
+    app.include_router(metrics_router, tags=["metrics"])
+
+
+# Optional gRPC listener, started when MORY_GRPC_PORT is set
+grpc_server = None
 
 
 @app.on_event("startup")
 async def startup_event():
     """Initialize application on startup"""
+    # Fail fast on broken configuration instead of limping along
+    failures = [result for result in run_config_checks() if result.is_error]
+    if failures:
+        raise RuntimeError(
+            "Invalid configuration, refusing to start:\n" + format_report(failures)
+        )
+
+    # Refuse to share the data directory with another running server
+    if not settings.allow_multiple_instances:
+        instance_lock.acquire()
+
     # Create database tables
     create_tables()
 
-    print(f"🚀 Mory Server starting on {settings.host}:{settings.port}")
-    print(f"📊 Database: {settings.sqlite_url}")
-    print(f"🔍 Semantic Search: {'Enabled' if settings.is_semantic_available else 'Disabled'}")
-    print(f"📝 Obsidian: {'Configured' if settings.obsidian_vault_path else 'Not configured'}")
-    print(f"🌐 API Documentation: http://{settings.host}:{settings.port}/docs")
+    # Files backend: pick up edits made to the markdown files while the server was down
+    store = file_store()
+    if store is not None:
+        db = SessionLocal()
+        try:
+            await store.sync_to_database(db)
+        finally:
+            db.close()
+
+    if settings.grpc_port:
+        global grpc_server
+        try:
+            from .grpc_server import start_grpc_server
+
+            grpc_server = await start_grpc_server(settings.grpc_port)
+        except ImportError:
+            logger.warning("MORY_GRPC_PORT is set but grpcio/grpcio-tools are not installed")
+
+    logger.info(f"🚀 Mory Server starting on {settings.host}:{settings.port}")
+    logger.info(f"📊 Database: {settings.sqlite_url}")
+    logger.info(
+        f"🔍 Semantic Search: {'Enabled' if settings.is_semantic_available else 'Disabled'}"
+    )
+    logger.info(
+        f"📝 Obsidian: {'Configured' if settings.obsidian_vault_path else 'Not configured'}"
+    )
+    logger.info(f"🌐 API Documentation: http://{settings.host}:{settings.port}/docs")
+
+    config_watcher.start()
+
+    scheduler.configure(settings.jobs)
+    scheduler.start()
 
 
 @app.on_event("shutdown")
 async def shutdown_event():
     """Cleanup on application shutdown"""
-    print("🛑 Mory Server shutting down")
+    logger.info("🛑 Mory Server shutting down")
+    await config_watcher.stop()
+    await scheduler.stop()
+
+    # Ordered shutdown: stop accepting, drain handlers, then flush and close storage
+    if grpc_server is not None:
+        await grpc_server.stop(settings.shutdown_timeout)
+    if not await in_flight.drain(settings.shutdown_timeout):
+        logger.warning(f"{in_flight.count} request(s) still running after shutdown timeout")
+    await webhook_dispatcher.drain(settings.shutdown_timeout)
+    db = SessionLocal()
+    try:
+        search_counter.flush(db)
+    finally:
+        db.close()
+    checkpoint_and_close()
+    logger.info("Database checkpointed and closed")
+    instance_lock.release()
 
 
 @app.get("/")
@@ -66,7 +226,84 @@ async def root():
     }
 
 
-if __name__ == "__main__":
+def main() -> int:
+    """Command line entry point for mory-server"""
+    import argparse
+
+    parser = argparse.ArgumentParser(description="Mory Server - Personal Memory Server")
+    parser.add_argument(
+        "--validate-config",
+        action="store_true",
+        help="Check configuration, print a report and exit",
+    )
+    parser.add_argument(
+        "--debug",
+        action="store_true",
+        help="Record per-request timing breakdowns (see /api/diagnostics)",
+    )
+    parser.add_argument(
+        "--force",
+        action="store_true",
+        help="Start even if another server holds the data directory lock",
+    )
+    parser.add_argument(
+        "--ping-openai",
+        action="store_true",
+        help="With --validate-config, verify the OpenAI API key with a live request",
+    )
+    subparsers = parser.add_subparsers(dest="command")
+    subparsers.add_parser("init", help="Interactively create a .env configuration")
+    install_parser = subparsers.add_parser(
+        "install-claude", help="Register Mory in the Claude Desktop config"
+    )
+    install_parser.add_argument("--config", help="Path to claude_desktop_config.json")
+    args = parser.parse_args()
+
+    if args.command == "init":
+        from .core.wizard import SetupWizard
+
+        return SetupWizard().run()
+
+    if args.command == "install-claude":
+        from pathlib import Path
+
+        from .core.claude_desktop import install_server
+
+        try:
+            path, backup = install_server(
+                Path(args.config) if args.config else None, port=settings.port
+            )
+        except (OSError, ValueError) as e:
+            print(f"❌ Failed to update Claude Desktop config: {e}")
+            return 1
+        if backup:
+            print(f"📁 Original config backed up to: {backup}")
+        print(f"✅ Mory registered in {path}")
+        print("   Restart Claude Desktop to load the server")
+        return 0
+
+    if args.validate_config:
+        results = run_config_checks(live=args.ping_openai)
+        print(format_report(results))
+        return 1 if any(result.is_error for result in results) else 0
+
+    import os
+
+    # Flags are exported to the environment so the reloader's worker process inherits them
+    if args.debug:
+        os.environ["MORY_DEBUG"] = "true"
+        settings.debug = True
+    if args.force:
+        os.environ["MORY_ALLOW_MULTIPLE_INSTANCES"] = "true"
+        settings.allow_multiple_instances = True
+
     import uvicorn
 
     uvicorn.run("app.main:app", host=settings.host, port=settings.port, reload=settings.debug)
+    return 0
+
+
+if __name__ == "__main__":
+    import sys
+
+    sys.exit(main())
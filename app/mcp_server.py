@@ -3,22 +3,98 @@ MCP (Model Context Protocol) server implementation for Mory
 Provides memory management tools for Claude Desktop integration via HTTP API
 """
 
+import asyncio
 import json
 import logging
 import os
+import random
+import re
+import time
+import weakref
+from pathlib import Path
 from typing import Any
+from urllib.parse import quote
 
 import httpx
+from jsonschema import Draft202012Validator
 from mcp import types
 from mcp.server import Server
 
+from . import __version__
+from .core.budget import fit_to_budget
+from .core.config import settings
+from .core.fileutil import atomic_write_text
+from .core.i18n import translate
+from .core.lifecycle import InFlightTracker
+from .core.logging_config import new_request_id, request_id_var
+from .core.working_memory import WorkingMemory
+
+# Instructions sent in the MCP handshake so clients know what the tools are for
+SERVER_INSTRUCTIONS = """Mory is a personal memory store that persists information across conversations.
+Use save_memory when the user shares facts, preferences, or decisions worth remembering.
+Use search_memories before answering questions that may depend on what the user told you earlier.
+Use get_memory and list_memories to inspect stored memories by ID or to browse recent ones.
+Saved memories may be pending human review; the user approves them (mory-cli approve)."""
+
 # Initialize MCP server
-mcp_server = Server("mory")
+mcp_server = Server(
+    settings.mcp_server_name,
+    version=__version__,
+    instructions=SERVER_INSTRUCTIONS,
+)
 logger = logging.getLogger(__name__)
 
+# In-flight tool calls, drained on shutdown
+tool_calls = InFlightTracker()
+
 # API base URL from environment
 API_BASE_URL = os.getenv("MORY_API_URL", "http://localhost:8080")
 
+# Retries of a tool call the server rejected with 503 because the database was busy
+BUSY_RETRIES = 3
+
+
+class _NoSession:
+    """Stands in for the MCP session when a tool is called outside a client request"""
+
+
+_no_session = _NoSession()
+
+# Context hints from set_context, per MCP session; they go away with the session
+session_contexts: weakref.WeakKeyDictionary[Any, str] = weakref.WeakKeyDictionary()
+
+# Working-memory notes, per MCP session as well
+working_memories: weakref.WeakKeyDictionary[Any, WorkingMemory] = weakref.WeakKeyDictionary()
+
+
+def _current_session() -> Any:
+    try:
+        return mcp_server.request_context.session
+    except LookupError:
+        return _no_session
+
+
+def _working_memory() -> WorkingMemory:
+    session = _current_session()
+    if session not in working_memories:
+        working_memories[session] = WorkingMemory(ttl=settings.working_memory_ttl)
+    return working_memories[session]
+
+
+class BusyRetryTransport(httpx.AsyncHTTPTransport):
+    """Retry requests answered with 503 + Retry-After, with jitter"""
+
+    async def handle_async_request(self, request: httpx.Request) -> httpx.Response:
+        for attempt in range(BUSY_RETRIES):
+            response = await super().handle_async_request(request)
+            if response.status_code != 503 or "Retry-After" not in response.headers:
+                return response
+            await response.aclose()
+            delay = float(response.headers["Retry-After"]) * (1 + random.random()) / 2
+            logger.warning(f"Server busy, retrying {request.url.path} in {delay:.2f}s")
+            await asyncio.sleep(delay * (attempt + 1))
+        return await super().handle_async_request(request)
+
 
 @mcp_server.list_tools()
 async def handle_list_tools() -> list[types.Tool]:
@@ -48,10 +124,112 @@ async def handle_list_tools() -> list[types.Tool]:
                         "description": "Tags for categorization and search",
                         "default": [],
                     },
+                    "namespace": {
+                        "type": "string",
+                        "description": "Memory namespace/profile (defaults to MORY_NAMESPACE)",
+                    },
+                    "agent_id": {
+                        "type": "string",
+                        "description": "Agent saving the memory (defaults to MORY_AGENT_ID)",
+                    },
+                    "remind_at": {
+                        "type": "string",
+                        "description": "ISO 8601 time to bring this memory back up, e.g. 2025-06-02T09:00:00+09:00 (optional)",
+                    },
+                    "metadata": {
+                        "type": "object",
+                        "description": 'Structured fields, e.g. {"location": "Kyoto", "people": ["Alice"], "source_url": "..."} (optional)',
+                    },
+                    "confidence": {
+                        "type": "number",
+                        "description": "How sure you are (0.0-1.0). Use < 1.0 for guesses or inferences the user has not confirmed",
+                        "minimum": 0,
+                        "maximum": 1,
+                    },
+                    "template": {
+                        "type": "string",
+                        "description": "Structured memory type such as contact, decision, bookmark or credential_reference; put its fields in metadata (see list_templates) (optional)",
+                    },
+                    "content_type": {
+                        "type": "string",
+                        "description": "text, markdown, json, code or code:<language> such as code:python; detected from the value when omitted. Code and JSON are returned as fenced blocks in rendered",
+                        "pattern": "^(text|markdown|json|code(:[\\w+#-]+)?)$",
+                    },
                 },
                 "required": ["category", "value"],
             },
         ),
+        types.Tool(
+            name="save_url",
+            description="Save a web page as a bookmark memory: the page is fetched, its main text extracted and summarized, and the title, summary and URL stored so the link can be found by search later",
+            inputSchema={
+                "type": "object",
+                "properties": {
+                    "url": {
+                        "type": "string",
+                        "description": "http(s) URL of the page",
+                        "pattern": "^https?://",
+                    },
+                    "namespace": {
+                        "type": "string",
+                        "description": "Memory namespace/profile (defaults to MORY_NAMESPACE)",
+                    },
+                    "agent_id": {
+                        "type": "string",
+                        "description": "Agent saving the memory (defaults to MORY_AGENT_ID)",
+                    },
+                },
+                "required": ["url"],
+            },
+        ),
+        types.Tool(
+            name="suggest_category",
+            description="Recommend existing categories for a new memory, ranked by similarity to the category descriptions. Call before save_memory and prefer a suggested category over inventing a new one.",
+            inputSchema={
+                "type": "object",
+                "properties": {
+                    "value": {
+                        "type": "string",
+                        "description": "Content of the memory to be saved",
+                    },
+                    "limit": {
+                        "type": "integer",
+                        "description": "Number of suggestions",
+                        "default": 3,
+                        "minimum": 1,
+                        "maximum": 20,
+                    },
+                    "namespace": {
+                        "type": "string",
+                        "description": "Memory namespace/profile (defaults to MORY_NAMESPACE)",
+                    },
+                },
+                "required": ["value"],
+            },
+        ),
+        types.Tool(
+            name="describe_category",
+            description="Describe what belongs in a category, so suggest_category can route new memories to it",
+            inputSchema={
+                "type": "object",
+                "properties": {
+                    "name": {
+                        "type": "string",
+                        "description": "Category name",
+                    },
+                    "description": {
+                        "type": "string",
+                        "description": "What memories in this category are about",
+                        "maxLength": 1000,
+                    },
+                    "namespace": {
+                        "type": "string",
+                        "description": "Memory namespace/profile (defaults to MORY_NAMESPACE)",
+                    },
+                },
+                "required": ["name", "description"],
+            },
+        ),
         types.Tool(
             name="get_memory",
             description="Retrieve a specific memory by key",
@@ -66,6 +244,10 @@ async def handle_list_tools() -> list[types.Tool]:
                         "type": "string",
                         "description": "Filter by category (optional)",
                     },
+                    "namespace": {
+                        "type": "string",
+                        "description": "Memory namespace/profile (defaults to MORY_NAMESPACE)",
+                    },
                 },
                 "required": ["key"],
             },
@@ -93,6 +275,19 @@ async def handle_list_tools() -> list[types.Tool]:
                         "default": 0,
                         "minimum": 0,
                     },
+                    "namespace": {
+                        "type": "string",
+                        "description": "Memory namespace/profile (defaults to MORY_NAMESPACE)",
+                    },
+                    "owner": {
+                        "type": "string",
+                        "description": "Only return memories saved by this agent (optional)",
+                    },
+                    "include_archived": {
+                        "type": "boolean",
+                        "description": "Include archived memories",
+                        "default": False,
+                    },
                 },
             },
         ),
@@ -115,6 +310,25 @@ async def handle_list_tools() -> list[types.Tool]:
                         "items": {"type": "string"},
                         "description": "Filter by tags (optional)",
                     },
+                    "metadata": {
+                        "type": "object",
+                        "additionalProperties": {"type": "string"},
+                        "description": 'Match metadata fields, e.g. {"person": "Alice"} (optional)',
+                    },
+                    "source": {
+                        "type": "string",
+                        "description": 'Source type such as "mcp", "obsidian" or "import", or an exact source (optional)',
+                    },
+                    "advanced": {
+                        "type": "boolean",
+                        "description": 'Interpret AND/OR/NOT, "phrases", prefix* and NEAR(a b, 5) in the query (optional)',
+                        "default": False,
+                    },
+                    "language": {
+                        "type": "string",
+                        "enum": ["ja", "zh", "ko", "en"],
+                        "description": "Only memories written in this language (optional)",
+                    },
                     "limit": {
                         "type": "integer",
                         "description": "Maximum number of results",
@@ -122,146 +336,1536 @@ async def handle_list_tools() -> list[types.Tool]:
                         "minimum": 1,
                         "maximum": 50,
                     },
+                    "namespace": {
+                        "type": "string",
+                        "description": "Memory namespace/profile (defaults to MORY_NAMESPACE)",
+                    },
+                    "owner": {
+                        "type": "string",
+                        "description": "Only return memories saved by this agent (optional)",
+                    },
+                    "include_pending": {
+                        "type": "boolean",
+                        "description": "Include memories awaiting approval",
+                        "default": False,
+                    },
+                    "include_archived": {
+                        "type": "boolean",
+                        "description": "Include archived memories",
+                        "default": False,
+                    },
                 },
                 "required": ["query"],
             },
         ),
-    ]
-
-
-@mcp_server.call_tool()
-async def handle_call_tool(name: str, arguments: dict[str, Any]) -> list[types.TextContent]:
-    """Execute MCP tool calls via HTTP API"""
-    try:
-        async with httpx.AsyncClient() as client:
-            if name == "save_memory":
-                return await _save_memory(arguments, client)
-            elif name == "get_memory":
-                return await _get_memory(arguments, client)
-            elif name == "list_memories":
-                return await _list_memories(arguments, client)
-            elif name == "search_memories":
-                return await _search_memories(arguments, client)
-            else:
-                raise ValueError(f"Unknown tool: {name}")
-
-    except Exception as e:
-        logger.error(f"Tool {name} failed: {str(e)}")
-        return [types.TextContent(type="text", text=f"Error: {str(e)}")]
-
-
-async def _save_memory(
-    arguments: dict[str, Any], client: httpx.AsyncClient
-) -> list[types.TextContent]:
-    """Save or update a memory via HTTP API"""
-    try:
-        # Prepare request data
-        memory_data = {
-            "category": arguments["category"],
-            "key": arguments.get("key"),
-            "value": arguments["value"],
-            "tags": arguments.get("tags", []),
-        }
-
-        # Make HTTP request to FastAPI server
-        response = await client.post(
-            f"{API_BASE_URL}/api/memories",
-            json=memory_data,
-            headers={"Content-Type": "application/json"},
-        )
-        response.raise_for_status()
-
-        result = response.json()
-        return [types.TextContent(type="text", text=json.dumps(result, indent=2))]
-
-    except httpx.HTTPStatusError as e:
-        error_detail = e.response.text if e.response else str(e)
-        raise ValueError(f"HTTP {e.response.status_code}: {error_detail}") from e
-    except Exception as e:
-        raise ValueError(f"Failed to save memory: {str(e)}") from e
-
-
-async def _get_memory(
-    arguments: dict[str, Any], client: httpx.AsyncClient
-) -> list[types.TextContent]:
-    """Retrieve a specific memory by key via HTTP API"""
-    try:
-        key = arguments["key"]
-        category = arguments.get("category")
-
-        # Build query parameters
-        params = {}
-        if category:
-            params["category"] = category
-
-        # Make HTTP request
-        response = await client.get(f"{API_BASE_URL}/api/memories/{key}", params=params)
-        response.raise_for_status()
-
-        result = response.json()
-        return [types.TextContent(type="text", text=json.dumps(result, indent=2))]
-
-    except httpx.HTTPStatusError as e:
-        if e.response.status_code == 404:
-            error_msg = f"Memory with key '{key}'"
-            if arguments.get("category"):
-                error_msg += f" in category '{arguments['category']}'"
-            error_msg += " not found"
-            raise ValueError(error_msg) from e
-        else:
-            error_detail = e.response.text if e.response else str(e)
-            raise ValueError(f"HTTP {e.response.status_code}: {error_detail}") from e
-    except Exception as e:
-        raise ValueError(f"Failed to get memory: {str(e)}") from e
-
-
-async def _list_memories(
-    arguments: dict[str, Any], client: httpx.AsyncClient
-) -> list[types.TextContent]:
-    """List memories with optional filtering via HTTP API"""
-    try:
-        # Build query parameters
-        params = {}
-        if arguments.get("category"):
-            params["category"] = arguments["category"]
-        if arguments.get("limit"):
-            params["limit"] = arguments["limit"]
-        if arguments.get("offset"):
-            params["offset"] = arguments["offset"]
-
-        # Make HTTP request
-        response = await client.get(f"{API_BASE_URL}/api/memories", params=params)
-        response.raise_for_status()
-
-        result = response.json()
-        return [types.TextContent(type="text", text=json.dumps(result, indent=2))]
-
-    except httpx.HTTPStatusError as e:
-        error_detail = e.response.text if e.response else str(e)
-        raise ValueError(f"HTTP {e.response.status_code}: {error_detail}") from e
-    except Exception as e:
-        raise ValueError(f"Failed to list memories: {str(e)}") from e
-
-
-async def _search_memories(
-    arguments: dict[str, Any], client: httpx.AsyncClient
-) -> list[types.TextContent]:
-    """Search memories using full-text search via HTTP API"""
-    try:
-        # Prepare search request data
-        search_data = {
-            "query": arguments["query"],
-            "category": arguments.get("category"),
-            "tags": arguments.get("tags", []),
-            "limit": arguments.get("limit", 10),
-        }
-
-        # Make HTTP request
-        response = await client.post(
-            f"{API_BASE_URL}/api/memories/search",
-            json=search_data,
-            headers={"Content-Type": "application/json"},
-        )
+        types.Tool(
+            name="list_templates",
+            description="List memory templates (contact, decision, bookmark, credential_reference, ...) and the metadata fields each one expects",
+            inputSchema={"type": "object", "properties": {}},
+        ),
+        types.Tool(
+            name="surface_memory",
+            description="Bring up memories the user has not seen for a while (spaced repetition). Long-unseen, rarely read memories are most likely; surfaced ones count as read.",
+            inputSchema={
+                "type": "object",
+                "properties": {
+                    "count": {
+                        "type": "integer",
+                        "description": "Number of memories to surface",
+                        "default": 3,
+                        "minimum": 1,
+                        "maximum": 20,
+                    },
+                    "tag": {
+                        "type": "string",
+                        "description": "Only review memories with this tag (optional)",
+                    },
+                    "min_idle_days": {
+                        "type": "number",
+                        "description": "Skip memories read or changed within this many days",
+                        "default": 1,
+                        "minimum": 0,
+                    },
+                    "namespace": {
+                        "type": "string",
+                        "description": "Memory namespace/profile (defaults to MORY_NAMESPACE)",
+                    },
+                },
+            },
+        ),
+        types.Tool(
+            name="get_recent_memories",
+            description="Memories created or updated recently, grouped by category. Use for recaps such as what was learned today.",
+            inputSchema={
+                "type": "object",
+                "properties": {
+                    "window": {
+                        "type": "string",
+                        "description": 'How far back to look, e.g. "24h", "7d" or "2w"',
+                        "default": "24h",
+                    },
+                    "limit": {
+                        "type": "integer",
+                        "description": "Maximum number of memories",
+                        "default": 100,
+                        "minimum": 1,
+                        "maximum": 500,
+                    },
+                    "namespace": {
+                        "type": "string",
+                        "description": "Memory namespace/profile (defaults to MORY_NAMESPACE)",
+                    },
+                },
+            },
+        ),
+        types.Tool(
+            name="suggest_keys",
+            description="Complete a half-remembered memory: returns memory keys (IDs) whose summary starts with the prefix, and matching tags",
+            inputSchema={
+                "type": "object",
+                "properties": {
+                    "prefix": {
+                        "type": "string",
+                        "description": "Beginning of a summary or tag",
+                    },
+                    "limit": {
+                        "type": "integer",
+                        "description": "Maximum suggestions of each kind",
+                        "default": 10,
+                        "minimum": 1,
+                        "maximum": 50,
+                    },
+                    "namespace": {
+                        "type": "string",
+                        "description": "Memory namespace/profile (defaults to MORY_NAMESPACE)",
+                    },
+                },
+                "required": ["prefix"],
+            },
+        ),
+        types.Tool(
+            name="save_working_memory",
+            description="Keep a short-term note for the current task (intermediate results, plans, things to check). Working memory is not saved to the long-term store and disappears when the session ends; use promote_to_long_term for notes worth keeping.",
+            inputSchema={
+                "type": "object",
+                "properties": {
+                    "value": {
+                        "type": "string",
+                        "description": "The note",
+                    },
+                    "tags": {
+                        "type": "array",
+                        "items": {"type": "string"},
+                        "description": "Tags, used when the note is promoted",
+                        "default": [],
+                    },
+                },
+                "required": ["value"],
+            },
+        ),
+        types.Tool(
+            name="list_working_memory",
+            description="List the working-memory notes of the current session, oldest first",
+            inputSchema={"type": "object", "properties": {}},
+        ),
+        types.Tool(
+            name="promote_to_long_term",
+            description="Save a working-memory note as a regular memory and remove it from working memory",
+            inputSchema={
+                "type": "object",
+                "properties": {
+                    "id": {
+                        "type": "string",
+                        "description": "Working-memory note ID (wm_...)",
+                    },
+                    "category": {
+                        "type": "string",
+                        "description": "Memory category for organization",
+                    },
+                    "tags": {
+                        "type": "array",
+                        "items": {"type": "string"},
+                        "description": "Tags (defaults to the note's tags)",
+                    },
+                    "namespace": {
+                        "type": "string",
+                        "description": "Memory namespace/profile (defaults to MORY_NAMESPACE)",
+                    },
+                    "agent_id": {
+                        "type": "string",
+                        "description": "Agent saving the memory (defaults to MORY_AGENT_ID)",
+                    },
+                },
+                "required": ["id", "category"],
+            },
+        ),
+        types.Tool(
+            name="set_context",
+            description="Tell Mory what the user is working on (e.g. \"working on project mory\"). Until changed, searches in this session rank memories whose category or tags match the hint higher. An empty context clears it.",
+            inputSchema={
+                "type": "object",
+                "properties": {
+                    "context": {
+                        "type": "string",
+                        "description": "Short description of the current task or project",
+                        "maxLength": 500,
+                    },
+                },
+                "required": ["context"],
+            },
+        ),
+        types.Tool(
+            name="report_search_result",
+            description="Tell Mory which search result answered the question, using the query_id returned by search_memories (only present when the query log is enabled)",
+            inputSchema={
+                "type": "object",
+                "properties": {
+                    "query_id": {
+                        "type": "integer",
+                        "description": "query_id from the search_memories response",
+                    },
+                    "memory_id": {
+                        "type": "string",
+                        "description": "ID of the memory that was used",
+                    },
+                    "namespace": {
+                        "type": "string",
+                        "description": "Memory namespace/profile (defaults to MORY_NAMESPACE)",
+                    },
+                },
+                "required": ["query_id", "memory_id"],
+            },
+        ),
+        types.Tool(
+            name="get_search_analytics",
+            description="Most frequent search queries and queries that found nothing, from the query log. Zero-result queries point at memories worth adding.",
+            inputSchema={
+                "type": "object",
+                "properties": {
+                    "days": {
+                        "type": "integer",
+                        "description": "Period to analyze, in days",
+                        "default": 30,
+                        "minimum": 1,
+                    },
+                    "limit": {
+                        "type": "integer",
+                        "description": "Queries per list",
+                        "default": 10,
+                        "minimum": 1,
+                        "maximum": 100,
+                    },
+                    "namespace": {
+                        "type": "string",
+                        "description": "Memory namespace/profile (defaults to MORY_NAMESPACE)",
+                    },
+                },
+            },
+        ),
+        types.Tool(
+            name="search_operations",
+            description="Search the history of saved, updated and deleted memories, including content that no longer exists. Use for questions like when something was deleted or what a memory said before an edit.",
+            inputSchema={
+                "type": "object",
+                "properties": {
+                    "query": {
+                        "type": "string",
+                        "description": "Words from the memory content or its ID",
+                    },
+                    "operation": {
+                        "type": "string",
+                        "enum": ["saved", "updated", "deleted", "imported"],
+                        "description": "Only this operation (optional)",
+                    },
+                    "since": {
+                        "type": "string",
+                        "description": "ISO 8601 UTC time; only entries at or after it (optional)",
+                    },
+                    "until": {
+                        "type": "string",
+                        "description": "ISO 8601 UTC time; only entries at or before it (optional)",
+                    },
+                    "limit": {
+                        "type": "integer",
+                        "description": "Maximum number of results",
+                        "default": 10,
+                        "minimum": 1,
+                        "maximum": 100,
+                    },
+                },
+                "required": ["query"],
+            },
+        ),
+        types.Tool(
+            name="list_pending",
+            description="List memories awaiting human approval before they become permanent",
+            inputSchema={
+                "type": "object",
+                "properties": {
+                    "limit": {
+                        "type": "integer",
+                        "description": "Maximum number of pending memories to return",
+                        "default": 20,
+                        "minimum": 1,
+                        "maximum": 100,
+                    },
+                    "namespace": {
+                        "type": "string",
+                        "description": "Memory namespace/profile (defaults to MORY_NAMESPACE)",
+                    },
+                },
+            },
+        ),
+        types.Tool(
+            name="approve_memory",
+            description="Approve a pending memory so it becomes permanent (only when MORY_TOOL_APPROVAL is enabled and the user confirms)",
+            inputSchema={
+                "type": "object",
+                "properties": {
+                    "memory_id": {
+                        "type": "string",
+                        "description": "ID of the pending memory",
+                    },
+                },
+                "required": ["memory_id"],
+            },
+        ),
+        types.Tool(
+            name="reject_memory",
+            description="Reject and discard a pending memory",
+            inputSchema={
+                "type": "object",
+                "properties": {
+                    "memory_id": {
+                        "type": "string",
+                        "description": "ID of the pending memory",
+                    },
+                },
+                "required": ["memory_id"],
+            },
+        ),
+        types.Tool(
+            name="verify_memory",
+            description="Mark a tentative memory as verified once the user confirms it is correct",
+            inputSchema={
+                "type": "object",
+                "properties": {
+                    "memory_id": {
+                        "type": "string",
+                        "description": "ID of the memory the user confirmed",
+                    },
+                },
+                "required": ["memory_id"],
+            },
+        ),
+        types.Tool(
+            name="get_due_reminders",
+            description="List memories whose reminder time has passed. Call at the start of a session and mention them to the user.",
+            inputSchema={
+                "type": "object",
+                "properties": {
+                    "include_upcoming_hours": {
+                        "type": "integer",
+                        "description": "Also include reminders due within this many hours (up to 720, 30 days)",
+                        "default": 0,
+                        "minimum": 0,
+                        "maximum": 720,
+                    },
+                    "namespace": {
+                        "type": "string",
+                        "description": "Memory namespace/profile (defaults to MORY_NAMESPACE)",
+                    },
+                },
+            },
+        ),
+        types.Tool(
+            name="acknowledge_reminder",
+            description="Mark a reminder as handled once it has been brought up with the user",
+            inputSchema={
+                "type": "object",
+                "properties": {
+                    "memory_id": {
+                        "type": "string",
+                        "description": "ID of the memory whose reminder was handled",
+                    },
+                },
+                "required": ["memory_id"],
+            },
+        ),
+        types.Tool(
+            name="summarize_category",
+            description="Condense all memories with a tag into one summary memory and archive the originals. Preview with dry_run first; after the user agrees, call again with dry_run=false and the confirmation_token from the preview.",
+            inputSchema={
+                "type": "object",
+                "properties": {
+                    "tag": {
+                        "type": "string",
+                        "description": "Tag (category) whose memories should be condensed",
+                    },
+                    "dry_run": {
+                        "type": "boolean",
+                        "description": "Only preview the summary; nothing is saved or archived",
+                        "default": True,
+                    },
+                    "older_than_days": {
+                        "type": "integer",
+                        "description": "Only include memories not updated for this many days",
+                        "minimum": 0,
+                    },
+                    "confirmation_token": {
+                        "type": "string",
+                        "description": "Token from the preview; required when dry_run is false",
+                    },
+                    "namespace": {
+                        "type": "string",
+                        "description": "Memory namespace/profile (defaults to MORY_NAMESPACE)",
+                    },
+                },
+                "required": ["tag"],
+            },
+        ),
+        types.Tool(
+            name="delete_memories",
+            description="Delete every memory matching a category, tag and/or creation date range, e.g. to undo a bad import. Always preview with dry_run first; after the user agrees, call again with dry_run=false and the confirmation_token from the preview.",
+            inputSchema={
+                "type": "object",
+                "properties": {
+                    "category": {
+                        "type": "string",
+                        "description": "Category (first tag) of the memories to delete",
+                    },
+                    "tag": {
+                        "type": "string",
+                        "description": "Delete memories carrying this tag",
+                    },
+                    "date_from": {
+                        "type": "string",
+                        "description": "Only memories created at or after this ISO 8601 time",
+                    },
+                    "date_to": {
+                        "type": "string",
+                        "description": "Only memories created at or before this ISO 8601 time",
+                    },
+                    "dry_run": {
+                        "type": "boolean",
+                        "description": "Only count the matching memories; nothing is deleted",
+                        "default": True,
+                    },
+                    "confirmation_token": {
+                        "type": "string",
+                        "description": "Token from the preview; required when dry_run is false",
+                    },
+                    "namespace": {
+                        "type": "string",
+                        "description": "Memory namespace/profile (defaults to MORY_NAMESPACE)",
+                    },
+                },
+            },
+        ),
+        types.Tool(
+            name="move_memories",
+            description="Move every memory matching a category, tag and/or creation date range to another category. Preview with dry_run first; after the user agrees, call again with dry_run=false and the confirmation_token from the preview.",
+            inputSchema={
+                "type": "object",
+                "properties": {
+                    "category": {
+                        "type": "string",
+                        "description": "Category (first tag) of the memories to move",
+                    },
+                    "tag": {
+                        "type": "string",
+                        "description": "Move memories carrying this tag",
+                    },
+                    "date_from": {
+                        "type": "string",
+                        "description": "Only memories created at or after this ISO 8601 time",
+                    },
+                    "date_to": {
+                        "type": "string",
+                        "description": "Only memories created at or before this ISO 8601 time",
+                    },
+                    "new_category": {
+                        "type": "string",
+                        "description": "Category to move them to; it becomes their first tag",
+                    },
+                    "dry_run": {
+                        "type": "boolean",
+                        "description": "Only list the matching memories; nothing is changed",
+                        "default": True,
+                    },
+                    "confirmation_token": {
+                        "type": "string",
+                        "description": "Token from the preview; required when dry_run is false",
+                    },
+                    "namespace": {
+                        "type": "string",
+                        "description": "Memory namespace/profile (defaults to MORY_NAMESPACE)",
+                    },
+                },
+                "required": ["new_category"],
+            },
+        ),
+        types.Tool(
+            name="retag_memories",
+            description="Add and/or remove tags on every memory matching a category, tag and/or creation date range. Preview with dry_run first; after the user agrees, call again with dry_run=false and the confirmation_token from the preview.",
+            inputSchema={
+                "type": "object",
+                "properties": {
+                    "category": {
+                        "type": "string",
+                        "description": "Category (first tag) of the memories to retag",
+                    },
+                    "tag": {
+                        "type": "string",
+                        "description": "Retag memories carrying this tag",
+                    },
+                    "date_from": {
+                        "type": "string",
+                        "description": "Only memories created at or after this ISO 8601 time",
+                    },
+                    "date_to": {
+                        "type": "string",
+                        "description": "Only memories created at or before this ISO 8601 time",
+                    },
+                    "add_tags": {
+                        "type": "array",
+                        "items": {"type": "string"},
+                        "description": "Tags to add",
+                    },
+                    "remove_tags": {
+                        "type": "array",
+                        "items": {"type": "string"},
+                        "description": "Tags to remove",
+                    },
+                    "dry_run": {
+                        "type": "boolean",
+                        "description": "Only list the matching memories; nothing is changed",
+                        "default": True,
+                    },
+                    "confirmation_token": {
+                        "type": "string",
+                        "description": "Token from the preview; required when dry_run is false",
+                    },
+                    "namespace": {
+                        "type": "string",
+                        "description": "Memory namespace/profile (defaults to MORY_NAMESPACE)",
+                    },
+                },
+            },
+        ),
+        types.Tool(
+            name="get_diagnostics",
+            description="Show per-request timing breakdowns recorded when the server runs in debug mode",
+            inputSchema={
+                "type": "object",
+                "properties": {
+                    "limit": {
+                        "type": "integer",
+                        "description": "Number of recent requests to include",
+                        "default": 20,
+                        "minimum": 1,
+                        "maximum": 100,
+                    },
+                },
+            },
+        ),
+        types.Tool(
+            name="get_report",
+            description="Markdown report for periodic self-review: memory growth per month, top categories and tags, embedding coverage, largest memories and recent highlights",
+            inputSchema={
+                "type": "object",
+                "properties": {
+                    "days": {
+                        "type": "integer",
+                        "description": "Period counted as recent, in days",
+                        "default": 30,
+                        "minimum": 1,
+                    },
+                    "namespace": {
+                        "type": "string",
+                        "description": "Memory namespace/profile (defaults to MORY_NAMESPACE)",
+                    },
+                },
+            },
+        ),
+        types.Tool(
+            name="generate_obsidian_canvas",
+            description="Write an Obsidian canvas (.canvas) of the memories in a category or with a tag into the vault: cards grouped by category, linked when their content is similar",
+            inputSchema={
+                "type": "object",
+                "properties": {
+                    "category": {
+                        "type": "string",
+                        "description": "Memories in this category (category or tag is required)",
+                    },
+                    "tag": {
+                        "type": "string",
+                        "description": "Memories with this tag",
+                    },
+                    "name": {
+                        "type": "string",
+                        "description": "Canvas file name without .canvas (defaults to the category or tag)",
+                    },
+                    "limit": {
+                        "type": "integer",
+                        "description": "Maximum memories on the canvas, oldest first",
+                        "default": 50,
+                        "minimum": 1,
+                        "maximum": 200,
+                    },
+                    "namespace": {
+                        "type": "string",
+                        "description": "Memory namespace/profile (defaults to MORY_NAMESPACE)",
+                    },
+                },
+            },
+        ),
+        types.Tool(
+            name="get_metrics",
+            description="Show server metrics: tool call counts, errors, search latency, embedding API calls and database size",
+            inputSchema={"type": "object", "properties": {}},
+        ),
+        types.Tool(
+            name="health_check",
+            description="Run a self-test of the memory server (database, write access, embeddings) and report pass/fail per check",
+            inputSchema={
+                "type": "object",
+                "properties": {
+                    "check_embedding": {
+                        "type": "boolean",
+                        "description": "Also verify the embedding API with a live request",
+                        "default": False,
+                    },
+                },
+            },
+        ),
+    ]
+
+
+# Error codes in failed tool results, so clients can branch without parsing messages
+ERROR_NOT_FOUND = "not_found"
+ERROR_CONFLICT = "conflict"  # Duplicate key, or a state change that no longer applies
+ERROR_CONFIRMATION_REQUIRED = "confirmation_required"  # Preview first, then pass its token
+ERROR_FORBIDDEN = "forbidden"
+ERROR_INVALID_ARGUMENTS = "invalid_arguments"
+ERROR_TOO_LARGE = "too_large"
+ERROR_RATE_LIMITED = "rate_limited"
+ERROR_UNAVAILABLE = "unavailable"  # Server unreachable, busy or shutting down
+ERROR_UNKNOWN_TOOL = "unknown_tool"
+ERROR_INTERNAL = "internal"
+
+_STATUS_ERRORS = {
+    400: ERROR_INVALID_ARGUMENTS,
+    403: ERROR_FORBIDDEN,
+    404: ERROR_NOT_FOUND,
+    409: ERROR_CONFLICT,
+    413: ERROR_TOO_LARGE,
+    422: ERROR_INVALID_ARGUMENTS,
+    428: ERROR_CONFIRMATION_REQUIRED,
+    429: ERROR_RATE_LIMITED,
+    503: ERROR_UNAVAILABLE,
+}
+
+
+class ToolError(ValueError):
+    """Tool failure that does not come from an API response, with its error code"""
+
+    def __init__(self, code: str, message: str):
+        super().__init__(message)
+        self.code = code
+
+
+def error_code(error: BaseException) -> str:
+    """Code for a failed tool call, from the ToolError, HTTP status or connection error
+    behind it (handlers re-raise API errors with a readable message `from` the original)"""
+    cause: BaseException | None = error
+    while cause is not None:
+        if isinstance(cause, ToolError):
+            return cause.code
+        if isinstance(cause, httpx.HTTPStatusError):
+            return _STATUS_ERRORS.get(cause.response.status_code, ERROR_INTERNAL)
+        if isinstance(cause, httpx.TransportError):
+            return ERROR_UNAVAILABLE
+        cause = cause.__cause__
+    return ERROR_INTERNAL
+
+
+def error_result(message: Any, code: str, **extra: Any) -> list[types.TextContent]:
+    """Failed tool call as {"error": message, "code": code, ...}"""
+    result = {"error": str(message), "code": code, **extra}
+    return [types.TextContent(type="text", text=json.dumps(result, indent=2, ensure_ascii=False))]
+
+
+def validate_arguments(schema: dict[str, Any], arguments: dict[str, Any]) -> list[dict[str, str]]:
+    """Check tool arguments against the tool's input schema; one entry per problem"""
+    validator = Draft202012Validator(schema)
+    return [
+        {"field": error.json_path, "rule": str(error.validator), "message": error.message}
+        for error in sorted(validator.iter_errors(arguments), key=lambda e: e.json_path)
+    ]
+
+
+# The SDK's own input validation is turned off so errors come back in our structured form
+@mcp_server.call_tool(validate_input=False)
+async def handle_call_tool(name: str, arguments: dict[str, Any]) -> list[types.TextContent]:
+    """Execute MCP tool calls via HTTP API"""
+    if tool_calls.draining:
+        return error_result(translate("shutting_down"), ERROR_UNAVAILABLE)
+
+    with tool_calls.track():
+        return await _call_tool(name, arguments)
+
+
+async def _call_tool(name: str, arguments: dict[str, Any]) -> list[types.TextContent]:
+    """Dispatch a tool call to its handler"""
+    request_id = new_request_id()
+    request_id_var.set(request_id)
+    start_time = time.perf_counter()
+    logger.info(f"Tool {name} called")
+
+    try:
+        schemas = {tool.name: tool.inputSchema for tool in await handle_list_tools()}
+        errors = validate_arguments(schemas[name], arguments) if name in schemas else []
+        if errors:
+            logger.warning(f"Tool {name} rejected: {len(errors)} invalid argument(s)")
+            message = translate("invalid_arguments", name=name)
+            return error_result(message, ERROR_INVALID_ARGUMENTS, errors=errors)
+
+        # Forward the request ID so API logs can be correlated with tool calls
+        headers = {"X-Request-ID": request_id, "X-Mory-Tool": name}
+        headers["X-Mory-Namespace"] = arguments.get("namespace") or settings.namespace
+        agent_id = arguments.get("agent_id") or settings.agent_id
+        if agent_id:
+            headers["X-Mory-Agent"] = agent_id
+        async with httpx.AsyncClient(headers=headers, transport=BusyRetryTransport()) as client:
+            if name == "save_memory":
+                return await _save_memory(arguments, client)
+            elif name == "save_url":
+                return await _save_url(arguments, client)
+            elif name == "suggest_category":
+                return await _suggest_category(arguments, client)
+            elif name == "describe_category":
+                return await _describe_category(arguments, client)
+            elif name == "get_memory":
+                return await _get_memory(arguments, client)
+            elif name == "list_memories":
+                return await _list_memories(arguments, client)
+            elif name == "search_memories":
+                return await _search_memories(arguments, client)
+            elif name == "list_templates":
+                return await _list_templates(arguments, client)
+            elif name == "surface_memory":
+                return await _surface_memory(arguments, client)
+            elif name == "get_recent_memories":
+                return await _get_recent_memories(arguments, client)
+            elif name == "suggest_keys":
+                return await _suggest_keys(arguments, client)
+            elif name == "save_working_memory":
+                return _save_working_memory(arguments)
+            elif name == "list_working_memory":
+                return _list_working_memory(arguments)
+            elif name == "promote_to_long_term":
+                return await _promote_to_long_term(arguments, client)
+            elif name == "set_context":
+                return _set_context(arguments)
+            elif name == "report_search_result":
+                return await _report_search_result(arguments, client)
+            elif name == "get_search_analytics":
+                return await _get_search_analytics(arguments, client)
+            elif name == "search_operations":
+                return await _search_operations(arguments, client)
+            elif name == "list_pending":
+                return await _list_pending(arguments, client)
+            elif name == "approve_memory":
+                return await _review_memory(arguments, client, "approve")
+            elif name == "reject_memory":
+                return await _review_memory(arguments, client, "reject")
+            elif name == "verify_memory":
+                return await _verify_memory(arguments, client)
+            elif name == "get_due_reminders":
+                return await _get_due_reminders(arguments, client)
+            elif name == "acknowledge_reminder":
+                return await _acknowledge_reminder(arguments, client)
+            elif name == "summarize_category":
+                return await _summarize_category(arguments, client)
+            elif name in BULK_TOOLS:
+                return await _bulk_memories(name, arguments, client)
+            elif name == "get_diagnostics":
+                return await _get_diagnostics(arguments, client)
+            elif name == "get_report":
+                return await _get_report(arguments, client)
+            elif name == "generate_obsidian_canvas":
+                return await _generate_obsidian_canvas(arguments, client)
+            elif name == "get_metrics":
+                return await _get_metrics(arguments, client)
+            elif name == "health_check":
+                return await _health_check(arguments, client)
+            else:
+                raise ToolError(ERROR_UNKNOWN_TOOL, translate("unknown_tool", name=name))
+
+    except Exception as e:
+        code = error_code(e)
+        logger.error(f"Tool {name} failed ({code}): {str(e)}")
+        return error_result(e, code)
+    finally:
+        elapsed_ms = (time.perf_counter() - start_time) * 1000
+        logger.info(f"Tool {name} finished in {elapsed_ms:.1f}ms")
+
+
+async def _save_memory(
+    arguments: dict[str, Any], client: httpx.AsyncClient
+) -> list[types.TextContent]:
+    """Save or update a memory via HTTP API"""
+    try:
+        # Prepare request data
+        memory_data = {
+            "category": arguments["category"],
+            "key": arguments.get("key"),
+            "value": arguments["value"],
+            "tags": arguments.get("tags", []),
+        }
+        if arguments.get("remind_at"):
+            memory_data["remind_at"] = arguments["remind_at"]
+        if arguments.get("metadata"):
+            memory_data["metadata"] = arguments["metadata"]
+        if arguments.get("confidence") is not None:
+            memory_data["confidence"] = arguments["confidence"]
+        if arguments.get("template"):
+            memory_data["template"] = arguments["template"]
+        if arguments.get("content_type"):
+            memory_data["content_type"] = arguments["content_type"]
+
+        # Make HTTP request to FastAPI server
+        response = await client.post(
+            f"{API_BASE_URL}/api/memories",
+            json=memory_data,
+            headers={"Content-Type": "application/json"},
+        )
+        response.raise_for_status()
+
+        result = response.json()
+        return [types.TextContent(type="text", text=json.dumps(result, indent=2))]
+
+    except httpx.HTTPStatusError as e:
+        if e.response.status_code in (413, 429):
+            # Limit violations: pass the server's explanation through verbatim
+            raise ValueError(e.response.json().get("detail", e.response.text)) from e
+        error_detail = e.response.text if e.response else str(e)
+        raise ValueError(
+            translate("http_error", status=e.response.status_code, detail=error_detail)
+        ) from e
+    except Exception as e:
+        raise ValueError(translate("failed.save_memory", error=e)) from e
+
+
+async def _save_url(
+    arguments: dict[str, Any], client: httpx.AsyncClient
+) -> list[types.TextContent]:
+    """Save a web page as a bookmark memory via HTTP API"""
+    try:
+        # The server fetches and summarizes the page, which can take a while
+        response = await client.post(
+            f"{API_BASE_URL}/api/memories/url", json={"url": arguments["url"]}, timeout=60.0
+        )
+        response.raise_for_status()
+
+        result = response.json()
+        return [types.TextContent(type="text", text=json.dumps(result, indent=2))]
+
+    except httpx.HTTPStatusError as e:
+        if e.response.status_code in (413, 422, 429):
+            raise ValueError(e.response.json().get("detail", e.response.text)) from e
+        error_detail = e.response.text if e.response else str(e)
+        raise ValueError(
+            translate("http_error", status=e.response.status_code, detail=error_detail)
+        ) from e
+    except Exception as e:
+        raise ValueError(translate("failed.save_url", error=e)) from e
+
+
+async def _suggest_category(
+    arguments: dict[str, Any], client: httpx.AsyncClient
+) -> list[types.TextContent]:
+    """Suggest categories for a new memory via HTTP API"""
+    try:
+        response = await client.post(
+            f"{API_BASE_URL}/api/categories/suggest",
+            json={"value": arguments["value"], "limit": arguments.get("limit", 3)},
+        )
+        response.raise_for_status()
+
+        result = response.json()
+        return [types.TextContent(type="text", text=json.dumps(result, indent=2))]
+
+    except httpx.HTTPStatusError as e:
+        error_detail = e.response.text if e.response else str(e)
+        raise ValueError(
+            translate("http_error", status=e.response.status_code, detail=error_detail)
+        ) from e
+    except Exception as e:
+        raise ValueError(translate("failed.suggest_category", error=e)) from e
+
+
+async def _describe_category(
+    arguments: dict[str, Any], client: httpx.AsyncClient
+) -> list[types.TextContent]:
+    """Set a category description via HTTP API"""
+    try:
+        response = await client.put(
+            f"{API_BASE_URL}/api/categories/{quote(arguments['name'], safe='')}",
+            json={"description": arguments["description"]},
+        )
+        response.raise_for_status()
+
+        result = response.json()
+        return [types.TextContent(type="text", text=json.dumps(result, indent=2))]
+
+    except httpx.HTTPStatusError as e:
+        error_detail = e.response.text if e.response else str(e)
+        raise ValueError(
+            translate("http_error", status=e.response.status_code, detail=error_detail)
+        ) from e
+    except Exception as e:
+        raise ValueError(translate("failed.describe_category", error=e)) from e
+
+
+async def _get_memory(
+    arguments: dict[str, Any], client: httpx.AsyncClient
+) -> list[types.TextContent]:
+    """Retrieve a specific memory by key via HTTP API"""
+    try:
+        key = arguments["key"]
+        category = arguments.get("category")
+
+        # Build query parameters
+        params = {}
+        if category:
+            params["category"] = category
+
+        # Make HTTP request
+        response = await client.get(f"{API_BASE_URL}/api/memories/{key}", params=params)
+        response.raise_for_status()
+
+        result = response.json()
+        return [types.TextContent(type="text", text=json.dumps(result, indent=2))]
+
+    except httpx.HTTPStatusError as e:
+        if e.response.status_code == 404:
+            if arguments.get("category"):
+                error_msg = translate(
+                    "memory_not_found_in_category", key=key, category=arguments["category"]
+                )
+            else:
+                error_msg = translate("memory_not_found", key=key)
+            raise ValueError(error_msg) from e
+        else:
+            error_detail = e.response.text if e.response else str(e)
+            raise ValueError(
+                translate("http_error", status=e.response.status_code, detail=error_detail)
+            ) from e
+    except Exception as e:
+        raise ValueError(translate("failed.get_memory", error=e)) from e
+
+
+async def _list_memories(
+    arguments: dict[str, Any], client: httpx.AsyncClient
+) -> list[types.TextContent]:
+    """List memories with optional filtering via HTTP API"""
+    try:
+        # Build query parameters
+        params = {}
+        if arguments.get("category"):
+            params["category"] = arguments["category"]
+        if arguments.get("limit"):
+            params["limit"] = arguments["limit"]
+        if arguments.get("offset"):
+            params["offset"] = arguments["offset"]
+        if arguments.get("owner"):
+            params["owner"] = arguments["owner"]
+        if arguments.get("include_archived"):
+            params["include_archived"] = "true"
+
+        # Make HTTP request
+        response = await client.get(f"{API_BASE_URL}/api/memories", params=params)
+        response.raise_for_status()
+
+        result = fit_to_budget(response.json(), "memories")
+        return [types.TextContent(type="text", text=json.dumps(result, indent=2))]
+
+    except httpx.HTTPStatusError as e:
+        error_detail = e.response.text if e.response else str(e)
+        raise ValueError(
+            translate("http_error", status=e.response.status_code, detail=error_detail)
+        ) from e
+    except Exception as e:
+        raise ValueError(translate("failed.list_memories", error=e)) from e
+
+
+async def _search_memories(
+    arguments: dict[str, Any], client: httpx.AsyncClient
+) -> list[types.TextContent]:
+    """Search memories using full-text search via HTTP API"""
+    try:
+        # Prepare search request data
+        search_data = {
+            "query": arguments["query"],
+            "category": arguments.get("category"),
+            "tags": arguments.get("tags", []),
+            "metadata": arguments.get("metadata"),
+            "source": arguments.get("source"),
+            "owner": arguments.get("owner"),
+            "include_pending": arguments.get("include_pending", False),
+            "include_archived": arguments.get("include_archived", False),
+            "advanced": arguments.get("advanced", False),
+            "language": arguments.get("language"),
+            "limit": arguments.get("limit", 10),
+            "context": session_contexts.get(_current_session()),
+        }
+
+        # Make HTTP request
+        response = await client.post(
+            f"{API_BASE_URL}/api/memories/search",
+            json=search_data,
+            headers={"Content-Type": "application/json"},
+        )
+        response.raise_for_status()
+
+        result = fit_to_budget(response.json(), "results")
+        return [types.TextContent(type="text", text=json.dumps(result, indent=2))]
+
+    except httpx.HTTPStatusError as e:
+        error_detail = e.response.text if e.response else str(e)
+        raise ValueError(
+            translate("http_error", status=e.response.status_code, detail=error_detail)
+        ) from e
+    except Exception as e:
+        raise ValueError(translate("failed.search_memories", error=e)) from e
+
+
+async def _list_pending(
+    arguments: dict[str, Any], client: httpx.AsyncClient
+) -> list[types.TextContent]:
+    """List memories awaiting approval via HTTP API"""
+    try:
+        params = {"limit": arguments.get("limit", 20)}
+
+        response = await client.get(f"{API_BASE_URL}/api/memories/pending", params=params)
+        response.raise_for_status()
+
+        result = fit_to_budget(response.json(), "memories")
+        return [types.TextContent(type="text", text=json.dumps(result, indent=2))]
+
+    except httpx.HTTPStatusError as e:
+        error_detail = e.response.text if e.response else str(e)
+        raise ValueError(
+            translate("http_error", status=e.response.status_code, detail=error_detail)
+        ) from e
+    except Exception as e:
+        raise ValueError(translate("failed.list_pending", error=e)) from e
+
+
+async def _review_memory(
+    arguments: dict[str, Any], client: httpx.AsyncClient, action: str
+) -> list[types.TextContent]:
+    """Approve or reject a pending memory via HTTP API"""
+    try:
+        memory_id = arguments["memory_id"]
+
+        response = await client.post(f"{API_BASE_URL}/api/memories/{memory_id}/{action}")
+        response.raise_for_status()
+
+        result = response.json()
+        return [types.TextContent(type="text", text=json.dumps(result, indent=2))]
+
+    except httpx.HTTPStatusError as e:
+        if e.response.status_code in (403, 404, 409):
+            raise ValueError(e.response.json().get("detail", e.response.text)) from e
+        error_detail = e.response.text if e.response else str(e)
+        raise ValueError(
+            translate("http_error", status=e.response.status_code, detail=error_detail)
+        ) from e
+    except Exception as e:
+        raise ValueError(translate(f"failed.{action}_memory", error=e)) from e
+
+
+async def _verify_memory(
+    arguments: dict[str, Any], client: httpx.AsyncClient
+) -> list[types.TextContent]:
+    """Mark a memory as verified via HTTP API"""
+    try:
+        memory_id = arguments["memory_id"]
+
+        response = await client.post(f"{API_BASE_URL}/api/memories/{memory_id}/verify")
+        response.raise_for_status()
+
+        result = response.json()
+        return [types.TextContent(type="text", text=json.dumps(result, indent=2))]
+
+    except httpx.HTTPStatusError as e:
+        if e.response.status_code in (403, 404):
+            raise ValueError(e.response.json().get("detail", e.response.text)) from e
+        error_detail = e.response.text if e.response else str(e)
+        raise ValueError(
+            translate("http_error", status=e.response.status_code, detail=error_detail)
+        ) from e
+    except Exception as e:
+        raise ValueError(translate("failed.verify_memory", error=e)) from e
+
+
+async def _list_templates(
+    arguments: dict[str, Any], client: httpx.AsyncClient
+) -> list[types.TextContent]:
+    """List memory templates via HTTP API"""
+    try:
+        response = await client.get(f"{API_BASE_URL}/api/templates")
+        response.raise_for_status()
+
+        result = response.json()
+        return [types.TextContent(type="text", text=json.dumps(result, indent=2))]
+
+    except httpx.HTTPStatusError as e:
+        error_detail = e.response.text if e.response else str(e)
+        raise ValueError(
+            translate("http_error", status=e.response.status_code, detail=error_detail)
+        ) from e
+    except Exception as e:
+        raise ValueError(translate("failed.list_templates", error=e)) from e
+
+
+async def _surface_memory(
+    arguments: dict[str, Any], client: httpx.AsyncClient
+) -> list[types.TextContent]:
+    """Pick memories due for review via HTTP API"""
+    try:
+        params = {
+            "count": arguments.get("count", 3),
+            "min_idle_days": arguments.get("min_idle_days", 1),
+        }
+        if arguments.get("tag"):
+            params["tag"] = arguments["tag"]
+
+        response = await client.post(f"{API_BASE_URL}/api/memories/surface", params=params)
+        response.raise_for_status()
+
+        result = response.json()
+        return [types.TextContent(type="text", text=json.dumps(result, indent=2))]
+
+    except httpx.HTTPStatusError as e:
+        error_detail = e.response.text if e.response else str(e)
+        raise ValueError(
+            translate("http_error", status=e.response.status_code, detail=error_detail)
+        ) from e
+    except Exception as e:
+        raise ValueError(translate("failed.surface_memory", error=e)) from e
+
+
+async def _get_recent_memories(
+    arguments: dict[str, Any], client: httpx.AsyncClient
+) -> list[types.TextContent]:
+    """List recently changed memories via HTTP API"""
+    try:
+        params = {"window": arguments.get("window", "24h"), "limit": arguments.get("limit", 100)}
+
+        response = await client.get(f"{API_BASE_URL}/api/memories/recent", params=params)
+        response.raise_for_status()
+
+        result = response.json()
+        return [types.TextContent(type="text", text=json.dumps(result, indent=2))]
+
+    except httpx.HTTPStatusError as e:
+        error_detail = e.response.text if e.response else str(e)
+        raise ValueError(
+            translate("http_error", status=e.response.status_code, detail=error_detail)
+        ) from e
+    except Exception as e:
+        raise ValueError(translate("failed.get_recent_memories", error=e)) from e
+
+
+async def _suggest_keys(
+    arguments: dict[str, Any], client: httpx.AsyncClient
+) -> list[types.TextContent]:
+    """Get completions for a prefix via HTTP API"""
+    try:
+        params = {"prefix": arguments["prefix"], "limit": arguments.get("limit", 10)}
+
+        response = await client.get(f"{API_BASE_URL}/api/memories/suggest", params=params)
+        response.raise_for_status()
+
+        result = response.json()
+        return [types.TextContent(type="text", text=json.dumps(result, indent=2))]
+
+    except httpx.HTTPStatusError as e:
+        error_detail = e.response.text if e.response else str(e)
+        raise ValueError(
+            translate("http_error", status=e.response.status_code, detail=error_detail)
+        ) from e
+    except Exception as e:
+        raise ValueError(translate("failed.suggest_keys", error=e)) from e
+
+
+def _save_working_memory(arguments: dict[str, Any]) -> list[types.TextContent]:
+    """Add a note to this session's working memory"""
+    item = _working_memory().add(arguments["value"], arguments.get("tags"))
+    return [types.TextContent(type="text", text=json.dumps(item.to_dict(), indent=2))]
+
+
+def _list_working_memory(arguments: dict[str, Any]) -> list[types.TextContent]:
+    """List this session's working-memory notes"""
+    items = [item.to_dict() for item in _working_memory().items()]
+    result = fit_to_budget({"items": items, "total": len(items)}, "items")
+    return [types.TextContent(type="text", text=json.dumps(result, indent=2))]
+
+
+async def _promote_to_long_term(
+    arguments: dict[str, Any], client: httpx.AsyncClient
+) -> list[types.TextContent]:
+    """Save a working-memory note via HTTP API, then drop it from working memory"""
+    working_memory = _working_memory()
+    item = working_memory.get(arguments["id"])
+    if item is None:
+        raise ToolError(ERROR_NOT_FOUND, translate("working_memory_not_found", id=arguments["id"]))
+    try:
+        memory_data = {
+            "category": arguments["category"],
+            "value": item.value,
+            "tags": arguments.get("tags", item.tags),
+        }
+        response = await client.post(f"{API_BASE_URL}/api/memories", json=memory_data)
+        response.raise_for_status()
+
+        working_memory.remove(item.id)
+        result = response.json()
+        return [types.TextContent(type="text", text=json.dumps(result, indent=2))]
+
+    except httpx.HTTPStatusError as e:
+        if e.response.status_code in (413, 429):
+            raise ValueError(e.response.json().get("detail", e.response.text)) from e
+        error_detail = e.response.text if e.response else str(e)
+        raise ValueError(
+            translate("http_error", status=e.response.status_code, detail=error_detail)
+        ) from e
+    except Exception as e:
+        raise ValueError(translate("failed.promote_to_long_term", error=e)) from e
+
+
+def _set_context(arguments: dict[str, Any]) -> list[types.TextContent]:
+    """Remember the context hint for the rest of this session"""
+    context = arguments["context"].strip()
+    session = _current_session()
+    if context:
+        session_contexts[session] = context
+        message = translate("context_set", context=context)
+    else:
+        session_contexts.pop(session, None)
+        message = translate("context_cleared")
+    return [types.TextContent(type="text", text=message)]
+
+
+async def _report_search_result(
+    arguments: dict[str, Any], client: httpx.AsyncClient
+) -> list[types.TextContent]:
+    """Report the search result that was used via HTTP API"""
+    try:
+        response = await client.post(
+            f"{API_BASE_URL}/api/memories/search/{arguments['query_id']}/chosen",
+            json={"memory_id": arguments["memory_id"]},
+        )
+        response.raise_for_status()
+
+        result = response.json()
+        return [types.TextContent(type="text", text=json.dumps(result, indent=2))]
+
+    except httpx.HTTPStatusError as e:
+        if e.response.status_code == 404:
+            raise ValueError(e.response.json().get("detail", e.response.text)) from e
+        error_detail = e.response.text if e.response else str(e)
+        raise ValueError(
+            translate("http_error", status=e.response.status_code, detail=error_detail)
+        ) from e
+    except Exception as e:
+        raise ValueError(translate("failed.report_search_result", error=e)) from e
+
+
+async def _get_search_analytics(
+    arguments: dict[str, Any], client: httpx.AsyncClient
+) -> list[types.TextContent]:
+    """Fetch query log analytics via HTTP API"""
+    try:
+        params = {"days": arguments.get("days", 30), "limit": arguments.get("limit", 10)}
+        response = await client.get(f"{API_BASE_URL}/api/memories/search/analytics", params=params)
+        response.raise_for_status()
+
+        result = response.json()
+        return [types.TextContent(type="text", text=json.dumps(result, indent=2))]
+
+    except httpx.HTTPStatusError as e:
+        error_detail = e.response.text if e.response else str(e)
+        raise ValueError(
+            translate("http_error", status=e.response.status_code, detail=error_detail)
+        ) from e
+    except Exception as e:
+        raise ValueError(translate("failed.get_search_analytics", error=e)) from e
+
+
+async def _search_operations(
+    arguments: dict[str, Any], client: httpx.AsyncClient
+) -> list[types.TextContent]:
+    """Search the operation log via HTTP API"""
+    try:
+        params = {"q": arguments["query"], "limit": arguments.get("limit", 10)}
+        for name in ("operation", "since", "until"):
+            if arguments.get(name):
+                params[name] = arguments[name]
+
+        response = await client.get(f"{API_BASE_URL}/api/operations/search", params=params)
+        response.raise_for_status()
+
+        result = fit_to_budget(response.json(), "results")
+        return [types.TextContent(type="text", text=json.dumps(result, indent=2))]
+
+    except httpx.HTTPStatusError as e:
+        error_detail = e.response.text if e.response else str(e)
+        raise ValueError(
+            translate("http_error", status=e.response.status_code, detail=error_detail)
+        ) from e
+    except Exception as e:
+        raise ValueError(translate("failed.search_operations", error=e)) from e
+
+
+async def _get_due_reminders(
+    arguments: dict[str, Any], client: httpx.AsyncClient
+) -> list[types.TextContent]:
+    """List due reminders via HTTP API"""
+    try:
+        params = {"include_upcoming_hours": arguments.get("include_upcoming_hours", 0)}
+
+        response = await client.get(f"{API_BASE_URL}/api/memories/reminders", params=params)
+        response.raise_for_status()
+
+        result = response.json()
+        return [types.TextContent(type="text", text=json.dumps(result, indent=2))]
+
+    except httpx.HTTPStatusError as e:
+        error_detail = e.response.text if e.response else str(e)
+        raise ValueError(
+            translate("http_error", status=e.response.status_code, detail=error_detail)
+        ) from e
+    except Exception as e:
+        raise ValueError(translate("failed.get_due_reminders", error=e)) from e
+
+
+async def _acknowledge_reminder(
+    arguments: dict[str, Any], client: httpx.AsyncClient
+) -> list[types.TextContent]:
+    """Acknowledge a reminder via HTTP API"""
+    try:
+        memory_id = arguments["memory_id"]
+
+        response = await client.post(f"{API_BASE_URL}/api/memories/{memory_id}/acknowledge")
+        response.raise_for_status()
+
+        text = translate("reminder_acknowledged", memory_id=memory_id)
+        return [types.TextContent(type="text", text=text)]
+
+    except httpx.HTTPStatusError as e:
+        if e.response.status_code in (404, 409):
+            raise ValueError(e.response.json().get("detail", e.response.text)) from e
+        error_detail = e.response.text if e.response else str(e)
+        raise ValueError(
+            translate("http_error", status=e.response.status_code, detail=error_detail)
+        ) from e
+    except Exception as e:
+        raise ValueError(translate("failed.acknowledge_reminder", error=e)) from e
+
+
+async def _summarize_category(
+    arguments: dict[str, Any], client: httpx.AsyncClient
+) -> list[types.TextContent]:
+    """Condense a tag's memories into one summary via HTTP API"""
+    try:
+        payload = {"tag": arguments["tag"], "dry_run": arguments.get("dry_run", True)}
+        if arguments.get("older_than_days") is not None:
+            payload["older_than_days"] = arguments["older_than_days"]
+        if arguments.get("confirmation_token"):
+            payload["confirmation_token"] = arguments["confirmation_token"]
+
+        response = await client.post(
+            f"{API_BASE_URL}/api/memories/summarize", json=payload, timeout=120.0
+        )
+        response.raise_for_status()
+
+        result = response.json()
+        if result["dry_run"]:
+            header = translate("summary_preview", count=len(result["source_ids"]))
+            header += "\n" + translate("confirm_with_token", token=result["confirmation_token"])
+        else:
+            header = translate(
+                "summary_saved",
+                memory_id=result["memory"]["id"],
+                count=len(result["source_ids"]),
+            )
+        return [types.TextContent(type="text", text=f"{header}\n\n{result['summary']}")]
+
+    except httpx.HTTPStatusError as e:
+        if e.response.status_code in (404, 409, 428):
+            raise ValueError(e.response.json().get("detail", e.response.text)) from e
+        error_detail = e.response.text if e.response else str(e)
+        raise ValueError(
+            translate("http_error", status=e.response.status_code, detail=error_detail)
+        ) from e
+    except Exception as e:
+        raise ValueError(translate("failed.summarize_category", error=e)) from e
+
+
+# Bulk tools: endpoint under /api/memories/bulk and arguments besides the filter
+BULK_TOOLS = {
+    "delete_memories": ("delete", ()),
+    "move_memories": ("move", ("new_category",)),
+    "retag_memories": ("retag", ("add_tags", "remove_tags")),
+}
+BULK_FILTER_ARGUMENTS = ("category", "tag", "date_from", "date_to", "confirmation_token")
+
+
+async def _bulk_memories(
+    name: str, arguments: dict[str, Any], client: httpx.AsyncClient
+) -> list[types.TextContent]:
+    """Delete, move or retag the memories matching a filter via HTTP API (preview first)"""
+    endpoint, own_arguments = BULK_TOOLS[name]
+    try:
+        payload = {
+            key: arguments[key]
+            for key in (*BULK_FILTER_ARGUMENTS, *own_arguments)
+            if arguments.get(key)
+        }
+        payload["dry_run"] = arguments.get("dry_run", True)
+
+        response = await client.post(f"{API_BASE_URL}/api/memories/bulk/{endpoint}", json=payload)
+        response.raise_for_status()
+
+        result = response.json()
+        category = arguments.get("new_category")
+        if result["dry_run"]:
+            text = translate(f"preview.{name}", count=result["count"], category=category)
+            if result["memory_ids"]:
+                text += "\n" + translate("confirm_with_token", token=result["confirmation_token"])
+                text += "\n\n" + "\n".join(f"- {memory_id}" for memory_id in result["memory_ids"])
+        else:
+            text = translate(f"done.{name}", count=result["count"], category=category)
+        return [types.TextContent(type="text", text=text)]
+
+    except httpx.HTTPStatusError as e:
+        if e.response.status_code in (409, 428):
+            raise ValueError(e.response.json().get("detail", e.response.text)) from e
+        error_detail = e.response.text if e.response else str(e)
+        raise ValueError(
+            translate("http_error", status=e.response.status_code, detail=error_detail)
+        ) from e
+    except Exception as e:
+        raise ValueError(translate(f"failed.{name}", error=e)) from e
+
+
+async def _get_diagnostics(
+    arguments: dict[str, Any], client: httpx.AsyncClient
+) -> list[types.TextContent]:
+    """Fetch debug timing diagnostics via HTTP API"""
+    try:
+        params = {"limit": arguments.get("limit", 20)}
+
+        response = await client.get(f"{API_BASE_URL}/api/diagnostics", params=params)
+        response.raise_for_status()
+
+        result = response.json()
+        return [types.TextContent(type="text", text=json.dumps(result, indent=2))]
+
+    except httpx.HTTPStatusError as e:
+        error_detail = e.response.text if e.response else str(e)
+        raise ValueError(
+            translate("http_error", status=e.response.status_code, detail=error_detail)
+        ) from e
+    except Exception as e:
+        raise ValueError(translate("failed.get_diagnostics", error=e)) from e
+
+
+async def _get_report(
+    arguments: dict[str, Any], client: httpx.AsyncClient
+) -> list[types.TextContent]:
+    """Fetch the markdown digest report via HTTP API"""
+    try:
+        params = {"days": arguments.get("days", 30)}
+        response = await client.get(f"{API_BASE_URL}/api/memories/report", params=params)
+        response.raise_for_status()
+
+        return [types.TextContent(type="text", text=response.json()["markdown"])]
+
+    except httpx.HTTPStatusError as e:
+        error_detail = e.response.text if e.response else str(e)
+        raise ValueError(
+            translate("http_error", status=e.response.status_code, detail=error_detail)
+        ) from e
+    except Exception as e:
+        raise ValueError(translate("failed.get_report", error=e)) from e
+
+
+async def _generate_obsidian_canvas(
+    arguments: dict[str, Any], client: httpx.AsyncClient
+) -> list[types.TextContent]:
+    """Fetch a canvas of the matching memories via HTTP API and write it into the vault"""
+    if not settings.obsidian_vault_path:
+        raise ToolError(ERROR_INVALID_ARGUMENTS, translate("vault_not_configured"))
+    try:
+        params = {key: arguments[key] for key in ("category", "tag", "limit") if arguments.get(key)}
+        response = await client.get(f"{API_BASE_URL}/api/memories/canvas", params=params)
+        response.raise_for_status()
+
+        result = response.json()
+        name = arguments.get("name") or arguments.get("category") or arguments.get("tag")
+        file_name = re.sub(r'[\\/:*?"<>|]', "-", name).strip(" .") or "mory"
+        path = Path(settings.obsidian_vault_path).expanduser() / f"{file_name}.canvas"
+        canvas = result["canvas"]
+        atomic_write_text(path, json.dumps(canvas, indent=2, ensure_ascii=False) + "\n")
+
+        text = translate(
+            "canvas_written", path=path, count=result["count"], edges=len(canvas["edges"])
+        )
+        return [types.TextContent(type="text", text=text)]
+
+    except httpx.HTTPStatusError as e:
+        error_detail = e.response.text if e.response else str(e)
+        raise ValueError(
+            translate("http_error", status=e.response.status_code, detail=error_detail)
+        ) from e
+    except Exception as e:
+        raise ValueError(translate("failed.generate_obsidian_canvas", error=e)) from e
+
+
+async def _get_metrics(
+    arguments: dict[str, Any], client: httpx.AsyncClient
+) -> list[types.TextContent]:
+    """Fetch server metrics via HTTP API"""
+    try:
+        response = await client.get(f"{API_BASE_URL}/api/metrics")
+        response.raise_for_status()
+
+        result = response.json()
+        return [types.TextContent(type="text", text=json.dumps(result, indent=2))]
+
+    except httpx.HTTPStatusError as e:
+        error_detail = e.response.text if e.response else str(e)
+        raise ValueError(
+            translate("http_error", status=e.response.status_code, detail=error_detail)
+        ) from e
+    except Exception as e:
+        raise ValueError(translate("failed.get_metrics", error=e)) from e
+
+
+async def _health_check(
+    arguments: dict[str, Any], client: httpx.AsyncClient
+) -> list[types.TextContent]:
+    """Run the server self-test via HTTP API"""
+    try:
+        params = {"check_embedding": str(bool(arguments.get("check_embedding", False))).lower()}
+
+        response = await client.get(f"{API_BASE_URL}/api/health/selftest", params=params)
         response.raise_for_status()
 
         result = response.json()
@@ -269,9 +1873,13 @@ async def _search_memories(
 
     except httpx.HTTPStatusError as e:
         error_detail = e.response.text if e.response else str(e)
-        raise ValueError(f"HTTP {e.response.status_code}: {error_detail}") from e
+        raise ValueError(
+            translate("http_error", status=e.response.status_code, detail=error_detail)
+        ) from e
+    except httpx.ConnectError as e:
+        raise ValueError(translate("server_unreachable", url=API_BASE_URL)) from e
     except Exception as e:
-        raise ValueError(f"Failed to search memories: {str(e)}") from e
+        raise ValueError(translate("failed.health_check", error=e)) from e
 
 
 # Server configuration
@@ -286,4 +1894,4 @@ async def start_mcp_server():
 
 
 # Export the server instance
-__all__ = ["mcp_server", "start_mcp_server"]
+__all__ = ["mcp_server", "start_mcp_server", "tool_calls"]
@@ -1,5 +1,10 @@
 # Database models for Mory Server
 
+from .category import Category
+from .daily_stats import DailyStats
 from .memory import Memory
+from .memory_chunk import MemoryChunk
+from .operation_log import OperationLog
+from .search_log import SearchLog
 
-__all__ = ["Memory"]
+__all__ = ["Category", "DailyStats", "Memory", "MemoryChunk", "OperationLog", "SearchLog"]
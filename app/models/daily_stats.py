@@ -0,0 +1,57 @@
+"""Daily statistics model for Mory Server
+One row per namespace and day, so growth can be charted over time
+"""
+
+import json
+from datetime import datetime
+
+from sqlalchemy import DateTime, Integer, String, Text, UniqueConstraint
+from sqlalchemy.orm import Mapped, mapped_column
+
+from ..core.database import Base
+
+
+class DailyStats(Base):
+    """Counts for one namespace on one (UTC) day"""
+
+    __tablename__ = "daily_stats"
+
+    id: Mapped[int] = mapped_column(Integer, primary_key=True, autoincrement=True)
+    day: Mapped[str] = mapped_column(String)  # YYYY-MM-DD
+    namespace: Mapped[str] = mapped_column(String, default="default")
+
+    # Totals as of the day's last daily_stats job run (None until it has run)
+    total_memories: Mapped[int | None] = mapped_column(Integer)
+    memories_with_embeddings: Mapped[int | None] = mapped_column(Integer)
+    categories: Mapped[str | None] = mapped_column(Text)  # JSON: category -> count
+
+    # Activity during the day
+    searches: Mapped[int] = mapped_column(Integer, default=0, server_default="0")
+    embeddings_generated: Mapped[int] = mapped_column(Integer, default=0, server_default="0")
+
+    updated_at: Mapped[datetime] = mapped_column(
+        DateTime, default=datetime.utcnow, onupdate=datetime.utcnow
+    )
+
+    __table_args__ = (UniqueConstraint("day", "namespace", name="uq_daily_stats_day_namespace"),)
+
+    @property
+    def categories_dict(self) -> dict[str, int] | None:
+        try:
+            return json.loads(self.categories) if self.categories else None
+        except json.JSONDecodeError:
+            return None
+
+    def to_dict(self) -> dict:
+        return {
+            "day": self.day,
+            "namespace": self.namespace,
+            "total_memories": self.total_memories,
+            "memories_with_embeddings": self.memories_with_embeddings,
+            "categories": self.categories_dict,
+            "searches": self.searches,
+            "embeddings_generated": self.embeddings_generated,
+        }
+
+    def __repr__(self):
+        return f"<DailyStats(day='{self.day}', namespace='{self.namespace}')>"
@@ -4,13 +4,27 @@ SQLAlchemy model compatible with existing CLI data structure
 
 import json
 from datetime import datetime
+from typing import TYPE_CHECKING
 from uuid import uuid4
 
-from sqlalchemy import DateTime, Index, LargeBinary, String, Text
-from sqlalchemy.orm import Mapped, mapped_column, validates
+from sqlalchemy import (
+    Boolean,
+    DateTime,
+    Float,
+    Index,
+    Integer,
+    LargeBinary,
+    String,
+    Text,
+    func,
+)
+from sqlalchemy.orm import Mapped, mapped_column, relationship, validates
 
 from ..core.database import Base
 
+if TYPE_CHECKING:
+    from .memory_chunk import MemoryChunk
+
 
 class Memory(Base):
     """Simplified AI-driven memory model (Issue #112)"""
@@ -22,10 +36,47 @@ class Memory(Base):
         String, primary_key=True, default=lambda: f"mem_{uuid4().hex[:8]}"
     )
     value: Mapped[str] = mapped_column(Text)  # Only user input required
+    # text, markdown, code[:<language>] or json (see services/content_types.py)
+    content_type: Mapped[str] = mapped_column(String, default="text", server_default="text")
+    # Detected from value whenever it changes: ja, zh, ko or en (None without letters)
+    language: Mapped[str | None] = mapped_column(String)
+    # SHA-256 of the normalized value, to spot duplicates (see services/dedup.py)
+    content_hash: Mapped[str | None] = mapped_column(String)
+
+    # 🗂️ Profile isolation (e.g. "work" vs "personal")
+    namespace: Mapped[str] = mapped_column(String, default="default", server_default="default")
+    owner: Mapped[str | None] = mapped_column(String)  # Agent that saved the memory
+    # Provenance as "<type>:<detail>", e.g. mcp:save_memory, obsidian:notes/a.md, import:x.csv
+    source: Mapped[str | None] = mapped_column(String)
+
+    # ✅ Human review: "pending" memories are staged until approved
+    review_status: Mapped[str] = mapped_column(
+        String, default="approved", server_default="approved"
+    )
+
+    # 🔎 Credibility: tentative facts carry a lower confidence until verified
+    confidence: Mapped[float] = mapped_column(Float, default=1.0, server_default="1.0")
+    verified: Mapped[bool] = mapped_column(Boolean, default=False, server_default="0")
+    verified_at: Mapped[datetime | None] = mapped_column(DateTime)
+
+    # 🗄️ Archive tier: archived memories are kept but condensed or out of the way
+    archived_at: Mapped[datetime | None] = mapped_column(DateTime)
+    access_count: Mapped[int] = mapped_column(Integer, default=0, server_default="0")
+    last_accessed_at: Mapped[datetime | None] = mapped_column(DateTime)
+
+    # 🧭 Structured metadata (location, source_url, people, ...) as a JSON object
+    metadata_json: Mapped[str] = mapped_column(Text, default="{}", server_default="{}")
+    # Template (contact, decision, ...) whose declared fields the metadata carries
+    template: Mapped[str | None] = mapped_column(String)
+
+    # ⏰ Reminder: surfaced by get_due_reminders once this (UTC) time has passed
+    remind_at: Mapped[datetime | None] = mapped_column(DateTime)
 
     # 🤖 AI-generated fields (all automatic)
     summary: Mapped[str | None] = mapped_column(Text)  # AI-generated summary
     tags: Mapped[str] = mapped_column(Text, default="[]")  # AI-generated comprehensive tags
+    # Tags the auto_tag step added, kept until reviewed (JSON list, a subset of tags)
+    auto_tags: Mapped[str | None] = mapped_column(Text)
 
     # ⏰ System timestamps
     created_at: Mapped[datetime] = mapped_column(DateTime, default=datetime.utcnow)
@@ -37,14 +88,36 @@ class Memory(Base):
     # 🔍 Search optimization (single embedding from summary)
     embedding: Mapped[bytes | None] = mapped_column(LargeBinary)  # Summary-based vector
     embedding_model: Mapped[str | None] = mapped_column(String)  # Model used for embedding
+    # Per-chunk vectors of texts too long for one embedding (embedding is then their mean)
+    chunks: Mapped[list["MemoryChunk"]] = relationship(
+        cascade="all, delete-orphan", order_by="MemoryChunk.chunk_index", passive_deletes=True
+    )
 
     # Simplified indexes
     __table_args__ = (
         Index("idx_updated_at", "updated_at"),
         Index("idx_ai_processed", "ai_processed_at"),
         Index("idx_tags_search", "tags"),
+        Index("idx_namespace_updated", "namespace", "updated_at"),
+        Index("idx_owner", "owner"),
+        Index("idx_source", "source"),
+        Index("idx_review_status", "review_status"),
+        Index("idx_archived_at", "archived_at"),
+        Index("idx_remind_at", "remind_at"),
+        Index("idx_language", "namespace", "language"),
+        Index("idx_content_hash", "namespace", "content_hash"),
     )
 
+    @validates("value")
+    def validate_value(self, key, value):
+        """Keep the detected language and content hash in step with the content"""
+        from ..services.dedup import content_hash
+        from ..services.language import detect_language
+
+        self.language = detect_language(value or "")
+        self.content_hash = content_hash(value or "")
+        return value
+
     @validates("tags")
     def validate_tags(self, key, value):
         """Ensure tags is always valid JSON"""
@@ -72,11 +145,47 @@ class Memory(Base):
         """Set tags from Python list"""
         self.tags = json.dumps(value)
 
+    @property
+    def auto_tags_list(self) -> list[str]:
+        """Machine-generated tags still awaiting review"""
+        try:
+            return json.loads(self.auto_tags) if self.auto_tags else []
+        except json.JSONDecodeError:
+            return []
+
+    @property
+    def metadata_dict(self) -> dict:
+        """Get metadata as Python dict"""
+        try:
+            value = json.loads(self.metadata_json) if self.metadata_json else {}
+        except json.JSONDecodeError:
+            return {}
+        return value if isinstance(value, dict) else {}
+
+    @metadata_dict.setter
+    def metadata_dict(self, value: dict):
+        """Set metadata from Python dict"""
+        self.metadata_json = json.dumps(value or {}, ensure_ascii=False)
+
+    @property
+    def rendered(self) -> str | None:
+        """Card text for templated memories, a fenced block for code and JSON"""
+        from ..services.content_types import render_content
+        from ..services.templates import render
+
+        return render(self.template, self.metadata_dict) or render_content(
+            self.value, self.content_type
+        )
+
     @property
     def has_embedding(self) -> bool:
         """Check if memory has semantic embedding"""
         return self.embedding is not None and len(self.embedding) > 0
 
+    @property
+    def is_archived(self) -> bool:
+        return self.archived_at is not None
+
     @property
     def is_ai_processed(self) -> bool:
         """Check if AI processing is complete"""
@@ -96,8 +205,24 @@ class Memory(Base):
         """Convert to dictionary for API responses"""
         return {
             "id": self.id,
+            "namespace": self.namespace,
+            "owner": self.owner,
+            "source": self.source,
+            "review_status": self.review_status,
+            "confidence": self.confidence,
+            "verified": bool(self.verified),
+            "archived_at": self.archived_at.isoformat() if self.archived_at else None,
+            "access_count": self.access_count or 0,
+            "last_accessed_at": (
+                self.last_accessed_at.isoformat() if self.last_accessed_at else None
+            ),
+            "remind_at": self.remind_at.isoformat() if self.remind_at else None,
             "value": self.value,
+            "content_type": self.content_type,
             "tags": self.tags_list,  # AI-generated comprehensive tags
+            "metadata": self.metadata_dict,
+            "template": self.template,
+            "rendered": self.rendered,
             "created_at": self.created_at.isoformat() if self.created_at else None,
             "updated_at": self.updated_at.isoformat() if self.updated_at else None,
             "has_embedding": self.has_embedding,
@@ -109,3 +234,7 @@ class Memory(Base):
     def __repr__(self):
         tags_preview = self.tags_list[:2] if self.tags_list else []
         return f"<Memory(id='{self.id}', tags={tags_preview}, status='{self.processing_status}')>"
+
+
+# Case-insensitive prefix lookups on summaries (completion)
+Index("idx_summary_lower", func.lower(Memory.summary))
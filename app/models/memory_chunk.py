@@ -0,0 +1,28 @@
+"""Memory chunk model for Mory Server
+Values too long for one embedding request are split into chunks, each with its own vector;
+semantic search scores such a memory by its best matching chunk.
+"""
+
+from sqlalchemy import ForeignKey, Index, Integer, LargeBinary, String
+from sqlalchemy.orm import Mapped, mapped_column
+
+from ..core.database import Base
+
+
+class MemoryChunk(Base):
+    """Embedding of one slice of a long memory"""
+
+    __tablename__ = "memory_chunks"
+
+    id: Mapped[int] = mapped_column(Integer, primary_key=True, autoincrement=True)
+    memory_id: Mapped[str] = mapped_column(
+        String, ForeignKey("memories.id", ondelete="CASCADE")
+    )
+    chunk_index: Mapped[int] = mapped_column(Integer)
+    start: Mapped[int] = mapped_column(Integer)  # Character offset of the chunk in the text
+    embedding: Mapped[bytes] = mapped_column(LargeBinary)
+
+    __table_args__ = (Index("idx_memory_chunks_memory", "memory_id"),)
+
+    def __repr__(self):
+        return f"<MemoryChunk(memory_id='{self.memory_id}', chunk_index={self.chunk_index})>"
@@ -0,0 +1,56 @@
+"""Operation log model for Mory Server
+Append-only record of notable operations (redactions, approvals, ...)
+Each entry carries a hash over its content and the previous entry's hash, so an
+exported history shows whether entries were altered or removed afterwards.
+"""
+
+import json
+from datetime import datetime
+
+from sqlalchemy import DateTime, Index, Integer, String, Text
+from sqlalchemy.orm import Mapped, mapped_column
+
+from ..core.database import Base
+
+
+class OperationLog(Base):
+    """Single operation log entry"""
+
+    __tablename__ = "operation_log"
+
+    id: Mapped[int] = mapped_column(Integer, primary_key=True, autoincrement=True)
+    operation: Mapped[str] = mapped_column(String)  # e.g. "redaction"
+    memory_id: Mapped[str | None] = mapped_column(String)
+    agent_id: Mapped[str | None] = mapped_column(String)
+    details: Mapped[str] = mapped_column(Text, default="{}")  # JSON payload
+    created_at: Mapped[datetime] = mapped_column(DateTime, default=datetime.utcnow)
+    # Hash chain, filled in by services.operation_log when the entry is flushed
+    prev_hash: Mapped[str | None] = mapped_column(String)
+    hash: Mapped[str | None] = mapped_column(String)
+
+    __table_args__ = (
+        Index("idx_oplog_created", "created_at"),
+        Index("idx_oplog_operation", "operation"),
+        Index("idx_oplog_memory", "memory_id"),
+    )
+
+    @property
+    def details_dict(self) -> dict:
+        try:
+            return json.loads(self.details) if self.details else {}
+        except json.JSONDecodeError:
+            return {}
+
+    def to_dict(self) -> dict:
+        return {
+            "id": self.id,
+            "operation": self.operation,
+            "memory_id": self.memory_id,
+            "agent_id": self.agent_id,
+            "details": self.details_dict,
+            "created_at": self.created_at.isoformat() if self.created_at else None,
+            "hash": self.hash,
+        }
+
+    def __repr__(self):
+        return f"<OperationLog(id={self.id}, operation='{self.operation}')>"
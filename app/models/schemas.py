@@ -1,10 +1,20 @@
 """Pydantic schemas for request/response models"""
 
 import json
-from datetime import datetime
+from datetime import UTC, datetime
 from typing import Any
 
-from pydantic import BaseModel, Field, field_validator
+from pydantic import AliasChoices, BaseModel, Field, field_validator, model_validator
+
+# text, markdown, json, code or code:<language>
+CONTENT_TYPE_PATTERN = r"^(text|markdown|json|code(:[\w+#-]+)?)$"
+
+
+def _naive_utc(value: datetime | None) -> datetime | None:
+    """Store timestamps as naive UTC like the rest of the schema"""
+    if value is not None and value.tzinfo is not None:
+        return value.astimezone(UTC).replace(tzinfo=None)
+    return value
 
 
 class MemoryBase(BaseModel):
@@ -20,6 +30,24 @@ class MemoryCreate(BaseModel):
     """Request model for creating memories - ultra-simple (Issue #112)"""
 
     value: str = Field(..., description="Memory content (only user input required)")
+    remind_at: datetime | None = Field(None, description="When to surface this as a reminder")
+    metadata: dict[str, Any] | None = Field(
+        None, description="Structured fields such as location, source_url or people"
+    )
+    source: str | None = Field(
+        None, description="Provenance, e.g. import:notes.csv (defaults to the calling client)"
+    )
+    confidence: float = Field(
+        1.0, ge=0.0, le=1.0, description="Confidence for tentative facts (1.0 = certain)"
+    )
+    template: str | None = Field(
+        None, description="Template (contact, decision, ...) whose fields metadata must carry"
+    )
+    content_type: str | None = Field(
+        None,
+        description="text, markdown, code, code:<language> or json (detected when omitted)",
+        pattern=CONTENT_TYPE_PATTERN,
+    )
     # Note: summary and tags will be generated by AI automatically
 
     @field_validator("value")
@@ -29,11 +57,25 @@ class MemoryCreate(BaseModel):
             raise ValueError("Value cannot be empty")
         return v.strip()
 
+    @field_validator("remind_at")
+    @classmethod
+    def validate_remind_at(cls, v):
+        return _naive_utc(v)
+
 
 class MemoryUpdate(BaseModel):
     """Request model for updating memories - simplified (Issue #112)"""
 
     value: str | None = Field(None, description="Updated memory content")
+    remind_at: datetime | None = Field(None, description="New reminder time (null clears it)")
+    metadata: dict[str, Any] | None = Field(None, description="Replacement metadata object")
+    confidence: float | None = Field(None, ge=0.0, le=1.0, description="New confidence")
+    template: str | None = Field(None, description="New template (null removes it)")
+    content_type: str | None = Field(
+        None,
+        description="New content type (detected again when the value changes without one)",
+        pattern=CONTENT_TYPE_PATTERN,
+    )
     # Note: updating value will trigger AI re-processing of summary and tags
 
     @field_validator("value")
@@ -43,14 +85,53 @@ class MemoryUpdate(BaseModel):
             raise ValueError("Value cannot be empty")
         return v.strip() if v else v
 
+    @field_validator("value", "confidence")
+    @classmethod
+    def validate_not_null(cls, v):
+        # Only given fields are validated: leave these out to keep them, null cannot clear them
+        if v is None:
+            raise ValueError("Cannot be null; leave the field out to keep the current value")
+        return v
+
+    @field_validator("remind_at")
+    @classmethod
+    def validate_remind_at(cls, v):
+        return _naive_utc(v)
+
 
 class MemoryResponse(MemoryBase):
     """Response model for memory data - AI-driven (Issue #112)"""
 
     id: str = Field(..., description="Unique memory identifier")
+    namespace: str = Field("default", description="Namespace (profile) the memory belongs to")
+    owner: str | None = Field(None, description="Agent that saved the memory")
+    source: str | None = Field(None, description="Where the memory came from (type:detail)")
+    confidence: float = Field(1.0, description="Confidence in the fact (0.0-1.0)")
+    verified: bool = Field(False, description="Whether the fact has been confirmed")
+    review_status: str = Field("approved", description="Review state: approved/pending")
+    archived_at: datetime | None = Field(None, description="When the memory was archived")
+    access_count: int = Field(0, description="Number of times the memory was read")
+    remind_at: datetime | None = Field(None, description="Reminder time (UTC)")
+    metadata: dict[str, Any] = Field(
+        default_factory=dict,
+        # Memory.metadata is SQLAlchemy's table registry, so read metadata_dict
+        validation_alias=AliasChoices("metadata_dict", "metadata"),
+        description="Structured metadata fields",
+    )
+    template: str | None = Field(None, description="Template the metadata follows")
+    content_type: str = Field("text", description="text, markdown, code[:language] or json")
+    rendered: str | None = Field(
+        None, description="Card text for templated memories, fenced block for code and JSON"
+    )
     created_at: datetime = Field(..., description="Creation timestamp")
     updated_at: datetime = Field(..., description="Last update timestamp")
     has_embedding: bool = Field(False, description="Whether memory has semantic embedding")
+    language: str | None = Field(None, description="Detected language: ja, zh, ko or en")
+    auto_tags: list[str] = Field(
+        default_factory=list,
+        validation_alias=AliasChoices("auto_tags_list", "auto_tags"),
+        description="Machine-generated tags awaiting review",
+    )
 
     # AI processing status
     ai_processed_at: datetime | None = Field(None, description="AI processing completion timestamp")
@@ -79,8 +160,20 @@ class MemorySummaryResponse(BaseModel):
     """Optimized response model for memory summaries - AI-driven (Issue #112)"""
 
     id: str = Field(..., description="Unique memory identifier")
+    namespace: str = Field("default", description="Namespace (profile) the memory belongs to")
+    owner: str | None = Field(None, description="Agent that saved the memory")
+    source: str | None = Field(None, description="Where the memory came from (type:detail)")
+    confidence: float = Field(1.0, description="Confidence in the fact (0.0-1.0)")
+    verified: bool = Field(False, description="Whether the fact has been confirmed")
+    review_status: str = Field("approved", description="Review state: approved/pending")
+    archived_at: datetime | None = Field(None, description="When the memory was archived")
     tags: list[str] = Field(default_factory=list, description="AI-generated comprehensive tags")
     summary: str | None = Field(None, description="AI-generated summary")
+    template: str | None = Field(None, description="Template the metadata follows")
+    content_type: str = Field("text", description="text, markdown, code[:language] or json")
+    rendered: str | None = Field(
+        None, description="Card text for templated memories, fenced block for code and JSON"
+    )
     created_at: datetime = Field(..., description="Creation timestamp")
     updated_at: datetime = Field(..., description="Last update timestamp")
     has_embedding: bool = Field(False, description="Whether memory has semantic embedding")
@@ -118,6 +211,161 @@ class MemoryListSummaryResponse(BaseModel):
     total: int = Field(..., description="Total number of memories")
 
 
+class RecentMemoryGroup(BaseModel):
+    """Recent memories sharing a category (their first tag)"""
+
+    category: str = Field(..., description="First tag, or uncategorized")
+    memories: list[MemorySummaryResponse] = Field(..., description="Most recently changed first")
+
+
+class RecentMemoriesResponse(BaseModel):
+    """Memories created or updated within a time window, grouped by category"""
+
+    window: str = Field(..., description='Requested window, e.g. "24h"')
+    since: datetime = Field(..., description="Start of the window (UTC)")
+    total: int = Field(..., description="Number of memories in the window")
+    groups: list[RecentMemoryGroup] = Field(..., description="Most recently active first")
+
+
+class SummarizeCategoryRequest(BaseModel):
+    """Request model for condensing all memories with a tag into one summary memory"""
+
+    tag: str = Field(..., min_length=1, description="Tag (category) to condense")
+    dry_run: bool = Field(True, description="Preview the summary without saving or archiving")
+    older_than_days: int | None = Field(
+        None, ge=0, description="Only condense memories not updated for this many days"
+    )
+    limit: int = Field(200, ge=2, le=1000, description="Maximum number of memories to condense")
+    confirmation_token: str | None = Field(
+        None, description="Token from the dry-run preview, required when dry_run is false"
+    )
+
+
+class CategoryDescriptionRequest(BaseModel):
+    """Request model for describing what belongs in a category"""
+
+    description: str = Field(
+        ..., min_length=1, max_length=1000, description="What the category holds"
+    )
+
+
+class CategorySuggestRequest(BaseModel):
+    """Request model for suggesting a category for a new memory"""
+
+    value: str = Field(..., min_length=1, description="Content of the memory to categorize")
+    limit: int = Field(3, ge=1, le=20, description="Number of suggestions")
+
+
+class SummarizeCategoryResponse(BaseModel):
+    """Response model for category summarization"""
+
+    tag: str = Field(..., description="Condensed tag")
+    dry_run: bool = Field(..., description="Whether this was only a preview")
+    source_ids: list[str] = Field(..., description="Memories included in the summary")
+    summary: str = Field(..., description="Condensed summary text")
+    memory: MemoryResponse | None = Field(None, description="Saved summary memory")
+    confirmation_token: str | None = Field(
+        None, description="Pass back with dry_run=false to carry out the previewed run"
+    )
+
+
+class MemoryFilter(BaseModel):
+    """Which memories a bulk operation applies to (all given conditions must match)"""
+
+    category: str | None = Field(None, min_length=1, description="First tag, or uncategorized")
+    tag: str | None = Field(None, min_length=1, description="Any of the memory's tags")
+    date_from: datetime | None = Field(None, description="Created at or after")
+    date_to: datetime | None = Field(None, description="Created at or before")
+
+    @model_validator(mode="after")
+    def require_condition(self):
+        if not (self.category or self.tag or self.date_from or self.date_to):
+            raise ValueError("Give at least one of category, tag, date_from or date_to")
+        return self
+
+
+class BulkRequest(MemoryFilter):
+    """Filter plus the two-phase confirmation every bulk operation takes"""
+
+    dry_run: bool = Field(True, description="Only preview the matching memories")
+    confirmation_token: str | None = Field(
+        None, description="Token from the dry-run preview, required when dry_run is false"
+    )
+
+
+class DeleteMemoriesRequest(BulkRequest):
+    """Request model for deleting every memory matching a filter"""
+
+
+class MoveMemoriesRequest(BulkRequest):
+    """Request model for moving every memory matching a filter to another category"""
+
+    new_category: str = Field(
+        ..., min_length=1, max_length=50, description="Category (first tag) to move them to"
+    )
+
+    @field_validator("new_category")
+    @classmethod
+    def validate_new_category(cls, v):
+        v = v.strip()
+        if not v:
+            raise ValueError("new_category cannot be empty")
+        return v
+
+
+class RetagMemoriesRequest(BulkRequest):
+    """Request model for adding and removing tags on every memory matching a filter"""
+
+    add_tags: list[str] = Field(default_factory=list, description="Tags to add")
+    remove_tags: list[str] = Field(default_factory=list, description="Tags to remove")
+
+    @field_validator("add_tags", "remove_tags")
+    @classmethod
+    def validate_tags(cls, v):
+        return list(dict.fromkeys(tag.strip() for tag in v if tag.strip()))
+
+    @model_validator(mode="after")
+    def require_change(self):
+        if not (self.add_tags or self.remove_tags):
+            raise ValueError("Give add_tags or remove_tags")
+        if set(self.add_tags) & set(self.remove_tags):
+            raise ValueError("A tag cannot be both added and removed")
+        return self
+
+
+class BulkOperationResponse(BaseModel):
+    """Response model for bulk operations on filtered memories"""
+
+    dry_run: bool = Field(..., description="Whether this was only a preview")
+    count: int = Field(..., description="Number of memories matched (and changed, unless dry run)")
+    memory_ids: list[str] = Field(..., description="IDs of the matched memories")
+    confirmation_token: str | None = Field(
+        None, description="Pass back with dry_run=false to carry out the previewed run"
+    )
+
+
+class SaveUrlRequest(BaseModel):
+    """Request model for saving a web page as a bookmark memory"""
+
+    url: str = Field(..., description="http(s) URL of the page to save")
+
+    @field_validator("url")
+    @classmethod
+    def validate_url(cls, v):
+        v = v.strip()
+        if not v.lower().startswith(("http://", "https://")) or " " in v:
+            raise ValueError("URL must start with http:// or https://")
+        return v
+
+
+class SyncApplyRequest(BaseModel):
+    """Changes pushed by a syncing peer"""
+
+    changes: dict[str, Any] = Field(..., description="Output of the peer's /sync/changes")
+    since: datetime | None = Field(None, description="Peer's previous sync time")
+    peer: str | None = Field(None, description="Peer identifier for conflict logs")
+
+
 class MemoryStatsResponse(BaseModel):
     """Response model for memory statistics"""
 
@@ -127,6 +375,12 @@ class MemoryStatsResponse(BaseModel):
     categories: dict[str, int] = Field(..., description="Memory count per category")
     recent_memories: int = Field(..., description="Memories created in last 24 hours")
     storage_info: dict[str, Any] = Field(..., description="Storage backend information")
+    limits: dict[str, int] = Field(
+        default_factory=dict, description="Rate limit and payload size enforcement counts"
+    )
+    jobs: dict[str, dict[str, Any]] = Field(
+        default_factory=dict, description="Last-run status of scheduled jobs"
+    )
 
 
 class ErrorResponse(BaseModel):
@@ -148,12 +402,37 @@ class SearchRequest(BaseModel):
     """Request model for memory search - simplified (Issue #112)"""
 
     query: str = Field(..., description="Search query", min_length=1)
+    namespace: str | None = Field(
+        None, description="Namespace to search (defaults to header/config)"
+    )
+    owner: str | None = Field(None, description="Only return memories saved by this agent")
+    source: str | None = Field(
+        None, description='Source type ("obsidian") or exact source ("mcp:save_memory")'
+    )
+    include_pending: bool = Field(False, description="Include memories awaiting approval")
+    include_archived: bool = Field(False, description="Include archived memories")
     tags: list[str] | None = Field(None, description="Filter by AI-generated tags")
+    metadata: dict[str, str] | None = Field(
+        None,
+        description='Match metadata fields, e.g. {"person": "Alice"} (list fields match any item)',
+    )
     date_from: datetime | None = Field(None, description="Search from date")
     date_to: datetime | None = Field(None, description="Search to date")
     limit: int = Field(20, ge=1, le=100, description="Maximum results")
     offset: int = Field(0, ge=0, description="Results offset")
     search_type: str = Field("hybrid", description="Search type: fts5, semantic, or hybrid")
+    advanced: bool = Field(
+        False,
+        description='Use FTS5 syntax: AND/OR/NOT, "phrases", prefix*, NEAR(a b, 5)',
+    )
+    language: str | None = Field(
+        None, description="Only memories in this language: ja, zh, ko or en"
+    )
+    context: str | None = Field(
+        None,
+        description="Session context hint; memories whose category or tags match it rank higher",
+        max_length=500,
+    )
     # Issue #111: Add include_full_text parameter for optimized search responses
     include_full_text: bool = Field(
         False, description="Include full content in results (Issue #111)"
@@ -193,6 +472,23 @@ class SearchResponse(BaseModel):
     search_type: str = Field(..., description="Search type used")
     execution_time_ms: float = Field(..., description="Search execution time in milliseconds")
     filters: dict[str, Any] = Field(..., description="Applied filters")
+    query_id: int | None = Field(
+        None, description="Query log entry to report the chosen result to (MORY_QUERY_LOG)"
+    )
+
+
+class ReviewAutoTagsRequest(BaseModel):
+    """Request model for reviewing machine-generated tags"""
+
+    keep: list[str] | None = Field(
+        None, description="Auto tags to keep (default: all); the others are removed"
+    )
+
+
+class ChosenResultRequest(BaseModel):
+    """Request model for reporting which search result was used"""
+
+    memory_id: str = Field(..., min_length=1, description="ID of the memory that was used")
 
 
 # Issue #111: Optimized search response with summaries
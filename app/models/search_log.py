@@ -0,0 +1,57 @@
+"""Search query log model for Mory Server
+One entry per logged search (MORY_QUERY_LOG), with the result the caller chose if reported
+"""
+
+import json
+from datetime import datetime
+
+from sqlalchemy import DateTime, Index, Integer, String, Text
+from sqlalchemy.orm import Mapped, mapped_column
+
+from ..core.database import Base
+
+
+class SearchLog(Base):
+    """Single logged search"""
+
+    __tablename__ = "search_log"
+
+    id: Mapped[int] = mapped_column(Integer, primary_key=True, autoincrement=True)
+    namespace: Mapped[str] = mapped_column(String, default="default")
+    agent_id: Mapped[str | None] = mapped_column(String)
+    query: Mapped[str] = mapped_column(Text)
+    normalized_query: Mapped[str] = mapped_column(Text)  # Lowercased, whitespace collapsed
+    search_type: Mapped[str | None] = mapped_column(String)
+    hits: Mapped[int] = mapped_column(Integer, default=0)
+    result_ids: Mapped[str] = mapped_column(Text, default="[]")  # JSON, in result order
+    chosen_id: Mapped[str | None] = mapped_column(String)
+    chosen_at: Mapped[datetime | None] = mapped_column(DateTime)
+    created_at: Mapped[datetime] = mapped_column(DateTime, default=datetime.utcnow)
+
+    __table_args__ = (
+        Index("idx_search_log_created", "namespace", "created_at"),
+        Index("idx_search_log_query", "normalized_query"),
+    )
+
+    @property
+    def result_ids_list(self) -> list[str]:
+        try:
+            return json.loads(self.result_ids) if self.result_ids else []
+        except json.JSONDecodeError:
+            return []
+
+    def to_dict(self) -> dict:
+        return {
+            "id": self.id,
+            "namespace": self.namespace,
+            "agent_id": self.agent_id,
+            "query": self.query,
+            "search_type": self.search_type,
+            "hits": self.hits,
+            "result_ids": self.result_ids_list,
+            "chosen_id": self.chosen_id,
+            "created_at": self.created_at.isoformat() if self.created_at else None,
+        }
+
+    def __repr__(self):
+        return f"<SearchLog(id={self.id}, query='{self.query}')>"
@@ -0,0 +1,73 @@
+"""Archive tier
+Archived memories drop out of default list/search results but stay searchable with
+include_archived=true. The auto-archive policy moves stale, rarely read memories there.
+"""
+
+import logging
+from datetime import datetime, timedelta
+
+from sqlalchemy import func, or_
+from sqlalchemy.orm import Session
+
+from ..core.config import settings
+from ..core.events import MEMORY_UPDATED, MemoryEvent, event_bus
+from ..models.memory import Memory
+
+logger = logging.getLogger(__name__)
+
+
+def set_archived(
+    db: Session,
+    memory: Memory,
+    archived: bool,
+    agent_id: str | None = None,
+    details: dict | None = None,
+) -> MemoryEvent:
+    """Archive or restore a memory (does not commit); publish the returned event after
+    committing so the change is logged and reaches the files, webhooks and git mirror"""
+    memory.archived_at = datetime.utcnow() if archived else None
+    return MemoryEvent(
+        MEMORY_UPDATED,
+        memory,
+        session=db,
+        agent_id=agent_id,
+        details=details or {},
+        operation="archived" if archived else "unarchived",
+    )
+
+
+def find_stale_memories(
+    db: Session, after_days: int, max_access_count: int | None = None
+) -> list[Memory]:
+    """Unarchived memories neither updated nor read for after_days days"""
+    cutoff = datetime.utcnow() - timedelta(days=after_days)
+    last_touched = func.coalesce(Memory.last_accessed_at, Memory.updated_at)
+    query = db.query(Memory).filter(
+        Memory.archived_at.is_(None),
+        Memory.review_status == "approved",
+        Memory.updated_at < cutoff,
+        last_touched < cutoff,
+    )
+    if max_access_count is not None:
+        query = query.filter(
+            or_(Memory.access_count.is_(None), Memory.access_count <= max_access_count)
+        )
+    return query.all()
+
+
+async def auto_archive(db: Session) -> list[str]:
+    """Apply the MORY_ARCHIVE_AFTER_DAYS policy; returns the archived IDs"""
+    if settings.archive_after_days <= 0:
+        return []
+
+    stale = find_stale_memories(
+        db, settings.archive_after_days, settings.archive_max_access_count
+    )
+    events = [set_archived(db, memory, True, details={"reason": "auto"}) for memory in stale]
+    db.commit()
+
+    if stale:
+        logger.info(f"Auto-archived {len(stale)} stale memories")
+    for event in events:
+        await event_bus.publish(event)
+    return [memory.id for memory in stale]
@@ -0,0 +1,115 @@
+"""Automatic tags for memories saved without any (MORY_AUTO_TAG)
+Tags come from the most similar tagged memories when embeddings are available, otherwise
+from keywords extracted locally with RAKE. They are recorded in Memory.auto_tags so a
+human can keep or drop them later.
+"""
+
+import json
+import re
+from collections import Counter
+
+import numpy as np
+from sqlalchemy.orm import Session
+
+from ..models.memory import Memory
+
+MAX_AUTO_TAGS = 5
+NEIGHBORS = 5
+# Neighbors less similar than this do not lend their tags
+NEIGHBOR_THRESHOLD = 0.5
+MAX_PHRASE_WORDS = 3
+
+STOPWORDS = frozenset(
+    """
+    a about after again all also am an and any are as at be because been before being
+    but by can could did do does doing down during each few for from further had has have
+    having he her here hers him his how i if in into is it its just me more most my no
+    nor not now of off on once only or other our ours out over own same she should so
+    some such than that the their them then there these they this those through to too
+    under until up very was we were what when where which while who whom why will with
+    would you your yours
+    """.split()
+)
+
+# English words, katakana or kanji runs; any other single character ends a phrase
+_TOKEN = re.compile(r"[a-z][a-z0-9+#]*|[ァ-ヿ]{2,}|[一-鿿]{2,}|\S")
+
+
+def _phrases(text: str) -> list[list[str]]:
+    phrases, phrase = [], []
+    for token in _TOKEN.findall(text.lower()):
+        if len(token) > 1 and token not in STOPWORDS:
+            phrase.append(token)
+            continue
+        if phrase:
+            phrases.append(phrase)
+        phrase = []
+    if phrase:
+        phrases.append(phrase)
+    return [phrase for phrase in phrases if len(phrase) <= MAX_PHRASE_WORDS]
+
+
+def extract_keywords(text: str, limit: int = MAX_AUTO_TAGS) -> list[str]:
+    """RAKE keywords: phrases between stopwords, scored by word degree / frequency"""
+    phrases = _phrases(text)
+    frequency: Counter[str] = Counter()
+    degree: Counter[str] = Counter()
+    for phrase in phrases:
+        for word in phrase:
+            frequency[word] += 1
+            degree[word] += len(phrase)
+    scores: dict[str, float] = {}
+    for phrase in phrases:
+        scores["-".join(phrase)] = sum(degree[word] / frequency[word] for word in phrase)
+    # Ties keep the order of first appearance
+    return sorted(scores, key=lambda keyword: scores[keyword], reverse=True)[:limit]
+
+
+def neighbor_tags(db: Session, memory: Memory, limit: int = MAX_AUTO_TAGS) -> list[str]:
+    """Tags of the most similar tagged memories, weighted by similarity"""
+    if not memory.has_embedding:
+        return []
+    vector = np.frombuffer(memory.embedding, dtype=np.float32)
+    candidates = db.query(Memory).filter(
+        Memory.namespace == memory.namespace,
+        Memory.id != memory.id,
+        Memory.embedding.is_not(None),
+        Memory.tags != "[]",
+        Memory.archived_at.is_(None),
+    )
+    similarities = []
+    for other in candidates:
+        other_vector = np.frombuffer(other.embedding, dtype=np.float32)
+        if other_vector.shape != vector.shape:
+            continue
+        norm = np.linalg.norm(vector) * np.linalg.norm(other_vector)
+        similarity = float(np.dot(vector, other_vector) / norm) if norm else 0.0
+        if similarity >= NEIGHBOR_THRESHOLD:
+            similarities.append((similarity, other.tags_list))
+
+    weights: Counter[str] = Counter()
+    nearest = sorted(similarities, key=lambda item: item[0], reverse=True)[:NEIGHBORS]
+    for similarity, tags in nearest:
+        for tag in tags:
+            weights[tag] += similarity
+    return [tag for tag, _ in weights.most_common(limit)]
+
+
+def auto_tag(db: Session, memory: Memory) -> list[str]:
+    """Tag an untagged memory and mark the tags for review (not committed)"""
+    if memory.tags_list:
+        return []
+    tags = neighbor_tags(db, memory) or extract_keywords(memory.value)
+    if tags:
+        memory.tags_list = tags
+        memory.auto_tags = json.dumps(tags, ensure_ascii=False)
+    return tags
+
+
+def review_auto_tags(memory: Memory, keep: list[str] | None = None) -> list[str]:
+    """Keep the chosen auto tags (all by default), drop the rest; returns the dropped ones"""
+    pending = memory.auto_tags_list
+    dropped = [tag for tag in pending if keep is not None and tag not in keep]
+    memory.tags_list = [tag for tag in memory.tags_list if tag not in dropped]
+    memory.auto_tags = None
+    return dropped
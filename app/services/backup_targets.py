@@ -0,0 +1,192 @@
+"""Off-site backup targets
+Uploads backup snapshots to S3-compatible storage (AWS, MinIO, GCS interoperability)
+or WebDAV (Nextcloud etc.), configured with MORY_BACKUP_TARGETS. When
+MORY_BACKUP_ENCRYPTION_KEY is set, uploads are encrypted client-side with Fernet
+(pip install 'mory-server[backup]').
+"""
+
+import hashlib
+import hmac
+import logging
+from datetime import datetime
+from typing import Any
+from urllib.parse import quote, urlparse
+
+import httpx
+
+logger = logging.getLogger(__name__)
+
+ENCRYPTED_SUFFIX = ".enc"
+
+DEFAULT_ENDPOINTS = {
+    "s3": "https://s3.{region}.amazonaws.com",
+    "gcs": "https://storage.googleapis.com",
+}
+
+REQUIRED_FIELDS = {
+    "s3": ("bucket", "access_key", "secret_key"),
+    "gcs": ("bucket", "access_key", "secret_key"),
+    "webdav": ("url",),
+}
+
+
+def _fernet(key: str):
+    try:
+        from cryptography.fernet import Fernet
+    except ImportError as e:
+        raise RuntimeError(
+            "Backup encryption requires cryptography (pip install 'mory-server[backup]')"
+        ) from e
+    return Fernet(key.encode())
+
+
+def encrypt(data: bytes, key: str) -> bytes:
+    """Encrypt a snapshot with a Fernet key (see `Fernet.generate_key()`)"""
+    return _fernet(key).encrypt(data)
+
+
+def decrypt(data: bytes, key: str) -> bytes:
+    """Decrypt a snapshot downloaded from a backup target"""
+    return _fernet(key).decrypt(data)
+
+
+class BackupTarget:
+    """Destination a snapshot is uploaded to"""
+
+    def __init__(self, config: dict[str, Any], transport: httpx.BaseTransport | None = None):
+        self.config = config
+        self.transport = transport
+
+    def upload(self, filename: str, data: bytes) -> str:
+        """Upload a file and return where it was stored"""
+        raise NotImplementedError
+
+
+class S3Target(BackupTarget):
+    """S3-compatible object storage, signed with AWS Signature Version 4"""
+
+    def _object_url(self, filename: str) -> tuple[str, str]:
+        default_region = "auto" if self.config["type"] == "gcs" else "us-east-1"
+        region = self.config.get("region", default_region)
+        endpoint = self.config.get("endpoint") or DEFAULT_ENDPOINTS[self.config["type"]]
+        endpoint = endpoint.format(region=region).rstrip("/")
+        prefix = self.config.get("prefix", "").strip("/")
+        key = f"{prefix}/{filename}" if prefix else filename
+        return f"{endpoint}/{self.config['bucket']}/{quote(key)}", region
+
+    def _sign(self, url: str, region: str, payload: bytes, now: datetime) -> dict[str, str]:
+        parsed = urlparse(url)
+        amz_date = now.strftime("%Y%m%dT%H%M%SZ")
+        date = now.strftime("%Y%m%d")
+        payload_hash = hashlib.sha256(payload).hexdigest()
+        headers = {
+            "host": parsed.netloc,
+            "x-amz-content-sha256": payload_hash,
+            "x-amz-date": amz_date,
+        }
+        signed_headers = ";".join(headers)
+        canonical_request = "\n".join(
+            [
+                "PUT",
+                parsed.path,
+                "",
+                "".join(f"{k}:{v}\n" for k, v in headers.items()),
+                signed_headers,
+                payload_hash,
+            ]
+        )
+        scope = f"{date}/{region}/s3/aws4_request"
+        string_to_sign = "\n".join(
+            [
+                "AWS4-HMAC-SHA256",
+                amz_date,
+                scope,
+                hashlib.sha256(canonical_request.encode()).hexdigest(),
+            ]
+        )
+
+        key = f"AWS4{self.config['secret_key']}".encode()
+        for part in (date, region, "s3", "aws4_request"):
+            key = hmac.new(key, part.encode(), hashlib.sha256).digest()
+        signature = hmac.new(key, string_to_sign.encode(), hashlib.sha256).hexdigest()
+
+        headers["authorization"] = (
+            f"AWS4-HMAC-SHA256 Credential={self.config['access_key']}/{scope}, "
+            f"SignedHeaders={signed_headers}, Signature={signature}"
+        )
+        del headers["host"]  # httpx sets it from the URL
+        return headers
+
+    def upload(self, filename: str, data: bytes) -> str:
+        url, region = self._object_url(filename)
+        headers = self._sign(url, region, data, datetime.utcnow())
+        with httpx.Client(timeout=300.0, transport=self.transport) as client:
+            response = client.put(url, content=data, headers=headers)
+            response.raise_for_status()
+        return url
+
+
+class WebDAVTarget(BackupTarget):
+    """WebDAV collection such as a Nextcloud folder"""
+
+    def upload(self, filename: str, data: bytes) -> str:
+        url = f"{self.config['url'].rstrip('/')}/{quote(filename)}"
+        auth = None
+        if self.config.get("username"):
+            auth = (self.config["username"], self.config.get("password", ""))
+        with httpx.Client(timeout=300.0, auth=auth, transport=self.transport) as client:
+            response = client.put(url, content=data)
+            response.raise_for_status()
+        return url
+
+
+TARGET_TYPES: dict[str, type[BackupTarget]] = {
+    "s3": S3Target,
+    "gcs": S3Target,
+    "webdav": WebDAVTarget,
+}
+
+
+def validate_target(config: dict[str, Any]) -> str | None:
+    """Return a problem with a target definition, or None when it looks usable"""
+    target_type = config.get("type")
+    if target_type not in TARGET_TYPES:
+        return f"unknown type '{target_type}' (expected one of: {', '.join(TARGET_TYPES)})"
+    missing = [name for name in REQUIRED_FIELDS[target_type] if not config.get(name)]
+    if missing:
+        return f"{target_type} target is missing {', '.join(missing)}"
+    return None
+
+
+def build_target(
+    config: dict[str, Any], transport: httpx.BaseTransport | None = None
+) -> BackupTarget:
+    problem = validate_target(config)
+    if problem:
+        raise ValueError(problem)
+    return TARGET_TYPES[config["type"]](config, transport=transport)
+
+
+def upload_snapshot(
+    filename: str,
+    data: bytes,
+    targets: list[dict[str, Any]],
+    encryption_key: str | None = None,
+    transport: httpx.BaseTransport | None = None,
+) -> tuple[list[str], list[str]]:
+    """Upload a snapshot to every target; returns (uploaded locations, errors)"""
+    if encryption_key:
+        data = encrypt(data, encryption_key)
+        filename += ENCRYPTED_SUFFIX
+
+    uploaded: list[str] = []
+    errors: list[str] = []
+    for config in targets:
+        name = config.get("name") or config.get("type")
+        try:
+            target = build_target(config, transport=transport)
+            uploaded.append(target.upload(filename, data))
+        except (ValueError, httpx.HTTPError) as e:
+            logger.error(f"Backup upload to {name} failed: {e}")
+            errors.append(f"{name}: {e}")
+    return uploaded, errors
@@ -0,0 +1,165 @@
+"""Bulk operations on memories matching a filter
+Cleaning up a bad import or reorganizing a category selects memories by category, tag
+and creation date instead of changing them one by one. Every operation is previewed
+first and only carried out with the preview's confirmation token (see core/confirmation).
+Moves and retags are one UPDATE statement and one operation log entry, not a per-memory
+event, so webhooks and the git mirror do not see them individually.
+"""
+
+import json
+import logging
+from datetime import datetime
+from typing import Any
+
+from sqlalchemy import DateTime, bindparam, case, func, text
+from sqlalchemy.orm import Query, Session
+
+from ..core.events import MEMORY_DELETED, MemoryEvent, event_bus
+from ..models.memory import Memory
+from ..models.schemas import MemoryFilter
+from .counts import has_tag
+from .file_store import DEFAULT_CATEGORY, file_store
+from .operation_log import record_operation
+from .sync import memory_record
+
+logger = logging.getLogger(__name__)
+
+# A memory's tags as a JSON array, whatever is stored
+_TAGS = "CASE WHEN json_valid(memories.tags) THEN memories.tags ELSE '[]' END"
+
+# Make :category the first tag, keeping the other tags after it
+MOVE_SQL = text(
+    f"""
+    UPDATE memories SET updated_at = :now, tags = (
+        SELECT json_group_array(value) FROM (
+            SELECT :category AS value, -1 AS position
+            UNION ALL
+            SELECT tag.value, tag.key FROM json_each({_TAGS}) tag
+            WHERE tag.key > 0 AND tag.value != :category
+            ORDER BY position
+        )
+    )
+    WHERE id IN (SELECT value FROM json_each(:ids))
+    """
+).bindparams(bindparam("now", type_=DateTime))
+
+# Drop the :remove tags and append the :add tags a memory does not have yet
+RETAG_SQL = text(
+    f"""
+    UPDATE memories SET updated_at = :now, tags = (
+        SELECT json_group_array(value) FROM (
+            SELECT tag.value AS value, tag.key AS position FROM json_each({_TAGS}) tag
+            WHERE tag.value NOT IN (SELECT value FROM json_each(:remove))
+            UNION ALL
+            SELECT added.value, 1000000 + added.key FROM json_each(:add) added
+            WHERE added.value NOT IN (SELECT value FROM json_each({_TAGS}))
+            ORDER BY position
+        )
+    )
+    WHERE id IN (SELECT value FROM json_each(:ids))
+    """
+).bindparams(bindparam("now", type_=DateTime))
+
+
+def category_column():
+    """SQL expression for a memory's category: its first tag, or uncategorized"""
+    first_tag = case((func.json_valid(Memory.tags), func.json_extract(Memory.tags, "$[0]")))
+    return func.coalesce(first_tag, DEFAULT_CATEGORY)
+
+
+def filter_memories(db: Session, namespace: str, memory_filter: MemoryFilter) -> Query:
+    """Memories in the namespace matching every condition, oldest first
+    (pending and archived memories included)"""
+    query = db.query(Memory).filter(Memory.namespace == namespace)
+    if memory_filter.category:
+        query = query.filter(category_column() == memory_filter.category)
+    if memory_filter.tag:
+        query = query.filter(has_tag(memory_filter.tag))
+    if memory_filter.date_from:
+        query = query.filter(Memory.created_at >= memory_filter.date_from)
+    if memory_filter.date_to:
+        query = query.filter(Memory.created_at <= memory_filter.date_to)
+    return query.order_by(Memory.created_at.asc())
+
+
+def bulk_scope(namespace: str, params: dict[str, Any], memories: list[Memory]) -> dict[str, Any]:
+    """What a confirmation token is bound to: the request and the exact memory versions"""
+    return {
+        "namespace": namespace,
+        "params": params,
+        "memories": [[memory.id, memory.updated_at.isoformat()] for memory in memories],
+    }
+
+
+async def delete_memories(db: Session, memories: list[Memory], agent_id: str | None) -> None:
+    """Delete the memories in one transaction, then publish a deletion event for each"""
+    for memory in memories:
+        db.delete(memory)
+    db.commit()
+    logger.info(f"Bulk-deleted {len(memories)} memories")
+    for memory in memories:
+        await event_bus.publish(
+            MemoryEvent(
+                MEMORY_DELETED,
+                memory,
+                session=db,
+                agent_id=agent_id,
+                details={"summary": memory.summary, "bulk": "delete_memories"},
+            )
+        )
+
+
+def _update_tags(
+    db: Session,
+    statement,
+    operation: str,
+    memories: list[Memory],
+    agent_id: str | None,
+    details: dict[str, Any],
+    **params: Any,
+) -> None:
+    """Run a tag UPDATE over the memories, log it once, and rewrite their files"""
+    memory_ids = [memory.id for memory in memories]
+    before = {memory.id: memory.tags_list for memory in memories}
+    db.execute(statement, {"now": datetime.utcnow(), "ids": json.dumps(memory_ids), **params})
+    record_operation(
+        db,
+        operation,
+        agent_id=agent_id,
+        details={**details, "memory_ids": memory_ids, "before": before},
+    )
+    db.commit()
+    logger.info(f"Bulk {operation} {len(memories)} memories")
+
+    store = file_store()
+    if store is not None:
+        store.write_many(memory_record(memory) for memory in memories)
+
+
+def move_memories(
+    db: Session, memories: list[Memory], category: str, agent_id: str | None
+) -> None:
+    """Make category the first tag of every memory (commits)"""
+    _update_tags(
+        db, MOVE_SQL, "moved", memories, agent_id, {"category": category}, category=category
+    )
+
+
+def retag_memories(
+    db: Session,
+    memories: list[Memory],
+    add: list[str],
+    remove: list[str],
+    agent_id: str | None,
+) -> None:
+    """Add and remove tags on every memory (commits)"""
+    _update_tags(
+        db,
+        RETAG_SQL,
+        "retagged",
+        memories,
+        agent_id,
+        {"add": add, "remove": remove},
+        add=json.dumps(add),
+        remove=json.dumps(remove),
+    )
@@ -0,0 +1,144 @@
+"""Obsidian canvas export
+Lays memories out as a JSON Canvas (the .canvas format Obsidian opens) for visual review
+of a topic: one group per category holding a grid of text cards. Memories have no
+explicit relations, so edges link each memory to its most similar neighbors by embedding.
+"""
+
+from collections import defaultdict
+from collections.abc import Callable
+from typing import Any
+
+import numpy as np
+from sqlalchemy.orm import Session
+
+from ..models.memory import Memory
+from .bulk import category_column
+from .counts import has_tag
+from .file_store import DEFAULT_CATEGORY
+
+CANVAS_LIMIT = 50
+MAX_CANVAS_LIMIT = 200
+
+# Card and group geometry, in canvas pixels
+CARD_WIDTH = 400
+CARD_HEIGHT = 240
+GAP = 40
+LABEL_SPACE = 40  # Above the cards, for the group's label
+COLUMNS = 4
+
+# Characters of a memory shown on its card
+CARD_TEXT_LENGTH = 600
+
+# Edges: cosine similarity needed, and the most edges drawn from one memory
+EDGE_SIMILARITY = 0.8
+EDGES_PER_MEMORY = 2
+
+
+def canvas_memories(
+    db: Session,
+    namespace: str,
+    category: str | None = None,
+    tag: str | None = None,
+    limit: int = CANVAS_LIMIT,
+    visible: Callable[[Memory], bool] | None = None,
+) -> list[Memory]:
+    """Approved, unarchived memories in the category and with the tag, oldest first
+
+    With a visible predicate, memories it rejects are left out before the limit applies.
+    """
+    query = db.query(Memory).filter(
+        Memory.namespace == namespace,
+        Memory.review_status == "approved",
+        Memory.archived_at.is_(None),
+    )
+    if category:
+        query = query.filter(category_column() == category)
+    if tag:
+        query = query.filter(has_tag(tag))
+    query = query.order_by(Memory.created_at, Memory.id)
+    if visible is None:
+        return query.limit(limit).all()
+    return [memory for memory in query.all() if visible(memory)][:limit]
+
+
+def card_text(memory: Memory) -> str:
+    """Markdown for a memory's card: its (shortened) content, tags and ID"""
+    value = memory.value.strip()
+    if len(value) > CARD_TEXT_LENGTH:
+        value = value[:CARD_TEXT_LENGTH].rstrip() + "…"
+    lines = [value, ""]
+    if memory.tags_list:
+        lines.append(" ".join(f"#{tag.replace(' ', '-')}" for tag in memory.tags_list))
+    lines.append(f"`{memory.id}`")
+    return "\n".join(lines)
+
+
+def similarity_edges(memories: list[Memory]) -> list[tuple[str, str, float]]:
+    """(from ID, to ID, similarity) for each memory's closest neighbors, each pair once"""
+    embedded = [memory for memory in memories if memory.has_embedding]
+    vectors = [np.frombuffer(memory.embedding, dtype=np.float32) for memory in embedded]
+    if len(vectors) < 2 or len({vector.shape for vector in vectors}) > 1:
+        return []
+    matrix = np.stack(vectors)
+    norms = np.linalg.norm(matrix, axis=1, keepdims=True)
+    matrix = matrix / np.where(norms == 0, 1, norms)
+    similarities = matrix @ matrix.T
+    np.fill_diagonal(similarities, -1.0)
+
+    edges: dict[tuple[int, int], float] = {}
+    for i, row in enumerate(similarities):
+        for j in np.argsort(row)[::-1][:EDGES_PER_MEMORY]:
+            if row[j] < EDGE_SIMILARITY:
+                break
+            edges.setdefault((min(i, j), max(i, j)), float(row[j]))
+    return [(embedded[i].id, embedded[j].id, score) for (i, j), score in sorted(edges.items())]
+
+
+def build_canvas(memories: list[Memory]) -> dict[str, Any]:
+    """JSON Canvas document: category groups side by side, cards in a grid inside each"""
+    by_category: dict[str, list[Memory]] = defaultdict(list)
+    for memory in memories:
+        tags = memory.tags_list
+        by_category[tags[0] if tags else DEFAULT_CATEGORY].append(memory)
+
+    nodes: list[dict[str, Any]] = []
+    x = 0
+    # Largest categories first, ties by name
+    for index, (category, members) in enumerate(
+        sorted(by_category.items(), key=lambda item: (-len(item[1]), item[0]))
+    ):
+        columns = min(COLUMNS, len(members))
+        rows = -(-len(members) // columns)
+        width = columns * (CARD_WIDTH + GAP) + GAP
+        height = LABEL_SPACE + rows * (CARD_HEIGHT + GAP) + GAP
+        nodes.append(
+            {
+                "id": f"group-{index}",
+                "type": "group",
+                "label": category,
+                "x": x,
+                "y": 0,
+                "width": width,
+                "height": height,
+            }
+        )
+        for position, memory in enumerate(members):
+            row, column = divmod(position, columns)
+            nodes.append(
+                {
+                    "id": memory.id,
+                    "type": "text",
+                    "text": card_text(memory),
+                    "x": x + GAP + column * (CARD_WIDTH + GAP),
+                    "y": LABEL_SPACE + GAP + row * (CARD_HEIGHT + GAP),
+                    "width": CARD_WIDTH,
+                    "height": CARD_HEIGHT,
+                }
+            )
+        x += width + 2 * GAP
+
+    edges = [
+        {"id": f"edge-{index}", "fromNode": source, "toNode": target, "label": f"{score:.2f}"}
+        for index, (source, target, score) in enumerate(similarity_edges(memories))
+    ]
+    return {"nodes": nodes, "edges": edges}
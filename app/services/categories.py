@@ -0,0 +1,108 @@
+"""Category descriptions and category suggestions
+A short description per category is embedded so suggest_categories can route a new
+memory to the closest existing category instead of the assistant inventing a new one.
+Without embeddings, categories are matched by the words of their name and description.
+"""
+
+import re
+from typing import Any
+
+import numpy as np
+from sqlalchemy.orm import Session
+
+from ..core.config import settings
+from ..models.category import Category
+from .embedding import embedding_service
+from .report import category_counts
+
+DEFAULT_SUGGESTIONS = 3
+
+
+def _words(text: str) -> set[str]:
+    return {word for word in re.findall(r"\w+", text.lower()) if len(word) > 2}
+
+
+def _cosine(a: np.ndarray, b: np.ndarray) -> float:
+    return float(np.dot(a, b) / (np.linalg.norm(a) * np.linalg.norm(b)))
+
+
+async def describe_category(db: Session, namespace: str, name: str, description: str) -> Category:
+    """Set (or replace) a category's description and embed it; commits"""
+    category = (
+        db.query(Category).filter(Category.namespace == namespace, Category.name == name).first()
+    ) or Category(namespace=namespace, name=name)
+    category.description = description
+    embedding = await embedding_service.generate_embedding(f"{name}: {description}")
+    category.embedding = embedding.tobytes() if embedding is not None else None
+    category.embedding_model = settings.openai_model if embedding is not None else None
+    db.add(category)
+    db.commit()
+    return category
+
+
+def delete_category_description(db: Session, namespace: str, name: str) -> bool:
+    """Remove a category's description; False if it had none"""
+    deleted = (
+        db.query(Category).filter(Category.namespace == namespace, Category.name == name).delete()
+    )
+    db.commit()
+    return deleted > 0
+
+
+def list_categories(db: Session, namespace: str) -> list[dict[str, Any]]:
+    """Categories in use or described, with memory counts and descriptions"""
+    counts = dict(category_counts(db, namespace, limit=-1))
+    described = {
+        category.name: category
+        for category in db.query(Category).filter(Category.namespace == namespace)
+    }
+    names = sorted(counts.keys() | described.keys(), key=lambda name: (-counts.get(name, 0), name))
+    return [
+        {
+            "name": name,
+            "memories": counts.get(name, 0),
+            "description": described[name].description if name in described else None,
+        }
+        for name in names
+    ]
+
+
+async def suggest_categories(
+    db: Session, namespace: str, text: str, limit: int = DEFAULT_SUGGESTIONS
+) -> dict[str, Any]:
+    """Best matching categories for a new memory's text
+
+    Described categories are compared by embedding similarity when embeddings are
+    available; otherwise every known category scores by the share of its name and
+    description words found in the text.
+    """
+    categories = {category["name"]: category for category in list_categories(db, namespace)}
+    embedded = [
+        category
+        for category in db.query(Category).filter(Category.namespace == namespace)
+        if category.embedding
+    ]
+    vector = await embedding_service.generate_embedding(text) if embedded else None
+
+    scores: dict[str, float] = {}
+    if vector is not None:
+        method = "semantic"
+        for category in embedded:
+            scores[category.name] = _cosine(vector, np.frombuffer(category.embedding, np.float32))
+    else:
+        method = "keyword"
+        words = _words(text)
+        for name, category in categories.items():
+            category_words = _words(f"{name} {category['description'] or ''}")
+            if category_words and words & category_words:
+                scores[name] = len(words & category_words) / len(category_words)
+
+    ranked = sorted(scores.items(), key=lambda item: item[1], reverse=True)[:limit]
+    return {
+        "method": method,
+        "suggestions": [
+            {**categories[name], "score": round(score, 3)}
+            for name, score in ranked
+            if name in categories
+        ],
+    }
@@ -0,0 +1,85 @@
+"""Content types of memory values: text, markdown, code and json
+Detected on save unless the client says what it is. Code may name its language as
+"code:<language>", like sources name their detail. Code and JSON are rendered as
+fenced blocks so clients show them verbatim.
+"""
+
+import json
+import re
+
+CONTENT_TYPES = ("text", "markdown", "code", "json")
+
+_FENCED = re.compile(r"\A```([\w+#-]*)\n.*\n```\Z", re.DOTALL)
+_CODE_LINE = re.compile(
+    r"^\s*(def |class |import |from \S+ import |return\b|if .*:$|for .*:$|while .*:$|"
+    r"const |let |var |function\b|func |package |#include|public |private |SELECT |INSERT |"
+    r"<[a-zA-Z/!])|[;{}]\s*$|\)\s*:?\s*$|^\s*[\w.]+\s*=\s*\S"
+)
+_MARKDOWN_LINE = re.compile(r"^(#{1,6} |\s*[-*+] |\s*\d+\. |> |\|.*\|$)|\[[^\]]+\]\([^)]+\)|\*\*\S")
+
+# Keywords suggesting a language, checked in order
+_LANGUAGE_HINTS = (
+    ("python", re.compile(r"^\s*(def |import |from \S+ import |class \w+.*:$)", re.MULTILINE)),
+    ("go", re.compile(r"^\s*(package |func )", re.MULTILINE)),
+    ("javascript", re.compile(r"^\s*(const |let |function\b)|=>", re.MULTILINE)),
+    ("sql", re.compile(r"^\s*(SELECT|INSERT|UPDATE|CREATE TABLE) ", re.MULTILINE)),
+    ("html", re.compile(r"^\s*<[a-zA-Z!]", re.MULTILINE)),
+)
+
+
+def base_type(content_type: str | None) -> str:
+    """text, markdown, code or json; unknown types count as text"""
+    base = (content_type or "text").split(":", 1)[0]
+    return base if base in CONTENT_TYPES else "text"
+
+
+def _code_language(value: str) -> str:
+    for language, pattern in _LANGUAGE_HINTS:
+        if pattern.search(value):
+            return language
+    return ""
+
+
+def detect_content_type(value: str) -> str:
+    """Guess the content type of a value, e.g. "json", "code:python" or "text" """
+    stripped = value.strip()
+    if stripped[:1] in ("{", "["):
+        try:
+            json.loads(stripped)
+            return "json"
+        except ValueError:
+            pass
+
+    fenced = _FENCED.match(stripped)
+    if fenced:
+        language = fenced.group(1) or _code_language(stripped)
+        return f"code:{language}" if language else "code"
+
+    lines = [line for line in stripped.splitlines() if line.strip()]
+    if len(lines) >= 2 and sum(1 for line in lines if _CODE_LINE.search(line)) * 2 > len(lines):
+        language = _code_language(stripped)
+        return f"code:{language}" if language else "code"
+    if any(_MARKDOWN_LINE.search(line) for line in lines):
+        return "markdown"
+    return "text"
+
+
+def render_content(value: str, content_type: str | None) -> str | None:
+    """Fenced block for code and JSON values (None for text and markdown)"""
+    base = base_type(content_type)
+    if base == "json":
+        try:
+            value = json.dumps(json.loads(value), indent=2, ensure_ascii=False)
+        except ValueError:
+            pass
+        language = "json"
+    elif base == "code":
+        if _FENCED.match(value.strip()):
+            return value.strip()
+        language = content_type.partition(":")[2]
+    else:
+        return None
+    # A fence longer than any backtick run inside the value
+    longest = max((len(run) for run in re.findall(r"`+", value)), default=0)
+    fence = "`" * max(3, longest + 1)
+    return f"{fence}{language}\n{value}\n{fence}"
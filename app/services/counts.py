@@ -0,0 +1,86 @@
+"""Counting queries
+Stats, health checks and metrics ask SQLite with COUNT(*) instead of loading every
+memory and counting in Python.
+"""
+
+from sqlalchemy import TextClause, case, func, text
+from sqlalchemy.orm import Query, Session
+
+from ..models.memory import Memory
+
+
+def _scoped(db: Session, namespace: str | None, *columns) -> Query:
+    query = db.query(*columns) if columns else db.query(Memory)
+    if namespace is not None:
+        query = query.filter(Memory.namespace == namespace)
+    return query
+
+
+def has_tag(tag: str) -> TextClause:
+    """Filter for memories carrying the tag, compared as a whole JSON array element"""
+    return text(
+        "EXISTS (SELECT 1 FROM json_each(CASE WHEN json_valid(memories.tags) "
+        "THEN memories.tags ELSE '[]' END) WHERE json_each.value = :has_tag)"
+    ).bindparams(has_tag=tag)
+
+
+def count_memories(db: Session, namespace: str | None = None, tag: str | None = None) -> int:
+    """Number of memories, optionally only those carrying a tag"""
+    query = _scoped(db, namespace, func.count(Memory.id))
+    if tag is not None:
+        query = query.filter(has_tag(tag))
+    return query.scalar() or 0
+
+
+def count_with_embeddings(db: Session, namespace: str | None = None) -> int:
+    """Number of memories that have an embedding"""
+    query = _scoped(db, namespace, func.count(Memory.id))
+    return query.filter(Memory.embedding.isnot(None)).scalar() or 0
+
+
+def memory_exists(db: Session, memory_id: str, namespace: str | None = None) -> bool:
+    """Whether a memory ID exists, without loading the row"""
+    query = _scoped(db, namespace).filter(Memory.id == memory_id)
+    return db.query(query.exists()).scalar()
+
+
+def tag_counts(
+    db: Session, namespace: str | None = None, limit: int | None = None
+) -> list[tuple[str, int]]:
+    """(tag, number of memories) pairs, most used first"""
+    sql = """
+        SELECT tag.value AS tag, COUNT(*) AS uses
+        FROM memories m, json_each(CASE WHEN json_valid(m.tags) THEN m.tags ELSE '[]' END) tag
+        WHERE (:namespace IS NULL OR m.namespace = :namespace)
+        GROUP BY tag.value
+        ORDER BY uses DESC, tag.value
+    """
+    if limit is not None:
+        sql += " LIMIT :limit"
+    rows = db.execute(text(sql), {"namespace": namespace, "limit": limit}).all()
+    return [(row.tag, row.uses) for row in rows]
+
+
+def dashboard_counts(db: Session, namespace: str | None = None) -> dict[str, int]:
+    """All dashboard stat cards in one aggregate query"""
+
+    def count_where(condition):
+        return func.coalesce(func.sum(case((condition, 1), else_=0)), 0)
+
+    row = _scoped(
+        db,
+        namespace,
+        func.count(Memory.id),
+        count_where(Memory.embedding.isnot(None)),
+        count_where(Memory.ai_processed_at.isnot(None)),
+        count_where(Memory.review_status == "pending"),
+        count_where(Memory.archived_at.isnot(None)),
+    ).one()
+    total, embedded, processed, pending, archived = (int(value or 0) for value in row)
+    return {
+        "total_memories": total,
+        "memories_with_embeddings": embedded,
+        "ai_processed": processed,
+        "pending_review": pending,
+        "archived": archived,
+    }
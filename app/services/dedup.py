@@ -0,0 +1,31 @@
+"""Duplicate detection by normalized content
+content_hash ignores case, Unicode width variants and whitespace, so the same note
+imported again under another file name or re-indented still matches its memory.
+"""
+
+import hashlib
+import unicodedata
+
+from sqlalchemy.orm import Session
+
+from ..models.memory import Memory
+
+
+def normalize(text: str) -> str:
+    """NFKC, case-folded, with runs of whitespace collapsed to one space"""
+    return " ".join(unicodedata.normalize("NFKC", text).casefold().split())
+
+
+def content_hash(text: str) -> str:
+    """SHA-256 of the normalized text"""
+    return hashlib.sha256(normalize(text).encode("utf-8")).hexdigest()
+
+
+def backfill_content_hashes(db: Session, batch_size: int = 500) -> int:
+    """Hash memories saved before content hashes were recorded; returns the count"""
+    ids = [row.id for row in db.query(Memory.id).filter(Memory.content_hash.is_(None))]
+    for start in range(0, len(ids), batch_size):
+        for memory in db.query(Memory).filter(Memory.id.in_(ids[start : start + batch_size])):
+            memory.content_hash = content_hash(memory.value or "")
+        db.commit()
+    return len(ids)
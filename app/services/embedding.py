@@ -1,11 +1,71 @@
 """Embedding service for generating and managing vector embeddings"""
 
+import logging
+from typing import Protocol
+
 import numpy as np
 import openai
 from sqlalchemy.orm import Session
 
 from ..core.config import settings
+from ..core.metrics import metrics
+from ..core.tracing import trace_span
 from ..models.memory import Memory
+from ..models.memory_chunk import MemoryChunk
+
+logger = logging.getLogger(__name__)
+
+# Characters repeated at the start of each chunk so a sentence cut in two stays findable
+CHUNK_OVERLAP = 200
+
+# Break points tried from the end of a chunk, best first
+_BREAKS = ("\n\n", "\n", "。", ". ", " ")
+
+
+class Embedder(Protocol):
+    """What search needs to embed a query (EmbeddingService, or a fake in tests)"""
+
+    async def generate_embedding(self, text: str) -> np.ndarray | None: ...
+
+
+def split_chunks(text: str, size: int, overlap: int = CHUNK_OVERLAP) -> list[tuple[int, str]]:
+    """(offset, text) slices of at most size characters, cut at a paragraph, line,
+    sentence or word break in their second half when there is one"""
+    if len(text) <= size:
+        return [(0, text)]
+    overlap = min(overlap, size // 4)
+    chunks = []
+    start = 0
+    while True:
+        end = min(start + size, len(text))
+        if end < len(text):
+            window = text[start:end]
+            for separator in _BREAKS:
+                cut = window.rfind(separator)
+                if cut > size // 2:
+                    end = start + cut + len(separator)
+                    break
+        chunks.append((start, text[start:end]))
+        if end >= len(text):
+            return chunks
+        start = end - overlap
+
+
+def load_chunk_vectors(db: Session, memory_ids: list[str]) -> dict[str, list[np.ndarray]]:
+    """Chunk vectors of the given memories, for those embedded in chunks"""
+    vectors: dict[str, list[np.ndarray]] = {}
+    if not memory_ids:
+        return vectors
+    chunks = (
+        db.query(MemoryChunk)
+        .filter(MemoryChunk.memory_id.in_(memory_ids))
+        .order_by(MemoryChunk.memory_id, MemoryChunk.chunk_index)
+        .all()
+    )
+    for chunk in chunks:
+        vector = np.frombuffer(chunk.embedding, dtype=np.float32)
+        vectors.setdefault(chunk.memory_id, []).append(vector)
+    return vectors
 
 
 class EmbeddingService:
@@ -31,11 +91,30 @@ class EmbeddingService:
             return None
 
         try:
-            response = openai.embeddings.create(model=settings.openai_model, input=text)
+            with trace_span("embedding"):
+                response = openai.embeddings.create(model=settings.openai_model, input=text)
+            metrics.inc("mory_embedding_api_calls_total", {"outcome": "success"})
             embedding_vector = response.data[0].embedding
             return np.array(embedding_vector, dtype=np.float32)
         except Exception as e:
-            print(f"Embedding generation failed: {e}")
+            metrics.inc("mory_embedding_api_calls_total", {"outcome": "error"})
+            logger.error(f"Embedding generation failed: {e}")
+            return None
+
+    async def generate_embeddings(self, texts: list[str]) -> list[np.ndarray] | None:
+        """Embedding vectors for several texts in one request (None if any fails)"""
+        if not self.enabled or not texts:
+            return None
+
+        try:
+            with trace_span("embedding"):
+                response = openai.embeddings.create(model=settings.openai_model, input=texts)
+            metrics.inc("mory_embedding_api_calls_total", {"outcome": "success"})
+            data = sorted(response.data, key=lambda item: item.index)
+            return [np.array(item.embedding, dtype=np.float32) for item in data]
+        except Exception as e:
+            metrics.inc("mory_embedding_api_calls_total", {"outcome": "error"})
+            logger.error(f"Embedding generation failed: {e}")
             return None
 
     async def generate_embedding_for_memory(self, memory: Memory) -> bool:
@@ -54,13 +133,28 @@ class EmbeddingService:
         # Use summary if available, otherwise use original value
         text_for_embedding = memory.summary or memory.value
 
-        embedding = await self.generate_embedding(text_for_embedding)
-        if embedding is not None:
-            memory.embedding = embedding.tobytes()
-            memory.embedding_model = settings.openai_model
-            return True
-
-        return False
+        chunks = split_chunks(text_for_embedding, settings.embedding_chunk_size)
+        if len(chunks) == 1:
+            embedding = await self.generate_embedding(text_for_embedding)
+            if embedding is None:
+                return False
+            memory.chunks = []
+        else:
+            vectors = await self.generate_embeddings([chunk for _, chunk in chunks])
+            if vectors is None:
+                return False
+            memory.chunks = [
+                MemoryChunk(chunk_index=index, start=start, embedding=vector.tobytes())
+                for index, ((start, _), vector) in enumerate(zip(chunks, vectors))
+            ]
+            # Single-vector consumers (similar memories, categories) use the normalized mean
+            unit = [vector / (np.linalg.norm(vector) or 1.0) for vector in vectors]
+            embedding = np.mean(unit, axis=0).astype(np.float32)
+            logger.info(f"Embedded {memory.id} in {len(chunks)} chunks")
+
+        memory.embedding = embedding.tobytes()
+        memory.embedding_model = settings.openai_model
+        return True
 
     async def generate_embeddings_batch(self, memories: list[Memory], db: Session) -> int:
         """Generate embeddings for multiple memories
@@ -0,0 +1,271 @@
+"""Markdown file storage
+With MORY_STORAGE_BACKEND=files every memory is also kept as <category>/<id>.md
+(frontmatter + markdown body) under MORY_FILES_DIR, so memories can be edited in any
+editor or Obsidian and diff cleanly. The files are authoritative: edits made to them are
+read back by mory-cli files-sync or the files_sync job, while SQLite remains the
+search index. Notes with "mory: ignore" in their frontmatter and notes in template
+folders (MORY_FILES_IGNORE_DIRS) are never loaded. index.json maps memory IDs to paths and modification times for fast
+lookup without walking the tree. Changes to the index happen under a lock file, so the
+server and mory-cli can both write the same directory.
+"""
+
+import json
+import logging
+import re
+import time
+from collections.abc import Iterable, Iterator
+from contextlib import contextmanager
+from datetime import datetime
+from pathlib import Path
+from typing import Any
+from uuid import uuid4
+
+from sqlalchemy.orm import Session
+
+from ..core.config import settings
+from ..core.events import MEMORY_DELETED, MemoryEvent
+from ..core.fileutil import atomic_write_text, file_lock
+from ..models.memory import Memory
+from .sync import SyncResult, apply_changes, memory_record
+
+logger = logging.getLogger(__name__)
+
+INDEX_FILE = "index.json"
+LOCK_FILE = ".index.lock"
+DEFAULT_CATEGORY = "uncategorized"
+FRONTMATTER_DELIMITER = "---"
+IGNORE_FLAG = "ignore"  # Frontmatter "mory: ignore" keeps a note out of Mory
+
+_UNSAFE_PATH_CHARS = re.compile(r'[\\/:*?"<>|\s]+')
+
+
+def render(record: dict[str, Any]) -> str:
+    """Frontmatter (one JSON value per key, which is also valid YAML) plus the value"""
+    lines = [FRONTMATTER_DELIMITER]
+    for key, value in record.items():
+        if key != "value":
+            lines.append(f"{key}: {json.dumps(value, ensure_ascii=False)}")
+    lines.append(FRONTMATTER_DELIMITER)
+    return "\n".join(lines) + "\n\n" + (record.get("value") or "").rstrip("\n") + "\n"
+
+
+def parse(text: str) -> dict[str, Any]:
+    """Inverse of render; plain YAML scalars written by hand are kept as strings"""
+    record: dict[str, Any] = {}
+    body = text
+    lines = text.splitlines()
+    if lines and lines[0].strip() == FRONTMATTER_DELIMITER:
+        for index, line in enumerate(lines[1:], start=1):
+            if line.strip() == FRONTMATTER_DELIMITER:
+                body = "\n".join(lines[index + 1 :])
+                break
+            key, sep, raw = line.partition(":")
+            if not sep:
+                continue
+            raw = raw.strip()
+            try:
+                record[key.strip()] = json.loads(raw)
+            except json.JSONDecodeError:
+                record[key.strip()] = raw
+    record["value"] = body.strip("\n")
+    return record
+
+
+def _split_tags(raw: str) -> list[str]:
+    """Tags from a hand-written YAML flow list: [a, b] or a, b"""
+    return [tag.strip(" '\"") for tag in raw.strip("[]").split(",") if tag.strip()]
+
+
+def opted_out(record: dict[str, Any]) -> bool:
+    """Whether a note's frontmatter has mory: ignore"""
+    return str(record.get("mory", "")).strip().lower() == IGNORE_FLAG
+
+
+def in_ignored_dir(relative: Path) -> bool:
+    """Whether a note lies in a template folder (MORY_FILES_IGNORE_DIRS, any depth)"""
+    ignored_dirs = {name.lower() for name in settings.files_ignore_dirs}
+    return any(part.lower() in ignored_dirs for part in relative.parts[:-1])
+
+
+def category_for(record: dict[str, Any]) -> str:
+    """Directory for a memory: its first tag, made safe for file systems"""
+    tags = record.get("tags") or []
+    category = _UNSAFE_PATH_CHARS.sub("-", tags[0]).strip(".-") if tags else ""
+    return category or DEFAULT_CATEGORY
+
+
+class FileStore:
+    """Directory of markdown memories with an ID index"""
+
+    def __init__(self, root: str | Path):
+        self.root = Path(root)
+        self.index_path = self.root / INDEX_FILE
+        self._index: dict[str, dict[str, Any]] | None = None
+        self._lock_depth = 0
+
+    @property
+    def index(self) -> dict[str, dict[str, Any]]:
+        """memory ID -> {"path": relative path, "mtime": last written modification time}"""
+        if self._index is None:
+            try:
+                self._index = json.loads(self.index_path.read_text(encoding="utf-8"))
+            except (OSError, json.JSONDecodeError):
+                self._index = {}
+        return self._index
+
+    @contextmanager
+    def locked(self) -> Iterator[None]:
+        """Hold the index lock, starting from the index as another process may have left it"""
+        if self._lock_depth == 0:
+            self._index = None
+        self._lock_depth += 1
+        try:
+            if self._lock_depth > 1:
+                yield
+            else:
+                with file_lock(self.root / LOCK_FILE):
+                    yield
+        finally:
+            self._lock_depth -= 1
+
+    def save_index(self) -> None:
+        atomic_write_text(self.index_path, json.dumps(self.index, indent=2, sort_keys=True))
+
+    def path_for(self, memory_id: str) -> Path | None:
+        entry = self.index.get(memory_id)
+        return self.root / entry["path"] if entry else None
+
+    def write(self, record: dict[str, Any], save_index: bool = True) -> Path:
+        """Write one memory, moving its file if the category changed"""
+        with self.locked():
+            directory = self.root / category_for(record)
+            old_path = self.path_for(record["id"])
+            # Keep hand-chosen file names (Obsidian links) while the category is unchanged
+            if old_path is not None and old_path.parent == directory:
+                path = old_path
+            else:
+                path = directory / f"{record['id']}.md"
+            atomic_write_text(path, render(record))
+            if old_path is not None and old_path != path:
+                old_path.unlink(missing_ok=True)
+            self._remember(record["id"], path)
+            if save_index:
+                self.save_index()
+        return path
+
+    def remove(self, memory_id: str) -> None:
+        with self.locked():
+            path = self.path_for(memory_id)
+            if path is not None:
+                path.unlink(missing_ok=True)
+            self.index.pop(memory_id, None)
+            self.save_index()
+
+    def read(self, memory_id: str) -> dict[str, Any] | None:
+        path = self.path_for(memory_id)
+        if path is None or not path.exists():
+            return None
+        return parse(path.read_text(encoding="utf-8"))
+
+    def _remember(self, memory_id: str, path: Path) -> None:
+        self.index[memory_id] = {
+            "path": path.relative_to(self.root).as_posix(),
+            "mtime": path.stat().st_mtime,
+        }
+
+    def write_many(self, records: Iterable[dict[str, Any]]) -> None:
+        """Write several memories under one lock, saving the index once"""
+        with self.locked():
+            for record in records:
+                self.write(record, save_index=False)
+            self.save_index()
+
+    def export_all(self, db: Session) -> int:
+        """Write every memory in the database (initial migration to the files backend)"""
+        memories = db.query(Memory).all()
+        self.write_many(memory_record(memory) for memory in memories)
+        return len(memories)
+
+    def changes(self) -> dict[str, Any]:
+        """Files created, edited or deleted outside Mory, in sync.apply_changes format"""
+        now = time.time()
+        seen: set[str] = set()
+        memories: list[dict[str, Any]] = []
+
+        for path in sorted(self.root.rglob("*.md")):
+            relative = path.relative_to(self.root)
+            if any(part.startswith(".") for part in relative.parts):
+                continue
+            record = parse(path.read_text(encoding="utf-8"))
+            memory_id = record.get("id")
+            entry = self.index.get(memory_id) if memory_id else None
+            tracked = entry is not None and entry["path"] == relative.as_posix()
+            if tracked:
+                seen.add(memory_id)
+                if entry["mtime"] == path.stat().st_mtime:
+                    continue
+            # Template folders only hold back new notes: a "templates" category is real
+            if opted_out(record) or (not tracked and in_ignored_dir(relative)):
+                continue
+
+            if not memory_id:
+                # A new note written by hand: give it an ID and keep it where it is
+                memory_id = record["id"] = f"mem_{uuid4().hex[:8]}"
+                atomic_write_text(path, render(record))
+            seen.add(memory_id)
+            record["updated_at"] = datetime.utcfromtimestamp(path.stat().st_mtime).isoformat()
+            if isinstance(record.get("tags"), str):
+                record["tags"] = _split_tags(record["tags"])
+            record.setdefault("created_at", record["updated_at"])
+            record.setdefault("namespace", settings.namespace)
+            record.setdefault("review_status", "approved")
+            self._remember(memory_id, path)
+            memories.append(record)
+
+        # A file cannot have been deleted before it was last written
+        deleted = [
+            {
+                "id": memory_id,
+                "deleted_at": datetime.utcfromtimestamp(
+                    max(now, self.index[memory_id]["mtime"])
+                ).isoformat(),
+            }
+            for memory_id in set(self.index) - seen
+        ]
+        for entry in deleted:
+            self.index.pop(entry["id"], None)
+        return {"memories": memories, "deleted": deleted}
+
+    async def sync_to_database(self, db: Session) -> SyncResult:
+        """Apply edits made to the files to the database (the first run exports instead)"""
+        if not self.index_path.exists():
+            count = self.export_all(db)
+            logger.info(f"Wrote {count} memories to {self.root}")
+            return SyncResult()
+
+        with self.locked():
+            changes = self.changes()
+            self.save_index()
+        if not changes["memories"] and not changes["deleted"]:
+            return SyncResult()
+        result = await apply_changes(db, changes, since=None, peer="files")
+        logger.info(f"Loaded file edits from {self.root}: {result.to_dict()}")
+        return result
+
+
+def file_store() -> FileStore | None:
+    """Store for the configured files backend, or None when using SQLite only"""
+    if settings.storage_backend != "files":
+        return None
+    return FileStore(settings.files_dir or Path(settings.data_dir) / "memories")
+
+
+def store_event(event: MemoryEvent) -> None:
+    """Event bus subscriber keeping the markdown files in step with the database"""
+    store = file_store()
+    if store is None:
+        return
+    if event.type == MEMORY_DELETED:
+        store.remove(event.memory.id)
+    else:
+        store.write(memory_record(event.memory))
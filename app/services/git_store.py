@@ -0,0 +1,148 @@
+"""Git-backed versioning
+With MORY_GIT_DIR set, every memory change is written to memories/<id>.json in that
+directory and committed with a descriptive message, giving history, diffs and
+sync through ordinary git remotes (MORY_GIT_PUSH pushes after each commit).
+"""
+
+import asyncio
+import json
+import logging
+import subprocess
+from pathlib import Path
+from typing import Any
+
+from sqlalchemy.orm import Session
+
+from ..core.config import settings
+from ..core.events import MEMORY_DELETED, MemoryEvent
+from ..core.fileutil import atomic_write_text
+from ..models.memory import Memory
+from .sync import memory_record
+
+logger = logging.getLogger(__name__)
+
+COMMIT_VERBS = {
+    "saved": "Add",
+    "updated": "Update",
+    "deleted": "Delete",
+    "imported": "Import",
+}
+TITLE_LENGTH = 60
+
+
+class GitError(RuntimeError):
+    """A git command failed"""
+
+
+class GitMirror:
+    """Keeps a git working tree in step with the database"""
+
+    def __init__(self, repo_dir: str | Path, push: bool = False, remote: str = "origin"):
+        self.repo_dir = Path(repo_dir)
+        self.push = push
+        self.remote = remote
+
+    def _git(self, *args: str, check: bool = True) -> subprocess.CompletedProcess:
+        result = subprocess.run(
+            ["git", *args], cwd=self.repo_dir, capture_output=True, text=True
+        )
+        if check and result.returncode != 0:
+            raise GitError(f"git {args[0]} failed: {result.stderr.strip()}")
+        return result
+
+    def ensure_repo(self) -> None:
+        """Create the repository on first use"""
+        if (self.repo_dir / ".git").exists():
+            return
+        self.repo_dir.mkdir(parents=True, exist_ok=True)
+        self._git("init", "-q")
+        # Commit identity local to this repository so commits work without global config
+        if not self._git("config", "user.name", check=False).stdout.strip():
+            self._git("config", "user.name", "Mory")
+            self._git("config", "user.email", "mory@localhost")
+
+    def memory_path(self, memory_id: str) -> Path:
+        return self.repo_dir / "memories" / f"{memory_id}.json"
+
+    def _write(self, record: dict[str, Any]) -> None:
+        atomic_write_text(
+            self.memory_path(record["id"]),
+            json.dumps(record, indent=2, ensure_ascii=False, sort_keys=True) + "\n",
+        )
+
+    def _commit(self, message: str) -> bool:
+        """Commit staged changes; returns False when there was nothing to commit"""
+        self._git("add", "-A")
+        if self._git("diff", "--cached", "--quiet", check=False).returncode == 0:
+            return False
+        self._git("commit", "-q", "-m", message)
+        if self.push:
+            result = self._git("push", "-q", self.remote, "HEAD", check=False)
+            if result.returncode != 0:
+                logger.warning(f"git push to {self.remote} failed: {result.stderr.strip()}")
+        return True
+
+    def commit_change(self, memory_id: str, record: dict[str, Any] | None, message: str) -> bool:
+        """Write a memory's file (or remove it when record is None) and commit"""
+        self.ensure_repo()
+        if record is None:
+            self.memory_path(memory_id).unlink(missing_ok=True)
+        else:
+            self._write(record)
+        return self._commit(message)
+
+    def record(self, event_type: str, memory: Memory, agent_id: str | None = None) -> bool:
+        """Commit one memory event"""
+        record = None if event_type == MEMORY_DELETED else memory_record(memory)
+        return self.commit_change(
+            memory.id, record, commit_message(event_type, memory, agent_id)
+        )
+
+    def snapshot(self, db: Session) -> int:
+        """Write every memory and commit the result (initial import or resync)"""
+        self.ensure_repo()
+        memories = db.query(Memory).all()
+        existing = {path.stem for path in (self.repo_dir / "memories").glob("*.json")}
+        for memory in memories:
+            self._write(memory_record(memory))
+        for stale in existing - {memory.id for memory in memories}:
+            self.memory_path(stale).unlink()
+        self._commit(f"Snapshot of {len(memories)} memories")
+        return len(memories)
+
+
+def commit_message(event_type: str, memory: Memory, agent_id: str | None = None) -> str:
+    """One-line subject naming the memory, with tags and agent in the body"""
+    text = (memory.summary or memory.value or "").strip().splitlines()
+    title = text[0] if text else ""
+    if len(title) > TITLE_LENGTH:
+        title = title[: TITLE_LENGTH - 1] + "…"
+    subject = f"{COMMIT_VERBS.get(event_type, event_type.capitalize())} {memory.id}"
+    if title:
+        subject += f": {title}"
+
+    body = []
+    if memory.tags_list:
+        body.append(f"Tags: {', '.join(memory.tags_list)}")
+    if agent_id:
+        body.append(f"Agent: {agent_id}")
+    return "\n\n".join([subject, "\n".join(body)]) if body else subject
+
+
+def git_mirror() -> GitMirror | None:
+    """Mirror for the configured MORY_GIT_DIR, or None when disabled"""
+    if not settings.git_dir:
+        return None
+    return GitMirror(settings.git_dir, push=settings.git_push, remote=settings.git_remote)
+
+
+async def mirror_event(event: MemoryEvent) -> None:
+    """Event bus subscriber committing the change when MORY_GIT_DIR is set"""
+    mirror = git_mirror()
+    if mirror is None:
+        return
+    # Read the memory here (its session is not thread-safe); only git runs in a thread
+    memory = event.memory
+    record = None if event.type == MEMORY_DELETED else memory_record(memory)
+    message = commit_message(event.type, memory, event.agent_id)
+    await asyncio.to_thread(mirror.commit_change, memory.id, record, message)
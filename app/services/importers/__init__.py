@@ -0,0 +1,42 @@
+"""Importers turning exports from other tools into memories
+Each file importer parses an export path into ImportedMemory items (see
+base.import_memories); NotionImporter and MailImporter pull from the Notion API and
+an IMAP mailbox instead.
+"""
+
+from collections.abc import Callable, Iterable
+from pathlib import Path
+
+from . import apple_notes, chatgpt, claude, ics, joplin, logseq
+from .base import (
+    ImportedMemory,
+    ImportFailure,
+    ImportFormatError,
+    ImportProgress,
+    ImportResult,
+    import_memories,
+)
+from .mail import MailImporter
+from .notion import NotionImporter
+
+# Importers called without a path read the source directly where they can (Apple Notes)
+IMPORTERS: dict[str, Callable[[str | Path | None], Iterable[ImportedMemory | ImportFailure]]] = {
+    apple_notes.NAME: apple_notes.parse,
+    chatgpt.NAME: chatgpt.parse,
+    claude.NAME: claude.parse,
+    ics.NAME: ics.parse,
+    joplin.NAME: joplin.parse,
+    logseq.NAME: logseq.parse,
+}
+
+__all__ = [
+    "IMPORTERS",
+    "ImportFailure",
+    "ImportFormatError",
+    "ImportProgress",
+    "ImportResult",
+    "ImportedMemory",
+    "MailImporter",
+    "NotionImporter",
+    "import_memories",
+]
@@ -0,0 +1,85 @@
+"""Apple Notes importer (macOS)
+Reads notes through the Notes scripting bridge (osascript, JavaScript for Automation),
+or from a JSON file the same script wrote, e.g. on another Mac. Folders become
+categories; notes in "Recently Deleted" are skipped.
+"""
+
+import json
+import subprocess
+import sys
+from collections.abc import Iterator
+from datetime import UTC, datetime
+from pathlib import Path
+from typing import Any
+
+from .base import ImportedMemory, ImportFormatError, category_tag, read_export
+
+NAME = "apple-notes"
+DELETED_FOLDER = "Recently Deleted"
+
+# Prints every note as JSON; run with: osascript -l JavaScript -e "<script>"
+EXPORT_SCRIPT = """
+const notes = Application("Notes").notes();
+JSON.stringify(notes.map(note => ({
+    id: note.id(),
+    name: note.name(),
+    body: note.plaintext(),
+    folder: note.container().name(),
+    created: note.creationDate().toISOString(),
+    modified: note.modificationDate().toISOString(),
+})));
+"""
+
+
+def _timestamp(value: Any) -> datetime | None:
+    if not isinstance(value, str):
+        return None
+    try:
+        return datetime.fromisoformat(value).astimezone(UTC).replace(tzinfo=None)
+    except ValueError:
+        return None
+
+
+def read_notes() -> list[dict[str, Any]]:
+    """Every note in the Notes app (asks for automation permission on first use)"""
+    if sys.platform != "darwin":
+        raise ImportFormatError("Reading Apple Notes directly needs macOS; pass an exported file")
+    try:
+        completed = subprocess.run(
+            ["osascript", "-l", "JavaScript", "-e", EXPORT_SCRIPT],
+            capture_output=True,
+            text=True,
+            timeout=600,
+            check=True,
+        )
+    except subprocess.CalledProcessError as e:
+        raise ImportFormatError(f"osascript failed: {e.stderr.strip()}") from e
+    except subprocess.TimeoutExpired as e:
+        raise ImportFormatError("Timed out reading notes from the Notes app") from e
+    try:
+        return json.loads(completed.stdout)
+    except json.JSONDecodeError as e:
+        raise ImportFormatError(f"Unexpected output from osascript: {e}") from e
+
+
+def note_memories(notes: list[dict[str, Any]]) -> Iterator[ImportedMemory]:
+    for note in notes:
+        folder = note.get("folder") or "Notes"
+        body = (note.get("body") or "").strip()
+        if folder == DELETED_FOLDER or not body or not note.get("id"):
+            continue
+        title = (note.get("name") or "").strip() or body.splitlines()[0]
+        yield ImportedMemory(
+            source=f"{NAME}:{note['id']}",
+            value=body,
+            tags=[category_tag(folder, "notes"), NAME],
+            metadata={"title": title, "folder": folder},
+            created_at=_timestamp(note.get("created")),
+        )
+
+
+def parse(path: str | Path | None) -> Iterator[ImportedMemory]:
+    notes = read_export(path, "notes.json") if path else read_notes()
+    if not isinstance(notes, list):
+        raise ImportFormatError("Expected a list of notes")
+    yield from note_memories(notes)
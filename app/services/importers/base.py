@@ -0,0 +1,253 @@
+"""Shared machinery for importing memories from other tools
+An importer turns an export into ImportedMemory items and import_memories() stores them.
+Items are keyed by source, so importing a newer export again updates the memories that
+changed instead of duplicating them. A new item whose normalized content matches an
+existing memory (a renamed file, say) is skipped and reported as a duplicate. Files an
+importer cannot read become ImportFailure items; they and skipped items are listed in an
+error report under MORY_DATA_DIR.
+"""
+
+import json
+import re
+import zipfile
+from collections.abc import Callable
+from contextlib import nullcontext
+from dataclasses import asdict, dataclass, field
+from datetime import datetime
+from pathlib import Path
+from typing import Any
+
+from sqlalchemy.orm import Session
+
+from ...core.config import settings
+from ...core.database import bulk_load
+from ...core.events import MEMORY_IMPORTED, MemoryEvent, event_bus
+from ...core.fileutil import atomic_write_text
+from ...core.limits import PayloadTooLargeError, limit_value
+from ...models.memory import Memory
+from ..content_types import detect_content_type
+from ..dedup import backfill_content_hashes, content_hash
+
+MAX_CATEGORY_LENGTH = 50
+
+# Sources looked up per query (SQLite caps the number of bound parameters)
+SOURCE_LOOKUP_BATCH = 500
+
+# Items between progress reports of import_memories
+IMPORT_PROGRESS_EVERY = 100
+
+# Characters of an item's content kept in the error report
+SNIPPET_LENGTH = 300
+
+
+class ImportFormatError(ValueError):
+    """Raised when an export file is not in the expected format"""
+
+
+@dataclass
+class ImportedMemory:
+    """One memory produced by an importer"""
+
+    source: str  # "<importer>:<id in the other tool>", unique per item
+    value: str
+    tags: list[str] = field(default_factory=list)  # First tag is the category
+    metadata: dict[str, Any] = field(default_factory=dict)
+    created_at: datetime | None = None
+
+
+@dataclass
+class ImportFailure:
+    """A file or item an importer could not turn into a memory"""
+
+    source: str  # File path, or "<importer>:<id>" like ImportedMemory.source
+    reason: str
+    snippet: str = ""  # Start of the content (frontmatter, properties) to locate the problem
+
+
+@dataclass
+class ImportResult:
+    created: int = 0
+    updated: int = 0
+    unchanged: int = 0
+    skipped: int = 0  # Empty, or too long under MORY_OVERSIZE_POLICY=reject
+    failed: int = 0  # Could not be read or parsed
+    dry_run: bool = False
+    memory_ids: list[str] = field(default_factory=list)
+    # New items with the content of a memory (or earlier item): {"source", "duplicate_of"}
+    duplicates: list[dict[str, str]] = field(default_factory=list)
+    error_report: str | None = None  # Path of the report listing skipped and failed items
+
+    def to_dict(self) -> dict[str, Any]:
+        return asdict(self)
+
+
+# Called with (items processed, total items, counts so far)
+ImportProgress = Callable[[int, int, ImportResult], None]
+
+
+def category_tag(title: str | None, fallback: str) -> str:
+    """Category tag from a title: its lowercased words joined by hyphens"""
+    words = re.findall(r"\w+", (title or "").lower())
+    return "-".join(words)[:MAX_CATEGORY_LENGTH].strip("-") or fallback
+
+
+def read_export(path: str | Path | None, name: str, required: bool = True) -> Any:
+    """JSON document from an export: the file itself, or `name` in an export
+    directory or zip archive (None if missing and not required)"""
+    if path is None:
+        raise ImportFormatError("This importer needs the path of an export")
+    path = Path(path)
+    try:
+        if path.is_dir():
+            path = path / name
+            if not path.exists() and not required:
+                return None
+        elif zipfile.is_zipfile(path):
+            with zipfile.ZipFile(path) as archive:
+                member = next(
+                    (item for item in archive.namelist() if item.rsplit("/", 1)[-1] == name), None
+                )
+                if member is None:
+                    if required:
+                        raise ImportFormatError(f"{name} not found in {path}")
+                    return None
+                return json.loads(archive.read(member))
+        return json.loads(path.read_text(encoding="utf-8"))
+    except json.JSONDecodeError as e:
+        raise ImportFormatError(f"{path} is not valid JSON: {e}") from e
+
+
+def read_failure(file: Path, error: Exception) -> ImportFailure:
+    """Failure for a file that could not be read or parsed, with its first bytes"""
+    try:
+        snippet = file.read_bytes()[:SNIPPET_LENGTH].decode("utf-8", errors="replace")
+    except OSError:
+        snippet = ""
+    return ImportFailure(str(file), f"{type(error).__name__}: {error}", snippet)
+
+
+def write_error_report(failures: list[ImportFailure]) -> Path:
+    """Write the failures as JSON under MORY_DATA_DIR/import-reports"""
+    name = f"import-errors-{datetime.utcnow().strftime('%Y%m%dT%H%M%S%f')}.json"
+    path = Path(settings.data_dir) / "import-reports" / name
+    report = [asdict(failure) for failure in failures]
+    atomic_write_text(path, json.dumps(report, indent=2, ensure_ascii=False) + "\n")
+    return path
+
+
+def _existing_by(db: Session, namespace: str, column, keys: list[str]) -> dict[str, Memory]:
+    """Memories whose column has one of the keys, looked up in batches"""
+    existing: dict[str, Memory] = {}
+    for start in range(0, len(keys), SOURCE_LOOKUP_BATCH):
+        batch = keys[start : start + SOURCE_LOOKUP_BATCH]
+        for memory in db.query(Memory).filter(Memory.namespace == namespace, column.in_(batch)):
+            existing.setdefault(getattr(memory, column.key), memory)
+    return existing
+
+
+def existing_by_source(db: Session, namespace: str, sources: list[str]) -> dict[str, Memory]:
+    """Memories already imported from these sources"""
+    return _existing_by(db, namespace, Memory.source, sources)
+
+
+def existing_by_hash(db: Session, namespace: str, hashes: list[str]) -> dict[str, Memory]:
+    """Memories with these content hashes"""
+    return _existing_by(db, namespace, Memory.content_hash, hashes)
+
+
+async def import_memories(
+    db: Session,
+    items: list[ImportedMemory | ImportFailure],
+    namespace: str,
+    dry_run: bool = False,
+    progress: ImportProgress | None = None,
+) -> ImportResult:
+    """Create or update a memory per item; dry_run only counts what would change.
+    progress is called every IMPORT_PROGRESS_EVERY items and once at the end; an
+    exception raised from it (or Ctrl-C) cancels the import without saving anything.
+    Skipped and failed items are written to an error report (result.error_report)."""
+    result = ImportResult(dry_run=dry_run)
+    events: list[MemoryEvent] = []
+    failures: list[ImportFailure] = []
+    batch: dict[str, Memory] = {}
+
+    imported = [item for item in items if isinstance(item, ImportedMemory)]
+    existing = existing_by_source(db, namespace, [item.source for item in imported])
+    backfill_content_hashes(db)
+    same_content = existing_by_hash(
+        db,
+        namespace,
+        [content_hash(item.value) for item in imported if item.source not in existing],
+    )
+    new_hashes: dict[str, str] = {}  # Content hash -> source of an item created here
+
+    # One transaction for the whole import, committed when the block ends
+    with nullcontext() if dry_run else bulk_load(db, len(items)):
+        for done, item in enumerate(items):
+            if progress is not None and done and done % IMPORT_PROGRESS_EVERY == 0:
+                progress(done, len(items), result)
+            if isinstance(item, ImportFailure):
+                result.failed += 1
+                failures.append(item)
+                continue
+            try:
+                value, _ = limit_value(
+                    item.value.strip(), settings.max_value_length, settings.oversize_policy
+                )
+            except PayloadTooLargeError as e:
+                result.skipped += 1
+                failures.append(ImportFailure(item.source, str(e), item.value[:SNIPPET_LENGTH]))
+                continue
+            if not value:
+                result.skipped += 1
+                failures.append(ImportFailure(item.source, "Empty content"))
+                continue
+
+            memory = batch.get(item.source) or existing.get(item.source)
+            if memory is None:
+                digest = content_hash(value)
+                duplicate = same_content.get(digest)
+                duplicate_of = duplicate.id if duplicate else new_hashes.get(digest)
+                if duplicate_of:
+                    result.duplicates.append({"source": item.source, "duplicate_of": duplicate_of})
+                    continue
+                new_hashes[digest] = item.source
+                result.created += 1
+                if dry_run:
+                    continue
+                memory = Memory(
+                    value=value, namespace=namespace, owner=settings.agent_id, source=item.source
+                )
+                if item.created_at:
+                    memory.created_at = item.created_at
+                db.add(memory)
+            elif (
+                memory.value == value
+                and memory.tags_list == item.tags
+                and memory.metadata_dict == item.metadata
+            ):
+                result.unchanged += 1
+                continue
+            else:
+                result.updated += 1
+                if dry_run:
+                    continue
+                memory.value = value
+
+            memory.tags_list = item.tags
+            memory.metadata_dict = item.metadata
+            memory.content_type = detect_content_type(value)
+            batch[item.source] = memory
+            details = {"import": item.source.split(":", 1)[0]}
+            events.append(MemoryEvent(MEMORY_IMPORTED, memory, session=db, details=details))
+
+    if progress is not None:
+        progress(len(items), len(items), result)
+    if failures:
+        result.error_report = str(write_error_report(failures))
+    if dry_run:
+        return result
+    for event in events:
+        result.memory_ids.append(event.memory.id)
+        await event_bus.publish(event)
+    return result
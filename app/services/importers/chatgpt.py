@@ -0,0 +1,75 @@
+"""ChatGPT data export importer
+Reads conversations.json (or the export zip). Each conversation becomes one memory with
+its transcript along the branch last shown, categorised by the conversation title.
+"""
+
+from collections.abc import Iterator
+from datetime import UTC, datetime
+from pathlib import Path
+from typing import Any
+
+from .base import ImportedMemory, ImportFormatError, category_tag, read_export
+
+NAME = "chatgpt"
+ROLES = {"user": "User", "assistant": "Assistant"}
+
+
+def _timestamp(value: Any) -> datetime | None:
+    if not isinstance(value, int | float):
+        return None
+    return datetime.fromtimestamp(value, UTC).replace(tzinfo=None)
+
+
+def _message(node: dict[str, Any]) -> tuple[str, str] | None:
+    message = node.get("message") or {}
+    role = (message.get("author") or {}).get("role")
+    parts = (message.get("content") or {}).get("parts") or []
+    text = "\n".join(part for part in parts if isinstance(part, str)).strip()
+    if role not in ROLES or not text:
+        return None
+    return ROLES[role], text
+
+
+def transcript(conversation: dict[str, Any]) -> list[tuple[str, str]]:
+    """(speaker, text) pairs from the root to the conversation's current node"""
+    mapping = conversation.get("mapping") or {}
+    node_id = conversation.get("current_node")
+    if node_id not in mapping:
+        # No branch information: fall back to every message in time order
+        nodes = sorted(
+            mapping.values(),
+            key=lambda node: (node.get("message") or {}).get("create_time") or 0,
+        )
+        return [message for node in nodes if (message := _message(node))]
+
+    messages = []
+    seen = set()
+    while node_id in mapping and node_id not in seen:
+        seen.add(node_id)
+        node = mapping[node_id]
+        message = _message(node)
+        if message:
+            messages.append(message)
+        node_id = node.get("parent")
+    return messages[::-1]
+
+
+def parse(path: str | Path | None) -> Iterator[ImportedMemory]:
+    conversations = read_export(path, "conversations.json")
+    if not isinstance(conversations, list):
+        raise ImportFormatError("Expected a list of conversations (ChatGPT conversations.json)")
+
+    for conversation in conversations:
+        conversation_id = conversation.get("conversation_id") or conversation.get("id")
+        messages = transcript(conversation)
+        if not conversation_id or not messages:
+            continue
+        title = (conversation.get("title") or "").strip() or "Untitled"
+        body = "\n\n".join(f"{speaker}: {text}" for speaker, text in messages)
+        yield ImportedMemory(
+            source=f"{NAME}:{conversation_id}",
+            value=f"{title}\n\n{body}",
+            tags=[category_tag(title, NAME), NAME],
+            metadata={"title": title, "source_url": f"https://chatgpt.com/c/{conversation_id}"},
+            created_at=_timestamp(conversation.get("create_time")),
+        )
@@ -0,0 +1,102 @@
+"""Claude (claude.ai) data export importer
+Reads projects.json and conversations.json from the export zip or directory, or either
+file on its own. Project documents and instructions become memories categorised by the
+project name; conversations become transcripts categorised by their title.
+"""
+
+from collections.abc import Iterator
+from datetime import UTC, datetime
+from pathlib import Path
+from typing import Any
+
+from .base import ImportedMemory, ImportFormatError, category_tag, read_export
+
+NAME = "claude"
+SPEAKERS = {"human": "User", "assistant": "Assistant"}
+
+
+def _timestamp(value: Any) -> datetime | None:
+    if not isinstance(value, str):
+        return None
+    try:
+        parsed = datetime.fromisoformat(value)
+    except ValueError:
+        return None
+    # Stored as naive UTC like every other timestamp
+    return parsed.astimezone(UTC).replace(tzinfo=None) if parsed.tzinfo else parsed
+
+
+def _message_text(message: dict[str, Any]) -> str:
+    text = message.get("text") or ""
+    if not text.strip():
+        blocks = message.get("content") or []
+        text = "\n".join(block.get("text", "") for block in blocks if block.get("type") == "text")
+    return text.strip()
+
+
+def _project_memories(project: dict[str, Any]) -> Iterator[ImportedMemory]:
+    name = (project.get("name") or "").strip() or "Untitled project"
+    tags = [category_tag(name, NAME), NAME]
+    instructions = "\n\n".join(
+        part.strip()
+        for part in (project.get("description"), project.get("prompt_template"))
+        if part and part.strip()
+    )
+    if instructions and project.get("uuid"):
+        yield ImportedMemory(
+            source=f"{NAME}:project:{project['uuid']}",
+            value=f"{name}\n\n{instructions}",
+            tags=tags,
+            metadata={"project": name},
+            created_at=_timestamp(project.get("created_at")),
+        )
+    for doc in project.get("docs") or []:
+        content = (doc.get("content") or "").strip()
+        if not content or not doc.get("uuid"):
+            continue
+        filename = doc.get("filename") or "document"
+        yield ImportedMemory(
+            source=f"{NAME}:doc:{doc['uuid']}",
+            value=f"{filename}\n\n{content}",
+            tags=tags,
+            metadata={"project": name, "filename": filename},
+            created_at=_timestamp(doc.get("created_at")),
+        )
+
+
+def _conversation_memory(conversation: dict[str, Any]) -> ImportedMemory | None:
+    messages = [
+        (SPEAKERS[message.get("sender")], text)
+        for message in conversation.get("chat_messages") or []
+        if message.get("sender") in SPEAKERS and (text := _message_text(message))
+    ]
+    if not messages or not conversation.get("uuid"):
+        return None
+    title = (conversation.get("name") or "").strip() or "Untitled"
+    body = "\n\n".join(f"{speaker}: {text}" for speaker, text in messages)
+    return ImportedMemory(
+        source=f"{NAME}:conversation:{conversation['uuid']}",
+        value=f"{title}\n\n{body}",
+        tags=[category_tag(title, NAME), NAME],
+        metadata={"title": title, "source_url": f"https://claude.ai/chat/{conversation['uuid']}"},
+        created_at=_timestamp(conversation.get("created_at")),
+    )
+
+
+def parse(path: str | Path | None) -> Iterator[ImportedMemory]:
+    if path is not None and Path(path).is_file() and Path(path).suffix == ".json":
+        records = read_export(path, path.name)
+    else:
+        records = (read_export(path, "projects.json", required=False) or []) + (
+            read_export(path, "conversations.json", required=False) or []
+        )
+    if not isinstance(records, list):
+        raise ImportFormatError("Expected a list of projects or conversations (Claude export)")
+
+    for record in records:
+        if "docs" in record or "prompt_template" in record:
+            yield from _project_memories(record)
+        elif "chat_messages" in record:
+            memory = _conversation_memory(record)
+            if memory:
+                yield memory
@@ -0,0 +1,178 @@
+"""Calendar (iCalendar .ics) importer
+Each event becomes a memory holding its title, time, place, attendees and description,
+tagged with the calendar name (category), the event date and the attendees, and dated
+at its start so date-bounded searches find it. Recurring events are imported once, with
+their rule in the metadata; cancelled events are skipped. The path may be one .ics file
+or a directory of them (a calendar export).
+"""
+
+import re
+from collections.abc import Iterator
+from datetime import UTC, date, datetime
+from pathlib import Path
+from typing import Any
+from zoneinfo import ZoneInfo, ZoneInfoNotFoundError
+
+from .base import SNIPPET_LENGTH, ImportedMemory, ImportFailure, ImportFormatError, category_tag
+
+NAME = "ics"
+
+_ESCAPES = re.compile(r"\\([\\;,nN])")
+# NAME;PARAM=value;PARAM="quoted:value":VALUE
+_PARAM = re.compile(r';([^=;:]+)=("[^"]*"|[^;:]*)')
+
+
+def _unescape(value: str) -> str:
+    return _ESCAPES.sub(lambda m: "\n" if m.group(1) in "nN" else m.group(1), value)
+
+
+def content_lines(text: str) -> Iterator[tuple[str, dict[str, str], str]]:
+    """(name, parameters, value) for each unfolded content line"""
+    unfolded = re.sub(r"\r?\n[ \t]", "", text)
+    for line in unfolded.splitlines():
+        name_match = re.match(r"[A-Za-z0-9-]+", line)
+        if not name_match:
+            continue
+        name = name_match.group().upper()
+        rest = line[name_match.end() :]
+        params = {}
+        while match := _PARAM.match(rest):
+            params[match.group(1).upper()] = match.group(2).strip('"')
+            rest = rest[match.end() :]
+        if rest.startswith(":"):
+            yield name, params, rest[1:]
+
+
+def _time(value: str, params: dict[str, str]) -> datetime | date | None:
+    """DATE or DATE-TIME value; times are returned as aware datetimes when the zone is known"""
+    try:
+        if params.get("VALUE") == "DATE" or len(value) == 8:
+            return datetime.strptime(value, "%Y%m%d").date()
+        moment = datetime.strptime(value.rstrip("Z"), "%Y%m%dT%H%M%S")
+    except ValueError:
+        return None
+    if value.endswith("Z"):
+        return moment.replace(tzinfo=UTC)
+    if "TZID" in params:
+        try:
+            return moment.replace(tzinfo=ZoneInfo(params["TZID"]))
+        except (ZoneInfoNotFoundError, ValueError):
+            pass
+    return moment  # Floating time
+
+
+def _person(value: str, params: dict[str, str]) -> str:
+    address = re.sub(r"(?i)^mailto:", "", value)
+    return params.get("CN") or address
+
+
+def parse_calendar(text: str) -> Iterator[dict[str, Any]]:
+    """Events of an iCalendar document as dicts of their properties"""
+    calendar_name = None
+    event: dict[str, Any] | None = None
+    depth = 0  # Components nested in an event (VALARM)
+    for name, params, value in content_lines(text):
+        if name == "X-WR-CALNAME" and event is None:
+            calendar_name = _unescape(value)
+        elif name == "BEGIN":
+            if event is not None:
+                depth += 1
+            elif value.upper() == "VEVENT":
+                event = {"calendar": calendar_name, "attendees": []}
+        elif name == "END":
+            if depth:
+                depth -= 1
+            elif event is not None and value.upper() == "VEVENT":
+                yield event
+                event = None
+        elif event is None or depth:
+            continue
+        elif name in ("DTSTART", "DTEND", "RECURRENCE-ID"):
+            event[name.lower()] = _time(value, params)
+        elif name == "ATTENDEE":
+            event["attendees"].append(_person(value, params))
+        elif name == "ORGANIZER":
+            event["organizer"] = _person(value, params)
+        else:
+            event[name.lower()] = _unescape(value)
+
+
+def _utc(value: datetime | date | None) -> datetime | None:
+    if value is None:
+        return None
+    if not isinstance(value, datetime):
+        return datetime(value.year, value.month, value.day)
+    return value.astimezone(UTC).replace(tzinfo=None) if value.tzinfo else value
+
+
+def _when(start: datetime | date, end: datetime | date | None) -> str:
+    if not isinstance(start, datetime):
+        return start.isoformat()
+    text = start.strftime("%Y-%m-%d %H:%M")
+    if isinstance(end, datetime):
+        text += "-" + end.strftime("%H:%M" if end.date() == start.date() else "%Y-%m-%d %H:%M")
+    return f"{text} {start.tzname()}" if start.tzinfo else text
+
+
+def event_memory(event: dict[str, Any]) -> ImportedMemory | None:
+    """Memory for one event (None for cancelled events and events without a start)"""
+    start = event.get("dtstart")
+    if start is None or not event.get("uid") or event.get("status", "").upper() == "CANCELLED":
+        return None
+    title = (event.get("summary") or "").strip() or "(no title)"
+    attendees = list(dict.fromkeys(event["attendees"]))
+
+    lines = [title, "", f"When: {_when(start, event.get('dtend'))}"]
+    if event.get("location"):
+        lines.append(f"Where: {event['location']}")
+    if attendees:
+        lines.append(f"Attendees: {', '.join(attendees)}")
+    if event.get("description"):
+        lines.extend(["", event["description"].strip()])
+
+    tags = [category_tag(event.get("calendar"), "calendar"), start.strftime("%Y-%m-%d")]
+    for tag in [*attendees, NAME]:
+        if tag not in tags:
+            tags.append(tag)
+
+    source = f"{NAME}:{event['uid']}"
+    if recurrence_id := _utc(event.get("recurrence-id")):
+        # A changed occurrence of a recurring event
+        source += f"/{recurrence_id.isoformat()}"
+    metadata = {
+        "title": title,
+        "start": _utc(start).isoformat(),
+        "end": _utc(event.get("dtend")).isoformat() if event.get("dtend") else None,
+        "all_day": not isinstance(start, datetime),
+        "location": event.get("location"),
+        "people": attendees,
+        "organizer": event.get("organizer"),
+        "calendar": event.get("calendar"),
+        "recurrence": event.get("rrule"),
+        "source_url": event.get("url"),
+    }
+    return ImportedMemory(
+        source=source,
+        value="\n".join(lines),
+        tags=tags,
+        metadata={key: value for key, value in metadata.items() if value},
+        created_at=_utc(start),
+    )
+
+
+def parse(path: str | Path | None) -> Iterator[ImportedMemory | ImportFailure]:
+    if path is None:
+        raise ImportFormatError("This importer needs the path of an .ics file or directory")
+    path = Path(path)
+    files = sorted(path.glob("**/*.ics")) if path.is_dir() else [path]
+    for file in files:
+        text = file.read_text(encoding="utf-8", errors="replace")
+        if "BEGIN:VCALENDAR" not in text.upper():
+            if not path.is_dir():
+                raise ImportFormatError(f"{file} is not an iCalendar file")
+            # One bad file in a directory should not stop the others
+            yield ImportFailure(str(file), "Not an iCalendar file", text[:SNIPPET_LENGTH])
+            continue
+        for event in parse_calendar(text):
+            if memory := event_memory(event):
+                yield memory
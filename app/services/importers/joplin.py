@@ -0,0 +1,91 @@
+"""Joplin importer
+Reads a "RAW - Joplin Export Directory": one <id>.md per item, holding the title, a blank
+line, the body and a block of "key: value" properties. Notes (type_ 1) become memories
+categorised by their notebook (type_ 2); tags (type_ 5, linked by type_ 6 items) are kept.
+"""
+
+import re
+from collections.abc import Iterator
+from datetime import UTC, datetime
+from pathlib import Path
+from typing import Any
+
+from .base import ImportedMemory, ImportFailure, ImportFormatError, category_tag, read_failure
+
+NAME = "joplin"
+
+NOTE, FOLDER, TAG, NOTE_TAG = "1", "2", "5", "6"
+
+_PROPERTY = re.compile(r"^([a-z_]+): ?(.*)$")
+
+
+def _timestamp(value: str | None) -> datetime | None:
+    if not value:
+        return None
+    try:
+        return datetime.fromisoformat(value).astimezone(UTC).replace(tzinfo=None)
+    except ValueError:
+        return None
+
+
+def parse_item(text: str) -> dict[str, Any]:
+    """Properties of one exported item, with its title and body"""
+    lines = text.rstrip("\n").split("\n")
+    item: dict[str, Any] = {}
+    # The property block runs from the end of the file up to the last blank line
+    while lines and (match := _PROPERTY.match(lines[-1])):
+        item.setdefault(match.group(1), match.group(2))
+        lines.pop()
+    if item.get("type_") in (NOTE, FOLDER, TAG):
+        item["title"] = lines[0].strip() if lines else ""
+        item["body"] = "\n".join(lines[2:]).strip()
+    return item
+
+
+def item_memories(items: list[dict[str, Any]]) -> Iterator[ImportedMemory]:
+    folders = {item["id"]: item for item in items if item.get("type_") == FOLDER}
+    tag_names = {item["id"]: item["title"] for item in items if item.get("type_") == TAG}
+    note_tags: dict[str, list[str]] = {}
+    for link in items:
+        if link.get("type_") == NOTE_TAG and link.get("tag_id") in tag_names:
+            note_tags.setdefault(link.get("note_id", ""), []).append(tag_names[link["tag_id"]])
+
+    for note in items:
+        if note.get("type_") != NOTE or not note.get("id") or note.get("deleted_time", "0") != "0":
+            continue
+        if not note["body"] and not note["title"]:
+            continue
+        notebook = folders.get(note.get("parent_id", ""), {}).get("title")
+        tags = [category_tag(notebook, "notes")]
+        for tag in [*note_tags.get(note["id"], []), NAME]:
+            if tag not in tags:
+                tags.append(tag)
+        metadata = {
+            "title": note["title"],
+            "notebook": notebook,
+            "todo": note.get("is_todo") == "1",
+            "source_url": note.get("source_url"),
+        }
+        yield ImportedMemory(
+            source=f"{NAME}:{note['id']}",
+            value=f"{note['title']}\n\n{note['body']}".strip(),
+            tags=tags,
+            metadata={key: value for key, value in metadata.items() if value},
+            created_at=_timestamp(note.get("created_time")),
+        )
+
+
+def parse(path: str | Path | None) -> Iterator[ImportedMemory | ImportFailure]:
+    if path is None or not Path(path).is_dir():
+        raise ImportFormatError("This importer needs a Joplin RAW export directory")
+    items = []
+    failures = []
+    for file in sorted(Path(path).glob("*.md")):
+        try:
+            items.append(parse_item(file.read_text(encoding="utf-8")))
+        except (OSError, UnicodeDecodeError) as e:
+            failures.append(read_failure(file, e))
+    if not any(item.get("type_") == NOTE for item in items):
+        raise ImportFormatError(f"No Joplin notes found in {path}")
+    yield from failures
+    yield from item_memories(items)
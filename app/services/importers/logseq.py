@@ -0,0 +1,90 @@
+"""Logseq graph importer
+Reads the pages/ and journals/ folders of a graph. Each page becomes one memory holding
+its block outline, with block properties (id::, collapsed::) dropped and [[links]] turned
+into plain text. Journal pages are categorised as "journal" and dated by their day;
+other pages by their namespace root (projects/mory -> projects). Page tags:: are kept;
+pages with mory:: ignore are left out.
+"""
+
+import re
+from collections.abc import Iterator
+from datetime import datetime
+from pathlib import Path
+from urllib.parse import unquote
+
+from .base import ImportedMemory, ImportFailure, ImportFormatError, category_tag, read_failure
+
+NAME = "logseq"
+
+_PROPERTY = re.compile(r"^\s*(?:- )?([A-Za-z0-9_-]+):: ?(.*)$")
+_LINK = re.compile(r"\[\[([^\]]+)\]\]")
+_JOURNAL_NAME = re.compile(r"^\d{4}_\d{2}_\d{2}$")
+
+
+def page_title(file: Path) -> str:
+    """Page name from a file name (namespaces are stored as ___ or %2F)"""
+    return unquote(file.stem.replace("___", "/"))
+
+
+def outline(text: str) -> tuple[str, dict[str, str]]:
+    """Page text without block properties, and the page properties (before the first block)"""
+    properties: dict[str, str] = {}
+    lines = []
+    for line in text.splitlines():
+        match = _PROPERTY.match(line)
+        if match:
+            if not lines:
+                properties[match.group(1).lower()] = match.group(2).strip()
+            continue
+        if line.strip() in ("", "-"):
+            continue
+        lines.append(_LINK.sub(r"\1", line.rstrip()))
+    return "\n".join(lines), properties
+
+
+def _tags(value: str | None) -> list[str]:
+    names = _LINK.sub(r"\1", value or "").split(",")
+    return [name.strip().strip("#") for name in names if name.strip()]
+
+
+def page_memory(graph: Path, file: Path) -> ImportedMemory | None:
+    text, properties = outline(file.read_text(encoding="utf-8"))
+    if not text or properties.get("mory", "").lower() == "ignore":
+        return None
+    relative = file.relative_to(graph).with_suffix("").as_posix()
+    created_at = None
+    if file.parent.name == "journals" and _JOURNAL_NAME.match(file.stem):
+        created_at = datetime.strptime(file.stem, "%Y_%m_%d")
+        title = created_at.date().isoformat()
+        category = "journal"
+    else:
+        title = properties.get("title") or page_title(file)
+        category = category_tag(title.split("/", 1)[0] if "/" in title else None, NAME)
+
+    tags = [category]
+    for tag in [*_tags(properties.get("tags")), NAME]:
+        if tag not in tags:
+            tags.append(tag)
+    metadata = {"title": title, "aliases": _tags(properties.get("alias"))}
+    return ImportedMemory(
+        source=f"{NAME}:{relative}",
+        value=f"{title}\n\n{text}",
+        tags=tags,
+        metadata={key: value for key, value in metadata.items() if value},
+        created_at=created_at,
+    )
+
+
+def parse(path: str | Path | None) -> Iterator[ImportedMemory | ImportFailure]:
+    graph = Path(path) if path else None
+    if graph is None or not ((graph / "pages").is_dir() or (graph / "journals").is_dir()):
+        raise ImportFormatError("This importer needs a Logseq graph directory (with pages/)")
+    for folder in ("pages", "journals"):
+        for file in sorted((graph / folder).glob("*.md")):
+            try:
+                memory = page_memory(graph, file)
+            except (OSError, UnicodeDecodeError) as e:
+                yield read_failure(file, e)
+                continue
+            if memory:
+                yield memory
@@ -0,0 +1,150 @@
+"""Email ingestion over IMAP
+Messages in MORY_IMAP_FOLDER (a Gmail label is a folder) matching MORY_IMAP_SEARCH (e.g.
+FLAGGED for starred mail) become memories: subject and body, with sender and date in the
+metadata. Messages are read without marking them seen, and only messages newer than
+the last run are fetched.
+"""
+
+import asyncio
+import email
+import html
+import imaplib
+import json
+import logging
+import re
+from collections.abc import Callable
+from datetime import UTC
+from email.message import EmailMessage
+from email.policy import default as default_policy
+from email.utils import parseaddr, parsedate_to_datetime
+from pathlib import Path
+from typing import Any
+
+from sqlalchemy.orm import Session
+
+from ...core.config import settings
+from ...core.fileutil import atomic_write_text
+from .base import ImportedMemory, ImportResult, category_tag, import_memories
+
+logger = logging.getLogger(__name__)
+
+NAME = "email"
+
+_TAG = re.compile(r"<[^>]+>")
+_BLANK_LINES = re.compile(r"\n\s*\n\s*\n+")
+
+
+def _connect() -> imaplib.IMAP4:
+    if not settings.imap_host or not settings.imap_username:
+        raise ValueError("MORY_IMAP_HOST and MORY_IMAP_USERNAME must be set")
+    connection = imaplib.IMAP4_SSL(settings.imap_host, settings.imap_port)
+    connection.login(settings.imap_username, settings.imap_password or "")
+    return connection
+
+
+def message_text(message: EmailMessage) -> str:
+    """Plain text body, falling back to the HTML part with tags stripped"""
+    part = message.get_body(preferencelist=("plain", "html"))
+    if part is None:
+        return ""
+    text = part.get_content()
+    if part.get_content_subtype() == "html":
+        text = html.unescape(_TAG.sub("", re.sub(r"(?i)<br\s*/?>|</p>", "\n", text)))
+    return _BLANK_LINES.sub("\n\n", text).strip()
+
+
+def message_memory(raw: bytes, folder: str, uid: str) -> ImportedMemory:
+    """Memory for one RFC 822 message"""
+    message = email.message_from_bytes(raw, policy=default_policy)
+    subject = str(message.get("Subject") or "").strip() or "(no subject)"
+    name, address = parseaddr(str(message.get("From") or ""))
+    try:
+        sent = parsedate_to_datetime(str(message.get("Date")))
+    except (TypeError, ValueError):
+        sent = None
+    if sent and sent.tzinfo:
+        sent = sent.astimezone(UTC).replace(tzinfo=None)
+    message_id = str(message.get("Message-ID") or "").strip("<> ") or f"{folder}/{uid}"
+
+    metadata = {
+        "subject": subject,
+        "from": address,
+        "from_name": name,
+        "date": sent.isoformat() if sent else None,
+        "message_id": message_id,
+    }
+    return ImportedMemory(
+        source=f"{NAME}:{message_id}",
+        value=f"{subject}\n\n{message_text(message)}",
+        tags=[category_tag(folder, NAME), NAME],
+        metadata={key: value for key, value in metadata.items() if value},
+        created_at=sent,
+    )
+
+
+class MailImporter:
+    """Imports one IMAP folder, remembering the last message seen"""
+
+    def __init__(
+        self,
+        folder: str | None = None,
+        state_path: Path | None = None,
+        connect: Callable[[], imaplib.IMAP4] = _connect,
+    ):
+        self.folder = folder or settings.imap_folder
+        self.state_path = state_path or Path(settings.data_dir) / "imap_state.json"
+        self.connect = connect
+
+    def _load_state(self) -> dict[str, Any]:
+        if not self.state_path.exists():
+            return {}
+        try:
+            return json.loads(self.state_path.read_text(encoding="utf-8"))
+        except (OSError, json.JSONDecodeError):
+            return {}
+
+    def fetch(self, full: bool = False) -> tuple[list[ImportedMemory], dict[str, Any]]:
+        """Messages newer than the last run, and the folder state to save afterwards"""
+        state = {} if full else self._load_state().get(self.folder, {})
+        connection = self.connect()
+        try:
+            status, _ = connection.select(f'"{self.folder}"', readonly=True)
+            if status != "OK":
+                raise ValueError(f"IMAP folder '{self.folder}' not found")
+            _, validity = connection.response("UIDVALIDITY")
+            uid_validity = (validity[0] or b"").decode() if validity else ""
+
+            # UIDs are only comparable while the folder's UIDVALIDITY is unchanged
+            last_uid = 0
+            if state.get("uid_validity") == uid_validity:
+                last_uid = state.get("last_uid", 0)
+            criteria = settings.imap_search.split()
+            if last_uid:
+                criteria = ["UID", f"{last_uid + 1}:*", *criteria]
+            _, data = connection.uid("SEARCH", None, *criteria)
+            uids = [int(uid) for uid in (data[0] or b"").split() if int(uid) > last_uid]
+
+            items = []
+            for uid in uids:
+                _, parts = connection.uid("FETCH", str(uid), "(BODY.PEEK[])")
+                raw = next((part[1] for part in parts if isinstance(part, tuple)), None)
+                if raw:
+                    items.append(message_memory(raw, self.folder, str(uid)))
+        finally:
+            connection.logout()
+
+        new_state = {"uid_validity": uid_validity, "last_uid": max(uids, default=last_uid)}
+        return items, new_state
+
+    async def sync(
+        self, db: Session, namespace: str, dry_run: bool = False, full: bool = False
+    ) -> ImportResult:
+        """Import new messages (every matching message if full)"""
+        items, folder_state = await asyncio.to_thread(self.fetch, full)
+        result = await import_memories(db, items, namespace, dry_run=dry_run)
+        if not dry_run:
+            state = self._load_state()
+            state[self.folder] = folder_state
+            atomic_write_text(self.state_path, json.dumps(state, indent=2))
+        logger.info(f"IMAP folder {self.folder}: {result.to_dict()}")
+        return result
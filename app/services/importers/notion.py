@@ -0,0 +1,200 @@
+"""Notion database importer
+Pulls the pages of a Notion database through the API (MORY_NOTION_TOKEN; share the
+database with the integration). The select property named by MORY_NOTION_PROPERTIES
+"category" becomes the category tag, its "tags" multi-select the other tags and the
+title property (or "key") the memory's first line. Runs are incremental: only pages
+edited since the last run are fetched.
+"""
+
+import json
+import logging
+from datetime import UTC, datetime, timedelta
+from pathlib import Path
+from typing import Any
+
+import httpx
+from sqlalchemy.orm import Session
+
+from ...core.config import settings
+from ...core.fileutil import atomic_write_text
+from .base import ImportedMemory, ImportResult, category_tag, import_memories
+
+logger = logging.getLogger(__name__)
+
+NAME = "notion"
+API_URL = "https://api.notion.com/v1"
+API_VERSION = "2022-06-28"
+PAGE_SIZE = 100
+
+# Pages edited shortly before the last run are fetched again; importing twice is a no-op
+OVERLAP = timedelta(minutes=5)
+
+# Markdown-ish prefixes for the block types whose text is imported
+_BLOCK_PREFIXES = {
+    "paragraph": "",
+    "heading_1": "# ",
+    "heading_2": "## ",
+    "heading_3": "### ",
+    "bulleted_list_item": "- ",
+    "numbered_list_item": "1. ",
+    "to_do": "- [ ] ",
+    "quote": "> ",
+    "callout": "",
+    "toggle": "",
+    "code": "",
+}
+
+
+def _plain(rich_text: list[dict[str, Any]] | None) -> str:
+    return "".join(part.get("plain_text", "") for part in rich_text or [])
+
+
+def property_values(prop: dict[str, Any] | None) -> list[str]:
+    """Text values of a page property (title, rich_text, select, status, multi_select)"""
+    if not prop:
+        return []
+    kind = prop.get("type")
+    value = prop.get(kind)
+    if kind in ("title", "rich_text"):
+        text = _plain(value).strip()
+        return [text] if text else []
+    if kind in ("select", "status"):
+        return [value["name"]] if value else []
+    if kind == "multi_select":
+        return [option["name"] for option in value or []]
+    return []
+
+
+def block_text(block: dict[str, Any]) -> str | None:
+    kind = block.get("type")
+    if kind not in _BLOCK_PREFIXES:
+        return None
+    content = block.get(kind) or {}
+    text = _plain(content.get("rich_text"))
+    if not text.strip():
+        return None
+    prefix = _BLOCK_PREFIXES[kind]
+    if kind == "to_do" and content.get("checked"):
+        prefix = "- [x] "
+    if kind == "code":
+        return f"```{content.get('language', '')}\n{text}\n```"
+    return prefix + text
+
+
+def page_memory(page: dict[str, Any], body: str, properties: dict[str, str]) -> ImportedMemory:
+    """Memory for a Notion page and its text"""
+    props = page.get("properties") or {}
+    key_property = properties.get("key") or next(
+        (name for name, prop in props.items() if prop.get("type") == "title"), None
+    )
+    title = next(iter(property_values(props.get(key_property))), "") or "Untitled"
+    category = next(iter(property_values(props.get(properties.get("category", "")))), None)
+    tags = [category_tag(category, NAME)]
+    for tag in [*property_values(props.get(properties.get("tags", ""))), NAME]:
+        if tag not in tags:
+            tags.append(tag)
+
+    metadata = {"title": title, "source_url": page.get("url")}
+    if category:
+        metadata["category"] = category
+    created = page.get("created_time")
+    return ImportedMemory(
+        source=f"{NAME}:{page['id']}",
+        value=f"{title}\n\n{body}".strip(),
+        tags=tags,
+        metadata={key: value for key, value in metadata.items() if value},
+        created_at=(
+            datetime.fromisoformat(created).astimezone(UTC).replace(tzinfo=None)
+            if created
+            else None
+        ),
+    )
+
+
+class NotionImporter:
+    """Imports one Notion database, remembering when it last ran"""
+
+    def __init__(
+        self,
+        database_id: str,
+        token: str | None = None,
+        state_path: Path | None = None,
+        transport: httpx.AsyncBaseTransport | None = None,
+    ):
+        self.database_id = database_id
+        self.token = token or settings.notion_token
+        self.state_path = state_path or Path(settings.data_dir) / "notion_state.json"
+        self.transport = transport
+
+    def _load_state(self) -> dict[str, str]:
+        if not self.state_path.exists():
+            return {}
+        try:
+            return json.loads(self.state_path.read_text(encoding="utf-8"))
+        except (OSError, json.JSONDecodeError):
+            return {}
+
+    def last_run(self) -> datetime | None:
+        value = self._load_state().get(self.database_id)
+        return datetime.fromisoformat(value) if value else None
+
+    def _save_last_run(self, value: datetime) -> None:
+        state = self._load_state()
+        state[self.database_id] = value.isoformat()
+        atomic_write_text(self.state_path, json.dumps(state, indent=2))
+
+    async def _pages(self, client: httpx.AsyncClient, since: datetime | None) -> list[dict]:
+        body: dict[str, Any] = {"page_size": PAGE_SIZE}
+        if since:
+            body["filter"] = {
+                "timestamp": "last_edited_time",
+                "last_edited_time": {"on_or_after": since.isoformat()},
+            }
+        pages = []
+        while True:
+            response = await client.post(f"/databases/{self.database_id}/query", json=body)
+            response.raise_for_status()
+            data = response.json()
+            pages.extend(data.get("results", []))
+            if not data.get("has_more"):
+                return pages
+            body["start_cursor"] = data["next_cursor"]
+
+    async def _body(self, client: httpx.AsyncClient, page_id: str) -> str:
+        """Text of the page's top-level blocks"""
+        lines = []
+        params: dict[str, Any] = {"page_size": PAGE_SIZE}
+        while True:
+            response = await client.get(f"/blocks/{page_id}/children", params=params)
+            response.raise_for_status()
+            data = response.json()
+            lines.extend(text for block in data.get("results", []) if (text := block_text(block)))
+            if not data.get("has_more"):
+                return "\n".join(lines)
+            params["start_cursor"] = data["next_cursor"]
+
+    async def sync(
+        self, db: Session, namespace: str, dry_run: bool = False, full: bool = False
+    ) -> ImportResult:
+        """Import pages edited since the last run (every page if full)"""
+        if not self.token:
+            raise ValueError("MORY_NOTION_TOKEN is not set")
+        started = datetime.now(UTC)
+        last = None if full else self.last_run()
+        headers = {"Authorization": f"Bearer {self.token}", "Notion-Version": API_VERSION}
+
+        async with httpx.AsyncClient(
+            base_url=API_URL, headers=headers, timeout=60.0, transport=self.transport
+        ) as client:
+            pages = await self._pages(client, last - OVERLAP if last else None)
+            items = [
+                page_memory(page, await self._body(client, page["id"]), settings.notion_properties)
+                for page in pages
+                if not page.get("archived") and not page.get("in_trash")
+            ]
+
+        result = await import_memories(db, items, namespace, dry_run=dry_run)
+        if not dry_run:
+            self._save_last_run(started)
+        logger.info(f"Notion database {self.database_id}: {result.to_dict()}")
+        return result
@@ -0,0 +1,266 @@
+"""Built-in scheduled jobs
+Enable with MORY_JOBS: backup, weekly_review, embedding_backfill, pending_purge, auto_archive,
+sync, files_sync, compact, notion_sync, mail_sync, daily_stats
+"""
+
+import asyncio
+import logging
+import sqlite3
+from collections import defaultdict
+from datetime import datetime, timedelta
+from pathlib import Path
+
+from ..core.config import settings
+from ..core.database import SessionLocal, compact_database, retry_on_busy
+from ..core.events import MEMORY_DELETED, MemoryEvent, event_bus
+from ..core.fileutil import atomic_write_text
+from ..core.scheduler import Scheduler
+from ..models.memory import Memory
+from .archive import auto_archive
+from .backup_targets import upload_snapshot
+from .embedding import embedding_service
+from .file_store import file_store
+from .importers import MailImporter, NotionImporter
+from .stats import count_activity, record_daily_stats
+from .sync import SyncClient
+
+logger = logging.getLogger(__name__)
+
+BACKFILL_BATCH_SIZE = 50
+
+
+def _database_path() -> Path | None:
+    url = settings.sqlite_url
+    if not url.startswith("sqlite:///") or url.endswith(":memory:"):
+        return None
+    return Path(url.removeprefix("sqlite:///"))
+
+
+async def backup_job() -> str:
+    """Copy the database with SQLite's online backup API, upload it to any
+    MORY_BACKUP_TARGETS and prune old local copies"""
+    db_path = _database_path()
+    if db_path is None or not db_path.exists():
+        return "skipped: no SQLite database file"
+
+    backup_dir = Path(settings.data_dir) / "backups"
+    backup_dir.mkdir(parents=True, exist_ok=True)
+    target = backup_dir / f"memories_{datetime.now().strftime('%Y%m%d_%H%M%S')}.db"
+
+    source = sqlite3.connect(db_path)
+    destination = sqlite3.connect(target)
+    try:
+        source.backup(destination)
+    finally:
+        destination.close()
+        source.close()
+
+    backups = sorted(backup_dir.glob("memories_*.db"))
+    for old in backups[: max(len(backups) - settings.backup_keep, 0)]:
+        old.unlink()
+
+    if not settings.backup_targets:
+        return f"backup written to {target.name}"
+
+    uploaded, errors = await asyncio.to_thread(
+        upload_snapshot,
+        target.name,
+        target.read_bytes(),
+        settings.backup_targets,
+        settings.backup_encryption_key,
+    )
+    if errors:
+        raise RuntimeError(f"backup {target.name} upload failed: {'; '.join(errors)}")
+    return f"backup written to {target.name}, uploaded to {len(uploaded)} target(s)"
+
+
+async def weekly_review_job() -> str:
+    """Write a Markdown digest of the last week's memories, grouped by tag"""
+    since = datetime.utcnow() - timedelta(days=7)
+    db = SessionLocal()
+    try:
+        memories = (
+            db.query(Memory)
+            .filter(Memory.namespace == settings.namespace, Memory.updated_at >= since)
+            .order_by(Memory.updated_at.desc())
+            .all()
+        )
+        by_tag: dict[str, list[Memory]] = defaultdict(list)
+        for memory in memories:
+            for tag in memory.tags_list or ["untagged"]:
+                by_tag[tag].append(memory)
+
+        today = datetime.now().strftime("%Y-%m-%d")
+        lines = [f"# Weekly review {today}", "", f"{len(memories)} memories changed", ""]
+        for tag in sorted(by_tag, key=lambda t: -len(by_tag[t])):
+            lines.append(f"## {tag}")
+            for memory in by_tag[tag]:
+                text = (memory.summary or memory.value).replace("\n", " ")
+                lines.append(f"- {text} (`{memory.id}`)")
+            lines.append("")
+    finally:
+        db.close()
+
+    path = Path(settings.data_dir) / "reviews" / f"review_{today}.md"
+    atomic_write_text(path, "\n".join(lines))
+    return f"{len(memories)} memories in {path.name}"
+
+
+@retry_on_busy
+async def embedding_backfill_job() -> str:
+    """Generate embeddings for memories that do not have one yet"""
+    if not embedding_service.enabled:
+        return "skipped: semantic search disabled"
+
+    db = SessionLocal()
+    try:
+        missing = (
+            db.query(Memory).filter(Memory.embedding.is_(None)).limit(BACKFILL_BATCH_SIZE).all()
+        )
+        generated = await embedding_service.generate_embeddings_batch(missing, db)
+        for memory in missing:
+            if memory.embedding is not None:
+                count_activity(db, memory.namespace, embeddings_generated=1)
+        db.commit()
+    finally:
+        db.close()
+    return f"{generated}/{len(missing)} embeddings generated"
+
+
+@retry_on_busy
+async def pending_purge_job() -> str:
+    """Discard memories left in the approval queue past the retention period"""
+    cutoff = datetime.utcnow() - timedelta(days=settings.pending_retention_days)
+    db = SessionLocal()
+    try:
+        stale = (
+            db.query(Memory)
+            .filter(Memory.review_status == "pending", Memory.created_at < cutoff)
+            .all()
+        )
+        for memory in stale:
+            db.delete(memory)
+        db.commit()
+        for memory in stale:
+            await event_bus.publish(
+                MemoryEvent(
+                    MEMORY_DELETED,
+                    memory,
+                    session=db,
+                    details={"reason": "pending"},
+                    operation="purged",
+                )
+            )
+    finally:
+        db.close()
+    return f"{len(stale)} stale pending memories purged"
+
+
+@retry_on_busy
+async def auto_archive_job() -> str:
+    """Archive stale memories according to MORY_ARCHIVE_AFTER_DAYS"""
+    if settings.archive_after_days <= 0:
+        return "skipped: MORY_ARCHIVE_AFTER_DAYS is 0"
+
+    db = SessionLocal()
+    try:
+        archived = await auto_archive(db)
+    finally:
+        db.close()
+    return f"{len(archived)} memories archived"
+
+
+async def sync_job() -> str:
+    """Sync with every peer in MORY_SYNC_PEERS"""
+    if not settings.sync_peers:
+        return "skipped: MORY_SYNC_PEERS is empty"
+
+    db = SessionLocal()
+    try:
+        for peer in settings.sync_peers:
+            await SyncClient(peer, settings.sync_token).sync(db)
+    finally:
+        db.close()
+    return f"synced with {len(settings.sync_peers)} peer(s)"
+
+
+@retry_on_busy
+async def files_sync_job() -> str:
+    """Load edits made to the markdown files of the files backend"""
+    store = file_store()
+    if store is None:
+        return "skipped: MORY_STORAGE_BACKEND is not files"
+
+    db = SessionLocal()
+    try:
+        result = await store.sync_to_database(db)
+    finally:
+        db.close()
+    return f"{result.created} created, {result.updated} updated, {result.deleted} deleted"
+
+
+@retry_on_busy
+async def notion_sync_job() -> str:
+    """Import pages edited in the MORY_NOTION_DATABASE_ID database since the last run"""
+    if not settings.notion_database_id or not settings.notion_token:
+        return "skipped: MORY_NOTION_DATABASE_ID or MORY_NOTION_TOKEN is not set"
+
+    db = SessionLocal()
+    try:
+        result = await NotionImporter(settings.notion_database_id).sync(db, settings.namespace)
+    finally:
+        db.close()
+    return f"{result.created} created, {result.updated} updated from Notion"
+
+
+@retry_on_busy
+async def mail_sync_job() -> str:
+    """Import new messages from the MORY_IMAP_FOLDER mailbox folder"""
+    if not settings.imap_host:
+        return "skipped: MORY_IMAP_HOST is not set"
+
+    db = SessionLocal()
+    try:
+        result = await MailImporter().sync(db, settings.namespace)
+    finally:
+        db.close()
+    return f"{result.created} created, {result.updated} updated from {settings.imap_folder}"
+
+
+@retry_on_busy
+async def compact_job() -> str:
+    """Reclaim space left by deletions (WAL checkpoint, VACUUM, ANALYZE)"""
+    if _database_path() is None:
+        return "skipped: no SQLite database file"
+    report = await asyncio.to_thread(compact_database)
+    return f"{report['reclaimed']} bytes reclaimed ({report['size_after']} bytes now)"
+
+
+@retry_on_busy
+async def daily_stats_job() -> str:
+    """Record today's memory, embedding and per-category totals in daily_stats"""
+    db = SessionLocal()
+    try:
+        rows = record_daily_stats(db)
+    finally:
+        db.close()
+    return f"recorded {len(rows)} namespaces"
+
+
+def register_default_jobs(scheduler: Scheduler) -> None:
+    scheduler.register("backup", backup_job)
+    scheduler.register("weekly_review", weekly_review_job)
+    scheduler.register("embedding_backfill", embedding_backfill_job)
+    scheduler.register("pending_purge", pending_purge_job)
+    scheduler.register("auto_archive", auto_archive_job)
+    scheduler.register("sync", sync_job)
+    scheduler.register("files_sync", files_sync_job)
+    scheduler.register("compact", compact_job)
+    scheduler.register("notion_sync", notion_sync_job)
+    scheduler.register("mail_sync", mail_sync_job)
+    scheduler.register("daily_stats", daily_stats_job)
+
+
+# Global scheduler instance
+scheduler = Scheduler(state_path=Path(settings.data_dir) / "scheduler.json")
+register_default_jobs(scheduler)
@@ -0,0 +1,84 @@
+"""Language detection by script
+Good enough to tell the languages of a mixed Japanese/English store apart without a model:
+kana means Japanese, hangul Korean, han characters alone Chinese, and Latin letters are
+taken as English. The language picks how search splits text: unicode61 tokens for
+space-separated languages, character bigrams for CJK text.
+"""
+
+import re
+from collections import Counter
+
+from sqlalchemy.orm import Session
+
+from ..models.memory import Memory
+
+CJK_LANGUAGES = frozenset({"ja", "zh", "ko"})
+LANGUAGES = ("ja", "zh", "ko", "en")
+
+# Share of CJK letters above which mixed text counts as CJK
+CJK_SHARE = 0.2
+
+_CJK_RUN = re.compile(
+    "([\u3040-\u30ff\u31f0-\u31ff\u3400-\u4dbf\u4e00-\u9fff\uac00-\ud7af\uf900-\ufaff]+)"
+)
+
+
+def _script(char: str) -> str | None:
+    code = ord(char)
+    if 0x3040 <= code <= 0x30FF or 0x31F0 <= code <= 0x31FF or 0xFF66 <= code <= 0xFF9F:
+        return "kana"
+    if 0xAC00 <= code <= 0xD7AF or 0x1100 <= code <= 0x11FF or 0x3130 <= code <= 0x318F:
+        return "hangul"
+    if 0x4E00 <= code <= 0x9FFF or 0x3400 <= code <= 0x4DBF or 0xF900 <= code <= 0xFAFF:
+        return "han"
+    if char.isalpha() and char.isascii() or 0x00C0 <= code <= 0x024F:
+        return "latin"
+    return None
+
+
+def detect_language(text: str) -> str | None:
+    """ja, zh, ko or en for the dominant script of text; None without letters"""
+    scripts = Counter(script for script in map(_script, text) if script)
+    letters = sum(scripts.values())
+    if not letters:
+        return None
+    cjk = scripts["kana"] + scripts["hangul"] + scripts["han"]
+    if cjk / letters >= CJK_SHARE or cjk >= scripts["latin"]:
+        if scripts["kana"]:
+            return "ja"
+        if scripts["hangul"] >= scripts["han"]:
+            return "ko"
+        return "zh"
+    return "en"
+
+
+def is_cjk(text: str) -> bool:
+    return _CJK_RUN.search(text) is not None
+
+
+def bigrams(word: str) -> list[str]:
+    """Overlapping character pairs of the CJK runs in word; other parts stay whole
+
+    "京都旅行" -> ["京都", "都旅", "旅行"], "Python入門" -> ["Python", "入門"]
+    """
+    pairs: list[str] = []
+    for i, part in enumerate(_CJK_RUN.split(word)):
+        if not part:
+            continue
+        if i % 2 == 0 or len(part) <= 2:
+            pairs.append(part)
+        else:
+            pairs.extend(part[j : j + 2] for j in range(len(part) - 1))
+    return list(dict.fromkeys(pairs))
+
+
+def backfill_languages(db: Session, batch_size: int = 500) -> int:
+    """Detect the language of memories saved before it was recorded; returns the count"""
+    ids = [row.id for row in db.query(Memory.id).filter(Memory.language.is_(None))]
+    updated = 0
+    for start in range(0, len(ids), batch_size):
+        for memory in db.query(Memory).filter(Memory.id.in_(ids[start : start + batch_size])):
+            memory.language = detect_language(memory.value or "")
+            updated += memory.language is not None
+        db.commit()
+    return updated
@@ -0,0 +1,188 @@
+"""Helpers for writing to, searching and exporting the operation log"""
+
+import csv
+import hashlib
+import io
+import json
+from datetime import datetime
+from typing import Any
+
+from sqlalchemy import event, or_, select, update
+from sqlalchemy.orm import Session
+from sqlalchemy.orm.attributes import set_committed_value
+
+from ..models.operation_log import OperationLog
+
+# Memory fields copied into "before"/"after" snapshots so deleted content stays findable
+SNAPSHOT_FIELDS = ("value", "summary", "tags", "namespace")
+
+# Columns of an audit export, in CSV order; details stay the JSON text that was hashed
+AUDIT_FIELDS = (
+    "id",
+    "created_at",
+    "operation",
+    "memory_id",
+    "agent_id",
+    "details",
+    "prev_hash",
+    "hash",
+)
+
+
+def record_operation(
+    db: Session,
+    operation: str,
+    memory_id: str | None = None,
+    agent_id: str | None = None,
+    details: dict[str, Any] | None = None,
+) -> OperationLog:
+    """Add an operation log entry to the session (committed with the caller's transaction)"""
+    entry = OperationLog(
+        operation=operation,
+        memory_id=memory_id,
+        agent_id=agent_id,
+        details=json.dumps(details or {}, ensure_ascii=False, default=str),
+    )
+    db.add(entry)
+    return entry
+
+
+def audit_record(entry: OperationLog) -> dict[str, Any]:
+    """Entry as exported for auditing"""
+    return {
+        "id": entry.id,
+        "created_at": entry.created_at.isoformat() if entry.created_at else None,
+        "operation": entry.operation,
+        "memory_id": entry.memory_id,
+        "agent_id": entry.agent_id,
+        "details": entry.details,
+        "prev_hash": entry.prev_hash,
+        "hash": entry.hash,
+    }
+
+
+def chain_hash(record: dict[str, Any], prev_hash: str | None) -> str:
+    """SHA-256 over the previous hash and an entry's content (missing values count as "")"""
+    fields = [prev_hash or ""] + [
+        str(record.get(field) or "") for field in AUDIT_FIELDS if field not in ("prev_hash", "hash")
+    ]
+    payload = json.dumps(fields, ensure_ascii=False, separators=(",", ":"))
+    return hashlib.sha256(payload.encode("utf-8")).hexdigest()
+
+
+@event.listens_for(Session, "after_flush")
+def _chain_new_entries(session: Session, flush_context: Any) -> None:
+    """Link entries inserted by this flush to the chain
+
+    Runs after the INSERT, while the transaction holds SQLite's write lock, so no
+    other writer can append in between.
+    """
+    entries = sorted(
+        (obj for obj in session.new if isinstance(obj, OperationLog)), key=lambda e: e.id
+    )
+    if not entries:
+        return
+    connection = session.connection()
+    prev_hash = connection.execute(
+        select(OperationLog.hash)
+        .where(OperationLog.id < entries[0].id)
+        .order_by(OperationLog.id.desc())
+        .limit(1)
+    ).scalar()
+    for entry in entries:
+        entry_hash = chain_hash(audit_record(entry), prev_hash)
+        connection.execute(
+            update(OperationLog)
+            .where(OperationLog.id == entry.id)
+            .values(prev_hash=prev_hash, hash=entry_hash)
+        )
+        set_committed_value(entry, "prev_hash", prev_hash)
+        set_committed_value(entry, "hash", entry_hash)
+        prev_hash = entry_hash
+
+
+def verify_chain(records: list[dict[str, Any]]) -> list[str]:
+    """Problems found in consecutive audit records; empty if the chain is intact"""
+    problems = []
+    previous: dict[str, Any] | None = None
+    for record in records:
+        if not record.get("hash"):
+            problems.append(f"Entry {record['id']} has no hash (written before hash chaining)")
+        elif chain_hash(record, record.get("prev_hash")) != record["hash"]:
+            problems.append(f"Entry {record['id']} does not match its hash (altered)")
+        if previous is not None and previous.get("hash") and (
+            (record.get("prev_hash") or "") != previous["hash"]
+        ):
+            problems.append(
+                f"Entry {record['id']} does not follow entry {previous['id']} (entries removed)"
+            )
+        previous = record
+    return problems
+
+
+def audit_entries(
+    db: Session, since: datetime | None = None, until: datetime | None = None
+) -> list[OperationLog]:
+    """Log entries in a time range, in chain order"""
+    entries = db.query(OperationLog)
+    if since:
+        entries = entries.filter(OperationLog.created_at >= since)
+    if until:
+        entries = entries.filter(OperationLog.created_at <= until)
+    return entries.order_by(OperationLog.id.asc()).all()
+
+
+def export_audit(records: list[dict[str, Any]], format: str = "jsonl") -> str:
+    """Audit records as JSON lines or CSV"""
+    if format == "csv":
+        output = io.StringIO()
+        writer = csv.DictWriter(output, fieldnames=AUDIT_FIELDS, lineterminator="\n")
+        writer.writeheader()
+        writer.writerows(records)
+        return output.getvalue()
+    return "".join(json.dumps(record, ensure_ascii=False) + "\n" for record in records)
+
+
+def load_audit(text: str, format: str = "jsonl") -> list[dict[str, Any]]:
+    """Records read back from export_audit output"""
+    if format == "csv":
+        return list(csv.DictReader(io.StringIO(text)))
+    return [json.loads(line) for line in text.splitlines() if line.strip()]
+
+
+def memory_snapshot(memory: Any) -> dict[str, Any]:
+    """Content of a memory as recorded in operation log details"""
+    return {field: getattr(memory, field, None) for field in SNAPSHOT_FIELDS}
+
+
+def _contains(column, term: str):
+    escaped = term.replace("\\", "\\\\").replace("%", "\\%").replace("_", "\\_")
+    return column.ilike(f"%{escaped}%", escape="\\")
+
+
+def search_operations(
+    db: Session,
+    query: str,
+    operation: str | None = None,
+    since: datetime | None = None,
+    until: datetime | None = None,
+    limit: int = 20,
+) -> list[OperationLog]:
+    """Log entries whose memory ID or details contain every query term, newest first
+
+    Details hold the before/after snapshots, so this finds memories that have since
+    been changed or deleted ("when did I delete the note about the conference").
+    """
+    entries = db.query(OperationLog)
+    for term in query.split():
+        entries = entries.filter(
+            or_(_contains(OperationLog.details, term), _contains(OperationLog.memory_id, term))
+        )
+    if operation:
+        entries = entries.filter(OperationLog.operation == operation)
+    if since:
+        entries = entries.filter(OperationLog.created_at >= since)
+    if until:
+        entries = entries.filter(OperationLog.created_at <= until)
+    entries = entries.order_by(OperationLog.created_at.desc(), OperationLog.id.desc())
+    return entries.limit(limit).all()
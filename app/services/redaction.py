@@ -0,0 +1,107 @@
+"""Redaction of sensitive content before it is persisted
+Regex rules (built-in plus MORY_REDACTION_RULES) either mask matches or reject the save
+"""
+
+import re
+from dataclasses import dataclass, field
+
+from ..core.config import settings
+
+# Built-in rules applied whenever redaction is enabled
+BUILTIN_RULES: dict[str, str] = {
+    "credit_card": r"\b(?:\d[ -]?){12,18}\d\b",
+    "openai_api_key": r"\bsk-(?:proj-)?[A-Za-z0-9_-]{20,}\b",
+    "aws_access_key": r"\b(?:AKIA|ASIA)[0-9A-Z]{16}\b",
+    "github_token": r"\bgh[pousr]_[A-Za-z0-9]{36,}\b",
+    "private_key": r"-----BEGIN [A-Z ]*PRIVATE KEY-----[\s\S]*?-----END [A-Z ]*PRIVATE KEY-----",
+}
+
+
+class RedactionError(ValueError):
+    """Raised when content matches a rule and the policy is to reject it"""
+
+    def __init__(self, matches: dict[str, int]):
+        self.matches = matches
+        super().__init__(f"Content contains sensitive data ({', '.join(sorted(matches))})")
+
+
+@dataclass
+class RedactionResult:
+    """Outcome of running the redaction rules over a text"""
+
+    text: str
+    matches: dict[str, int] = field(default_factory=dict)  # rule name -> match count
+
+    @property
+    def redacted(self) -> bool:
+        return bool(self.matches)
+
+
+def _luhn_valid(candidate: str) -> bool:
+    """Luhn checksum, used to avoid masking arbitrary long numbers"""
+    digits = [int(c) for c in candidate if c.isdigit()]
+    if not 13 <= len(digits) <= 19:
+        return False
+    total = 0
+    for i, digit in enumerate(reversed(digits)):
+        if i % 2 == 1:
+            digit *= 2
+            if digit > 9:
+                digit -= 9
+        total += digit
+    return total % 10 == 0
+
+
+class RedactionService:
+    """Applies the configured redaction rules"""
+
+    def __init__(self):
+        self._compiled: dict[str, re.Pattern] = {}
+        self._compiled_from: dict[str, str] | None = None
+
+    @property
+    def enabled(self) -> bool:
+        return settings.redaction_enabled
+
+    def rules(self) -> dict[str, re.Pattern]:
+        """Compiled rules, rebuilt when the configured rules change"""
+        source = {**BUILTIN_RULES, **settings.redaction_rules}
+        if source != self._compiled_from:
+            self._compiled = {name: re.compile(pattern) for name, pattern in source.items()}
+            self._compiled_from = source
+        return self._compiled
+
+    def redact(self, text: str) -> RedactionResult:
+        """Mask every rule match as [REDACTED:<rule>]"""
+        matches: dict[str, int] = {}
+
+        for name, pattern in self.rules().items():
+
+            def _mask(match: re.Match, name: str = name) -> str:
+                if name == "credit_card" and not _luhn_valid(match.group(0)):
+                    return match.group(0)
+                matches[name] = matches.get(name, 0) + 1
+                return f"[REDACTED:{name}]"
+
+            text = pattern.sub(_mask, text)
+
+        return RedactionResult(text=text, matches=matches)
+
+    def apply(self, text: str) -> RedactionResult:
+        """Run the pipeline according to MORY_REDACTION_MODE
+
+        Raises:
+            RedactionError: If sensitive content is found and the mode is "reject"
+
+        """
+        if not self.enabled:
+            return RedactionResult(text=text)
+
+        result = self.redact(text)
+        if result.redacted and settings.redaction_mode == "reject":
+            raise RedactionError(result.matches)
+        return result
+
+
+# Global redaction service instance
+redaction_service = RedactionService()
@@ -0,0 +1,184 @@
+"""Markdown digest report
+A standalone overview of the memory store for periodic self-review: growth per month,
+day-by-day activity (when daily statistics are recorded), top categories and tags,
+embedding coverage, the largest memories and the memories from the report period that
+were read the most.
+"""
+
+from datetime import datetime, timedelta
+
+from sqlalchemy import func, text
+from sqlalchemy.orm import Session
+
+from ..models.memory import Memory
+from .counts import dashboard_counts, tag_counts
+from .file_store import DEFAULT_CATEGORY
+from .stats import stats_history
+
+DEFAULT_DAYS = 30
+GROWTH_MONTHS = 12
+TOP_COUNT = 10
+LIST_COUNT = 5
+BAR_WIDTH = 20
+
+
+def monthly_growth(
+    db: Session, namespace: str, months: int = GROWTH_MONTHS
+) -> list[tuple[str, int, int]]:
+    """(YYYY-MM, memories created, total at the end of the month) for the last months"""
+    rows = db.execute(
+        text(
+            """
+            SELECT strftime('%Y-%m', created_at) AS month, COUNT(*) AS created
+            FROM memories
+            WHERE namespace = :namespace
+            GROUP BY month
+            ORDER BY month
+            """
+        ),
+        {"namespace": namespace},
+    ).all()
+    growth = []
+    total = 0
+    for row in rows:
+        total += row.created
+        growth.append((row.month, row.created, total))
+    return growth[-months:]
+
+
+def category_counts(db: Session, namespace: str, limit: int = TOP_COUNT) -> list[tuple[str, int]]:
+    """(category, number of memories) pairs; the category is a memory's first tag"""
+    rows = db.execute(
+        text(
+            """
+            SELECT COALESCE(
+                CASE WHEN json_valid(tags) THEN json_extract(tags, '$[0]') END, :default
+            ) AS category, COUNT(*) AS uses
+            FROM memories
+            WHERE namespace = :namespace AND archived_at IS NULL
+            GROUP BY category
+            ORDER BY uses DESC, category
+            LIMIT :limit
+            """
+        ),
+        {"namespace": namespace, "default": DEFAULT_CATEGORY, "limit": limit},
+    ).all()
+    return [(row.category, row.uses) for row in rows]
+
+
+def _preview(memory: Memory, length: int = 80) -> str:
+    text = " ".join((memory.summary or memory.value).split()).replace("|", "\\|")
+    return text if len(text) <= length else text[: length - 1] + "…"
+
+
+def _bar(value: int, largest: int) -> str:
+    return "█" * max(round(value / largest * BAR_WIDTH), 1) if value else ""
+
+
+def _percent(part: int, whole: int) -> str:
+    return f"{part / whole:.0%}" if whole else "-"
+
+
+def _number(value: int | None) -> str:
+    return "-" if value is None else f"{value:,}"
+
+
+def build_report(
+    db: Session, namespace: str, days: int = DEFAULT_DAYS, now: datetime | None = None
+) -> str:
+    """The report as markdown"""
+    now = now or datetime.utcnow()
+    since = now - timedelta(days=days)
+    counts = dashboard_counts(db, namespace)
+    total = counts["total_memories"]
+    active = Memory.namespace == namespace, Memory.archived_at.is_(None)
+    created_recently = (
+        db.query(func.count(Memory.id))
+        .filter(Memory.namespace == namespace, Memory.created_at >= since)
+        .scalar()
+    )
+
+    lines = [
+        f"# Mory report: {namespace}",
+        "",
+        f"Generated {now:%Y-%m-%d %H:%M} UTC, covering the last {days} days.",
+        "",
+        "## Overview",
+        "",
+        "| | |",
+        "|---|---:|",
+        f"| Memories | {total:,} |",
+        f"| Created in the last {days} days | {created_recently:,} |",
+        f"| With embeddings | {counts['memories_with_embeddings']:,}"
+        f" ({_percent(counts['memories_with_embeddings'], total)}) |",
+        f"| Pending review | {counts['pending_review']:,} |",
+        f"| Archived | {counts['archived']:,} |",
+    ]
+
+    growth = monthly_growth(db, namespace)
+    lines += ["", "## Growth", ""]
+    if growth:
+        largest = max(created for _, created, _ in growth)
+        lines += ["| Month | New | Total | |", "|---|---:|---:|---|"]
+        lines += [
+            f"| {month} | {created:,} | {running:,} | {_bar(created, largest)} |"
+            for month, created, running in growth
+        ]
+    else:
+        lines.append("No memories yet.")
+
+    history = stats_history(db, namespace, days, now)
+    if history:
+        lines += [
+            "",
+            "## Daily activity",
+            "",
+            "| Day | Memories | With embeddings | Searches | Embeddings generated |",
+            "|---|---:|---:|---:|---:|",
+        ]
+        lines += [
+            f"| {row.day} | {_number(row.total_memories)} | "
+            f"{_number(row.memories_with_embeddings)} | {row.searches:,} | "
+            f"{row.embeddings_generated:,} |"
+            for row in history
+        ]
+
+    for title, pairs in (
+        ("Top categories", category_counts(db, namespace)),
+        ("Top tags", tag_counts(db, namespace, limit=TOP_COUNT)),
+    ):
+        lines += ["", f"## {title}", ""]
+        lines += [f"- {name} ({uses:,})" for name, uses in pairs] or ["None."]
+
+    largest_memories = (
+        db.query(Memory)
+        .filter(*active)
+        .order_by(func.length(Memory.value).desc())
+        .limit(LIST_COUNT)
+        .all()
+    )
+    lines += ["", "## Largest memories", ""]
+    if largest_memories:
+        lines += ["| ID | Characters | Memory |", "|---|---:|---|"]
+        lines += [
+            f"| {memory.id} | {len(memory.value):,} | {_preview(memory)} |"
+            for memory in largest_memories
+        ]
+    else:
+        lines.append("None.")
+
+    highlights = (
+        db.query(Memory)
+        .filter(*active, Memory.review_status == "approved", Memory.created_at >= since)
+        .order_by(
+            Memory.access_count.desc(), Memory.confidence.desc(), Memory.created_at.desc()
+        )
+        .limit(LIST_COUNT)
+        .all()
+    )
+    lines += ["", "## Recent highlights", ""]
+    lines += [
+        f"- {memory.created_at:%Y-%m-%d} {_preview(memory)} (read {memory.access_count}×)"
+        for memory in highlights
+    ] or [f"Nothing new in the last {days} days."]
+    return "\n".join(lines) + "\n"
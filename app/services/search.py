@@ -1,62 +1,457 @@
 """Search service for memory search functionality"""
 
+import json
+import logging
+import math
+import re
+import statistics
 import time
+from collections.abc import Callable
+from dataclasses import dataclass, field
 
 import numpy as np
-import openai
-from sqlalchemy import and_, or_, text
+from sqlalchemy import or_, text
+from sqlalchemy.exc import OperationalError
 from sqlalchemy.orm import Session
 
 from ..core.config import settings
-from ..core.database import check_fts5_support
+from ..core.database import FTS_COLUMNS, check_fts5_support
+from ..core.metrics import metrics
+from ..core.tracing import trace_span
 from ..models.memory import Memory
 from ..models.schemas import MemoryResponse, SearchRequest, SearchResponse, SearchResult
+from .content_types import base_type
+from .embedding import Embedder, embedding_service, load_chunk_vectors
+from .language import bigrams, is_cjk
+from .stats import count_search
+
+logger = logging.getLogger(__name__)
+
+# Unverified facts rank below verified ones, scaled further by their confidence
+UNVERIFIED_WEIGHT = 0.9
+
+# Memories whose category or tags match the session context (set_context) rank higher
+CONTEXT_BOOST = 1.5
+
+# Code and JSON memories containing a query identifier verbatim rank higher
+IDENTIFIER_BOOST = 1.3
+_IDENTIFIER = re.compile(r"[A-Za-z_][\w.]*\w")
+
+
+def credibility_weight(memory: MemoryResponse) -> float:
+    """Score multiplier preferring verified, high-confidence memories"""
+    if memory.verified:
+        return 1.0
+    return UNVERIFIED_WEIGHT * (0.5 + 0.5 * memory.confidence)
+
+
+def _words(text: str) -> set[str]:
+    return set(re.findall(r"\w+", text.lower()))
+
+
+def context_terms(context: str | None) -> set[str]:
+    """Words of a context hint ("working on project mory") that can match a tag"""
+    stopwords = {word.lower() for word in settings.search_stopwords}
+    return {word for word in _words(context or "") if len(word) > 2} - stopwords
+
+
+def context_weight(memory: MemoryResponse, terms: set[str]) -> float:
+    """Score multiplier for memories whose category or tags share a word with the context"""
+    if terms and any(terms & _words(tag) for tag in memory.tags):
+        return CONTEXT_BOOST
+    return 1.0
+
+
+def query_identifiers(query: str) -> set[str]:
+    """Words of a query that look like code identifiers: snake_case, camelCase, a.b"""
+    return {
+        word
+        for word in _IDENTIFIER.findall(query)
+        if "_" in word or "." in word or (word[1:] != word[1:].lower() and word != word.upper())
+    }
+
+
+def identifier_weight(memory: MemoryResponse, identifiers: set[str]) -> float:
+    """Score multiplier for code and JSON memories containing a query identifier exactly"""
+    if not identifiers or base_type(memory.content_type) not in ("code", "json"):
+        return 1.0
+    names = set(_IDENTIFIER.findall(memory.value))
+    names |= {part for name in names for part in name.split(".")}
+    return IDENTIFIER_BOOST if identifiers & names else 1.0
+
+
+def _rank(results: list[SearchResult], request: SearchRequest | None = None) -> list[SearchResult]:
+    """Apply the credibility, context and identifier weights and sort by score"""
+    terms = context_terms(request.context if request else None)
+    identifiers = query_identifiers(request.query) if request else set()
+    for result in results:
+        result.score *= (
+            credibility_weight(result.memory)
+            * context_weight(result.memory, terms)
+            * identifier_weight(result.memory, identifiers)
+        )
+    return sorted(results, key=lambda result: result.score, reverse=True)
+
+
+# Whether the reader may see a result's memory (the permission hook, bound to an agent)
+Visibility = Callable[[MemoryResponse], bool]
+
+
+def _paginate(
+    results: list[SearchResult], request: SearchRequest, visible: Visibility | None = None
+) -> tuple[list[SearchResult], int]:
+    """The requested page and the total, counting only results the reader may see"""
+    if visible is not None:
+        results = [result for result in results if visible(result.memory)]
+    return results[request.offset : request.offset + request.limit], len(results)
+
+
+NORMALIZATION_METHODS = ("minmax", "zscore", "none")
+
+
+def normalize_scores(results: list[SearchResult], method: str = "minmax") -> list[SearchResult]:
+    """Rescale one engine's scores for this query onto 0..1 (in place)
+
+    Keyword scores, bm25 relevance and cosine similarity use different scales, so
+    hybrid search normalizes each engine's results before weighting them.
+    minmax maps the best result to 1 and the worst to 0; zscore maps standard
+    scores through a logistic curve. A single result, or all-equal scores, get 1.0.
+    """
+    if method == "none" or not results:
+        return results
+
+    scores = [result.score for result in results]
+    low, high = min(scores), max(scores)
+    if high == low:
+        for result in results:
+            result.score = 1.0
+        return results
+
+    if method == "zscore":
+        mean = statistics.fmean(scores)
+        spread = statistics.pstdev(scores)
+        for result in results:
+            result.score = 1.0 / (1.0 + math.exp(-(result.score - mean) / spread))
+    else:
+        for result in results:
+            result.score = (result.score - low) / (high - low)
+    return results
+
+
+class SearchQueryError(ValueError):
+    """Raised when an advanced query is not valid FTS5 syntax"""
+
+
+FTS_OPERATORS = {"AND", "OR", "NOT"}
+
+# "phrase" (doubled quotes inside, may be unterminated) | ( | ) | , | anything else
+_ADVANCED_TOKEN = re.compile(r'"(?:[^"]|"")*"?|[(),]|[^\s(),"]+')
+
+
+def _fts5_string(term: str) -> str:
+    return '"' + term.replace('"', '""') + '"'
+
+
+# "quoted phrase" | bare word
+_QUERY_TOKEN = re.compile(r'"([^"]*)"|(\S+)')
+
+_STEM_SUFFIXES = ("ingly", "edly", "ing", "ies", "ed", "ly", "s")
+
+
+def stem(word: str) -> str:
+    """Light English suffix stripping; the stem stays a prefix of its word forms"""
+    if len(word) <= 4 or not word.isascii() or not word.isalpha():
+        return word
+    lower = word.lower()
+    for suffix in _STEM_SUFFIXES:
+        base = lower[: -len(suffix)]
+        if not lower.endswith(suffix) or len(base) < 3 or (suffix == "s" and base[-1] == "s"):
+            continue
+        # running -> runn -> run
+        if suffix.startswith("ing") and base[-1] == base[-2] and base[-1] not in "lsz":
+            base = base[:-1]
+        return base
+    return lower
+
+
+@dataclass
+class QueryTerm:
+    """One search term: a word, or a quoted phrase matched as a whole"""
+
+    text: str
+    phrase: bool = False
+    prefix: bool = False  # stemmed: also match longer word forms
+    synonyms: list[str] = field(default_factory=list)  # each matches as well as text
+
+    @property
+    def forms(self) -> list[str]:
+        return [self.text, *self.synonyms]
+
+
+def synonyms_for(word: str) -> list[str]:
+    """The other members of every MORY_SEARCH_SYNONYMS group containing the word"""
+    word = word.lower()
+    found: list[str] = []
+    for key, values in settings.search_synonyms.items():
+        group = [key, *([values] if isinstance(values, str) else values)]
+        if word in (member.lower() for member in group):
+            found.extend(m for m in group if m.lower() != word and m not in found)
+    return found
+
+
+def query_terms(query: str, stemming: bool | None = None) -> list[QueryTerm]:
+    """Split a query into words and "quoted phrases"
+
+    Stopwords are dropped (unless the query has nothing else), words are stemmed if
+    enabled, and configured synonyms become alternatives of their term.
+    """
+    if stemming is None:
+        stemming = settings.search_stemming
+    stopwords = {word.lower() for word in settings.search_stopwords}
+    terms = []
+    dropped = []
+    for phrase, word in _QUERY_TOKEN.findall(query):
+        if phrase.strip():
+            text = " ".join(phrase.split())
+            terms.append(QueryTerm(text, phrase=True, synonyms=synonyms_for(text)))
+        elif word:
+            stemmed = stem(word) if stemming else word
+            term = QueryTerm(
+                stemmed, prefix=stemmed.lower() != word.lower(), synonyms=synonyms_for(word)
+            )
+            (dropped if word.lower() in stopwords else terms).append(term)
+    return terms or dropped
+
+
+def split_cjk_terms(terms: list[QueryTerm]) -> list[QueryTerm]:
+    """Replace CJK words by their character bigrams
+
+    Japanese and Chinese are written without spaces, so a query word rarely occurs in a
+    memory exactly as typed; its bigrams also find text phrased a little differently.
+    """
+    split = []
+    for term in terms:
+        if term.phrase or not is_cjk(term.text):
+            split.append(term)
+        else:
+            split.extend(QueryTerm(pair) for pair in bigrams(term.text))
+    return split
+
+
+def parse_fts5_query(query: str, advanced: bool = False) -> str:
+    """Turn user input into an FTS5 MATCH expression
+
+    By default every word and "quoted phrase" is matched literally (all must occur);
+    stemmed words match as prefixes and synonyms are ORed with their term. With
+    advanced=True, AND/OR/NOT, parentheses, "quoted phrases", prefix* and
+    NEAR(a b, 5) keep their FTS5 meaning; other words are still quoted so colons
+    and minus signs cannot select columns or break the query.
+    """
+    if not advanced:
+        parts = []
+        for term in query_terms(query):
+            forms = [_fts5_string(term.text) + ("*" if term.prefix else "")]
+            forms.extend(_fts5_string(synonym) for synonym in term.synonyms)
+            parts.append(forms[0] if len(forms) == 1 else f"({' OR '.join(forms)})")
+        # FTS5 needs an explicit AND after a parenthesised group
+        return " AND ".join(parts)
+
+    parts = []
+    depth = 0
+    near_depth = None  # paren depth of an open NEAR( group
+    tokens = _ADVANCED_TOKEN.findall(query)
+    for i, token in enumerate(tokens):
+        if token.startswith('"'):
+            parts.append(token if len(token) > 1 and token.endswith('"') else token + '"')
+        elif token == "(":
+            depth += 1
+            parts.append(token)
+        elif token == ")":
+            if depth == 0:
+                raise SearchQueryError("Unbalanced ')' in search query")
+            depth -= 1
+            if near_depth is not None and depth < near_depth:
+                near_depth = None
+            parts.append(token)
+        elif token == ",":
+            if near_depth != depth:
+                raise SearchQueryError("',' is only allowed inside NEAR(...)")
+            parts.append(token)
+        elif token in FTS_OPERATORS:
+            parts.append(token)
+        elif token == "NEAR" and i + 1 < len(tokens) and tokens[i + 1] == "(":
+            near_depth = depth + 1
+            parts.append(token)
+        elif token.isdigit() and parts and parts[-1] == ",":
+            parts.append(token)
+        elif token.endswith("*") and token.rstrip("*"):
+            parts.append(_fts5_string(token.rstrip("*")) + "*")
+        else:
+            parts.append(_fts5_string(token))
+    if depth:
+        raise SearchQueryError("Unbalanced '(' in search query")
+    return " ".join(parts)
+
+
+def bm25_weights() -> list[float]:
+    """MORY_FTS_WEIGHTS in FTS column order, as bm25() expects them"""
+    return [float(settings.fts_weights.get(column, 1.0)) for column in FTS_COLUMNS]
+
+
+def _field_match(text: str, term: str) -> float:
+    """0..1 for one term in one field: saturating term frequency, earlier is better"""
+    position = text.find(term)
+    if position < 0:
+        return 0.0
+    frequency = text.count(term)
+    saturation = frequency / (frequency + 1.0)  # 1 hit 0.5, 3 hits 0.75
+    earliness = 1.0 - position / max(len(text), 1)
+    return saturation * (0.5 + 0.5 * earliness)
+
+
+def _term_score(
+    term: str, fields: dict[str, str], tags: list[str], weights: dict[str, float]
+) -> float:
+    score = sum(weights[name] * _field_match(text, term) for name, text in fields.items())
+    if term in tags:
+        return score + weights["tags"]
+    return score + weights["tags"] * max((_field_match(tag, term) for tag in tags), default=0.0)
+
+
+def like_score(memory: Memory, terms: list[QueryTerm | str]) -> float:
+    """Relevance of a LIKE match (0..1) using the same column weights as bm25()
+
+    Each term (word or phrase) scores per column, an exact tag counting as a full
+    match. The weighted column scores are averaged over all terms and scaled by the
+    share of terms found, so documents containing every term rank first. A term
+    with synonyms scores as its best matching form.
+    """
+    term_forms = [[term] if isinstance(term, str) else term.forms for term in terms if term]
+    if not term_forms:
+        return 0.0
+    try:
+        tags = [str(tag).lower() for tag in json.loads(memory.tags or "[]")]
+    except (TypeError, ValueError):
+        tags = []
+    fields = {"value": (memory.value or "").lower(), "summary": (memory.summary or "").lower()}
+    weights = dict(zip(FTS_COLUMNS, bm25_weights(), strict=True))
+    total_weight = sum(weights.values()) or 1.0
+
+    scores = [
+        max(_term_score(form.lower(), fields, tags, weights) for form in forms)
+        for forms in term_forms
+    ]
+    coverage = sum(1 for score in scores if score > 0) / len(scores)
+    return sum(scores) / len(scores) / total_weight * coverage
+
+
+def _metadata_filter(column: str, metadata: dict[str, str]) -> tuple[list[str], dict]:
+    """SQL conditions matching metadata keys; list values match if any item equals"""
+    conditions = []
+    params = {}
+    for i, (key, value) in enumerate(metadata.items()):
+        conditions.append(
+            f"EXISTS (SELECT 1 FROM json_each({column}, :meta_path_{i}) "
+            f"WHERE CAST(json_each.value AS TEXT) = :meta_value_{i})"
+        )
+        params[f"meta_path_{i}"] = '$."' + key.replace('"', "") + '"'
+        params[f"meta_value_{i}"] = str(value)
+    return conditions, params
+
+
+def tags_filter(column: str, tags: list[str]) -> tuple[str, dict]:
+    """Memories carrying any of the tags, compared as whole JSON array elements"""
+    placeholders = ", ".join(f":tag_{i}" for i in range(len(tags)))
+    condition = (
+        f"EXISTS (SELECT 1 FROM json_each(CASE WHEN json_valid({column}) "
+        f"THEN {column} ELSE '[]' END) WHERE json_each.value IN ({placeholders}))"
+    )
+    return condition, {f"tag_{i}": tag for i, tag in enumerate(tags)}
+
+
+def _like_pattern(term: str) -> str:
+    """Substring pattern with LIKE wildcards in the term matched literally"""
+    escaped = term.replace("\\", "\\\\").replace("%", "\\%").replace("_", "\\_")
+    return f"%{escaped}%"
+
+
+def _source_filter(column: str, source: str) -> tuple[str, dict]:
+    """Exact match for "type:detail", prefix match for a bare source type"""
+    if ":" in source:
+        return f"{column} = :source", {"source": source}
+    return f"({column} = :source OR {column} LIKE :source_prefix)", {
+        "source": source,
+        "source_prefix": f"{source}:%",
+    }
 
 
 class SearchService:
     """Service for memory search operations"""
 
-    def __init__(self) -> None:
-        """Initialize search service with available search backends"""
+    def __init__(self, embedder: Embedder | None = None) -> None:
+        """Initialize search service with available search backends
+
+        Args:
+            embedder: Embeds semantic queries (default: the shared embedding service)
+
+        """
         self.fts5_available = check_fts5_support()
         self.semantic_available = settings.is_semantic_available
-        if self.semantic_available:
-            openai.api_key = settings.openai_api_key
+        self.embedder = embedder or embedding_service
 
-    async def search_memories(self, request: SearchRequest, db: Session) -> SearchResponse:
-        """Perform memory search with specified type"""
+    async def search_memories(
+        self, request: SearchRequest, db: Session, visible: Visibility | None = None
+    ) -> SearchResponse:
+        """Perform memory search with specified type, leaving out results not visible"""
         start_time = time.time()
 
         # Determine search strategy
         search_type = self._determine_search_type(request.search_type)
 
+        # Clamp to the configured result limit (hot-reloadable)
+        if request.limit > settings.max_search_results:
+            request = request.model_copy(update={"limit": settings.max_search_results})
+
         results: list[SearchResult] = []
         total = 0
 
         if search_type == "fts5":
-            results, total = await self._search_fts5(request, db)
+            results, total = await self._search_fts5(request, db, visible)
         elif search_type == "semantic":
-            results, total = await self._search_semantic(request, db)
+            results, total = await self._search_semantic(request, db, visible)
         elif search_type == "hybrid":
-            results, total = await self._search_hybrid(request, db)
+            results, total = await self._search_hybrid(request, db, visible)
         else:
             # Fallback to LIKE search
-            results, total = await self._search_like(request, db)
+            results, total = await self._search_like(request, db, visible)
 
         execution_time = (time.time() - start_time) * 1000
+        metrics.observe(
+            "mory_search_duration_seconds", execution_time / 1000, {"search_type": search_type}
+        )
 
-        return SearchResponse(
+        response = SearchResponse(
             results=results,
             total=total,
             query=request.query,
             search_type=search_type,
             execution_time_ms=round(execution_time, 2),
             filters={
+                "namespace": request.namespace,
+                "owner": request.owner,
+                "source": request.source,
+                "include_pending": request.include_pending,
+                "include_archived": request.include_archived,
                 "tags": request.tags,
+                "metadata": request.metadata,
+                "language": request.language,
                 "date_from": request.date_from.isoformat() if request.date_from else None,
                 "date_to": request.date_to.isoformat() if request.date_to else None,
+                "context": request.context,
             },
         )
+        count_search(request.namespace or settings.namespace)
+        return response
 
     def _determine_search_type(self, requested_type: str) -> str:
         """Determine the actual search type to use"""
@@ -69,23 +464,32 @@ class SearchService:
         return requested_type
 
     async def _search_fts5(
-        self, request: SearchRequest, db: Session
+        self, request: SearchRequest, db: Session, visible: Visibility | None = None
     ) -> tuple[list[SearchResult], int]:
         """Perform FTS5 full-text search"""
         if not self.fts5_available:
-            return await self._search_like(request, db)
+            return await self._search_like(request, db, visible)
+
+        # unicode61 keeps a run of CJK characters as one token, so such queries rarely
+        # match; keyword search splits them into bigrams instead
+        if not request.advanced and is_cjk(request.query):
+            return await self._search_like(request, db, visible)
 
         # Build FTS5 query
-        fts_query = self._build_fts5_query(request.query)
+        fts_query = parse_fts5_query(request.query, request.advanced)
+        if not fts_query:
+            return [], 0
 
         # Build filter conditions and parameters
         filter_conditions, filter_params = self._build_fts5_filters(request)
 
-        # Build the main query
-        base_sql = """
-            SELECT m.*, fts.rank
-            FROM memories m
-            JOIN memories_fts fts ON m.id = fts.id
+        # bm25() is lower for better matches; weights favour tags and summaries
+        weight_params = {f"w_{i}": weight for i, weight in enumerate(bm25_weights())}
+        weights_sql = ", ".join(f":{name}" for name in weight_params)
+        base_sql = f"""
+            SELECT m.*, bm25(memories_fts, {weights_sql}) AS rank
+            FROM memories_fts
+            JOIN memories m ON m.rowid = memories_fts.rowid
             WHERE memories_fts MATCH :query
         """
 
@@ -95,14 +499,21 @@ class SearchService:
             query = text(base_sql)
 
         # Prepare parameters
-        params = {"query": fts_query}
+        params = {"query": fts_query, **weight_params}
         params.update(filter_params)
 
         # Execute search
-        result = db.execute(query, params)
-        rows = result.fetchall()
-
-        # Convert to SearchResult objects
+        with trace_span("fts_query"):
+            try:
+                rows = db.execute(query, params).fetchall()
+            except OperationalError as e:
+                # Plain queries are always valid, so only advanced syntax can land here
+                if request.advanced and "fts5" in str(e.orig):
+                    raise SearchQueryError(f"Invalid search query: {e.orig}") from e
+                raise
+
+        # Convert to SearchResult objects, scoring relative to the best match (1.0)
+        best = max((-float(row.rank) for row in rows), default=0.0)
         results = []
         for row in rows:
             memory = Memory()
@@ -110,31 +521,32 @@ class SearchService:
                 if hasattr(memory, key) and key != "rank":
                     setattr(memory, key, value)
 
+            relevance = -float(row.rank)
             results.append(
                 SearchResult(
                     memory=MemoryResponse.model_validate(memory),
-                    score=max(0.1, min(abs(float(row.rank)) / 10.0, 1.0)),  # Normalize FTS5 rank
+                    score=relevance / best if best > 0 else 1.0,
                     search_type="fts5",
                 )
             )
+        results = _rank(results, request)
 
-        # Apply pagination
-        total = len(results)
-        paginated_results = results[request.offset : request.offset + request.limit]
-
-        return paginated_results, total
+        return _paginate(results, request, visible)
 
     async def _search_semantic(
-        self, request: SearchRequest, db: Session
+        self, request: SearchRequest, db: Session, visible: Visibility | None = None
     ) -> tuple[list[SearchResult], int]:
         """Perform semantic search using OpenAI embeddings"""
         if not self.semantic_available:
-            return await self._search_fts5(request, db)
+            return await self._search_fts5(request, db, visible)
 
         try:
             # Generate embedding for query
-            response = openai.embeddings.create(model=settings.openai_model, input=request.query)
-            query_embedding = response.data[0].embedding
+            with trace_span("query_embedding"):
+                query_embedding = await self.embedder.generate_embedding(request.query)
+            if query_embedding is None:
+                logger.warning("Query embedding unavailable, falling back to FTS")
+                return await self._search_fts5(request, db, visible)
 
             # Get memories with embeddings
             query = db.query(Memory).filter(Memory.embedding.isnot(None))
@@ -142,54 +554,61 @@ class SearchService:
             # Apply filters
             query = self._apply_filters(query, request)
 
-            memories = query.all()
+            with trace_span("db_query"):
+                memories = query.all()
 
-            # Calculate similarities
+            # Calculate similarities; chunked memories score as their best chunk
             results = []
-            for memory in memories:
-                if memory.embedding:
-                    memory_embedding = np.frombuffer(memory.embedding, dtype=np.float32)
-                    similarity = self._cosine_similarity(query_embedding, memory_embedding)
-
-                    if similarity > 0.1:  # Minimum similarity threshold
-                        results.append(
-                            SearchResult(
-                                memory=MemoryResponse.model_validate(memory),
-                                score=float(similarity),
-                                search_type="semantic",
-                            )
+            with trace_span("semantic_scoring"):
+                chunk_vectors = load_chunk_vectors(db, [memory.id for memory in memories])
+                for memory in memories:
+                    if memory.embedding:
+                        vectors = chunk_vectors.get(memory.id) or [
+                            np.frombuffer(memory.embedding, dtype=np.float32)
+                        ]
+                        similarity = max(
+                            self._cosine_similarity(query_embedding, vector) for vector in vectors
                         )
 
-            # Sort by similarity
-            results.sort(key=lambda x: x.score, reverse=True)
+                        if similarity > settings.semantic_similarity_threshold:
+                            results.append(
+                                SearchResult(
+                                    memory=MemoryResponse.model_validate(memory),
+                                    score=float(similarity),
+                                    search_type="semantic",
+                                )
+                            )
 
-            # Apply pagination
-            total = len(results)
-            paginated_results = results[request.offset : request.offset + request.limit]
+            # Sort by similarity, preferring verified facts
+            results = _rank(results, request)
 
-            return paginated_results, total
+            return _paginate(results, request, visible)
 
         except Exception as e:
-            print(f"Semantic search failed: {e}")
-            return await self._search_fts5(request, db)
+            logger.warning(f"Semantic search failed, falling back to FTS: {e}")
+            return await self._search_fts5(request, db, visible)
 
     async def _search_hybrid(
-        self, request: SearchRequest, db: Session
+        self, request: SearchRequest, db: Session, visible: Visibility | None = None
     ) -> tuple[list[SearchResult], int]:
         """Perform hybrid search combining FTS5 and semantic search"""
-        # Get results from both search types
-        fts_results, _ = await self._search_fts5(request, db)
-        semantic_results, _ = await self._search_semantic(request, db)
+        # Get results from both search types, each normalized onto the same scale
+        fts_results, _ = await self._search_fts5(request, db, visible)
+        semantic_results, _ = await self._search_semantic(request, db, visible)
+        normalize_scores(fts_results, settings.score_normalization)
+        normalize_scores(semantic_results, settings.score_normalization)
 
         # Combine and re-rank results
         combined_results = {}
+        semantic_weight = settings.hybrid_search_weight
+        fts_weight = 1.0 - semantic_weight
 
         # Add FTS5 results with weight
         for result in fts_results:
             memory_id = result.memory.id
             combined_results[memory_id] = SearchResult(
                 memory=result.memory,
-                score=result.score * 0.3,  # 30% weight for FTS5
+                score=result.score * fts_weight,
                 search_type="hybrid",
             )
 
@@ -198,85 +617,62 @@ class SearchService:
             memory_id = result.memory.id
             if memory_id in combined_results:
                 # Combine scores
-                combined_results[memory_id].score += result.score * 0.7  # 70% weight for semantic
+                combined_results[memory_id].score += result.score * semantic_weight
             else:
                 combined_results[memory_id] = SearchResult(
-                    memory=result.memory, score=result.score * 0.7, search_type="hybrid"
+                    memory=result.memory,
+                    score=result.score * semantic_weight,
+                    search_type="hybrid",
                 )
 
         # Sort by combined score
         results = list(combined_results.values())
         results.sort(key=lambda x: x.score, reverse=True)
 
-        # Apply pagination
-        total = len(results)
-        paginated_results = results[request.offset : request.offset + request.limit]
-
-        return paginated_results, total
+        return _paginate(results, request, visible)
 
     async def _search_like(
-        self, request: SearchRequest, db: Session
+        self, request: SearchRequest, db: Session, visible: Visibility | None = None
     ) -> tuple[list[SearchResult], int]:
-        """Fallback LIKE search when FTS5 is not available"""
+        """Fallback LIKE search when FTS5 is not available
+
+        Matches memories containing any term; like_score ranks those with all terms first.
+        """
         query = db.query(Memory)
 
         # Build LIKE conditions
-        search_terms = request.query.split()
+        search_terms = split_cjk_terms(query_terms(request.query))
         like_conditions = []
 
-        for term in search_terms:
-            like_pattern = f"%{term}%"
+        for term in [form for term in search_terms for form in term.forms]:
+            like_pattern = _like_pattern(term)
             like_conditions.append(
                 or_(
-                    Memory.value.ilike(like_pattern),
-                    Memory.summary.ilike(like_pattern),
-                    Memory.tags.ilike(like_pattern),
+                    Memory.value.ilike(like_pattern, escape="\\"),
+                    Memory.summary.ilike(like_pattern, escape="\\"),
+                    Memory.tags.ilike(like_pattern, escape="\\"),
                 )
             )
 
         if like_conditions:
-            query = query.filter(and_(*like_conditions))
+            query = query.filter(or_(*like_conditions))
 
         # Apply other filters
         query = self._apply_filters(query, request)
 
-        # Get total count
-        total = query.count()
+        with trace_span("db_query"):
+            memories = query.order_by(Memory.updated_at.desc()).all()
 
-        # Apply pagination and ordering
-        memories = (
-            query.order_by(Memory.updated_at.desc())
-            .offset(request.offset)
-            .limit(request.limit)
-            .all()
-        )
-
-        # Convert to SearchResult objects
-        results = []
-        for memory in memories:
-            # Simple relevance scoring based on term frequency
-            score = self._calculate_like_score(memory, search_terms)
-            results.append(
-                SearchResult(
-                    memory=MemoryResponse.model_validate(memory), score=score, search_type="like"
-                )
+        # Rank every match before paginating so later pages are less relevant, not older
+        results = [
+            SearchResult(
+                memory=MemoryResponse.model_validate(memory),
+                score=like_score(memory, search_terms),
+                search_type="like",
             )
-
-        return results, total
-
-    def _build_fts5_query(self, query: str) -> str:
-        """Build FTS5 query string"""
-        # Split query into terms and escape special characters
-        terms = query.split()
-        escaped_terms = []
-
-        for term in terms:
-            # Remove special FTS5 characters and quote terms
-            escaped_term = term.replace('"', "").replace("'", "")
-            if escaped_term:
-                escaped_terms.append(f'"{escaped_term}"')
-
-        return " ".join(escaped_terms)
+            for memory in memories
+        ]
+        return _paginate(_rank(results, request), request, visible)
 
     def _build_fts5_filters(self, request: SearchRequest) -> tuple[str, dict]:
         """Build parameterized WHERE clause filters for FTS5 query"""
@@ -285,13 +681,38 @@ class SearchService:
 
         # Category filtering removed in simplified schema (Issue #112)
 
+        if request.namespace:
+            filters.append("m.namespace = :namespace")
+            params["namespace"] = request.namespace
+
+        if request.owner:
+            filters.append("m.owner = :owner")
+            params["owner"] = request.owner
+
+        if request.source:
+            condition, source_params = _source_filter("m.source", request.source)
+            filters.append(condition)
+            params.update(source_params)
+
+        if not request.include_pending:
+            filters.append("m.review_status = 'approved'")
+
+        if not request.include_archived:
+            filters.append("m.archived_at IS NULL")
+
         if request.tags:
-            tag_conditions = []
-            for i, tag in enumerate(request.tags):
-                param_name = f"tag_{i}"
-                tag_conditions.append(f"m.tags LIKE :{param_name}")
-                params[param_name] = f'%"{tag}"%'
-            filters.append(f"({' OR '.join(tag_conditions)})")
+            condition, tag_params = tags_filter("m.tags", request.tags)
+            filters.append(condition)
+            params.update(tag_params)
+
+        if request.metadata:
+            conditions, metadata_params = _metadata_filter("m.metadata_json", request.metadata)
+            filters.extend(conditions)
+            params.update(metadata_params)
+
+        if request.language:
+            filters.append("m.language = :language")
+            params["language"] = request.language
 
         if request.date_from:
             filters.append("m.created_at >= :date_from")
@@ -304,35 +725,36 @@ class SearchService:
         filter_sql = " AND ".join(filters) if filters else ""
         return filter_sql, params
 
-    def _build_filters(self, request: SearchRequest) -> str:
-        """Build WHERE clause filters for SQL query (legacy method for non-FTS5)"""
-        filters = []
-
+    def _apply_filters(self, query, request: SearchRequest):
+        """Apply filters to SQLAlchemy query"""
         # Category filtering removed in simplified schema (Issue #112)
 
-        if request.tags:
-            tag_conditions = []
-            for tag in request.tags:
-                tag_conditions.append(f"m.tags LIKE '%\"{tag}\"%'")
-            filters.append(f"({' OR '.join(tag_conditions)})")
+        if request.namespace:
+            query = query.filter(Memory.namespace == request.namespace)
 
-        if request.date_from:
-            filters.append(f"m.created_at >= '{request.date_from.isoformat()}'")
+        if request.owner:
+            query = query.filter(Memory.owner == request.owner)
 
-        if request.date_to:
-            filters.append(f"m.created_at <= '{request.date_to.isoformat()}'")
+        if request.source:
+            condition, params = _source_filter("memories.source", request.source)
+            query = query.filter(text(condition).bindparams(**params))
 
-        return " AND ".join(filters) if filters else ""
+        if not request.include_pending:
+            query = query.filter(Memory.review_status == "approved")
 
-    def _apply_filters(self, query, request: SearchRequest):
-        """Apply filters to SQLAlchemy query"""
-        # Category filtering removed in simplified schema (Issue #112)
+        if not request.include_archived:
+            query = query.filter(Memory.archived_at.is_(None))
 
         if request.tags:
-            tag_conditions = []
-            for tag in request.tags:
-                tag_conditions.append(Memory.tags.ilike(f'%"{tag}"%'))
-            query = query.filter(or_(*tag_conditions))
+            condition, params = tags_filter("memories.tags", request.tags)
+            query = query.filter(text(condition).bindparams(**params))
+
+        if request.metadata:
+            conditions, params = _metadata_filter("memories.metadata_json", request.metadata)
+            query = query.filter(text(" AND ".join(conditions)).bindparams(**params))
+
+        if request.language:
+            query = query.filter(Memory.language == request.language)
 
         if request.date_from:
             query = query.filter(Memory.created_at >= request.date_from)
@@ -342,24 +764,11 @@ class SearchService:
 
         return query
 
-    def _cosine_similarity(self, a: list[float], b: np.ndarray) -> float:
+    def _cosine_similarity(self, a: np.ndarray, b: np.ndarray) -> float:
         """Calculate cosine similarity between two vectors"""
         a_array = np.array(a, dtype=np.float32)
         return float(np.dot(a_array, b) / (np.linalg.norm(a_array) * np.linalg.norm(b)))
 
-    def _calculate_like_score(self, memory: Memory, search_terms: list[str]) -> float:
-        """Calculate relevance score for LIKE search"""
-        content = f"{memory.value} {memory.summary or ''} {memory.tags}"
-        content_lower = content.lower()
-
-        score = 0.0
-        for term in search_terms:
-            term_lower = term.lower()
-            count = content_lower.count(term_lower)
-            score += count * 0.1
-
-        return min(score, 1.0)
-
 
 # Global search service instance
 search_service = SearchService()
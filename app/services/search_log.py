@@ -0,0 +1,114 @@
+"""Search query log and analytics
+With MORY_QUERY_LOG enabled every API search is logged with its hit count and result IDs,
+and callers may report which result they actually used. The analytics show the most
+frequent queries and the queries that found nothing, i.e. memories worth adding.
+"""
+
+import json
+from datetime import datetime, timedelta
+from typing import Any
+
+from sqlalchemy import case, func
+from sqlalchemy.orm import Session
+
+from ..models.schemas import SearchResponse
+from ..models.search_log import SearchLog
+
+# Result IDs kept per entry; a choice further down the list is still recorded
+MAX_LOGGED_RESULTS = 20
+
+
+def normalize_query(query: str) -> str:
+    """Form queries are grouped by: lowercased, whitespace collapsed"""
+    return " ".join(query.lower().split())
+
+
+def log_search(
+    db: Session, namespace: str, agent_id: str | None, response: SearchResponse
+) -> SearchLog:
+    """Log a search and commit it; returns the entry"""
+    entry = SearchLog(
+        namespace=namespace,
+        agent_id=agent_id,
+        query=response.query,
+        normalized_query=normalize_query(response.query),
+        search_type=response.search_type,
+        hits=response.total,
+        result_ids=json.dumps(
+            [result.memory.id for result in response.results[:MAX_LOGGED_RESULTS]]
+        ),
+    )
+    db.add(entry)
+    db.commit()
+    return entry
+
+
+def record_choice(db: Session, log_id: int, memory_id: str, namespace: str) -> SearchLog | None:
+    """Remember which result of a logged search was used (None if there is no such entry)"""
+    entry = (
+        db.query(SearchLog).filter(SearchLog.id == log_id, SearchLog.namespace == namespace).first()
+    )
+    if entry is None:
+        return None
+    entry.chosen_id = memory_id
+    entry.chosen_at = datetime.utcnow()
+    db.commit()
+    return entry
+
+
+def search_analytics(
+    db: Session, namespace: str, days: int = 30, limit: int = 10, now: datetime | None = None
+) -> dict[str, Any]:
+    """Totals, most frequent queries and zero-result queries of the last days"""
+    since = (now or datetime.utcnow()) - timedelta(days=days)
+    scope = (SearchLog.namespace == namespace, SearchLog.created_at >= since)
+    searches = func.count(SearchLog.id)
+    chosen = func.sum(case((SearchLog.chosen_id.isnot(None), 1), else_=0))
+    last = func.max(SearchLog.created_at)
+
+    total, zero, with_choice = (
+        db.query(searches, func.sum(case((SearchLog.hits == 0, 1), else_=0)), chosen)
+        .filter(*scope)
+        .one()
+    )
+    top = (
+        db.query(SearchLog.normalized_query, searches, func.avg(SearchLog.hits), chosen, last)
+        .filter(*scope)
+        .group_by(SearchLog.normalized_query)
+        .order_by(searches.desc(), last.desc())
+        .limit(limit)
+        .all()
+    )
+    missing = (
+        db.query(SearchLog.normalized_query, searches, last)
+        .filter(*scope, SearchLog.hits == 0)
+        .group_by(SearchLog.normalized_query)
+        .order_by(searches.desc(), last.desc())
+        .limit(limit)
+        .all()
+    )
+    return {
+        "namespace": namespace,
+        "days": days,
+        "searches": total or 0,
+        "zero_result_searches": int(zero or 0),
+        "searches_with_choice": int(with_choice or 0),
+        "top_queries": [
+            {
+                "query": query,
+                "searches": count,
+                "average_hits": round(float(hits or 0), 1),
+                "chosen": int(picked or 0),
+                "last_searched_at": latest.isoformat() if latest else None,
+            }
+            for query, count, hits, picked, latest in top
+        ],
+        "zero_result_queries": [
+            {
+                "query": query,
+                "searches": count,
+                "last_searched_at": latest.isoformat() if latest else None,
+            }
+            for query, count, latest in missing
+        ],
+    }
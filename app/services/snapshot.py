@@ -0,0 +1,190 @@
+"""Snapshots of the complete data directory
+A snapshot is one tar.gz holding a consistent copy of memories.db (embeddings included),
+every other file in the data directory and a manifest.json with checksums. Restoring
+only writes into an empty data directory, so it can never clobber live data.
+"""
+
+import hashlib
+import io
+import json
+import os
+import shutil
+import sqlite3
+import tarfile
+import tempfile
+from datetime import UTC, datetime
+from pathlib import Path
+from typing import Any
+
+from .. import __version__
+from ..core.instance_lock import LOCK_FILENAME
+
+MANIFEST_NAME = "manifest.json"
+DATABASE_NAME = "memories.db"
+SNAPSHOT_FORMAT = 1
+
+# Not copied from the data directory: the database is added through SQLite's backup API,
+# its journal files are folded into that copy, and backups/snapshots would nest
+_SKIPPED = {
+    DATABASE_NAME,
+    f"{DATABASE_NAME}-wal",
+    f"{DATABASE_NAME}-shm",
+    f"{DATABASE_NAME}-journal",
+    LOCK_FILENAME,
+}
+_SKIPPED_DIRS = {"backups", "snapshots"}
+
+
+class SnapshotError(Exception):
+    """Raised when a snapshot cannot be created or restored"""
+
+
+def _sha256(path: Path) -> str:
+    digest = hashlib.sha256()
+    with path.open("rb") as handle:
+        for chunk in iter(lambda: handle.read(1 << 20), b""):
+            digest.update(chunk)
+    return digest.hexdigest()
+
+
+def _data_files(data_dir: Path, exclude: Path) -> list[Path]:
+    files = []
+    for path in sorted(data_dir.rglob("*")):
+        relative = path.relative_to(data_dir)
+        if relative.parts[0] in _SKIPPED_DIRS or relative.as_posix() in _SKIPPED:
+            continue
+        if path.is_file() and not path.is_symlink() and path.resolve() != exclude:
+            files.append(path)
+    return files
+
+
+def _database_counts(db_path: Path) -> dict[str, int]:
+    connection = sqlite3.connect(db_path)
+    try:
+        memories, embeddings = connection.execute(
+            "SELECT COUNT(*), COUNT(embedding) FROM memories"
+        ).fetchone()
+    except sqlite3.DatabaseError:
+        memories, embeddings = 0, 0
+    finally:
+        connection.close()
+    return {"memories": memories, "embeddings": embeddings}
+
+
+def default_snapshot_path(data_dir: str | Path) -> Path:
+    stamp = datetime.now().strftime("%Y%m%d_%H%M%S")
+    return Path(data_dir) / "snapshots" / f"mory_snapshot_{stamp}.tar.gz"
+
+
+def create_snapshot(
+    data_dir: str | Path, db_path: str | Path | None, output: str | Path
+) -> dict[str, Any]:
+    """Write the data directory to a tar.gz at output; returns the manifest"""
+    data_dir = Path(data_dir)
+    output = Path(output)
+    output.parent.mkdir(parents=True, exist_ok=True)
+
+    with tempfile.TemporaryDirectory() as staging:
+        entries: list[tuple[str, Path]] = []
+        counts: dict[str, int] = {}
+        if db_path is not None and Path(db_path).exists():
+            copy = Path(staging) / DATABASE_NAME
+            source = sqlite3.connect(db_path)
+            destination = sqlite3.connect(copy)
+            try:
+                source.backup(destination)
+            finally:
+                destination.close()
+                source.close()
+            entries.append((DATABASE_NAME, copy))
+            counts = _database_counts(copy)
+        if data_dir.exists():
+            entries.extend(
+                (path.relative_to(data_dir).as_posix(), path)
+                for path in _data_files(data_dir, output.resolve())
+            )
+        if not entries:
+            raise SnapshotError(f"Nothing to snapshot in {data_dir}")
+
+        manifest = {
+            "format": SNAPSHOT_FORMAT,
+            "mory_version": __version__,
+            "created_at": datetime.now(UTC).isoformat(),
+            **counts,
+            "files": [
+                {"path": name, "size": path.stat().st_size, "sha256": _sha256(path)}
+                for name, path in entries
+            ],
+        }
+
+        partial = output.with_name(f".{output.name}.tmp")
+        try:
+            with tarfile.open(partial, "w:gz") as archive:
+                body = json.dumps(manifest, indent=2).encode()
+                info = tarfile.TarInfo(MANIFEST_NAME)
+                info.size = len(body)
+                info.mtime = int(datetime.now().timestamp())
+                archive.addfile(info, io.BytesIO(body))
+                for name, path in entries:
+                    archive.add(path, arcname=name, recursive=False)
+            os.replace(partial, output)
+        except BaseException:
+            partial.unlink(missing_ok=True)
+            raise
+    return manifest
+
+
+def read_manifest(archive_path: str | Path) -> dict[str, Any]:
+    """Manifest of a snapshot archive"""
+    try:
+        with tarfile.open(archive_path, "r:gz") as archive:
+            member = archive.extractfile(MANIFEST_NAME)
+            if member is None:
+                raise KeyError(MANIFEST_NAME)
+            manifest = json.load(member)
+    except (OSError, KeyError, tarfile.TarError, json.JSONDecodeError) as e:
+        raise SnapshotError(f"{archive_path} is not a Mory snapshot: {e}") from e
+    if manifest.get("format") != SNAPSHOT_FORMAT:
+        raise SnapshotError(f"Unsupported snapshot format {manifest.get('format')!r}")
+    return manifest
+
+
+def restore_snapshot(archive_path: str | Path, data_dir: str | Path) -> dict[str, Any]:
+    """Extract a snapshot into an empty (or missing) data directory; returns the manifest
+
+    Every file is checked against the manifest before anything appears in data_dir.
+    """
+    data_dir = Path(data_dir)
+    if data_dir.exists() and any(data_dir.iterdir()):
+        raise SnapshotError(f"{data_dir} is not empty; restore only into an empty data directory")
+
+    manifest = read_manifest(archive_path)
+    expected = {entry["path"]: entry for entry in manifest["files"]}
+    data_dir.parent.mkdir(parents=True, exist_ok=True)
+    staging = Path(tempfile.mkdtemp(dir=data_dir.parent, prefix=f".{data_dir.name}.restore-"))
+    try:
+        with tarfile.open(archive_path, "r:gz") as archive:
+            for member in archive.getmembers():
+                if member.name == MANIFEST_NAME:
+                    continue
+                entry = expected.get(member.name)
+                target = (staging / member.name).resolve()
+                inside = staging.resolve() in target.parents
+                if entry is None or not member.isfile() or not inside:
+                    raise SnapshotError(f"Unexpected entry in snapshot: {member.name}")
+                target.parent.mkdir(parents=True, exist_ok=True)
+                with archive.extractfile(member) as source, target.open("wb") as destination:
+                    shutil.copyfileobj(source, destination)
+                if _sha256(target) != entry["sha256"]:
+                    raise SnapshotError(f"Checksum mismatch for {member.name}")
+                del expected[member.name]
+        if expected:
+            raise SnapshotError(f"Snapshot is missing {', '.join(sorted(expected))}")
+
+        if data_dir.exists():
+            data_dir.rmdir()
+        os.replace(staging, data_dir)
+    except BaseException:
+        shutil.rmtree(staging, ignore_errors=True)
+        raise
+    return manifest
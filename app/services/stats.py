@@ -0,0 +1,162 @@
+"""Daily statistics time series
+Embeddings are counted into today's daily_stats row as they happen. Searches are counted
+in memory and written in one go when statistics are read or recorded (and on shutdown), so
+a search never waits on a database write. The daily_stats job adds the day's totals
+(memories, embeddings, per-category counts) so growth can be charted rather than only shown
+as a point-in-time snapshot.
+"""
+
+import json
+import logging
+import threading
+from collections import Counter
+from datetime import datetime, timedelta
+
+from sqlalchemy import text
+from sqlalchemy.exc import OperationalError
+from sqlalchemy.orm import Session
+
+from ..models.daily_stats import DailyStats
+from ..models.memory import Memory
+from .counts import dashboard_counts
+from .file_store import DEFAULT_CATEGORY
+
+logger = logging.getLogger(__name__)
+
+_UPSERT = """
+    INSERT INTO daily_stats (
+        day, namespace, searches, embeddings_generated, updated_at
+    )
+    VALUES (:day, :namespace, :searches, :embeddings_generated, :now)
+    ON CONFLICT (day, namespace) DO UPDATE SET
+        searches = searches + excluded.searches,
+        embeddings_generated = embeddings_generated + excluded.embeddings_generated,
+        updated_at = excluded.updated_at
+"""
+
+
+def today(now: datetime | None = None) -> str:
+    return (now or datetime.utcnow()).strftime("%Y-%m-%d")
+
+
+def count_activity(
+    db: Session,
+    namespace: str,
+    searches: int = 0,
+    embeddings_generated: int = 0,
+    day: str | None = None,
+) -> None:
+    """Add to a day's activity counters, today's by default (committed with the caller's
+    transaction)"""
+    db.execute(
+        text(_UPSERT),
+        {
+            "day": day or today(),
+            "namespace": namespace,
+            "searches": searches,
+            "embeddings_generated": embeddings_generated,
+            "now": datetime.utcnow().isoformat(sep=" "),
+        },
+    )
+
+
+class SearchCounter:
+    """Searches per (day, namespace), kept in memory until flushed to daily_stats"""
+
+    def __init__(self) -> None:
+        self._lock = threading.Lock()
+        self._pending: Counter[tuple[str, str]] = Counter()
+
+    def add(self, namespace: str) -> None:
+        with self._lock:
+            self._pending[(today(), namespace)] += 1
+
+    def flush(self, db: Session) -> int:
+        """Write the pending counts; a busy database keeps them for the next flush"""
+        with self._lock:
+            pending = self._pending
+            self._pending = Counter()
+        if not pending:
+            return 0
+        try:
+            for (day, namespace), searches in pending.items():
+                count_activity(db, namespace, searches=searches, day=day)
+            db.commit()
+        except OperationalError as e:
+            db.rollback()
+            with self._lock:
+                self._pending.update(pending)
+            logger.debug(f"Search counts not written yet: {e}")
+            return 0
+        return sum(pending.values())
+
+    def reset(self) -> None:
+        with self._lock:
+            self._pending.clear()
+
+
+search_counter = SearchCounter()
+
+
+def count_search(namespace: str) -> None:
+    """Count a search (in memory; written by the next flush)"""
+    search_counter.add(namespace)
+
+
+def _category_counts(db: Session) -> dict[str, dict[str, int]]:
+    """namespace -> {category: number of memories}; the category is the first tag"""
+    rows = db.execute(
+        text(
+            """
+            SELECT namespace, COALESCE(
+                CASE WHEN json_valid(tags) THEN json_extract(tags, '$[0]') END, :default
+            ) AS category, COUNT(*) AS uses
+            FROM memories
+            GROUP BY namespace, category
+            """
+        ),
+        {"default": DEFAULT_CATEGORY},
+    ).all()
+    counts: dict[str, dict[str, int]] = {}
+    for row in rows:
+        counts.setdefault(row.namespace, {})[row.category] = row.uses
+    return counts
+
+
+def record_daily_stats(db: Session, now: datetime | None = None) -> list[DailyStats]:
+    """Store each namespace's current totals in today's row (updated on every run)"""
+    search_counter.flush(db)
+    day = today(now)
+    categories = _category_counts(db)
+    namespaces = {namespace for (namespace,) in db.query(Memory.namespace).distinct()}
+    rows = {row.namespace: row for row in db.query(DailyStats).filter(DailyStats.day == day).all()}
+
+    for namespace in sorted(namespaces | set(rows)):
+        counts = dashboard_counts(db, namespace)
+        row = rows.get(namespace)
+        if row is None:
+            row = rows[namespace] = DailyStats(
+                day=day, namespace=namespace, searches=0, embeddings_generated=0
+            )
+            db.add(row)
+        row.total_memories = counts["total_memories"]
+        row.memories_with_embeddings = counts["memories_with_embeddings"]
+        row.categories = json.dumps(categories.get(namespace, {}), ensure_ascii=False)
+        row.updated_at = now or datetime.utcnow()
+    db.commit()
+    return [rows[namespace] for namespace in sorted(rows)]
+
+
+def stats_history(
+    db: Session, namespace: str, days: int = 30, now: datetime | None = None
+) -> list[DailyStats]:
+    """Rows for the last days, oldest first"""
+    search_counter.flush(db)
+    first = today((now or datetime.utcnow()) - timedelta(days=days - 1))
+    return (
+        db.query(DailyStats)
+        .filter(DailyStats.namespace == namespace, DailyStats.day >= first)
+        .order_by(DailyStats.day)
+        .all()
+    )
+
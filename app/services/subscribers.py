@@ -0,0 +1,106 @@
+"""Default event bus subscribers
+Embeddings, automatic tags, markdown files, the operation log, webhooks and the git mirror
+react to memory events here rather than being called from each place that changes a memory.
+"""
+
+from datetime import datetime
+
+from sqlalchemy.orm import Session
+from sqlalchemy.orm.attributes import flag_modified
+
+from ..core.config import settings
+from ..core.events import (
+    EVENT_TYPES,
+    MEMORY_DELETED,
+    MEMORY_IMPORTED,
+    MEMORY_SAVED,
+    MEMORY_UPDATED,
+    EventBus,
+    MemoryEvent,
+    event_bus,
+)
+from ..models.memory import Memory
+from .auto_tag import auto_tag
+from .embedding import embedding_service
+from .file_store import store_event
+from .git_store import mirror_event
+from .operation_log import memory_snapshot, record_operation
+from .stats import count_activity
+from .webhooks import webhook_dispatcher
+
+
+def content_changed(event: MemoryEvent) -> bool:
+    """Whether an update touched the text the embedding is made from (or there is none)"""
+    memory = event.memory
+    if not memory.has_embedding:
+        return True
+    if event.operation:
+        # Archiving, approval, verification and the like leave the content alone
+        return False
+    before = event.details.get("before") or {}
+    return before.get("value") != memory.value or before.get("summary") != memory.summary
+
+
+def _commit_derived(session: Session, memory: Memory, updated_at: datetime) -> None:
+    """Commit data derived from the content (embedding, automatic tags) without counting it
+    as an edit: updated_at is written back as it was, so onupdate does not fire and a synced
+    memory keeps the peer's timestamp"""
+    memory.updated_at = updated_at
+    flag_modified(memory, "updated_at")
+    session.commit()
+    session.refresh(memory)
+
+
+async def embed_memory(event: MemoryEvent) -> None:
+    """Generate the embedding for new or changed content"""
+    if not embedding_service.enabled or event.session is None:
+        return
+    if event.type == MEMORY_UPDATED and not content_changed(event):
+        return
+    updated_at = event.memory.updated_at
+    if await embedding_service.generate_embedding_for_memory(event.memory):
+        count_activity(event.session, event.memory.namespace, embeddings_generated=1)
+        _commit_derived(event.session, event.memory, updated_at)
+
+
+def auto_tag_memory(event: MemoryEvent) -> None:
+    """Tag memories saved without tags when MORY_AUTO_TAG is on"""
+    if not settings.auto_tag or event.session is None:
+        return
+    updated_at = event.memory.updated_at
+    if auto_tag(event.session, event.memory):
+        _commit_derived(event.session, event.memory, updated_at)
+
+
+def log_operation(event: MemoryEvent) -> None:
+    """Record the change in the operation log, with the content it left behind"""
+    if event.session is None:
+        return
+    key = "before" if event.type == MEMORY_DELETED else "after"
+    record_operation(
+        event.session,
+        event.operation or event.type,
+        memory_id=event.memory.id,
+        agent_id=event.agent_id,
+        details={**event.details, key: memory_snapshot(event.memory)},
+    )
+    event.session.commit()
+
+
+def notify_webhooks(event: MemoryEvent) -> None:
+    webhook_dispatcher.dispatch(event.type, event.memory)
+
+
+def register_subscribers(bus: EventBus = event_bus) -> None:
+    """Subscribe the built-in handlers (safe to call more than once)"""
+    # Embedding first so later subscribers see has_embedding
+    for event_type in (MEMORY_SAVED, MEMORY_UPDATED, MEMORY_IMPORTED):
+        bus.subscribe(event_type, embed_memory)
+    # Auto tags use the embedding and must be in place for the file and log snapshots
+    for event_type in (MEMORY_SAVED, MEMORY_IMPORTED):
+        bus.subscribe(event_type, auto_tag_memory)
+    for event_type in EVENT_TYPES:
+        bus.subscribe(event_type, store_event)
+        bus.subscribe(event_type, log_operation)
+        bus.subscribe(event_type, notify_webhooks)
+        bus.subscribe(event_type, mirror_event)
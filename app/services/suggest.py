@@ -0,0 +1,116 @@
+"""Completions for half-remembered memories
+Tags and memory summaries starting with a prefix, for clients offering autocomplete.
+"""
+
+from collections import Counter
+from collections.abc import Callable
+from typing import Any
+
+from sqlalchemy import func, text
+from sqlalchemy.orm import Session
+
+from ..models.memory import Memory
+
+# Sorts after any character a summary can continue with, closing the prefix range
+_PREFIX_END = "\U0010ffff"
+
+
+def _like_prefix(prefix: str) -> str:
+    escaped = prefix.replace("\\", "\\\\").replace("%", "\\%").replace("_", "\\_")
+    return f"{escaped}%"
+
+
+Visibility = Callable[[Memory], bool]
+
+
+def suggest_tags(
+    db: Session,
+    prefix: str,
+    namespace: str | None = None,
+    limit: int = 10,
+    visible: Visibility | None = None,
+) -> list[tuple[str, int]]:
+    """Tags starting with the prefix (case-insensitive), most used first
+
+    With a visible predicate only memories it accepts are counted, which means loading them.
+    """
+    if visible is not None:
+        return _count_visible_tags(db, prefix, namespace, limit, visible)
+    rows = db.execute(
+        text("""
+            SELECT tag.value AS tag, COUNT(*) AS uses
+            FROM memories m,
+                json_each(CASE WHEN json_valid(m.tags) THEN m.tags ELSE '[]' END) tag
+            WHERE (:namespace IS NULL OR m.namespace = :namespace)
+              AND m.archived_at IS NULL
+              AND m.review_status = 'approved'
+              AND tag.value LIKE :pattern ESCAPE '\\'
+            GROUP BY tag.value
+            ORDER BY uses DESC, tag.value
+            LIMIT :limit
+        """),
+        {"namespace": namespace, "pattern": _like_prefix(prefix), "limit": limit},
+    ).all()
+    return [(row.tag, row.uses) for row in rows]
+
+
+def _count_visible_tags(
+    db: Session, prefix: str, namespace: str | None, limit: int, visible: Visibility
+) -> list[tuple[str, int]]:
+    query = db.query(Memory).filter(
+        Memory.archived_at.is_(None), Memory.review_status == "approved"
+    )
+    if namespace is not None:
+        query = query.filter(Memory.namespace == namespace)
+    start = prefix.lower()
+    uses: Counter[str] = Counter()
+    for memory in query.all():
+        if visible(memory):
+            uses.update(tag for tag in memory.tags_list if tag.lower().startswith(start))
+    return sorted(uses.items(), key=lambda item: (-item[1], item[0]))[:limit]
+
+
+def suggest_memories(
+    db: Session,
+    prefix: str,
+    namespace: str | None = None,
+    limit: int = 10,
+    visible: Visibility | None = None,
+) -> list[Memory]:
+    """Memories whose summary starts with the prefix, most recently updated first"""
+    start = prefix.lower()
+    summary = func.lower(Memory.summary)
+    # A range on lower(summary) uses idx_summary_lower, unlike LIKE 'prefix%'
+    query = db.query(Memory).filter(
+        summary >= start,
+        summary < start + _PREFIX_END,
+        Memory.archived_at.is_(None),
+        Memory.review_status == "approved",
+    )
+    if namespace is not None:
+        query = query.filter(Memory.namespace == namespace)
+    query = query.order_by(Memory.updated_at.desc())
+    if visible is None:
+        return query.limit(limit).all()
+    return [memory for memory in query.all() if visible(memory)][:limit]
+
+
+def suggest(
+    db: Session,
+    prefix: str,
+    namespace: str | None = None,
+    limit: int = 10,
+    visible: Visibility | None = None,
+) -> dict[str, Any]:
+    """Tag and memory completions for a prefix"""
+    return {
+        "prefix": prefix,
+        "tags": [
+            {"tag": tag, "count": uses}
+            for tag, uses in suggest_tags(db, prefix, namespace, limit, visible)
+        ],
+        "memories": [
+            {"id": memory.id, "summary": memory.summary}
+            for memory in suggest_memories(db, prefix, namespace, limit, visible)
+        ],
+    }
@@ -6,6 +6,8 @@ from typing import Any
 import openai
 
 from ..core.config import settings
+from ..core.metrics import metrics
+from ..core.tracing import trace_span
 from ..models.memory import Memory
 
 
@@ -57,7 +59,13 @@ class SummarizationService:
             prompt = self._create_prompt(text, max_len, language)
 
             # Call OpenAI API
-            response = await self._call_openai_api(prompt)
+            with trace_span("summarization"):
+                try:
+                    response = await self._call_openai_api(prompt)
+                except Exception:
+                    metrics.inc("mory_summary_api_calls_total", {"outcome": "error"})
+                    raise
+            metrics.inc("mory_summary_api_calls_total", {"outcome": "success"})
 
             # Extract and validate summary
             summary = self._extract_summary(response, max_len)
@@ -70,6 +78,36 @@ class SummarizationService:
             else:
                 raise Exception(f"Summary generation failed: {str(e)}") from e
 
+    async def condense(self, texts: list[str], language: str = "ja") -> str:
+        """Condense many related memories into a single summary note
+
+        Falls back to a bullet list of the inputs when the API is unavailable.
+        """
+        fallback = "\n".join(f"- {text.splitlines()[0][:200]}" for text in texts if text)
+        if not self.enabled or not settings.openai_api_key:
+            return fallback
+
+        items = "\n".join(f"- {text}" for text in texts)
+        instructions = {
+            "ja": "以下のメモを重複を除いて一つのノートにまとめてください。"
+            "重要な事実・決定事項は残し、要約内容のみを返してください。",
+            "en": "Merge the following notes into a single note without duplicates. "
+            "Keep important facts and decisions and return only the merged note.",
+        }
+        prompt = f"{instructions.get(language, instructions['ja'])}\n\n{items}"
+
+        try:
+            self.call_count += 1
+            with trace_span("summarization"):
+                response = await self._call_openai_api(prompt, max_tokens=1000)
+            metrics.inc("mory_summary_api_calls_total", {"outcome": "success"})
+            return response or fallback
+        except Exception as e:
+            metrics.inc("mory_summary_api_calls_total", {"outcome": "error"})
+            if self.fallback_enabled:
+                return fallback
+            raise Exception(f"Condensing memories failed: {str(e)}") from e
+
     async def should_regenerate_summary(self, memory: Memory) -> bool:
         """
         Check if summary should be regenerated
@@ -146,7 +184,7 @@ Summary:""",
 
         return prompts.get(language, prompts["ja"])
 
-    async def _call_openai_api(self, prompt: str) -> str:
+    async def _call_openai_api(self, prompt: str, max_tokens: int = 100) -> str:
         """Call OpenAI Chat Completion API"""
         try:
             # Use the new OpenAI client API
@@ -155,7 +193,7 @@ Summary:""",
             response = await client.chat.completions.create(
                 model=self.model,
                 messages=[{"role": "user", "content": prompt}],
-                max_tokens=100,  # Limit response tokens for summaries
+                max_tokens=max_tokens,  # Limit response tokens for summaries
                 temperature=0.3,  # Lower temperature for consistent summaries
             )
 
@@ -0,0 +1,84 @@
+"""Spaced-repetition surfacing
+Picks memories the user has not seen for a while so they can be reviewed. Each read
+doubles the interval before a memory is due again, like a spaced-repetition deck.
+"""
+
+import random
+from collections.abc import Callable
+from datetime import datetime, timedelta
+
+from sqlalchemy import func
+from sqlalchemy.orm import Session
+
+from ..models.memory import Memory
+from .counts import has_tag
+
+# Days before a never-read memory is due; each read doubles it
+BASE_INTERVAL_DAYS = 1.0
+MAX_INTERVAL_DOUBLINGS = 10
+
+
+def last_seen(memory: Memory) -> datetime:
+    return memory.last_accessed_at or memory.updated_at or memory.created_at
+
+
+def review_weight(memory: Memory, now: datetime | None = None) -> float:
+    """How overdue a memory is: days unseen divided by its current review interval"""
+    now = now or datetime.utcnow()
+    idle_days = max((now - last_seen(memory)).total_seconds() / 86400, 0.0)
+    doublings = min(memory.access_count or 0, MAX_INTERVAL_DOUBLINGS)
+    return idle_days / (BASE_INTERVAL_DAYS * 2**doublings)
+
+
+def pick_memories_to_surface(
+    db: Session,
+    namespace: str,
+    count: int = 3,
+    min_idle_days: float = 1.0,
+    tag: str | None = None,
+    rng: random.Random | None = None,
+    visible: Callable[[Memory], bool] | None = None,
+) -> list[Memory]:
+    """Weighted random sample of memories not seen for min_idle_days, most overdue likeliest
+
+    Memories the visible predicate rejects are never picked.
+    """
+    now = datetime.utcnow()
+    cutoff = now - timedelta(days=min_idle_days)
+    query = db.query(Memory).filter(
+        Memory.namespace == namespace,
+        Memory.review_status == "approved",
+        Memory.archived_at.is_(None),
+        func.coalesce(Memory.last_accessed_at, Memory.updated_at) < cutoff,
+    )
+    if tag:
+        query = query.filter(has_tag(tag))
+
+    rng = rng or random.Random()
+    # Efraimidis-Spirakis: the top keys u^(1/w) form a weighted sample without replacement
+    keyed = []
+    for memory in query.all():
+        if visible is not None and not visible(memory):
+            continue
+        weight = review_weight(memory, now)
+        if weight > 0:
+            keyed.append((rng.random() ** (1.0 / weight), memory))
+    keyed.sort(key=lambda item: item[0], reverse=True)
+    return [memory for _, memory in keyed[:count]]
+
+
+def mark_surfaced(db: Session, memories: list[Memory]) -> None:
+    """Count surfacing as a read so the memory's next review moves further out"""
+    now = datetime.utcnow()
+    for memory in memories:
+        db.query(Memory).filter(Memory.id == memory.id).update(
+            {
+                Memory.access_count: func.coalesce(Memory.access_count, 0) + 1,
+                Memory.last_accessed_at: now,
+                Memory.updated_at: Memory.updated_at,
+            },
+            synchronize_session=False,
+        )
+    db.commit()
+    for memory in memories:
+        db.refresh(memory)
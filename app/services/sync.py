@@ -0,0 +1,257 @@
+"""Bi-directional sync between two Mory instances
+Each side exports memories changed since the last sync plus deletions from the
+operation log; the other side applies them with last-writer-wins on updated_at.
+Conflicts (both sides changed) are resolved by timestamp and logged.
+"""
+
+import json
+import logging
+from dataclasses import asdict, dataclass, field
+from datetime import datetime, timedelta
+from pathlib import Path
+from typing import Any
+
+import httpx
+from sqlalchemy.orm import Session
+
+from ..core.config import settings
+from ..core.events import MEMORY_DELETED, MEMORY_IMPORTED, MemoryEvent, event_bus
+from ..core.fileutil import atomic_write_text
+from ..models.memory import Memory
+from ..models.operation_log import OperationLog
+from .operation_log import record_operation
+
+logger = logging.getLogger(__name__)
+
+TOKEN_HEADER = "X-Mory-Sync-Token"
+
+# Operation log entries that remove a memory
+DELETE_OPERATIONS = ("deleted", "rejected", "purged")
+
+# Re-exchange changes from shortly before the last sync to tolerate clock skew;
+# applying a change twice is a no-op
+OVERLAP = timedelta(minutes=5)
+
+_PLAIN_FIELDS = (
+    "namespace",
+    "owner",
+    "source",
+    "review_status",
+    "value",
+    "summary",
+    "confidence",
+    "verified",
+    "template",
+    "content_type",
+)
+_DATETIME_FIELDS = (
+    "created_at",
+    "updated_at",
+    "ai_processed_at",
+    "archived_at",
+    "remind_at",
+    "verified_at",
+)
+
+
+def _iso(value: datetime | None) -> str | None:
+    return value.isoformat() if value else None
+
+
+def _parse(value: str | None) -> datetime | None:
+    return datetime.fromisoformat(value) if value else None
+
+
+def memory_record(memory: Memory) -> dict[str, Any]:
+    """Persistent fields of a memory (embeddings are regenerated on the receiving side)"""
+    record: dict[str, Any] = {"id": memory.id}
+    record.update({name: getattr(memory, name) for name in _PLAIN_FIELDS})
+    record.update({name: _iso(getattr(memory, name)) for name in _DATETIME_FIELDS})
+    record["tags"] = memory.tags_list
+    record["metadata"] = memory.metadata_dict
+    return record
+
+
+def _apply_record(memory: Memory, record: dict[str, Any]) -> None:
+    for name in _PLAIN_FIELDS:
+        if name in record:
+            setattr(memory, name, record[name])
+    for name in _DATETIME_FIELDS:
+        if name in record:
+            setattr(memory, name, _parse(record[name]))
+    memory.tags_list = record.get("tags", [])
+    memory.metadata_dict = record.get("metadata", {})
+
+
+def export_changes(db: Session, since: datetime | None) -> dict[str, Any]:
+    """Memories updated and deleted since the given time (everything when None)"""
+    memories = db.query(Memory)
+    deletions = db.query(OperationLog).filter(OperationLog.operation.in_(DELETE_OPERATIONS))
+    if since is not None:
+        memories = memories.filter(Memory.updated_at > since)
+        deletions = deletions.filter(OperationLog.created_at > since)
+
+    return {
+        "server_time": datetime.utcnow().isoformat(),
+        "memories": [memory_record(memory) for memory in memories.all()],
+        "deleted": [
+            {"id": entry.memory_id, "deleted_at": entry.created_at.isoformat()}
+            for entry in deletions.all()
+            if entry.memory_id
+        ],
+    }
+
+
+@dataclass
+class SyncResult:
+    """Outcome of applying one side's changes"""
+
+    created: int = 0
+    updated: int = 0
+    deleted: int = 0
+    skipped: int = 0
+    conflicts: list[str] = field(default_factory=list)
+
+    def to_dict(self) -> dict[str, Any]:
+        return asdict(self)
+
+
+def _deleted_at(db: Session, memory_id: str) -> datetime | None:
+    entry = (
+        db.query(OperationLog)
+        .filter(
+            OperationLog.memory_id == memory_id,
+            OperationLog.operation.in_(DELETE_OPERATIONS),
+        )
+        .order_by(OperationLog.created_at.desc())
+        .first()
+    )
+    return entry.created_at if entry else None
+
+
+def _log_conflict(db: Session, memory_id: str, winner: str, peer: str | None) -> None:
+    logger.warning(f"Sync conflict on {memory_id}: kept {winner} version")
+    record_operation(
+        db, "sync_conflict", memory_id=memory_id, details={"winner": winner, "peer": peer}
+    )
+
+
+async def apply_changes(
+    db: Session, changes: dict[str, Any], since: datetime | None, peer: str | None = None
+) -> SyncResult:
+    """Apply a peer's exported changes, newest updated_at winning"""
+    result = SyncResult()
+    events: list[MemoryEvent] = []
+    details = {"sync": peer}
+
+    for record in changes.get("memories", []):
+        remote_updated = _parse(record["updated_at"])
+        memory = db.query(Memory).filter(Memory.id == record["id"]).first()
+
+        if memory is None:
+            deleted_at = _deleted_at(db, record["id"])
+            if deleted_at and deleted_at >= remote_updated:
+                result.skipped += 1  # Deleted here after the peer's last edit
+                continue
+            memory = Memory(id=record["id"])
+            _apply_record(memory, record)
+            db.add(memory)
+            result.created += 1
+            events.append(MemoryEvent(MEMORY_IMPORTED, memory, session=db, details=details))
+            continue
+
+        if memory.updated_at >= remote_updated:
+            if memory.updated_at > remote_updated and since and remote_updated > since:
+                result.conflicts.append(memory.id)
+                _log_conflict(db, memory.id, "local", peer)
+            else:
+                result.skipped += 1
+            continue
+
+        if since and memory.updated_at > since:
+            result.conflicts.append(memory.id)
+            _log_conflict(db, memory.id, "remote", peer)
+        _apply_record(memory, record)
+        result.updated += 1
+        events.append(MemoryEvent(MEMORY_IMPORTED, memory, session=db, details=details))
+
+    for deletion in changes.get("deleted", []):
+        memory = db.query(Memory).filter(Memory.id == deletion["id"]).first()
+        if memory is None:
+            continue
+        if memory.updated_at > _parse(deletion["deleted_at"]):
+            # Edited here after the peer deleted it: keep the edit
+            result.conflicts.append(memory.id)
+            _log_conflict(db, memory.id, "local", peer)
+            continue
+        db.delete(memory)
+        result.deleted += 1
+        events.append(MemoryEvent(MEMORY_DELETED, memory, session=db, details=details))
+
+    db.commit()
+    for event in events:
+        await event_bus.publish(event)
+    return result
+
+
+class SyncClient:
+    """Syncs the local database with a peer server over HTTP"""
+
+    def __init__(
+        self,
+        peer_url: str,
+        token: str | None = None,
+        state_path: Path | None = None,
+        transport: httpx.AsyncBaseTransport | None = None,
+    ):
+        self.peer_url = peer_url.rstrip("/")
+        self.token = token
+        self.state_path = state_path or Path(settings.data_dir) / "sync_state.json"
+        self.transport = transport
+
+    def _load_state(self) -> dict[str, str]:
+        if not self.state_path.exists():
+            return {}
+        try:
+            return json.loads(self.state_path.read_text(encoding="utf-8"))
+        except (OSError, json.JSONDecodeError):
+            return {}
+
+    def last_sync(self) -> datetime | None:
+        return _parse(self._load_state().get(self.peer_url))
+
+    def _save_last_sync(self, value: datetime) -> None:
+        state = self._load_state()
+        state[self.peer_url] = value.isoformat()
+        atomic_write_text(self.state_path, json.dumps(state, indent=2))
+
+    async def sync(self, db: Session) -> dict[str, Any]:
+        """Pull the peer's changes, push ours, then remember the sync time"""
+        started = datetime.utcnow()
+        last = self.last_sync()
+        since = last - OVERLAP if last else None
+        headers = {TOKEN_HEADER: self.token} if self.token else {}
+
+        async with httpx.AsyncClient(
+            base_url=self.peer_url, headers=headers, timeout=60.0, transport=self.transport
+        ) as client:
+            params = {"since": since.isoformat()} if since else {}
+            response = await client.get("/api/sync/changes", params=params)
+            response.raise_for_status()
+            pulled = await apply_changes(db, response.json(), since, peer=self.peer_url)
+
+            local = export_changes(db, since)
+            response = await client.post(
+                "/api/sync/apply",
+                json={
+                    "changes": local,
+                    "since": since.isoformat() if since else None,
+                    "peer": settings.agent_id or "peer",
+                },
+            )
+            response.raise_for_status()
+            pushed = response.json()
+
+        self._save_last_sync(started)
+        logger.info(f"Synced with {self.peer_url}: pulled {pulled.to_dict()}, pushed {pushed}")
+        return {"peer": self.peer_url, "pulled": pulled.to_dict(), "pushed": pushed}
@@ -0,0 +1,180 @@
+"""Memory templates for structured facts
+A template declares the metadata fields a kind of memory carries (a contact, a
+decision, ...). Fields are validated when a templated memory is saved and the memory
+is rendered as a short card in get/list output. MORY_MEMORY_TEMPLATES adds or
+overrides templates, e.g.
+{"recipe": {"description": "A dish", "fields": {"dish": {"required": true}}}}
+"""
+
+import re
+from datetime import date
+from typing import Any
+
+from ..core.config import settings
+
+FIELD_TYPES = ("string", "url", "email", "date", "number", "choice")
+
+# Metadata keys a credential reference must never carry: it points at a secret store
+_SECRET_KEYS = re.compile(r"password|passwd|secret|token|api[_-]?key|private[_-]?key", re.I)
+_EMAIL = re.compile(r"[^@\s]+@[^@\s]+\.[^@\s]+")
+_URL = re.compile(r"https?://\S+")
+
+BUILTIN_TEMPLATES: dict[str, dict[str, Any]] = {
+    "contact": {
+        "description": "A person and how to reach them",
+        "fields": {
+            "name": {"required": True},
+            "email": {"type": "email"},
+            "phone": {},
+            "organization": {},
+        },
+    },
+    "decision": {
+        "description": "A decision and why it was made",
+        "fields": {
+            "decision": {"required": True},
+            "rationale": {},
+            "status": {
+                "type": "choice",
+                "choices": ["proposed", "accepted", "superseded"],
+                "default": "accepted",
+            },
+            "decided_on": {"type": "date"},
+        },
+    },
+    "bookmark": {
+        "description": "A link worth keeping",
+        "fields": {
+            "url": {"type": "url", "required": True},
+            "title": {},
+        },
+    },
+    "credential_reference": {
+        "description": "Where a credential is stored (never the secret itself)",
+        "fields": {
+            "service": {"required": True},
+            "location": {"required": True},
+            "username": {},
+        },
+        "forbid_secrets": True,
+    },
+}
+
+
+class TemplateError(ValueError):
+    """Raised when a template is unknown or its fields do not validate"""
+
+
+def templates() -> dict[str, dict[str, Any]]:
+    """Built-in templates merged with MORY_MEMORY_TEMPLATES"""
+    return {**BUILTIN_TEMPLATES, **settings.memory_templates}
+
+
+def get_template(name: str) -> dict[str, Any]:
+    template = templates().get(name)
+    if template is None:
+        raise TemplateError(f"Unknown template '{name}' (available: {', '.join(templates())})")
+    return template
+
+
+def template_problem(name: str, template: Any) -> str | None:
+    """Why a template definition is unusable, or None"""
+    if not isinstance(template, dict) or not isinstance(template.get("fields"), dict):
+        return f"{name}: expected an object with a 'fields' object"
+    if not template["fields"]:
+        return f"{name}: declares no fields"
+    for field, spec in template["fields"].items():
+        if not isinstance(spec, dict):
+            return f"{name}.{field}: expected an object"
+        field_type = spec.get("type", "string")
+        if field_type not in FIELD_TYPES:
+            return f"{name}.{field}: unknown type '{field_type}'"
+        if field_type == "choice" and not spec.get("choices"):
+            return f"{name}.{field}: choice fields need 'choices'"
+    return None
+
+
+def _coerce(field: str, spec: dict[str, Any], value: Any) -> Any:
+    field_type = spec.get("type", "string")
+    if field_type == "number":
+        if isinstance(value, bool):
+            raise TemplateError(f"'{field}' must be a number")
+        try:
+            return float(value)
+        except (TypeError, ValueError):
+            raise TemplateError(f"'{field}' must be a number") from None
+
+    text = str(value).strip()
+    if field_type == "email" and not _EMAIL.fullmatch(text):
+        raise TemplateError(f"'{field}' must be an email address")
+    if field_type == "url" and not _URL.fullmatch(text):
+        raise TemplateError(f"'{field}' must be an http(s) URL")
+    if field_type == "date":
+        try:
+            return date.fromisoformat(text[:10]).isoformat()
+        except ValueError:
+            raise TemplateError(f"'{field}' must be a date (YYYY-MM-DD)") from None
+    if field_type == "choice" and text not in spec["choices"]:
+        raise TemplateError(f"'{field}' must be one of: {', '.join(spec['choices'])}")
+    return text
+
+
+def validate_fields(name: str, metadata: dict[str, Any] | None) -> dict[str, Any]:
+    """Check metadata against a template; returns it with defaults and normalised values
+
+    Keys the template does not declare are kept as ordinary metadata.
+    """
+    template = get_template(name)
+    result = dict(metadata or {})
+    if template.get("forbid_secrets"):
+        secret_keys = [key for key in result if _SECRET_KEYS.search(key)]
+        if secret_keys:
+            raise TemplateError(
+                f"'{name}' memories must not contain secrets ({', '.join(secret_keys)})"
+            )
+
+    for field, spec in template["fields"].items():
+        value = result.get(field)
+        if value is None or (isinstance(value, str) and not value.strip()):
+            if "default" in spec:
+                result[field] = spec["default"]
+            elif spec.get("required"):
+                raise TemplateError(f"'{name}' memories need '{field}'")
+            else:
+                result.pop(field, None)
+            continue
+        result[field] = _coerce(field, spec, value)
+    return result
+
+
+def render(name: str | None, metadata: dict[str, Any]) -> str | None:
+    """Card text for a templated memory: "[template] first field" then one line per field"""
+    if not name:
+        return None
+    template = templates().get(name)
+    if not isinstance(template, dict):
+        return None
+    declared = template.get("fields") or {}
+    fields = [field for field in declared if metadata.get(field) not in (None, "")]
+    if not fields:
+        return f"[{name}]"
+    title, *rest = fields
+    lines = [f"[{name}] {metadata[title]}"]
+    lines.extend(f"{field}: {metadata[field]}" for field in rest)
+    return "\n".join(lines)
+
+
+def describe_templates() -> list[dict[str, Any]]:
+    """Template definitions for listing to clients"""
+    return [
+        {
+            "name": name,
+            "description": template.get("description", ""),
+            "fields": {
+                field: {"type": spec.get("type", "string"), **spec}
+                for field, spec in template["fields"].items()
+            },
+            "builtin": name in BUILTIN_TEMPLATES and name not in settings.memory_templates,
+        }
+        for name, template in templates().items()
+    ]
@@ -0,0 +1,300 @@
+"""Web page clipping for save_url
+Fetches a page and pulls out its main text the way reader views do: scripts, navigation,
+headers, footers and sidebars are dropped, the <article> (or <main>) element is preferred
+when the page has one, and link-heavy blocks such as menus and tag clouds are skipped.
+
+URLs come from the assistant, so only public addresses are fetched (checked again after
+every redirect): a clip must not reach the loopback interface, the LAN or cloud metadata.
+The request is sent to the address that was checked, so a DNS answer that changes between
+the check and the connection (DNS rebinding) cannot redirect it.
+"""
+
+import asyncio
+import ipaddress
+import re
+import socket
+from collections.abc import Awaitable, Callable
+from dataclasses import dataclass
+from html.parser import HTMLParser
+
+import httpx
+
+from .. import __version__
+from ..core.config import settings
+
+FETCH_TIMEOUT = 20.0
+MAX_PAGE_BYTES = 5 * 1024 * 1024
+MAX_REDIRECTS = 5
+
+# Host name -> the IP addresses it resolves to
+Resolver = Callable[[str], Awaitable[list[str]]]
+
+# Elements whose content is never part of the article
+_SKIPPED = {
+    "script",
+    "style",
+    "noscript",
+    "template",
+    "svg",
+    "iframe",
+    "nav",
+    "header",
+    "footer",
+    "aside",
+    "form",
+    "button",
+}
+_BLOCKS = {
+    "p",
+    "h1",
+    "h2",
+    "h3",
+    "h4",
+    "h5",
+    "h6",
+    "li",
+    "pre",
+    "blockquote",
+    "dd",
+    "dt",
+    "figcaption",
+    "td",
+}
+_CONTAINERS = {"article", "main"}
+_WHITESPACE = re.compile(r"\s+")
+
+# Blocks shorter than this, or mostly link text, are navigation rather than content
+MIN_BLOCK_CHARS = 25
+MAX_LINK_RATIO = 0.5
+
+
+class WebClipError(Exception):
+    """Raised when a URL cannot be fetched or holds no readable page"""
+
+
+@dataclass
+class Article:
+    url: str  # After redirects
+    title: str
+    text: str
+    description: str = ""
+    site_name: str = ""
+
+
+@dataclass
+class _Block:
+    tag: str
+    text: str
+    link_chars: int
+    in_container: bool
+
+
+class _ArticleParser(HTMLParser):
+    def __init__(self) -> None:
+        super().__init__(convert_charrefs=True)
+        self.blocks: list[_Block] = []
+        self.meta: dict[str, str] = {}
+        self.title = ""
+        self._skip_depth = 0
+        self._container_depth = 0
+        self._in_title = False
+        self._link_depth = 0
+        self._block_tag: str | None = None
+        self._parts: list[str] = []
+        self._link_chars = 0
+
+    def _flush(self) -> None:
+        text = _WHITESPACE.sub(" ", "".join(self._parts)).strip()
+        if self._block_tag and text:
+            block = _Block(self._block_tag, text, self._link_chars, self._container_depth > 0)
+            self.blocks.append(block)
+        self._block_tag = None
+        self._parts = []
+        self._link_chars = 0
+
+    def handle_starttag(self, tag: str, attrs: list[tuple[str, str | None]]) -> None:
+        if tag == "meta":
+            attributes = dict(attrs)
+            name = attributes.get("property") or attributes.get("name")
+            if name and attributes.get("content"):
+                self.meta.setdefault(name.lower(), attributes["content"].strip())
+            return
+        if tag in _SKIPPED:
+            self._skip_depth += 1
+        elif tag in _CONTAINERS:
+            self._container_depth += 1
+        elif tag == "title":
+            self._in_title = True
+        elif tag == "a":
+            self._link_depth += 1
+        elif tag in _BLOCKS:
+            # Nested blocks (a <p> inside an <li>) start a new block
+            self._flush()
+            self._block_tag = tag
+        elif tag == "br":
+            self._parts.append(" ")
+
+    def handle_endtag(self, tag: str) -> None:
+        if tag in _SKIPPED:
+            self._skip_depth = max(self._skip_depth - 1, 0)
+        elif tag in _CONTAINERS:
+            self._flush()
+            self._container_depth = max(self._container_depth - 1, 0)
+        elif tag == "title":
+            self._in_title = False
+        elif tag == "a":
+            self._link_depth = max(self._link_depth - 1, 0)
+        elif tag in _BLOCKS and tag == self._block_tag:
+            self._flush()
+
+    def handle_data(self, data: str) -> None:
+        if self._in_title:
+            self.title += data
+        elif self._skip_depth == 0 and self._block_tag:
+            self._parts.append(data)
+            if self._link_depth:
+                self._link_chars += len(data.strip())
+
+    def close(self) -> None:
+        super().close()
+        self._flush()
+
+
+def _is_content(block: _Block) -> bool:
+    if block.tag.startswith("h"):
+        return True
+    if len(block.text) < MIN_BLOCK_CHARS:
+        return False
+    return block.link_chars / len(block.text) <= MAX_LINK_RATIO
+
+
+def extract_article(html: str, url: str) -> Article:
+    """Title, description and main text of an HTML page"""
+    parser = _ArticleParser()
+    parser.feed(html)
+    parser.close()
+
+    blocks = [block for block in parser.blocks if block.in_container] or parser.blocks
+    lines = []
+    for block in blocks:
+        if not _is_content(block):
+            continue
+        prefix = "#" * int(block.tag[1]) + " " if block.tag in ("h1", "h2", "h3") else ""
+        lines.append(prefix + block.text)
+
+    meta = parser.meta
+    title = meta.get("og:title") or _WHITESPACE.sub(" ", parser.title).strip()
+    return Article(
+        url=url,
+        title=title or url,
+        text="\n\n".join(lines),
+        description=meta.get("og:description") or meta.get("description", ""),
+        site_name=meta.get("og:site_name", ""),
+    )
+
+
+async def resolve_host(host: str) -> list[str]:
+    """IP addresses of a host name, from the system resolver"""
+    infos = await asyncio.get_running_loop().getaddrinfo(host, None, type=socket.SOCK_STREAM)
+    return [info[4][0] for info in infos]
+
+
+def is_public_address(address: str) -> bool:
+    """Whether an IP address is on the public internet (not loopback, private, link-local,
+    reserved, multicast, ...)"""
+    ip = ipaddress.ip_address(address.split("%", 1)[0])  # Without an IPv6 zone
+    return ip.is_global and not ip.is_multicast
+
+
+class WebClipper:
+    """Fetches pages for save_url"""
+
+    def __init__(
+        self, transport: httpx.AsyncBaseTransport | None = None, resolve: Resolver | None = None
+    ):
+        self.transport = transport
+        self.resolve = resolve or resolve_host
+
+    async def _check_destination(self, url: httpx.URL) -> str | None:
+        """Refuse URLs that are not http(s) or whose host is not a public address
+
+        Returns the vetted address to connect to, or None when the URL can be used as is.
+        """
+        if url.scheme not in ("http", "https"):
+            raise WebClipError(f"{url} is not an http(s) URL")
+        if settings.web_clip_allow_private:
+            return None
+        try:
+            addresses = [str(ipaddress.ip_address(url.host))]
+            pinned = None  # Already an address
+        except ValueError:
+            try:
+                addresses = await self.resolve(url.host)
+            except OSError as e:
+                raise WebClipError(f"Could not resolve {url.host}") from e
+            pinned = addresses[0] if addresses else None
+        if not addresses or not all(is_public_address(address) for address in addresses):
+            raise WebClipError(f"{url} points to a private or local address")
+        return pinned
+
+    @staticmethod
+    def _build_request(
+        client: httpx.AsyncClient, url: httpx.URL, address: str | None
+    ) -> httpx.Request:
+        """GET url, connecting to address instead of resolving the host again
+
+        The original host still goes out as the Host header and, over https, as the TLS
+        server name, so virtual hosting and certificate checks work as usual.
+        """
+        if address is None:
+            return client.build_request("GET", url)
+        host = f"[{address}]" if ":" in address else address
+        extensions = {"sni_hostname": url.raw_host.decode("ascii")} if url.scheme == "https" else {}
+        return client.build_request(
+            "GET",
+            url.copy_with(host=host),
+            headers={"Host": url.netloc.decode("ascii")},
+            extensions=extensions,
+        )
+
+    async def _read_page(self, response: httpx.Response, url: str) -> str:
+        response.raise_for_status()
+        content_type = response.headers.get("Content-Type", "")
+        if "html" not in content_type:
+            raise WebClipError(f"{url} is not a web page ({content_type or 'unknown'})")
+        body = bytearray()
+        async for chunk in response.aiter_bytes():
+            body.extend(chunk)
+            if len(body) > MAX_PAGE_BYTES:
+                raise WebClipError(f"{url} is larger than {MAX_PAGE_BYTES} bytes")
+        return body.decode(response.encoding or "utf-8", errors="replace")
+
+    async def fetch(self, url: str) -> Article:
+        """Download url and extract its article"""
+        headers = {"User-Agent": f"Mory/{__version__} (+save_url)", "Accept": "text/html"}
+        try:
+            target = httpx.URL(url)
+            # Redirects are followed by hand so each destination is checked before connecting
+            async with httpx.AsyncClient(
+                headers=headers, timeout=FETCH_TIMEOUT, transport=self.transport
+            ) as client:
+                for _ in range(MAX_REDIRECTS + 1):
+                    address = await self._check_destination(target)
+                    request = self._build_request(client, target, address)
+                    response = await client.send(request, stream=True)
+                    try:
+                        if not response.is_redirect:
+                            html = await self._read_page(response, url)
+                            return extract_article(html, str(target))
+                        # Relative to the URL asked for, not the pinned address
+                        target = target.join(response.headers["Location"])
+                    finally:
+                        await response.aclose()
+        except httpx.HTTPStatusError as e:
+            raise WebClipError(f"{url} returned HTTP {e.response.status_code}") from e
+        except (httpx.HTTPError, httpx.InvalidURL) as e:
+            raise WebClipError(f"Could not fetch {url}: {str(e) or type(e).__name__}") from e
+        raise WebClipError(f"{url} redirected more than {MAX_REDIRECTS} times")
+
+
+web_clipper = WebClipper()
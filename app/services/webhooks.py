@@ -0,0 +1,116 @@
+"""Webhook notifications for memory changes
+POSTs a signed JSON event to every URL in MORY_WEBHOOK_URLS, retrying failed deliveries
+"""
+
+import asyncio
+import hashlib
+import hmac
+import json
+import logging
+from datetime import UTC, datetime
+from typing import Any
+
+import httpx
+
+from ..core.config import settings
+from ..core.metrics import metrics
+
+logger = logging.getLogger(__name__)
+
+SIGNATURE_HEADER = "X-Mory-Signature"
+
+
+def memory_metadata(memory: Any) -> dict[str, Any]:
+    """Event payload fields for a memory (content is summarized, not sent in full)"""
+    return {
+        "id": memory.id,
+        "namespace": memory.namespace,
+        "owner": memory.owner,
+        "summary": memory.summary,
+        "tags": memory.tags_list,
+        "review_status": memory.review_status,
+        "created_at": memory.created_at.isoformat() if memory.created_at else None,
+        "updated_at": memory.updated_at.isoformat() if memory.updated_at else None,
+    }
+
+
+def sign(body: bytes, secret: str) -> str:
+    """HMAC-SHA256 signature sent as X-Mory-Signature: sha256=<hex>"""
+    return "sha256=" + hmac.new(secret.encode(), body, hashlib.sha256).hexdigest()
+
+
+class WebhookDispatcher:
+    """Delivers memory change events to the configured webhook URLs"""
+
+    def __init__(self, transport: httpx.AsyncBaseTransport | None = None):
+        self.transport = transport
+        self._tasks: set[asyncio.Task] = set()
+
+    @property
+    def enabled(self) -> bool:
+        return bool(settings.webhook_urls)
+
+    def build_event(self, event_type: str, memory: Any) -> dict[str, Any]:
+        return {
+            "event": f"memory.{event_type}",
+            "timestamp": datetime.now(UTC).isoformat(),
+            "memory": memory_metadata(memory),
+        }
+
+    async def deliver(self, client: httpx.AsyncClient, url: str, body: bytes) -> bool:
+        """POST one event, retrying network errors and 5xx responses with backoff"""
+        headers = {"Content-Type": "application/json"}
+        if settings.webhook_secret:
+            headers[SIGNATURE_HEADER] = sign(body, settings.webhook_secret)
+
+        attempts = settings.webhook_max_retries + 1
+        for attempt in range(attempts):
+            try:
+                response = await client.post(url, content=body, headers=headers)
+                if response.status_code < 400:
+                    metrics.inc("mory_webhook_deliveries_total", {"outcome": "success"})
+                    return True
+                if response.status_code < 500:
+                    # Client errors will not succeed on retry
+                    logger.warning(f"Webhook {url} rejected event: HTTP {response.status_code}")
+                    break
+                error = f"HTTP {response.status_code}"
+            except httpx.HTTPError as e:
+                error = str(e) or type(e).__name__
+
+            if attempt < attempts - 1:
+                await asyncio.sleep(settings.webhook_retry_backoff * 2**attempt)
+        else:
+            logger.warning(f"Webhook {url} failed after {attempts} attempts: {error}")
+
+        metrics.inc("mory_webhook_deliveries_total", {"outcome": "failure"})
+        return False
+
+    async def send(self, event: dict[str, Any]) -> list[bool]:
+        """Deliver an event to all URLs concurrently"""
+        body = json.dumps(event, ensure_ascii=False).encode()
+        async with httpx.AsyncClient(
+            timeout=settings.webhook_timeout, transport=self.transport
+        ) as client:
+            return await asyncio.gather(
+                *(self.deliver(client, url, body) for url in settings.webhook_urls)
+            )
+
+    def dispatch(self, event_type: str, memory: Any) -> None:
+        """Send an event in the background (no-op when no URLs are configured)"""
+        if not self.enabled:
+            return
+
+        event = self.build_event(event_type, memory)
+        task = asyncio.get_running_loop().create_task(self.send(event))
+        self._tasks.add(task)
+        task.add_done_callback(self._tasks.discard)
+
+    async def drain(self, timeout: float) -> None:
+        """Wait for queued deliveries before shutdown"""
+        if self._tasks:
+            await asyncio.wait(self._tasks, timeout=timeout)
+
+
+# Global webhook dispatcher instance
+webhook_dispatcher = WebhookDispatcher()
@@ -0,0 +1,269 @@
+"""Interactive terminal browser for memories (curses)
+Browse by tag, search incrementally (keyword) or semantically, view, edit and delete
+"""
+
+import asyncio
+import curses
+import os
+import subprocess
+import tempfile
+from collections import Counter
+from datetime import datetime
+
+from sqlalchemy.orm import Session
+
+from .core.events import MEMORY_DELETED, MEMORY_UPDATED, MemoryEvent, event_bus
+from .models.memory import Memory
+from .models.schemas import SearchRequest
+from .services.operation_log import memory_snapshot
+
+HELP = "↑↓/jk move  Enter view  / search  s semantic  t tag  e edit  d delete  q quit"
+
+
+class MemoryBrowser:
+    """Browser state, kept separate from curses drawing"""
+
+    def __init__(self, db: Session, namespace: str):
+        self.db = db
+        self.namespace = namespace
+        self.memories: list[Memory] = []
+        self.query = ""
+        self.tag: str | None = None
+        self.semantic_ids: list[str] | None = None  # Result order of the last semantic search
+        self.index = 0
+
+    def load(self) -> None:
+        """Reload memories from the database"""
+        self.memories = (
+            self.db.query(Memory)
+            .filter(Memory.namespace == self.namespace, Memory.archived_at.is_(None))
+            .order_by(Memory.updated_at.desc())
+            .all()
+        )
+        self.index = min(self.index, max(len(self.visible) - 1, 0))
+
+    def tag_counts(self) -> list[tuple[str, int]]:
+        """Tags across all memories, most used first"""
+        counts = Counter(tag for memory in self.memories for tag in memory.tags_list)
+        return counts.most_common()
+
+    def cycle_tag(self) -> None:
+        """Step through tag filters (None = all memories)"""
+        tags = [tag for tag, _ in self.tag_counts()]
+        if not tags:
+            self.tag = None
+        elif self.tag not in tags:
+            self.tag = tags[0]
+        else:
+            position = tags.index(self.tag) + 1
+            self.tag = tags[position] if position < len(tags) else None
+        self.index = 0
+
+    def set_query(self, query: str) -> None:
+        """Keyword filter, applied on every keystroke"""
+        self.query = query
+        self.semantic_ids = None
+        self.index = 0
+
+    def semantic_search(self, query: str) -> None:
+        """Order memories by semantic similarity to the query"""
+        from .services.search import search_service
+
+        request = SearchRequest(
+            query=query, namespace=self.namespace, search_type="semantic", limit=100
+        )
+        response = asyncio.run(search_service.search_memories(request, self.db))
+        self.query = ""
+        self.semantic_ids = [result.memory.id for result in response.results]
+        self.index = 0
+
+    @property
+    def visible(self) -> list[Memory]:
+        memories = self.memories
+        if self.semantic_ids is not None:
+            by_id = {memory.id: memory for memory in memories}
+            memories = [by_id[i] for i in self.semantic_ids if i in by_id]
+        if self.tag:
+            memories = [m for m in memories if self.tag in m.tags_list]
+        if self.query:
+            needle = self.query.lower()
+            memories = [
+                m
+                for m in memories
+                if needle in m.value.lower() or needle in (m.summary or "").lower()
+            ]
+        return memories
+
+    @property
+    def selected(self) -> Memory | None:
+        visible = self.visible
+        return visible[self.index] if 0 <= self.index < len(visible) else None
+
+    def move(self, delta: int) -> None:
+        self.index = max(0, min(self.index + delta, len(self.visible) - 1))
+
+    def delete_selected(self) -> None:
+        memory = self.selected
+        if memory:
+            self.db.delete(memory)
+            self.db.commit()
+            event = MemoryEvent(
+                MEMORY_DELETED, memory, session=self.db, details={"summary": memory.summary}
+            )
+            asyncio.run(event_bus.publish(event))
+            self.load()
+
+    def update_selected(self, value: str) -> bool:
+        """Replace the selected memory's content; returns False if unchanged"""
+        memory = self.selected
+        value = value.strip()
+        if not memory or not value or value == memory.value:
+            return False
+
+        before = memory_snapshot(memory)
+        memory.value = value
+        memory.updated_at = datetime.utcnow()
+        self.db.commit()
+        event = MemoryEvent(MEMORY_UPDATED, memory, session=self.db, details={"before": before})
+        asyncio.run(event_bus.publish(event))
+        self.load()
+        return True
+
+
+def _edit_in_editor(text: str) -> str:
+    """Open $EDITOR on the text and return the edited content"""
+    editor = os.environ.get("EDITOR", "vi")
+    with tempfile.NamedTemporaryFile("w+", suffix=".md", delete=False, encoding="utf-8") as f:
+        f.write(text)
+        path = f.name
+    try:
+        subprocess.call([editor, path])
+        with open(path, encoding="utf-8") as f:
+            return f.read()
+    finally:
+        os.unlink(path)
+
+
+def _prompt(stdscr, label: str, on_change=None) -> str | None:
+    """Read a line on the bottom row; Esc cancels"""
+    height, width = stdscr.getmaxyx()
+    text = ""
+    curses.curs_set(1)
+    try:
+        while True:
+            stdscr.move(height - 1, 0)
+            stdscr.clrtoeol()
+            stdscr.addnstr(height - 1, 0, f"{label}{text}", width - 1)
+            key = stdscr.get_wch()
+            if key in ("\n", "\r", curses.KEY_ENTER):
+                return text
+            if key == "\x1b":
+                return None
+            if key in ("\x7f", "\b", curses.KEY_BACKSPACE):
+                text = text[:-1]
+            elif isinstance(key, str) and key.isprintable():
+                text += key
+            if on_change:
+                on_change(text)
+    finally:
+        curses.curs_set(0)
+
+
+def _draw(stdscr, browser: MemoryBrowser, status: str) -> None:
+    stdscr.erase()
+    height, width = stdscr.getmaxyx()
+    visible = browser.visible
+
+    title = f" Mory [{browser.namespace}] {len(visible)}/{len(browser.memories)}"
+    if browser.tag:
+        title += f"  tag:{browser.tag}"
+    if browser.query:
+        title += f"  /{browser.query}"
+    if browser.semantic_ids is not None:
+        title += "  (semantic)"
+    stdscr.addnstr(0, 0, title.ljust(width), width, curses.A_REVERSE)
+
+    rows = height - 3
+    top = max(0, browser.index - rows + 1)
+    for row, memory in enumerate(visible[top : top + rows]):
+        text = (memory.summary or memory.value).replace("\n", " ")
+        line = f"{memory.id}  {text}"
+        attr = curses.A_BOLD | curses.A_REVERSE if top + row == browser.index else 0
+        stdscr.addnstr(row + 1, 0, line.ljust(width), width, attr)
+
+    stdscr.addnstr(height - 2, 0, status or HELP, width - 1, curses.A_DIM)
+    stdscr.refresh()
+
+
+def _show_detail(stdscr, memory: Memory) -> None:
+    stdscr.erase()
+    height, width = stdscr.getmaxyx()
+    lines = [
+        f"ID:      {memory.id}",
+        f"Tags:    {', '.join(memory.tags_list)}",
+        f"Source:  {memory.source or '-'}",
+        f"Updated: {memory.updated_at}",
+        f"Status:  {memory.processing_status}",
+        "",
+        f"Summary: {memory.summary or '-'}",
+        "",
+        *memory.value.splitlines(),
+    ]
+    for row, line in enumerate(lines[: height - 1]):
+        stdscr.addnstr(row, 0, line, width - 1)
+    stdscr.addnstr(height - 1, 0, "Press any key to return", width - 1, curses.A_DIM)
+    stdscr.getch()
+
+
+def _run(stdscr, browser: MemoryBrowser) -> None:
+    curses.curs_set(0)
+    status = ""
+
+    while True:
+        _draw(stdscr, browser, status)
+        status = ""
+        key = stdscr.getch()
+
+        if key in (ord("q"), 27):
+            return
+        elif key in (curses.KEY_DOWN, ord("j")):
+            browser.move(1)
+        elif key in (curses.KEY_UP, ord("k")):
+            browser.move(-1)
+        elif key in (curses.KEY_ENTER, 10, 13) and browser.selected:
+            _show_detail(stdscr, browser.selected)
+        elif key == ord("/"):
+
+            def _refresh(text: str) -> None:
+                browser.set_query(text)
+                _draw(stdscr, browser, "")
+
+            if _prompt(stdscr, "/", on_change=_refresh) is None:
+                browser.set_query("")
+        elif key == ord("s"):
+            query = _prompt(stdscr, "semantic: ")
+            if query:
+                try:
+                    browser.semantic_search(query)
+                except Exception as e:
+                    status = f"Semantic search failed: {e}"
+        elif key == ord("t"):
+            browser.cycle_tag()
+        elif key == ord("e") and browser.selected:
+            curses.endwin()
+            changed = browser.update_selected(_edit_in_editor(browser.selected.value))
+            stdscr.refresh()
+            status = "Saved" if changed else "No changes"
+        elif key == ord("d") and browser.selected:
+            answer = _prompt(stdscr, f"Delete {browser.selected.id}? [y/N] ")
+            if answer and answer.lower() in ("y", "yes"):
+                browser.delete_selected()
+                status = "Deleted"
+
+
+def run_tui(db: Session, namespace: str) -> int:
+    """Start the interactive browser"""
+    browser = MemoryBrowser(db, namespace)
+    browser.load()
+    curses.wrapper(_run, browser)
+    return 0
@@ -5,7 +5,9 @@ This script runs the MCP server for Claude Desktop integration
 """
 
 import asyncio
+import contextlib
 import logging
+import signal
 import sys
 from pathlib import Path
 
@@ -14,17 +16,12 @@ sys.path.insert(0, str(Path(__file__).parent))
 
 from mcp.server.stdio import stdio_server
 
-from app.mcp_server import mcp_server
+from app.core.config import settings
+from app.core.logging_config import setup_logging
+from app.mcp_server import mcp_server, tool_calls
 
-# Configure logging
-logging.basicConfig(
-    level=logging.INFO,
-    format="%(asctime)s - %(name)s - %(levelname)s - %(message)s",
-    handlers=[
-        logging.FileHandler("mcp_server.log"),
-        logging.StreamHandler(sys.stderr),
-    ],
-)
+# Configure logging (level, format and log file come from MORY_LOG_* settings)
+setup_logging()
 
 logger = logging.getLogger(__name__)
 
@@ -33,18 +30,44 @@ async def main():
     """Main entry point for MCP server"""
     logger.info("Starting Mory MCP Server...")
 
+    # SIGINT/SIGTERM trigger an ordered shutdown instead of cancelling mid-call
+    stop_requested = asyncio.Event()
+    loop = asyncio.get_running_loop()
+    for sig in (signal.SIGINT, signal.SIGTERM):
+        with contextlib.suppress(NotImplementedError):  # Not supported on Windows
+            loop.add_signal_handler(sig, stop_requested.set)
+
     try:
         # Run the server with stdio transport (required for Claude Desktop)
         async with stdio_server() as (read_stream, write_stream):
-            await mcp_server.run(
-                read_stream,
-                write_stream,
-                mcp_server.create_initialization_options(),
+            server_task = asyncio.create_task(
+                mcp_server.run(
+                    read_stream,
+                    write_stream,
+                    mcp_server.create_initialization_options(),
+                )
+            )
+            stop_task = asyncio.create_task(stop_requested.wait())
+            done, _ = await asyncio.wait(
+                {server_task, stop_task}, return_when=asyncio.FIRST_COMPLETED
             )
+
+            if stop_task in done:
+                logger.info(f"Shutdown requested, waiting for {tool_calls.count} tool call(s)")
+                if not await tool_calls.drain(settings.shutdown_timeout):
+                    logger.warning("Tool calls still running after shutdown timeout")
+                server_task.cancel()
+                with contextlib.suppress(asyncio.CancelledError):
+                    await server_task
+            else:
+                stop_task.cancel()
+                server_task.result()
     except Exception as e:
         logger.error(f"MCP Server failed: {e}")
         raise
 
+    logger.info("Mory MCP Server stopped")
+
 
 if __name__ == "__main__":
     asyncio.run(main())
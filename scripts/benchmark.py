@@ -0,0 +1,218 @@
+#!/usr/bin/env python3
+"""
+Benchmark suite for Mory Server
+Times save, get, list and search through the REST API for each storage backend at
+several database sizes, and compares the medians with a baseline so performance
+regressions are caught before release.
+"""
+
+import argparse
+import json
+import os
+import random
+import statistics
+import subprocess
+import sys
+import tempfile
+import time
+from pathlib import Path
+
+sys.path.append(str(Path(__file__).parent.parent))
+
+OPERATIONS = ("save", "get", "list", "search")
+STORES = ("sqlite", "files")
+DEFAULT_SIZES = (1000, 10000)
+DEFAULT_BASELINE = Path(__file__).resolve().parent.parent / "benchmarks" / "baseline.json"
+
+# Words the seeded memories are made of, so searches have realistic hit counts
+WORDS = (
+    "python golang sqlite search memory travel kyoto recipe meeting project deadline "
+    "review budget release server client index query vector backup garden music "
+    "東京 会議 旅行 料理 設計"
+).split()
+
+
+def make_value(rng: random.Random) -> str:
+    return " ".join(rng.choice(WORDS) for _ in range(rng.randint(8, 40)))
+
+
+def seed(size: int, rng: random.Random) -> list[str]:
+    """Insert size memories directly, bypassing the API (and its events) for speed"""
+    from app.core.database import SessionLocal, create_tables
+    from app.models.memory import Memory
+    from app.services.file_store import file_store
+
+    create_tables()
+    db = SessionLocal()
+    ids = []
+    try:
+        for start in range(0, size, 1000):
+            batch = [
+                Memory(value=make_value(rng), tags=json.dumps([rng.choice(WORDS[:10])]))
+                for _ in range(min(1000, size - start))
+            ]
+            db.add_all(batch)
+            db.commit()
+            ids.extend(memory.id for memory in batch)
+        store = file_store()
+        if store is not None:
+            store.export_all(db)
+    finally:
+        db.close()
+    return ids
+
+
+def timed(operation, count: int) -> dict[str, float]:
+    """Median and 95th percentile of count calls, in milliseconds"""
+    samples = []
+    for _ in range(count):
+        start = time.perf_counter()
+        operation()
+        samples.append((time.perf_counter() - start) * 1000)
+    samples.sort()
+    return {
+        "median_ms": round(statistics.median(samples), 3),
+        "p95_ms": round(samples[min(len(samples) - 1, int(len(samples) * 0.95))], 3),
+    }
+
+
+def run_worker(size: int, count: int) -> dict[str, dict[str, float]]:
+    """One store at one size; the store comes from the environment set by the parent"""
+    from fastapi.testclient import TestClient
+
+    from app.main import app
+
+    rng = random.Random(size)
+    ids = seed(size, rng)
+    client = TestClient(app)
+
+    def check(response):
+        if response.status_code >= 400:
+            raise RuntimeError(f"{response.request.url}: HTTP {response.status_code}")
+        return response
+
+    return {
+        "save": timed(
+            lambda: check(client.post("/api/memories", json={"value": make_value(rng)})), count
+        ),
+        "get": timed(lambda: check(client.get(f"/api/memories/{rng.choice(ids)}")), count),
+        "list": timed(lambda: check(client.get("/api/memories", params={"limit": 50})), count),
+        "search": timed(
+            lambda: check(
+                client.post(
+                    "/api/memories/search", json={"query": rng.choice(WORDS), "limit": 20}
+                )
+            ),
+            count,
+        ),
+    }
+
+
+def run_case(store: str, size: int, count: int) -> dict[str, dict[str, float]]:
+    """Run one case in a fresh process, since settings are read once at import"""
+    with tempfile.TemporaryDirectory(prefix="mory-bench-") as data_dir:
+        env = {
+            key: value
+            for key, value in os.environ.items()
+            if not key.startswith("MORY_") and key != "OPENAI_API_KEY"
+        }
+        env.update(
+            MORY_DATA_DIR=data_dir,
+            MORY_STORAGE_BACKEND=store,
+            MORY_SUMMARY_ENABLED="false",
+            MORY_SEMANTIC_SEARCH_ENABLED="false",
+            MORY_ALLOW_MULTIPLE_INSTANCES="true",
+            MORY_LOG_LEVEL="WARNING",
+        )
+        output = subprocess.run(
+            [sys.executable, __file__, "--worker", str(size), "--count", str(count)],
+            env=env,
+            cwd=Path(__file__).resolve().parent.parent,
+            capture_output=True,
+            text=True,
+            check=True,
+        ).stdout
+        return json.loads(output.strip().splitlines()[-1])
+
+
+def compare(results: dict, baseline: dict, tolerance: float) -> list[str]:
+    """Cases whose median is more than tolerance (0.5 = 50%) slower than the baseline"""
+    regressions = []
+    for case, operations in results.items():
+        for operation, timing in operations.items():
+            reference = baseline.get(case, {}).get(operation)
+            if not reference:
+                continue
+            limit = reference["median_ms"] * (1 + tolerance)
+            if timing["median_ms"] > limit:
+                regressions.append(
+                    f"{case} {operation}: {timing['median_ms']:.2f} ms "
+                    f"(baseline {reference['median_ms']:.2f} ms)"
+                )
+    return regressions
+
+
+def main():
+    parser = argparse.ArgumentParser(description="Mory Server benchmark suite")
+    parser.add_argument(
+        "--sizes",
+        default=",".join(str(size) for size in DEFAULT_SIZES),
+        help="Comma-separated database sizes (e.g. 1000,10000,100000)",
+    )
+    parser.add_argument("--stores", default=",".join(STORES), help="Comma-separated backends")
+    parser.add_argument("--count", type=int, default=100, help="Timed calls per operation")
+    parser.add_argument("--baseline", default=str(DEFAULT_BASELINE), help="Baseline JSON file")
+    parser.add_argument(
+        "--tolerance", type=float, default=0.5, help="Allowed slowdown before failing (0.5 = 50%%)"
+    )
+    parser.add_argument(
+        "--update-baseline", action="store_true", help="Write the results as the new baseline"
+    )
+    parser.add_argument("--worker", type=int, help=argparse.SUPPRESS)
+    args = parser.parse_args()
+
+    if args.worker is not None:
+        print(json.dumps(run_worker(args.worker, args.count)))
+        return 0
+
+    results = {}
+    for store in args.stores.split(","):
+        for size in (int(size) for size in args.sizes.split(",")):
+            case = f"{store}/{size}"
+            print(f"⏱️  {case}...", flush=True)
+            try:
+                results[case] = run_case(store, size, args.count)
+            except subprocess.CalledProcessError as e:
+                print(f"❌ {case} failed:\n{e.stderr}")
+                return 1
+            for operation in OPERATIONS:
+                timing = results[case][operation]
+                print(
+                    f"   {operation:<7} median {timing['median_ms']:8.2f} ms"
+                    f"   p95 {timing['p95_ms']:8.2f} ms"
+                )
+
+    baseline_path = Path(args.baseline)
+    if args.update_baseline:
+        baseline_path.parent.mkdir(parents=True, exist_ok=True)
+        baseline_path.write_text(json.dumps(results, indent=2) + "\n", encoding="utf-8")
+        print(f"✅ Baseline written to {baseline_path}")
+        return 0
+
+    if not baseline_path.exists():
+        print(f"⚠️  No baseline at {baseline_path} (create one with --update-baseline)")
+        return 0
+
+    baseline = json.loads(baseline_path.read_text(encoding="utf-8"))
+    regressions = compare(results, baseline, args.tolerance)
+    if regressions:
+        print(f"\n❌ {len(regressions)} regression(s) beyond {args.tolerance:.0%}:")
+        for regression in regressions:
+            print(f"   {regression}")
+        return 1
+    print(f"\n✅ No regressions beyond {args.tolerance:.0%} of the baseline")
+    return 0
+
+
+if __name__ == "__main__":
+    sys.exit(main())
@@ -34,10 +34,17 @@ app.dependency_overrides[get_db] = override_get_db
 
 @pytest.fixture(scope="function")
 def db_session():
-    """Create a fresh database for each test"""
+    """Create a fresh database for each test and yield a session on it"""
     from sqlalchemy import text
 
     from app.core.database import create_tables
+    from app.core.limits import write_rate_limiter
+    from app.services.stats import search_counter
+
+    # Writes from earlier tests must not count against the rate limit
+    write_rate_limiter.reset()
+    # Nor their searches be written into this test's daily_stats
+    search_counter.reset()
 
     # Clean up any existing FTS5 tables and triggers first
     try:
@@ -56,7 +63,9 @@ def db_session():
         create_tables(engine_override=engine)
     except Exception:
         pass  # FTS5 might not be available in test environment
-    yield
+    session = TestingSessionLocal()
+    yield session
+    session.close()
 
     # Clean up after test
     try:
@@ -0,0 +1,70 @@
+"""Tests for the hash-chained operation log and its audit export"""
+
+from sqlalchemy import text
+
+from app.cli import main
+from app.services.operation_log import (
+    audit_entries,
+    audit_record,
+    export_audit,
+    load_audit,
+    record_operation,
+    verify_chain,
+)
+from tests.conftest import TestingSessionLocal
+
+
+def _log(count: int) -> None:
+    db = TestingSessionLocal()
+    for i in range(count):
+        record_operation(db, "saved", memory_id=f"mem_{i}", details={"after": {"value": "メモ"}})
+    db.commit()
+    db.close()
+
+
+def test_entries_are_chained_across_commits(db_session):
+    _log(2)  # Two entries in one flush
+    _log(1)
+
+    db = TestingSessionLocal()
+    records = [audit_record(entry) for entry in audit_entries(db)]
+    assert [record["prev_hash"] for record in records[1:]] == [
+        record["hash"] for record in records[:-1]
+    ]
+    assert records[0]["prev_hash"] is None
+    assert verify_chain(records) == []
+
+    # Editing a logged operation breaks its hash, deleting one breaks the links
+    tampered_id = records[1]["id"]
+    db.execute(text("UPDATE operation_log SET details = '{}' WHERE id = :id"), {"id": tampered_id})
+    db.commit()
+    tampered = [audit_record(entry) for entry in audit_entries(db)]
+    assert verify_chain(tampered) == [f"Entry {tampered_id} does not match its hash (altered)"]
+    assert verify_chain([records[0], records[2]]) == [
+        f"Entry {records[2]['id']} does not follow entry {records[0]['id']} (entries removed)"
+    ]
+    db.close()
+
+
+def test_exports_verify_after_round_trip(db_session):
+    _log(3)
+    db = TestingSessionLocal()
+    records = [audit_record(entry) for entry in audit_entries(db)]
+    db.close()
+
+    for format in ("jsonl", "csv"):
+        assert verify_chain(load_audit(export_audit(records, format), format)) == []
+
+
+def test_export_and_verify_commands(db_session, capsys, tmp_path):
+    _log(2)
+    output = tmp_path / "audit.csv"
+
+    assert main(["export-audit", "--format", "csv", "-o", str(output)], TestingSessionLocal) == 0
+    assert output.read_text(encoding="utf-8").startswith("id,created_at,operation")
+    assert main(["verify-audit", str(output)], TestingSessionLocal) == 0
+    assert main(["verify-audit"], TestingSessionLocal) == 0
+
+    output.write_text(output.read_text(encoding="utf-8").replace("mem_1", "mem_9"))
+    assert main(["verify-audit", str(output)], TestingSessionLocal) == 1
+    assert "altered" in capsys.readouterr().err
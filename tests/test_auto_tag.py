@@ -0,0 +1,56 @@
+"""Tests for automatic tagging of untagged memories"""
+
+import json
+
+import numpy as np
+
+from app.core.config import settings
+from app.models.memory import Memory
+from app.services.auto_tag import auto_tag, extract_keywords
+from tests.conftest import TestingSessionLocal
+
+
+def test_extract_keywords():
+    text = "Set up Docker compose networking for the home server."
+    assert extract_keywords(text, limit=2) == ["docker-compose-networking", "home-server"]
+    assert extract_keywords("京都旅行の計画：新幹線のチケットを予約", limit=3) == [
+        "京都旅行",
+        "計画",
+        "新幹線",
+    ]
+
+
+def _vector(*values: float) -> bytes:
+    return np.array(values, dtype=np.float32).tobytes()
+
+
+def test_neighbor_tags_win_over_keywords(db_session):
+    db = TestingSessionLocal()
+    db.add(Memory(id="mem_near", value="a", tags=json.dumps(["travel"]), embedding=_vector(1, 0)))
+    db.add(Memory(id="mem_far", value="b", tags=json.dumps(["cooking"]), embedding=_vector(0, 1)))
+    memory = Memory(id="mem_new", value="Kyoto hotel booking", embedding=_vector(0.9, 0.1))
+    db.add(memory)
+    db.commit()
+
+    assert auto_tag(db, memory) == ["travel"]
+    assert memory.auto_tags_list == ["travel"]
+    db.close()
+
+
+def test_auto_tags_on_save_and_review(client, db_session, monkeypatch):
+    monkeypatch.setattr(settings, "auto_tag", True)
+    response = client.post("/api/memories", json={"value": "Renew passport before the Kyoto trip"})
+    memory = response.json()
+    assert memory["tags"] == memory["auto_tags"] == ["renew-passport", "kyoto-trip"]
+
+    pending = client.get("/api/memories/auto-tagged").json()
+    assert [m["id"] for m in pending["memories"]] == [memory["id"]]
+
+    response = client.post(f"/api/memories/{memory['id']}/auto-tags", json={"keep": ["kyoto-trip"]})
+    assert response.status_code == 200
+    assert response.json()["tags"] == ["kyoto-trip"]
+    assert response.json()["auto_tags"] == []
+    assert client.get("/api/memories/auto-tagged").json()["total"] == 0
+
+    again = client.post(f"/api/memories/{memory['id']}/auto-tags", json={})
+    assert again.status_code == 409
@@ -0,0 +1,77 @@
+"""Tests for off-site backup targets"""
+
+import httpx
+import pytest
+
+from app.services.backup_targets import decrypt, upload_snapshot, validate_target
+
+S3 = {
+    "type": "s3",
+    "bucket": "backups",
+    "prefix": "mory",
+    "region": "eu-west-1",
+    "access_key": "AKIDEXAMPLE",
+    "secret_key": "secret",
+}
+WEBDAV = {"type": "webdav", "url": "https://dav.example.com/files/me/", "username": "me"}
+
+
+def _recorder(status_code: int = 201):
+    requests: list[httpx.Request] = []
+
+    def handler(request: httpx.Request) -> httpx.Response:
+        requests.append(request)
+        return httpx.Response(status_code)
+
+    return requests, httpx.MockTransport(handler)
+
+
+def test_uploads_to_s3_and_webdav():
+    requests, transport = _recorder()
+    uploaded, errors = upload_snapshot(
+        "memories_1.db", b"snapshot", [S3, WEBDAV], transport=transport
+    )
+
+    assert errors == []
+    assert uploaded == [
+        "https://s3.eu-west-1.amazonaws.com/backups/mory/memories_1.db",
+        "https://dav.example.com/files/me/memories_1.db",
+    ]
+    s3_request, dav_request = requests
+    assert s3_request.method == "PUT" and s3_request.content == b"snapshot"
+    assert s3_request.headers["authorization"].startswith(
+        "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/"
+    )
+    assert "/eu-west-1/s3/aws4_request" in s3_request.headers["authorization"]
+    assert dav_request.headers["authorization"].startswith("Basic ")
+
+
+def test_failures_are_reported_per_target():
+    _, transport = _recorder(status_code=403)
+    uploaded, errors = upload_snapshot(
+        "memories_1.db", b"snapshot", [WEBDAV, {"type": "ftp"}], transport=transport
+    )
+    assert uploaded == []
+    assert len(errors) == 2
+    assert "unknown type 'ftp'" in errors[1]
+
+
+def test_validate_target():
+    assert validate_target(S3) is None
+    assert validate_target({"type": "gcs", "bucket": "b"}) == (
+        "gcs target is missing access_key, secret_key"
+    )
+
+
+def test_encrypted_upload():
+    fernet = pytest.importorskip("cryptography.fernet")
+    key = fernet.Fernet.generate_key().decode()
+    requests, transport = _recorder()
+
+    uploaded, _ = upload_snapshot(
+        "memories_1.db", b"snapshot", [WEBDAV], encryption_key=key, transport=transport
+    )
+
+    assert uploaded[0].endswith("memories_1.db.enc")
+    assert requests[0].content != b"snapshot"
+    assert decrypt(requests[0].content, key) == b"snapshot"
@@ -0,0 +1,18 @@
+"""Tests for the benchmark regression check"""
+
+from scripts.benchmark import compare
+
+
+def test_compare_flags_slowdowns_beyond_tolerance():
+    baseline = {
+        "sqlite/1000": {"save": {"median_ms": 2.0}, "search": {"median_ms": 10.0}},
+    }
+    results = {
+        "sqlite/1000": {"save": {"median_ms": 2.9}, "search": {"median_ms": 16.0}},
+        "files/1000": {"save": {"median_ms": 50.0}},  # Not in the baseline yet
+    }
+
+    regressions = compare(results, baseline, tolerance=0.5)
+
+    assert regressions == ["sqlite/1000 search: 16.00 ms (baseline 10.00 ms)"]
+    assert compare(results, baseline, tolerance=1.0) == []
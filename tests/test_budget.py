@@ -0,0 +1,43 @@
+"""Tests for the MCP response size budget"""
+
+import json
+
+from app.core.budget import TRUNCATION_MARK, fit_to_budget
+
+
+def _response(count: int, value_length: int = 100, total: int | None = None) -> dict:
+    memories = [{"id": f"m{i}", "value": "x" * value_length} for i in range(count)]
+    return {"memories": memories, "total": count if total is None else total}
+
+
+def test_small_response_is_untouched():
+    result = _response(3)
+    assert fit_to_budget(result, "memories", budget=10_000) is result
+
+
+def test_zero_budget_disables():
+    result = _response(50, value_length=5_000)
+    assert fit_to_budget(result, "memories", budget=0) is result
+
+
+def test_long_values_are_truncated_first():
+    fitted = fit_to_budget(_response(2, value_length=20_000), "memories", budget=5_000)
+    assert len(fitted["memories"]) == 2
+    assert all(m["value"].endswith(TRUNCATION_MARK) for m in fitted["memories"])
+    assert len(json.dumps(fitted, indent=2)) <= 5_000
+    assert "hint" not in fitted
+
+
+def test_items_dropped_with_hint():
+    fitted = fit_to_budget(_response(100, value_length=300, total=250), "memories", budget=3_000)
+    shown = len(fitted["memories"])
+    assert 1 <= shown < 100
+    assert len(json.dumps(fitted, indent=2)) <= 3_000 + len(fitted["hint"]) + 20
+    assert fitted["hint"].startswith(f"{250 - shown} more results")
+    assert fitted["memories"][0]["id"] == "m0"
+
+
+def test_at_least_one_item_is_kept():
+    fitted = fit_to_budget(_response(5, value_length=1_000), "memories", budget=10)
+    assert [m["id"] for m in fitted["memories"]] == ["m0"]
+    assert fitted["hint"].startswith("4 more results")
@@ -0,0 +1,198 @@
+"""Tests for bulk operations on filtered memories"""
+
+from datetime import datetime
+
+
+def seed(*memories):
+    """Insert (value, tags, created_at) memories; returns their IDs"""
+    from app.models.memory import Memory
+    from tests.conftest import TestingSessionLocal
+
+    db = TestingSessionLocal()
+    ids = []
+    for value, tags, created_at in memories:
+        memory = Memory(value=value, namespace="default", created_at=created_at)
+        memory.tags_list = tags
+        db.add(memory)
+        db.flush()
+        ids.append(memory.id)
+    db.commit()
+    db.close()
+    return ids
+
+
+class TestDeleteMemories:
+    """Tests for POST /api/memories/bulk/delete"""
+
+    def test_preview_then_delete(self, client, db_session):
+        """Only the previewed matches are deleted, and only with the token"""
+        imported = seed(
+            ("Imported note 1", ["reading", "imported"], datetime(2026, 10, 1, 9)),
+            ("Imported note 2", ["reading", "imported"], datetime(2026, 10, 1, 10)),
+        )
+        kept = seed(
+            ("Old imported note", ["reading", "imported"], datetime(2026, 9, 1)),
+            ("Own note", ["reading"], datetime(2026, 10, 1, 11)),
+        )
+        selection = {"tag": "imported", "date_from": "2026-10-01T00:00:00"}
+
+        preview = client.post("/api/memories/bulk/delete", json=selection).json()
+        assert preview["dry_run"] is True
+        assert preview["count"] == 2
+        assert preview["memory_ids"] == imported
+        assert client.get(f"/api/memories/{imported[0]}").status_code == 200
+        wildcard = {**selection, "tag": "import_d"}
+        assert client.post("/api/memories/bulk/delete", json=wildcard).json()["count"] == 0
+
+        response = client.post(
+            "/api/memories/bulk/delete", json={**selection, "dry_run": False}
+        )
+        assert response.status_code == 428
+
+        token = preview["confirmation_token"]
+        confirmed = {**selection, "dry_run": False, "confirmation_token": token}
+        response = client.post("/api/memories/bulk/delete", json=confirmed)
+        assert response.status_code == 200
+        assert response.json()["count"] == 2
+        for memory_id in imported:
+            assert client.get(f"/api/memories/{memory_id}").status_code == 404
+        for memory_id in kept:
+            assert client.get(f"/api/memories/{memory_id}").status_code == 200
+
+    def test_category_is_first_tag(self, client, db_session):
+        """category matches the first tag only; uncategorized matches untagged memories"""
+        ids = seed(
+            ("Kyoto trip", ["travel", "japan"], datetime(2026, 10, 1)),
+            ("Japan travel tips", ["japan", "travel"], datetime(2026, 10, 1)),
+            ("Loose note", [], datetime(2026, 10, 1)),
+        )
+
+        preview = client.post("/api/memories/bulk/delete", json={"category": "travel"}).json()
+        assert preview["memory_ids"] == [ids[0]]
+        preview = client.post(
+            "/api/memories/bulk/delete", json={"category": "uncategorized"}
+        ).json()
+        assert preview["memory_ids"] == [ids[2]]
+
+    def test_requires_a_condition(self, client, db_session):
+        """An empty filter never selects every memory"""
+        seed(("Anything", ["misc"], datetime(2026, 10, 1)))
+        response = client.post("/api/memories/bulk/delete", json={"dry_run": False})
+        assert response.status_code == 422
+
+    def test_changed_matches_invalidate_token(self, client, db_session):
+        """A memory matching after the preview makes the token stale"""
+        seed(("Draft 1", ["drafts"], datetime(2026, 10, 1)))
+        preview = client.post("/api/memories/bulk/delete", json={"tag": "drafts"}).json()
+        seed(("Draft 2", ["drafts"], datetime(2026, 10, 2)))
+
+        confirmed = {
+            "tag": "drafts",
+            "dry_run": False,
+            "confirmation_token": preview["confirmation_token"],
+        }
+        response = client.post("/api/memories/bulk/delete", json=confirmed)
+        assert response.status_code == 409
+        assert client.get("/api/memories").json()["total"] == 2
+
+
+def confirm(client, path, request):
+    """Preview a bulk operation, then run it with the preview's token"""
+    preview = client.post(path, json=request).json()
+    token = preview["confirmation_token"]
+    response = client.post(path, json={**request, "dry_run": False, "confirmation_token": token})
+    assert response.status_code == 200
+    return response.json()
+
+
+def tags_of(client, memory_id):
+    return client.get(f"/api/memories/{memory_id}").json()["tags"]
+
+
+class TestMoveMemories:
+    """Tests for POST /api/memories/bulk/move"""
+
+    def test_new_category_replaces_first_tag(self, client, db_session):
+        """The new category becomes the first tag; other tags are kept once"""
+        ids = seed(
+            ("Book notes", ["memo", "books"], datetime(2026, 10, 1)),
+            ("Reading list", ["memo", "reading"], datetime(2026, 10, 2)),
+            ("Groceries", ["shopping"], datetime(2026, 10, 3)),
+        )
+
+        result = confirm(
+            client, "/api/memories/bulk/move", {"category": "memo", "new_category": "reading"}
+        )
+        assert result["count"] == 2
+        assert tags_of(client, ids[0]) == ["reading", "books"]
+        assert tags_of(client, ids[1]) == ["reading"]
+        assert tags_of(client, ids[2]) == ["shopping"]
+
+    def test_logged_once_with_previous_tags(self, client, db_session):
+        """The whole move is one operation log entry"""
+        from app.models.operation_log import OperationLog
+        from tests.conftest import TestingSessionLocal
+
+        ids = seed(
+            ("Note 1", ["inbox"], datetime(2026, 10, 1)),
+            ("Note 2", [], datetime(2026, 10, 2)),
+        )
+        selection = {"date_from": "2026-10-01T00:00:00", "new_category": "work"}
+        confirm(client, "/api/memories/bulk/move", selection)
+
+        db = TestingSessionLocal()
+        entries = db.query(OperationLog).filter(OperationLog.operation == "moved").all()
+        db.close()
+        assert len(entries) == 1
+        details = entries[0].details_dict
+        assert details["memory_ids"] == ids
+        assert details["before"] == {ids[0]: ["inbox"], ids[1]: []}
+        assert tags_of(client, ids[1]) == ["work"]
+
+
+class TestRetagMemories:
+    """Tests for POST /api/memories/bulk/retag"""
+
+    def test_add_and_remove(self, client, db_session):
+        """Removed tags go, added tags are appended unless already present"""
+        ids = seed(
+            ("Imported 1", ["reading", "imported"], datetime(2026, 10, 1)),
+            ("Imported 2", ["reading", "imported", "books"], datetime(2026, 10, 2)),
+        )
+
+        result = confirm(
+            client,
+            "/api/memories/bulk/retag",
+            {"tag": "imported", "add_tags": ["books"], "remove_tags": ["imported"]},
+        )
+        assert result["count"] == 2
+        assert tags_of(client, ids[0]) == ["reading", "books"]
+        assert tags_of(client, ids[1]) == ["reading", "books"]
+
+    def test_rejects_conflicting_tags(self, client, db_session):
+        """A retag has to change something, and not add and remove the same tag"""
+        response = client.post("/api/memories/bulk/retag", json={"tag": "x"})
+        assert response.status_code == 422
+        response = client.post(
+            "/api/memories/bulk/retag", json={"tag": "x", "add_tags": ["y"], "remove_tags": ["y"]}
+        )
+        assert response.status_code == 422
+
+    def test_files_are_rewritten(self, client, db_session, tmp_path, monkeypatch):
+        """With the files backend, moved memories end up in their new category directory"""
+        from app.core.config import settings
+        from app.services.file_store import FileStore
+
+        monkeypatch.setattr(settings, "storage_backend", "files")
+        monkeypatch.setattr(settings, "files_dir", str(tmp_path))
+        memory = client.post("/api/memories", json={"value": "Misfiled note"}).json()
+        assert FileStore(tmp_path).path_for(memory["id"]).parent.name == "uncategorized"
+
+        confirm(
+            client,
+            "/api/memories/bulk/move",
+            {"category": "uncategorized", "new_category": "archive-2026"},
+        )
+        path = FileStore(tmp_path).path_for(memory["id"])
+        assert path.parent.name == "archive-2026"
+        assert '"archive-2026"' in path.read_text(encoding="utf-8")
@@ -0,0 +1,82 @@
+"""Tests for the Obsidian canvas export"""
+
+import json
+from datetime import datetime
+
+import numpy as np
+
+from app.models.memory import Memory
+from app.services.canvas import CARD_WIDTH, build_canvas, similarity_edges
+from tests.conftest import TestingSessionLocal
+
+
+def _vector(*values: float) -> bytes:
+    return np.array(values, dtype=np.float32).tobytes()
+
+
+def _memory(memory_id: str, tags: list[str], embedding: bytes | None = None) -> Memory:
+    value = f"Note {memory_id}"
+    return Memory(id=memory_id, value=value, tags=json.dumps(tags), embedding=embedding)
+
+
+def test_groups_cards_by_category():
+    memories = [
+        _memory("mem_a", ["reading", "books"]),
+        _memory("mem_b", ["travel"]),
+        _memory("mem_c", ["reading"]),
+    ]
+    canvas = build_canvas(memories)
+
+    groups = [node for node in canvas["nodes"] if node["type"] == "group"]
+    assert [group["label"] for group in groups] == ["reading", "travel"]
+    cards = {node["id"]: node for node in canvas["nodes"] if node["type"] == "text"}
+    assert set(cards) == {"mem_a", "mem_b", "mem_c"}
+    assert "#books" in cards["mem_a"]["text"] and "`mem_a`" in cards["mem_a"]["text"]
+
+    # Cards sit inside their group, side by side
+    reading, travel = groups
+    assert cards["mem_c"]["x"] == cards["mem_a"]["x"] + CARD_WIDTH + 40
+    for card in (cards["mem_a"], cards["mem_c"]):
+        assert reading["x"] < card["x"] < reading["x"] + reading["width"]
+        assert reading["y"] < card["y"] < reading["y"] + reading["height"]
+    assert travel["x"] > reading["x"] + reading["width"]
+    assert canvas["edges"] == []
+
+
+def test_edges_link_similar_memories():
+    memories = [
+        _memory("mem_a", ["travel"], _vector(1, 0)),
+        _memory("mem_b", ["travel"], _vector(0.95, 0.05)),
+        _memory("mem_c", ["cooking"], _vector(0, 1)),
+        _memory("mem_d", ["cooking"]),
+    ]
+    assert [(a, b) for a, b, _ in similarity_edges(memories)] == [("mem_a", "mem_b")]
+
+    edges = build_canvas(memories)["edges"]
+    assert len(edges) == 1
+    assert {edges[0]["fromNode"], edges[0]["toNode"]} == {"mem_a", "mem_b"}
+    assert float(edges[0]["label"]) > 0.99
+
+
+def test_canvas_endpoint(client, db_session):
+    db = TestingSessionLocal()
+    db.add(_memory("mem_a", ["reading"]))
+    db.add(_memory("mem_b", ["reading", "favorite"]))
+    db.add(_memory("mem_c", ["travel", "favorite"]))
+    archived = _memory("mem_d", ["reading"])
+    archived.archived_at = datetime(2024, 1, 1)
+    db.add(archived)
+    db.commit()
+    db.close()
+
+    result = client.get("/api/memories/canvas", params={"category": "reading"}).json()
+    assert result["count"] == 2
+    cards = [node["id"] for node in result["canvas"]["nodes"] if node["type"] == "text"]
+    assert sorted(cards) == ["mem_a", "mem_b"]
+
+    result = client.get("/api/memories/canvas", params={"tag": "favorite"}).json()
+    assert result["count"] == 2
+    result = client.get("/api/memories/canvas", params={"tag": "favo_ite"}).json()
+    assert result["count"] == 0
+
+    assert client.get("/api/memories/canvas").status_code == 400
@@ -0,0 +1,45 @@
+"""Tests for category descriptions and suggestions"""
+
+import json
+
+from app.models.memory import Memory
+from tests.conftest import TestingSessionLocal
+
+
+def test_describe_and_list_categories(client, db_session):
+    db = TestingSessionLocal()
+    db.add(Memory(id="mem_a", value="a", tags=json.dumps(["recipes", "dinner"])))
+    db.add(Memory(id="mem_b", value="b", tags=json.dumps(["recipes"])))
+    db.commit()
+    db.close()
+
+    response = client.put(
+        "/api/categories/travel", json={"description": "Trips, flights and hotel bookings"}
+    )
+    assert response.status_code == 200
+    assert response.json()["name"] == "travel"
+
+    categories = client.get("/api/categories").json()["categories"]
+    assert categories == [
+        {"name": "recipes", "memories": 2, "description": None},
+        {"name": "travel", "memories": 0, "description": "Trips, flights and hotel bookings"},
+    ]
+
+    assert client.delete("/api/categories/travel").status_code == 200
+    assert client.delete("/api/categories/travel").status_code == 404
+
+
+def test_keyword_suggestions_without_embeddings(client, db_session, monkeypatch):
+    from app.services.embedding import embedding_service
+
+    monkeypatch.setattr(embedding_service, "enabled", False)
+    client.put("/api/categories/travel", json={"description": "Trips, flights and hotels"})
+    client.put("/api/categories/health", json={"description": "Doctor visits and medication"})
+
+    response = client.post(
+        "/api/categories/suggest", json={"value": "Booked the hotel for the Kyoto trips"}
+    )
+    assert response.status_code == 200
+    result = response.json()
+    assert result["method"] == "keyword"
+    assert [suggestion["name"] for suggestion in result["suggestions"]] == ["travel"]
@@ -0,0 +1,59 @@
+"""Tests for Claude Desktop config installation"""
+
+import json
+
+import pytest
+
+from app.core.claude_desktop import default_config_path, install_server
+
+
+def test_default_config_path_per_os(monkeypatch, tmp_path):
+    """Config path follows each OS convention"""
+    monkeypatch.setenv("APPDATA", str(tmp_path / "appdata"))
+    monkeypatch.setenv("XDG_CONFIG_HOME", str(tmp_path / "xdg"))
+
+    assert "Application Support" in str(default_config_path("Darwin"))
+    assert default_config_path("Windows") == tmp_path / "appdata" / "Claude" / (
+        "claude_desktop_config.json"
+    )
+    assert default_config_path("Linux") == tmp_path / "xdg" / "Claude" / (
+        "claude_desktop_config.json"
+    )
+
+
+def test_install_creates_new_config(tmp_path):
+    """Missing config file is created with the mory entry"""
+    path = tmp_path / "Claude" / "claude_desktop_config.json"
+
+    written, backup = install_server(path, port=8081)
+
+    assert written == path
+    assert backup is None
+    config = json.loads(path.read_text())
+    assert config["mcpServers"]["mory"]["env"]["MORY_API_URL"] == "http://localhost:8081"
+
+
+def test_install_preserves_other_servers_and_backs_up(tmp_path):
+    """Existing servers are kept and the original file is backed up"""
+    path = tmp_path / "claude_desktop_config.json"
+    original = {"mcpServers": {"other": {"command": "other"}}, "theme": "dark"}
+    path.write_text(json.dumps(original))
+
+    _, backup = install_server(path)
+
+    config = json.loads(path.read_text())
+    assert config["mcpServers"]["other"] == {"command": "other"}
+    assert config["theme"] == "dark"
+    assert "mory" in config["mcpServers"]
+    assert json.loads(backup.read_text()) == original
+
+
+def test_install_rejects_invalid_json(tmp_path):
+    """Broken config files are not overwritten"""
+    path = tmp_path / "claude_desktop_config.json"
+    path.write_text("{not json")
+
+    with pytest.raises(ValueError):
+        install_server(path)
+
+    assert path.read_text() == "{not json"
@@ -0,0 +1,87 @@
+"""Tests for the mory-cli command line tool"""
+
+import json
+
+from app.cli import main
+from tests.conftest import TestingSessionLocal
+
+
+def run(argv, capsys):
+    code = main(argv, session_factory=TestingSessionLocal)
+    return code, capsys.readouterr().out
+
+
+def test_save_get_list_delete(db_session, capsys):
+    """Memories saved from the CLI can be read back and deleted"""
+    code, out = run(["save", "Remember the milk", "--tags", "shopping"], capsys)
+    assert code == 0
+    memory_id = out.strip()
+
+    code, out = run(["get", memory_id], capsys)
+    assert code == 0
+    data = json.loads(out)
+    assert data["value"] == "Remember the milk"
+    assert data["tags"] == ["shopping"]
+
+    code, out = run(["list"], capsys)
+    assert memory_id in out
+
+    code, _ = run(["delete", memory_id, "--yes"], capsys)
+    assert code == 0
+    code, _ = run(["get", memory_id], capsys)
+    assert code == 1
+
+
+def test_approve_and_reject(db_session, capsys):
+    """Pending memories are approved or discarded from the CLI"""
+    from app.models.memory import Memory
+
+    db = TestingSessionLocal()
+    db.add(Memory(id="mem_keep", value="Keep", review_status="pending"))
+    db.add(Memory(id="mem_drop", value="Drop", review_status="pending"))
+    db.commit()
+    db.close()
+
+    code, _ = run(["approve", "mem_keep"], capsys)
+    assert code == 0
+    code, out = run(["get", "mem_keep"], capsys)
+    assert json.loads(out)["review_status"] == "approved"
+    code, _ = run(["approve", "mem_keep"], capsys)
+    assert code == 1
+
+    code, _ = run(["reject", "mem_drop"], capsys)
+    assert code == 0
+    code, _ = run(["get", "mem_drop"], capsys)
+    assert code == 1
+
+
+def test_export(db_session, capsys, tmp_path):
+    """Export writes all memories of the namespace as JSON"""
+    run(["save", "First"], capsys)
+    run(["--namespace", "other", "save", "Second"], capsys)
+
+    output = tmp_path / "export.json"
+    code, _ = run(["export", "-o", str(output)], capsys)
+    assert code == 0
+    exported = json.loads(output.read_text(encoding="utf-8"))
+    assert [m["value"] for m in exported] == ["First"]
+
+
+def test_restore_refuses_non_empty_data_dir(capsys, tmp_path):
+    """Restore leaves a populated data directory alone"""
+    from app.services.snapshot import create_snapshot
+
+    source = tmp_path / "source"
+    source.mkdir()
+    (source / "notes.md").write_text("hello")
+    archive = tmp_path / "snap.tar.gz"
+    create_snapshot(source, None, archive)
+
+    code = main(["restore", str(archive), "--data-dir", str(source)])
+    assert code == 1
+    assert "not empty" in capsys.readouterr().err
+
+    target = tmp_path / "restored"
+    code = main(["restore", str(archive), "--data-dir", str(target)])
+    assert code == 0
+    assert (target / "notes.md").read_text() == "hello"
@@ -0,0 +1,34 @@
+"""Tests for the Python API client"""
+
+import pytest
+
+from app.client import MemoryNotFoundError, MoryClient
+
+
+def test_client_round_trip(client, db_session):
+    mory = MoryClient(http_client=client, namespace="blog")
+
+    saved = mory.save("Post idea: SQLite WAL internals", metadata={"status": "draft"})
+    assert saved.namespace == "blog"
+    assert saved.metadata == {"status": "draft"}
+
+    assert mory.get(saved.id).value == "Post idea: SQLite WAL internals"
+    listed = mory.list()
+    assert [memory.id for memory in listed.memories] == [saved.id]
+    assert mory.search("WAL", search_type="like").results[0].memory.id == saved.id
+
+    updated = mory.update(saved.id, value="Post idea: SQLite WAL checkpoints")
+    assert updated.value.endswith("checkpoints")
+    assert mory.archive(saved.id).archived_at is not None
+    assert mory.list().total == 0
+
+    mory.delete(saved.id)
+    with pytest.raises(MemoryNotFoundError) as error:
+        mory.get(saved.id)
+    assert error.value.status_code == 404
+
+
+def test_namespaces_are_kept_apart(client, db_session):
+    saved = MoryClient(http_client=client, namespace="work").save("Quarterly planning")
+    with pytest.raises(MemoryNotFoundError):
+        MoryClient(http_client=client, namespace="personal").get(saved.id)
@@ -0,0 +1,82 @@
+"""Tests for configuration hot reload"""
+
+import asyncio
+import logging
+import os
+
+import pytest
+
+from app.core.config import HOT_RELOAD_FIELDS, ConfigWatcher, reload_settings, settings
+
+
+@pytest.fixture
+def restore_settings():
+    """Restore hot-reloadable settings after each test"""
+    original = {name: getattr(settings, name) for name in HOT_RELOAD_FIELDS}
+    yield
+    settings.__dict__.update(original)
+
+
+def test_reload_applies_changed_env(monkeypatch, restore_settings):
+    """Changed environment values are applied to the global settings"""
+    monkeypatch.setenv("MORY_HYBRID_SEARCH_WEIGHT", "0.25")
+
+    changes = reload_settings()
+
+    assert changes == {"hybrid_search_weight": 0.25}
+    assert settings.hybrid_search_weight == 0.25
+
+
+def test_reload_ignores_non_reloadable_fields(monkeypatch, restore_settings):
+    """Settings outside HOT_RELOAD_FIELDS require a restart"""
+    original_port = settings.port
+    monkeypatch.setenv("MORY_PORT", str(original_port + 1))
+
+    changes = reload_settings()
+
+    assert "port" not in changes
+    assert settings.port == original_port
+
+
+def test_watcher_reloads_on_file_change(tmp_path, monkeypatch, restore_settings):
+    """Watcher only reloads when the watched file's mtime changes"""
+    env_file = tmp_path / ".env"
+    env_file.write_text("")
+    watcher = ConfigWatcher(env_file, interval=0)
+
+    assert watcher.check() == {}
+
+    monkeypatch.setenv("MORY_SEMANTIC_THRESHOLD", "0.4")
+    env_file.write_text("# touched\n")
+    stat = env_file.stat()
+    os.utime(env_file, (stat.st_atime, stat.st_mtime + 10))
+
+    assert watcher.check() == {"semantic_similarity_threshold": 0.4}
+
+
+async def test_watcher_logs_reloads_and_failures(tmp_path, caplog):
+    """Reloads are logged at INFO, a broken .env at WARNING, and polling carries on"""
+    outcomes = iter([{"hybrid_search_weight": 0.25}, ValueError("bad MORY_PORT")])
+
+    def check():
+        outcome = next(outcomes, {})
+        if isinstance(outcome, Exception):
+            raise outcome
+        return outcome
+
+    watcher = ConfigWatcher(tmp_path / ".env", interval=0.01)
+    watcher.check = check
+    caplog.set_level(logging.INFO, logger="app.core.config")
+    watcher.start()
+    try:
+        for _ in range(100):
+            await asyncio.sleep(0.01)
+            if len(caplog.records) >= 2:
+                break
+    finally:
+        await watcher.stop()
+
+    assert [(record.levelno, record.getMessage()) for record in caplog.records] == [
+        (logging.INFO, "Config reloaded: hybrid_search_weight"),
+        (logging.WARNING, "Config reload failed, keeping previous settings: bad MORY_PORT"),
+    ]
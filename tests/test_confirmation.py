@@ -0,0 +1,37 @@
+"""Tests for confirmation tokens of destructive bulk operations"""
+
+import pytest
+
+from app.core.confirmation import ConfirmationError, ConfirmationStore
+
+
+def test_token_is_single_use():
+    """A token returns the preview's data once, then is gone"""
+    store = ConfirmationStore()
+    token = store.issue("purge", {"ids": ["a", "b"]}, {"count": 2})
+
+    assert store.redeem(token, "purge", {"ids": ["a", "b"]}) == {"count": 2}
+    with pytest.raises(ConfirmationError):
+        store.redeem(token, "purge", {"ids": ["a", "b"]})
+
+
+def test_token_is_bound_to_operation_and_scope():
+    """A token does not confirm another operation or a changed set of memories"""
+    store = ConfirmationStore()
+
+    token = store.issue("purge", {"ids": ["a"]})
+    with pytest.raises(ConfirmationError):
+        store.redeem(token, "move", {"ids": ["a"]})
+
+    token = store.issue("purge", {"ids": ["a"]})
+    with pytest.raises(ConfirmationError):
+        store.redeem(token, "purge", {"ids": ["a", "c"]})
+
+
+def test_token_expires():
+    """Tokens older than the TTL are rejected"""
+    store = ConfirmationStore(ttl=0)
+    token = store.issue("purge", {})
+
+    with pytest.raises(ConfirmationError):
+        store.redeem(token, "purge", {})
@@ -0,0 +1,65 @@
+"""Tests for content types: detection, rendering and identifier boosts"""
+
+from app.models.memory import Memory
+from app.models.schemas import SearchRequest
+from app.services.content_types import detect_content_type, render_content
+from app.services.search import SearchService, query_identifiers
+from tests.conftest import TestingSessionLocal
+
+
+def test_detect_content_type():
+    assert detect_content_type('{"port": 8080}') == "json"
+    assert detect_content_type("def add(a, b):\n    return a + b") == "code:python"
+    assert detect_content_type("```go\nfunc main() {}\n```") == "code:go"
+    assert detect_content_type("# Plan\n\n- book hotel\n- buy tickets") == "markdown"
+    assert detect_content_type("Alice prefers tea over coffee.") == "text"
+
+
+def test_render_content():
+    assert render_content('{"a":1}', "json") == '```json\n{\n  "a": 1\n}\n```'
+    assert render_content("x = '```'", "code:python") == "````python\nx = '```'\n````"
+    assert render_content("plain", "text") is None
+
+
+def test_save_and_update_content_type(client, db_session):
+    saved = client.post("/api/memories", json={"value": '{"retries": 3}'}).json()
+    assert saved["content_type"] == "json"
+    assert saved["rendered"] == '```json\n{\n  "retries": 3\n}\n```'
+
+    updated = client.put(f"/api/memories/{saved['id']}", json={"value": "Retry three times"})
+    assert updated.json()["content_type"] == "text"
+    assert updated.json()["rendered"] is None
+
+    explicit = client.post(
+        "/api/memories", json={"value": "SELECT 1", "content_type": "code:sql"}
+    ).json()
+    assert explicit["rendered"] == "```sql\nSELECT 1\n```"
+
+    invalid = client.post("/api/memories", json={"value": "x", "content_type": "binary"})
+    assert invalid.status_code == 422
+
+
+def test_query_identifiers():
+    assert query_identifiers("where is parse_fts5_query used") == {"parse_fts5_query"}
+    assert query_identifiers("getUserById in os.path docs") == {"getUserById", "os.path"}
+    assert query_identifiers("Docker HTTP notes") == set()
+
+
+async def test_code_memories_with_identifier_rank_higher(db_session):
+    db = TestingSessionLocal()
+    # Without the boost the prose note ranks first: the match is at its very start
+    db.add(Memory(id="mem_prose", value="load_config reads the settings file at startup"))
+    db.add(
+        Memory(
+            id="mem_code",
+            value="def load_config(path):\n    return read(path)",
+            content_type="code:python",
+        )
+    )
+    db.commit()
+
+    response = await SearchService().search_memories(
+        SearchRequest(query="load_config", search_type="like"), db
+    )
+    assert [result.memory.id for result in response.results] == ["mem_code", "mem_prose"]
+    db.close()
@@ -0,0 +1,50 @@
+"""Tests for counting queries"""
+
+import json
+
+from app.models.memory import Memory
+from app.services.counts import (
+    count_memories,
+    count_with_embeddings,
+    dashboard_counts,
+    memory_exists,
+    tag_counts,
+)
+
+
+def _add(db, memory_id, tags, namespace="default", **fields):
+    db.add(
+        Memory(
+            id=memory_id, value=memory_id, namespace=namespace, tags=json.dumps(tags), **fields
+        )
+    )
+
+
+def test_counts(db_session):
+    _add(db_session, "mem_a", ["python", "work"], embedding=b"\x00" * 8)
+    _add(db_session, "mem_b", ["python"], review_status="pending")
+    _add(db_session, "mem_c", [], namespace="other")
+    db_session.add(Memory(id="mem_d", value="broken", namespace="other", tags="not json"))
+    db_session.commit()
+
+    assert count_memories(db_session) == 4
+    assert count_memories(db_session, "default") == 2
+    assert count_memories(db_session, "default", tag="python") == 2
+    assert count_memories(db_session, "default", tag="pyth_n") == 0
+    assert count_memories(db_session, "default", tag="pyth") == 0
+    assert count_with_embeddings(db_session) == 1
+
+    assert memory_exists(db_session, "mem_a")
+    assert not memory_exists(db_session, "mem_a", namespace="other")
+    assert not memory_exists(db_session, "mem_missing")
+
+    assert tag_counts(db_session, "default") == [("python", 2), ("work", 1)]
+    assert tag_counts(db_session, limit=1) == [("python", 2)]
+
+    assert dashboard_counts(db_session, "default") == {
+        "total_memories": 2,
+        "memories_with_embeddings": 1,
+        "ai_processed": 0,
+        "pending_review": 1,
+        "archived": 0,
+    }
@@ -0,0 +1,24 @@
+"""Tests for the web dashboard endpoints"""
+
+
+def test_dashboard_renders_stats_and_tags(client, db_session):
+    """The dashboard page shows embedding coverage and memories"""
+    client.post("/api/memories", json={"value": "Dashboard memory"})
+
+    response = client.get("/dashboard")
+    assert response.status_code == 200
+    assert "埋め込みカバレッジ" in response.text
+    assert "Dashboard memory" in response.text
+
+
+def test_dashboard_edit_and_search(client, db_session):
+    """Memories can be edited and searched from the dashboard"""
+    memory = client.post("/api/memories", json={"value": "Original text"}).json()
+
+    response = client.put(f"/dashboard/memories/{memory['id']}", json={"value": "Edited text"})
+    assert response.status_code == 200
+    assert response.json()["memory"]["value"] == "Edited text"
+
+    response = client.get("/dashboard/api/search", params={"q": "Edited", "search_type": "fts5"})
+    assert response.status_code == 200
+    assert [r["id"] for r in response.json()["results"]] == [memory["id"]]
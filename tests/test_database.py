@@ -0,0 +1,121 @@
+"""Tests for database engine setup and busy handling"""
+
+import sqlite3
+
+import pytest
+from sqlalchemy import text
+from sqlalchemy.exc import OperationalError
+from sqlalchemy.pool import QueuePool, StaticPool
+
+from app.core import database
+from app.core.database import build_engine, is_busy_error, retry_on_busy
+
+
+def _busy() -> OperationalError:
+    return OperationalError("COMMIT", {}, sqlite3.OperationalError("database is locked"))
+
+
+@pytest.fixture(autouse=True)
+def no_sleep(monkeypatch):
+    monkeypatch.setattr(database, "BUSY_RETRY_BASE_DELAY", 0)
+
+
+def test_file_databases_use_a_pool(tmp_path):
+    engine = build_engine(f"sqlite:///{tmp_path / 'pool.db'}")
+    assert isinstance(engine.pool, QueuePool)
+    with engine.connect() as conn:
+        assert conn.execute(text("PRAGMA busy_timeout")).scalar() > 0
+    engine.dispose()
+
+    assert isinstance(build_engine("sqlite:///:memory:").pool, StaticPool)
+
+
+def test_retry_on_busy_retries_until_success():
+    calls = []
+
+    @retry_on_busy
+    def unit_of_work():
+        calls.append(1)
+        if len(calls) < 3:
+            raise _busy()
+        return "done"
+
+    assert unit_of_work() == "done"
+    assert len(calls) == 3
+
+
+async def test_retry_on_busy_gives_up(monkeypatch):
+    monkeypatch.setattr(database.settings, "db_busy_retries", 2)
+    calls = []
+
+    @retry_on_busy
+    async def unit_of_work():
+        calls.append(1)
+        raise _busy()
+
+    with pytest.raises(OperationalError):
+        await unit_of_work()
+    assert len(calls) == 3
+
+
+def test_other_errors_are_not_retried():
+    calls = []
+
+    @retry_on_busy
+    def unit_of_work():
+        calls.append(1)
+        raise OperationalError("SELECT", {}, sqlite3.OperationalError("no such table: x"))
+
+    with pytest.raises(OperationalError):
+        unit_of_work()
+    assert len(calls) == 1
+    assert not is_busy_error(OperationalError("SELECT", {}, Exception("no such table")))
+
+
+def test_compact_database_reclaims_space(tmp_path):
+    engine = build_engine(f"sqlite:///{tmp_path / 'compact.db'}")
+    with engine.begin() as conn:
+        conn.execute(text("CREATE TABLE blobs (data TEXT)"))
+        for _ in range(200):
+            conn.execute(text("INSERT INTO blobs VALUES (:data)"), {"data": "x" * 4000})
+    with engine.begin() as conn:
+        conn.execute(text("DELETE FROM blobs"))
+
+    report = database.compact_database(engine)
+    engine.dispose()
+
+    assert report["reclaimed"] > 0
+    assert report["size_after"] < report["size_before"]
+
+
+def _fts_triggers(db) -> set[str]:
+    rows = db.execute(text("SELECT name FROM sqlite_master WHERE type = 'trigger'"))
+    return {name for (name,) in rows} & set(database.FTS_TRIGGERS)
+
+
+def test_bulk_load_rebuilds_fts_index_once(db_session, monkeypatch):
+    from app.models.memory import Memory
+    from tests.conftest import TestingSessionLocal, engine
+
+    if not database.create_fts5_table(engine):
+        pytest.skip("SQLite build without FTS5")
+    monkeypatch.setattr(database, "BULK_FTS_THRESHOLD", 2)
+
+    db = TestingSessionLocal()
+    with database.bulk_load(db, rows=3):
+        assert _fts_triggers(db) == set()
+        for i in range(3):
+            db.add(Memory(id=f"mem_bulk{i}", value=f"bulk loaded walrus {i}"))
+
+    assert _fts_triggers(db) == set(database.FTS_TRIGGERS)
+    hits = db.execute(text("SELECT rowid FROM memories_fts WHERE memories_fts MATCH 'walrus'"))
+    assert len(hits.all()) == 3
+
+    # A failed load keeps the triggers
+    with pytest.raises(RuntimeError):
+        with database.bulk_load(db, rows=3):
+            db.add(Memory(id="mem_lost", value="never stored"))
+            raise RuntimeError("import failed")
+    assert _fts_triggers(db) == set(database.FTS_TRIGGERS)
+    assert db.get(Memory, "mem_lost") is None
+    db.close()
@@ -0,0 +1,55 @@
+"""Tests for configuration diagnostics"""
+
+from app.core.config import Settings
+from app.core.diagnostics import check_obsidian_vault, format_report, run_config_checks
+
+
+def make_settings(**overrides) -> Settings:
+    """Create settings without reading the environment"""
+    values = {
+        "MORY_DATA_DIR": "data",
+        "OPENAI_API_KEY": None,
+        "MORY_OBSIDIAN_VAULT_PATH": None,
+    }
+    values.update(overrides)
+    return Settings(_env_file=None, **values)
+
+
+def test_valid_configuration_passes(tmp_path):
+    """A writable data dir and no optional integrations has no errors"""
+    config = make_settings(MORY_DATA_DIR=str(tmp_path))
+
+    results = run_config_checks(config)
+
+    assert not any(result.is_error for result in results)
+    assert {result.name for result in results} >= {"data_dir", "database", "obsidian_vault"}
+
+
+def test_missing_obsidian_vault_is_error(tmp_path):
+    """A configured but missing vault blocks startup"""
+    config = make_settings(MORY_OBSIDIAN_VAULT_PATH=str(tmp_path / "missing"))
+
+    result = check_obsidian_vault(config)
+
+    assert result.is_error
+    assert "MORY_OBSIDIAN_VAULT_PATH" in result.message
+
+
+def test_missing_openai_key_is_warning(tmp_path):
+    """Missing OpenAI key only degrades semantic search"""
+    config = make_settings(MORY_DATA_DIR=str(tmp_path))
+
+    openai_result = next(r for r in run_config_checks(config) if r.name == "openai")
+
+    assert not openai_result.ok
+    assert not openai_result.is_error
+
+
+def test_invalid_hybrid_weight_reported(tmp_path):
+    """Out-of-range hybrid weight is reported in the report"""
+    config = make_settings(MORY_DATA_DIR=str(tmp_path), MORY_HYBRID_SEARCH_WEIGHT=1.5)
+
+    report = format_report(run_config_checks(config))
+
+    assert "MORY_HYBRID_SEARCH_WEIGHT" in report
+    assert "1 error(s)" in report
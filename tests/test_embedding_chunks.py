@@ -0,0 +1,92 @@
+"""Tests for chunked embeddings of long memories"""
+
+from types import SimpleNamespace
+
+import numpy as np
+
+from app.core.config import settings
+from app.models.memory import Memory
+from app.models.memory_chunk import MemoryChunk
+from app.models.schemas import SearchRequest
+from app.services import embedding as embedding_module
+from app.services.embedding import embedding_service, split_chunks
+from app.services.search import SearchService
+from tests.conftest import TestingSessionLocal
+
+
+def _fake_openai(vector_for):
+    """Stand-in for the openai module answering embeddings.create with vector_for(text)"""
+
+    def create(model, input):
+        texts = input if isinstance(input, list) else [input]
+        data = [
+            SimpleNamespace(index=index, embedding=vector_for(text))
+            for index, text in enumerate(texts)
+        ]
+        return SimpleNamespace(data=data)
+
+    return SimpleNamespace(embeddings=SimpleNamespace(create=create))
+
+
+def test_split_chunks_cover_text_at_breaks():
+    text = "\n\n".join(f"Paragraph {i} " + "word " * 30 for i in range(20))
+    chunks = split_chunks(text, 500, overlap=50)
+
+    assert len(chunks) > 1
+    assert all(len(chunk) <= 500 for _, chunk in chunks)
+    for start, chunk in chunks:
+        assert text[start : start + len(chunk)] == chunk
+    assert chunks[-1][0] + len(chunks[-1][1]) == len(text)
+    # Cut after a paragraph break, then back up by the overlap
+    assert chunks[0][1].endswith("\n\n")
+    assert chunks[1][0] == len(chunks[0][1]) - 50
+
+    assert split_chunks("short", 500) == [(0, "short")]
+    assert [start for start, _ in split_chunks("あ" * 250, 100)] == [0, 75, 150]
+
+
+async def test_long_memory_embedded_in_chunks(client, db_session, monkeypatch):
+    monkeypatch.setattr(settings, "embedding_chunk_size", 100)
+    monkeypatch.setattr(embedding_service, "enabled", True)
+    monkeypatch.setattr(embedding_module, "openai", _fake_openai(lambda text: [1.0, 0.0]))
+
+    db = TestingSessionLocal()
+    memory = Memory(id="mem_long", value="sentence. " * 40)
+    db.add(memory)
+    assert await embedding_service.generate_embedding_for_memory(memory)
+    db.commit()
+    chunks = db.query(MemoryChunk).filter_by(memory_id="mem_long").count()
+    assert chunks == len(split_chunks(memory.value, 100)) > 1
+    assert np.allclose(np.frombuffer(memory.embedding, dtype=np.float32), [1.0, 0.0])
+
+    # Shortened below the chunk size: back to a single vector
+    memory.value = "short now"
+    assert await embedding_service.generate_embedding_for_memory(memory)
+    db.commit()
+    assert db.query(MemoryChunk).filter_by(memory_id="mem_long").count() == 0
+    db.close()
+
+
+async def test_semantic_search_scores_best_chunk(client, db_session, monkeypatch):
+    monkeypatch.setattr(settings, "embedding_chunk_size", 100)
+    monkeypatch.setattr(embedding_service, "enabled", True)
+    # Only the chunk about kyoto points the same way as the query
+    vector_for = lambda text: [1.0, 0.0] if "kyoto" in text else [0.0, 1.0]  # noqa: E731
+    monkeypatch.setattr(embedding_module, "openai", _fake_openai(vector_for))
+
+    db = TestingSessionLocal()
+    memory = Memory(id="mem_trip", value="other notes. " * 30 + "\n\nkyoto temples")
+    db.add(memory)
+    await embedding_service.generate_embedding_for_memory(memory)
+    db.commit()
+
+    service = SearchService()
+    service.semantic_available = True
+    results, total = await service._search_semantic(
+        SearchRequest(query="kyoto", search_type="semantic"), db
+    )
+    db.close()
+
+    assert total == 1
+    assert results[0].memory.id == "mem_trip"
+    assert results[0].score > 0.99  # The mean vector alone would score lower
@@ -0,0 +1,47 @@
+"""Tests for the internal event bus"""
+
+import pytest
+
+from app.core.events import MEMORY_DELETED, MEMORY_SAVED, EventBus, MemoryEvent
+from app.models.operation_log import OperationLog
+from tests.conftest import TestingSessionLocal
+
+
+async def test_publish_runs_sync_and_async_handlers_in_order():
+    """Handlers run in subscription order; a failing handler does not stop the rest"""
+    bus = EventBus()
+    calls = []
+
+    @bus.on(MEMORY_SAVED)
+    def first(event):
+        calls.append("first")
+        raise RuntimeError("boom")
+
+    @bus.on(MEMORY_SAVED, MEMORY_DELETED)
+    async def second(event):
+        calls.append(f"second:{event.type}")
+
+    await bus.publish(MemoryEvent(MEMORY_SAVED, memory=None))
+    await bus.publish(MemoryEvent(MEMORY_DELETED, memory=None))
+
+    assert calls == ["first", "second:saved", "second:deleted"]
+
+
+def test_unknown_event_type():
+    with pytest.raises(ValueError):
+        EventBus().subscribe("exploded", lambda event: None)
+
+
+def test_api_changes_are_recorded_in_operation_log(client, db_session):
+    """The operation log subscriber records saves and deletes"""
+    memory_id = client.post("/api/memories", json={"value": "Logged memory"}).json()["id"]
+    client.delete(f"/api/memories/{memory_id}")
+
+    with TestingSessionLocal() as db:
+        operations = [
+            entry.operation
+            for entry in db.query(OperationLog)
+            .filter(OperationLog.memory_id == memory_id)
+            .order_by(OperationLog.id)
+        ]
+    assert operations == ["saved", "deleted"]
@@ -0,0 +1,164 @@
+"""Tests for the markdown files backend"""
+
+import pytest
+
+from app.core.config import settings
+from app.models.memory import Memory
+from app.services.file_store import FileStore, parse, render
+
+
+@pytest.fixture
+def files_backend(tmp_path, monkeypatch):
+    monkeypatch.setattr(settings, "storage_backend", "files")
+    monkeypatch.setattr(settings, "files_dir", str(tmp_path))
+    return FileStore(tmp_path)
+
+
+def test_render_parse_roundtrip():
+    record = {
+        "id": "mem_1",
+        "tags": ["a", "b"],
+        "metadata": {"k": "v"},
+        "value": "Line 1\n\nLine 2",
+    }
+    assert parse(render(record)) == record
+
+    handwritten = "---\ntitle: Plain YAML\ntags: [work, idea]\n---\nBody"
+    assert parse(handwritten) == {"title": "Plain YAML", "tags": "[work, idea]", "value": "Body"}
+
+
+async def test_files_follow_api_changes_and_edits(client, db_session, files_backend):
+    response = client.post("/api/memories", json={"value": "Written through the API"})
+    memory_id = response.json()["id"]
+    path = files_backend.path_for(memory_id)
+    assert path.parent.name == "uncategorized"
+    assert "Written through the API" in path.read_text(encoding="utf-8")
+
+    # Edit the file by hand and load it back
+    store = FileStore(files_backend.root)
+    record = parse(path.read_text(encoding="utf-8"))
+    record["value"] = "Edited in an editor"
+    path.write_text(render(record), encoding="utf-8")
+
+    result = await store.sync_to_database(db_session)
+    assert result.updated == 1
+    assert db_session.get(Memory, memory_id).value == "Edited in an editor"
+
+    # Deleting the file deletes the memory
+    path.unlink()
+    result = await FileStore(files_backend.root).sync_to_database(db_session)
+    assert result.deleted == 1
+    db_session.expire_all()
+    assert db_session.get(Memory, memory_id) is None
+
+
+async def test_handwritten_note_is_imported(db_session, files_backend):
+    files_backend.export_all(db_session)  # Creates the index
+    note = files_backend.root / "ideas" / "new-note.md"
+    note.parent.mkdir()
+    note.write_text("---\ntags: [ideas, later]\n---\nA note written in Obsidian", encoding="utf-8")
+
+    result = await FileStore(files_backend.root).sync_to_database(db_session)
+
+    assert result.created == 1
+    memory = db_session.query(Memory).one()
+    assert memory.value == "A note written in Obsidian"
+    assert memory.tags_list == ["ideas", "later"]
+    assert parse(note.read_text(encoding="utf-8"))["id"] == memory.id
+
+
+def test_stores_sharing_a_directory_keep_each_others_entries(files_backend):
+    """A second process's store (e.g. mory-cli) must not drop entries from index.json"""
+    other = FileStore(files_backend.root)
+    files_backend.write({"id": "mem_server", "tags": [], "value": "From the server"})
+    assert other.index.keys() == {"mem_server"}
+
+    files_backend.write({"id": "mem_server2", "tags": [], "value": "Written meanwhile"})
+    other.write({"id": "mem_cli", "tags": ["notes"], "value": "From the CLI"})
+    assert FileStore(files_backend.root).index.keys() == {"mem_server", "mem_server2", "mem_cli"}
+
+    files_backend.remove("mem_server")
+    assert FileStore(files_backend.root).index.keys() == {"mem_server2", "mem_cli"}
+
+
+async def test_templates_and_ignored_notes_are_not_imported(db_session, files_backend):
+    files_backend.export_all(db_session)
+    templates = files_backend.root / "Templates"
+    templates.mkdir()
+    (templates / "daily.md").write_text("# {{date}}\n\n## Tasks", encoding="utf-8")
+    draft = files_backend.root / "ideas" / "draft.md"
+    draft.parent.mkdir()
+    draft.write_text("---\nmory: ignore\n---\nNot for Mory", encoding="utf-8")
+
+    result = await FileStore(files_backend.root).sync_to_database(db_session)
+
+    assert result.created == 0
+    assert db_session.query(Memory).count() == 0
+    assert "id:" not in draft.read_text(encoding="utf-8")
+
+
+def _file_record(files_backend, memory_id):
+    path = FileStore(files_backend.root).path_for(memory_id)
+    return parse(path.read_text(encoding="utf-8")) if path else None
+
+
+def test_files_follow_approval_and_rejection(client, db_session, files_backend, monkeypatch):
+    monkeypatch.setattr(settings, "require_approval", True)
+    tool = {"X-Mory-Tool": "save_memory"}
+    kept = client.post("/api/memories", json={"value": "Kept guess"}, headers=tool).json()
+    wrong = client.post("/api/memories", json={"value": "Wrong guess"}, headers=tool).json()
+    assert _file_record(files_backend, kept["id"])["review_status"] == "pending"
+
+    monkeypatch.setattr(settings, "approval_token", "secret")
+    client.post(f"/api/memories/{kept['id']}/approve", headers={"X-Mory-Approval-Token": "secret"})
+    assert _file_record(files_backend, kept["id"])["review_status"] == "approved"
+
+    path = FileStore(files_backend.root).path_for(wrong["id"])
+    client.post(f"/api/memories/{wrong['id']}/reject")
+    assert not path.exists()
+    assert _file_record(files_backend, wrong["id"]) is None
+
+
+def test_files_follow_archiving(client, db_session, files_backend):
+    memory_id = client.post("/api/memories", json={"value": "Archived note"}).json()["id"]
+
+    client.post(f"/api/memories/{memory_id}/archive")
+    assert _file_record(files_backend, memory_id)["archived_at"]
+
+    client.post(f"/api/memories/{memory_id}/unarchive")
+    assert _file_record(files_backend, memory_id)["archived_at"] is None
+
+
+def test_files_follow_summarized_originals(client, db_session, files_backend, monkeypatch):
+    from app.services.summarization import summarization_service
+
+    monkeypatch.setattr(summarization_service, "enabled", False)
+    originals = [Memory(value=f"Homelab note {i}", tags='["homelab"]') for i in range(2)]
+    db_session.add_all(originals)
+    db_session.commit()
+    ids = [memory.id for memory in originals]
+    files_backend.export_all(db_session)
+    assert _file_record(files_backend, ids[0])["archived_at"] is None
+
+    preview = client.post("/api/memories/summarize", json={"tag": "homelab"}).json()
+    token = preview["confirmation_token"]
+    confirmed = {"tag": "homelab", "dry_run": False, "confirmation_token": token}
+    assert client.post("/api/memories/summarize", json=confirmed).status_code == 200
+
+    for memory_id in ids:
+        assert _file_record(files_backend, memory_id)["archived_at"]
+
+
+def test_files_follow_verification_and_reminders(client, db_session, files_backend):
+    memory = client.post(
+        "/api/memories",
+        json={"value": "Maybe true", "confidence": 0.5, "remind_at": "2020-01-01T00:00:00Z"},
+    ).json()
+
+    client.post(f"/api/memories/{memory['id']}/verify")
+    record = _file_record(files_backend, memory["id"])
+    assert record["verified"] is True
+    assert record["confidence"] == 1.0
+
+    client.post(f"/api/memories/{memory['id']}/acknowledge")
+    assert _file_record(files_backend, memory["id"])["remind_at"] is None
@@ -0,0 +1,49 @@
+"""Tests for crash-safe file helpers"""
+
+import threading
+from unittest.mock import patch
+
+import pytest
+
+from app.core.fileutil import atomic_write_text, file_lock
+
+
+def test_atomic_write_creates_and_replaces(tmp_path):
+    """Writes new files and replaces existing ones"""
+    target = tmp_path / "nested" / "config.json"
+
+    atomic_write_text(target, "first")
+    atomic_write_text(target, "second")
+
+    assert target.read_text() == "second"
+    assert [p.name for p in target.parent.iterdir()] == ["config.json"]
+
+
+def test_failed_write_keeps_original(tmp_path):
+    """A failure before rename leaves the original file intact"""
+    target = tmp_path / "config.json"
+    target.write_text("original")
+
+    with patch("app.core.fileutil.os.replace", side_effect=OSError("disk full")):
+        with pytest.raises(OSError):
+            atomic_write_text(target, "new content")
+
+    assert target.read_text() == "original"
+    assert [p.name for p in tmp_path.iterdir()] == ["config.json"]
+
+
+def test_file_lock_waits_for_the_holder(tmp_path):
+    """A second holder blocks until the first releases the lock"""
+    lock_path = tmp_path / "store.lock"
+    acquired = threading.Event()
+
+    def contender():
+        with file_lock(lock_path):
+            acquired.set()
+
+    with file_lock(lock_path):
+        thread = threading.Thread(target=contender)
+        thread.start()
+        assert not acquired.wait(0.2)
+    thread.join(timeout=5)
+    assert acquired.is_set()
@@ -0,0 +1,69 @@
+"""Tests for git-backed versioning"""
+
+import json
+import shutil
+import subprocess
+
+import pytest
+
+from app.models.memory import Memory
+from app.services.git_store import GitMirror, commit_message
+
+pytestmark = pytest.mark.skipif(shutil.which("git") is None, reason="git not installed")
+
+
+def _log(repo) -> list[str]:
+    result = subprocess.run(
+        ["git", "log", "--format=%s"], cwd=repo, capture_output=True, text=True, check=True
+    )
+    return result.stdout.splitlines()
+
+
+def _memory(value: str) -> Memory:
+    memory = Memory(id="mem_git00001", value=value, summary=None)
+    memory.tags_list = ["notes"]
+    return memory
+
+
+def test_commits_each_change(tmp_path):
+    mirror = GitMirror(tmp_path / "repo")
+    memory = _memory("First line\nsecond line")
+
+    assert mirror.record("saved", memory, agent_id="claude")
+    stored = json.loads(mirror.memory_path(memory.id).read_text(encoding="utf-8"))
+    assert stored["value"] == "First line\nsecond line"
+    assert stored["tags"] == ["notes"]
+
+    # Re-recording identical content makes no empty commit
+    assert not mirror.record("updated", memory)
+
+    memory.value = "Changed"
+    assert mirror.record("updated", memory)
+    assert mirror.record("deleted", memory)
+    assert not mirror.memory_path(memory.id).exists()
+
+    assert _log(tmp_path / "repo") == [
+        "Delete mem_git00001: Changed",
+        "Update mem_git00001: Changed",
+        "Add mem_git00001: First line",
+    ]
+
+
+def test_commit_message():
+    memory = _memory("x" * 100)
+    message = commit_message("saved", memory, agent_id="claude")
+    subject, body = message.split("\n\n")
+    assert subject == f"Add mem_git00001: {'x' * 59}…"
+    assert body == "Tags: notes\nAgent: claude"
+
+
+def test_snapshot_removes_stale_files(tmp_path, db_session):
+    mirror = GitMirror(tmp_path / "repo")
+    mirror.record("saved", _memory("Gone from the database"))
+    db_session.add(Memory(id="mem_git00002", value="Still here"))
+    db_session.commit()
+
+    assert mirror.snapshot(db_session) == 1
+    files = sorted(path.name for path in (tmp_path / "repo" / "memories").iterdir())
+    assert files == ["mem_git00002.json"]
+    assert _log(tmp_path / "repo")[0] == "Snapshot of 1 memories"
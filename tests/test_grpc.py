@@ -0,0 +1,53 @@
+"""Tests for the optional gRPC listener"""
+
+import socket
+
+import pytest
+
+pytest.importorskip("grpc_tools")
+
+import grpc  # noqa: E402
+
+from app.grpc_server import load_protos, start_grpc_server  # noqa: E402
+from tests.conftest import TestingSessionLocal  # noqa: E402
+
+
+def _free_port() -> int:
+    with socket.socket() as sock:
+        sock.bind(("127.0.0.1", 0))
+        return sock.getsockname()[1]
+
+
+async def test_grpc_save_get_search_delete(db_session):
+    """Memory operations over gRPC share the REST handlers and storage"""
+    protos, services = load_protos()
+    port = _free_port()
+    server = await start_grpc_server(port, host="127.0.0.1", session_factory=TestingSessionLocal)
+
+    try:
+        async with grpc.aio.insecure_channel(f"127.0.0.1:{port}") as channel:
+            stub = services.MemoryServiceStub(channel)
+
+            saved = await stub.SaveMemory(protos.SaveMemoryRequest(value="gRPC homelab note"))
+            assert saved.id.startswith("mem_")
+
+            fetched = await stub.GetMemory(protos.GetMemoryRequest(id=saved.id))
+            assert fetched.value == "gRPC homelab note"
+
+            listed = await stub.ListMemories(protos.ListMemoriesRequest())
+            assert listed.total == 1
+
+            results = [
+                result
+                async for result in stub.Search(
+                    protos.SearchRequest(query="homelab", search_type="fts5")
+                )
+            ]
+            assert [r.memory.id for r in results] == [saved.id]
+
+            await stub.DeleteMemory(protos.DeleteMemoryRequest(id=saved.id))
+            with pytest.raises(grpc.aio.AioRpcError) as excinfo:
+                await stub.GetMemory(protos.GetMemoryRequest(id=saved.id))
+            assert excinfo.value.code() == grpc.StatusCode.NOT_FOUND
+    finally:
+        await server.stop(None)
@@ -58,3 +58,24 @@ def test_detailed_health_check():
     assert "host" in config
     assert "port" in config
     assert "debug" in config
+
+
+def test_selftest_passes_with_working_database(db_session):
+    """Self-test reports pass for database checks"""
+    response = client.get("/api/health/selftest")
+    assert response.status_code == 200
+
+    data = response.json()
+    assert data["status"] == "pass"
+    checks = {check["name"]: check for check in data["checks"]}
+    assert checks["database_connectivity"]["status"] == "pass"
+    assert checks["database_write"]["status"] == "pass"
+    assert "vector_store" in checks
+
+
+def test_selftest_write_probe_is_rolled_back(db_session):
+    """Write probe does not leave a memory behind"""
+    client.get("/api/health/selftest")
+
+    response = client.get("/api/memories")
+    assert response.json()["total"] == 0
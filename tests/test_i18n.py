@@ -0,0 +1,50 @@
+"""Tests for MCP tool message catalogs"""
+
+import string
+
+import pytest
+
+from app.core.config import Settings, settings
+from app.core.diagnostics import check_locale
+from app.core.i18n import MESSAGES, translate
+
+
+def test_catalogs_have_the_same_keys():
+    english = set(MESSAGES["en"])
+    for locale, catalog in MESSAGES.items():
+        assert set(catalog) == english, locale
+
+
+def test_catalog_placeholders_match_english():
+    def fields(text):
+        return {name for _, name, _, _ in string.Formatter().parse(text) if name}
+
+    for key, text in MESSAGES["en"].items():
+        assert fields(MESSAGES["ja"][key]) == fields(text), key
+
+
+def test_translate_uses_configured_locale(monkeypatch):
+    monkeypatch.setattr(settings, "locale", "ja")
+    assert translate("memory_not_found", key="abc") == "キー 'abc' のメモリが見つかりません"
+
+    monkeypatch.setattr(settings, "locale", "en")
+    assert translate("memory_not_found", key="abc") == "Memory with key 'abc' not found"
+
+
+def test_http_errors_are_translated(monkeypatch):
+    monkeypatch.setattr(settings, "locale", "ja")
+    assert translate("http_error", status=500, detail="boom") == "HTTPエラー 500: boom"
+    assert "http://localhost:8080" in translate("server_unreachable", url="http://localhost:8080")
+
+
+@pytest.mark.parametrize("locale", ["fr", ""])
+def test_unknown_locale_falls_back_to_english(monkeypatch, locale):
+    monkeypatch.setattr(settings, "locale", locale)
+    assert translate("unknown_tool", name="x") == "Unknown tool: x"
+
+
+def test_check_locale():
+    assert check_locale(Settings(_env_file=None, MORY_LOCALE="ja")).ok
+    result = check_locale(Settings(_env_file=None, MORY_LOCALE="fr"))
+    assert not result.ok
+    assert result.severity == "warning"
@@ -0,0 +1,471 @@
+"""Tests for importing ChatGPT, Claude and Notion data"""
+
+import json
+import zipfile
+
+import httpx
+
+from app.cli import main
+from app.models.memory import Memory
+from app.services.importers import (
+    MailImporter,
+    NotionImporter,
+    apple_notes,
+    chatgpt,
+    claude,
+    ics,
+    import_memories,
+    joplin,
+    logseq,
+)
+from app.services.importers.base import ImportedMemory, category_tag
+from tests.conftest import TestingSessionLocal
+
+CHATGPT_CONVERSATION = {
+    "id": "c-1",
+    "title": "Trip to Kyoto",
+    "create_time": 1717200000.0,
+    "current_node": "n3",
+    "mapping": {
+        "root": {"message": None, "parent": None},
+        "n1": {
+            "message": {"author": {"role": "user"}, "content": {"parts": ["Where to stay?"]}},
+            "parent": "root",
+        },
+        "n2-old": {
+            "message": {"author": {"role": "assistant"}, "content": {"parts": ["Old answer"]}},
+            "parent": "n1",
+        },
+        "n2": {
+            "message": {"author": {"role": "assistant"}, "content": {"parts": ["Gion is nice"]}},
+            "parent": "n1",
+        },
+        "n3": {
+            "message": {"author": {"role": "system"}, "content": {"parts": ["hidden"]}},
+            "parent": "n2",
+        },
+    },
+}
+
+
+def test_category_tag():
+    assert category_tag("Trip to Kyoto!", "x") == "trip-to-kyoto"
+    assert category_tag("京都 旅行", "x") == "京都-旅行"
+    assert category_tag("  ", "chatgpt") == "chatgpt"
+
+
+def test_chatgpt_follows_current_branch(tmp_path):
+    path = tmp_path / "conversations.json"
+    path.write_text(json.dumps([CHATGPT_CONVERSATION]))
+
+    [item] = list(chatgpt.parse(path))
+    assert item.source == "chatgpt:c-1"
+    assert item.tags == ["trip-to-kyoto", "chatgpt"]
+    assert item.value == "Trip to Kyoto\n\nUser: Where to stay?\n\nAssistant: Gion is nice"
+    assert item.metadata["source_url"] == "https://chatgpt.com/c/c-1"
+    assert item.created_at.year == 2024
+
+
+def test_claude_export_zip(tmp_path):
+    projects = [
+        {
+            "uuid": "p-1",
+            "name": "Home Renovation",
+            "description": "Kitchen remodel",
+            "prompt_template": "",
+            "docs": [{"uuid": "d-1", "filename": "budget.md", "content": "Total: 2M yen"}],
+        }
+    ]
+    conversations = [
+        {
+            "uuid": "c-9",
+            "name": "Tile choices",
+            "created_at": "2024-06-01T09:00:00+09:00",
+            "chat_messages": [
+                {"sender": "human", "text": "Which tile?"},
+                {"sender": "assistant", "text": "", "content": [{"type": "text", "text": "Matte"}]},
+            ],
+        }
+    ]
+    archive = tmp_path / "export.zip"
+    with zipfile.ZipFile(archive, "w") as zf:
+        zf.writestr("data/projects.json", json.dumps(projects))
+        zf.writestr("data/conversations.json", json.dumps(conversations))
+
+    items = {item.source: item for item in claude.parse(archive)}
+    assert set(items) == {"claude:project:p-1", "claude:doc:d-1", "claude:conversation:c-9"}
+    assert items["claude:doc:d-1"].tags == ["home-renovation", "claude"]
+    assert items["claude:doc:d-1"].value == "budget.md\n\nTotal: 2M yen"
+    conversation = items["claude:conversation:c-9"]
+    assert conversation.value.endswith("User: Which tile?\n\nAssistant: Matte")
+    assert conversation.created_at.hour == 0  # 09:00+09:00 stored as UTC
+
+
+async def test_import_is_idempotent_and_updates(db_session):
+    db = TestingSessionLocal()
+    item = ImportedMemory(source="chatgpt:c-1", value="v1", tags=["a", "chatgpt"])
+
+    preview = await import_memories(db, [item], "default", dry_run=True)
+    assert preview.created == 1
+    assert db.query(Memory).count() == 0
+
+    first = await import_memories(db, [item], "default")
+    assert first.created == 1
+    again = await import_memories(db, [item], "default")
+    assert again.unchanged == 1
+
+    item.value = "v2"
+    updated = await import_memories(db, [item], "default")
+    assert updated.updated == 1
+    memory = db.query(Memory).one()
+    assert memory.value == "v2"
+    assert memory.source == "chatgpt:c-1"
+    assert memory.tags_list == ["a", "chatgpt"]
+    db.close()
+
+
+async def test_import_reports_progress(db_session, monkeypatch):
+    from app.services.importers import base
+
+    monkeypatch.setattr(base, "IMPORT_PROGRESS_EVERY", 2)
+    db = TestingSessionLocal()
+    items = [ImportedMemory(source=f"chatgpt:c-{i}", value=f"v{i}") for i in range(5)]
+    reports = []
+
+    await import_memories(
+        db, items, "default", progress=lambda done, total, result: reports.append((done, total))
+    )
+    assert reports == [(2, 5), (4, 5), (5, 5)]
+    db.close()
+
+
+def test_cli_import_cancelled(db_session, capsys, tmp_path, monkeypatch):
+    """Ctrl-C during an import leaves nothing behind"""
+    from app import cli
+    from app.services.importers import base
+
+    def interrupt(done, total, result):
+        raise KeyboardInterrupt
+
+    monkeypatch.setattr(base, "IMPORT_PROGRESS_EVERY", 1)
+    monkeypatch.setattr(cli, "_report_import_progress", interrupt)
+    path = tmp_path / "conversations.json"
+    path.write_text(json.dumps([CHATGPT_CONVERSATION, {**CHATGPT_CONVERSATION, "id": "c-2"}]))
+
+    code = main(["import", "chatgpt", str(path)], session_factory=TestingSessionLocal)
+    assert code == 1
+    assert "cancelled" in capsys.readouterr().err
+    db = TestingSessionLocal()
+    assert db.query(Memory).count() == 0
+    db.close()
+
+
+async def test_same_content_under_new_source_is_a_duplicate(db_session):
+    """A renamed file with the same text is reported instead of creating a second memory"""
+    db = TestingSessionLocal()
+    original = ImportedMemory(source="logseq:pages/kyoto", value="Kyoto trip\n\n- Book hotel")
+    await import_memories(db, [original], "default")
+    memory = db.query(Memory).one()
+
+    renamed = ImportedMemory(source="logseq:pages/kyoto-2024", value="kyoto  TRIP\n- Book hotel")
+    copy = ImportedMemory(source="logseq:pages/other", value="Other page")
+    copy_again = ImportedMemory(source="logseq:pages/other-copy", value="Other page ")
+    result = await import_memories(db, [renamed, copy, copy_again], "default")
+
+    assert result.created == 1
+    assert result.duplicates == [
+        {"source": "logseq:pages/kyoto-2024", "duplicate_of": memory.id},
+        {"source": "logseq:pages/other-copy", "duplicate_of": "logseq:pages/other"},
+    ]
+    assert db.query(Memory).count() == 2
+    db.close()
+
+
+def test_cli_import(db_session, capsys, tmp_path):
+    path = tmp_path / "conversations.json"
+    path.write_text(json.dumps([CHATGPT_CONVERSATION]))
+
+    code = main(["import", "chatgpt", str(path)], session_factory=TestingSessionLocal)
+    assert code == 0
+    assert json.loads(capsys.readouterr().out)["created"] == 1
+
+    bad = tmp_path / "bad.json"
+    bad.write_text("{not json")
+    code = main(["import", "chatgpt", str(bad)], session_factory=TestingSessionLocal)
+    assert code == 1
+
+
+def _notion_transport(pages, requests):
+    def handler(request: httpx.Request) -> httpx.Response:
+        requests.append(request)
+        if request.url.path.endswith("/query"):
+            return httpx.Response(200, json={"results": pages, "has_more": False})
+        return httpx.Response(
+            200,
+            json={
+                "results": [
+                    {"type": "heading_2", "heading_2": {"rich_text": [{"plain_text": "Plan"}]}},
+                    {
+                        "type": "to_do",
+                        "to_do": {"rich_text": [{"plain_text": "Book hotel"}], "checked": True},
+                    },
+                    {"type": "image", "image": {}},
+                ],
+                "has_more": False,
+            },
+        )
+
+    return httpx.MockTransport(handler)
+
+
+NOTION_PAGE = {
+    "id": "page-1",
+    "url": "https://www.notion.so/page-1",
+    "created_time": "2024-06-01T00:00:00.000Z",
+    "properties": {
+        "Name": {"type": "title", "title": [{"plain_text": "Kyoto trip"}]},
+        "Category": {"type": "select", "select": {"name": "Travel"}},
+        "Tags": {"type": "multi_select", "multi_select": [{"name": "japan"}]},
+    },
+}
+
+
+async def test_notion_sync_is_incremental(db_session, tmp_path):
+    db = TestingSessionLocal()
+    requests = []
+    importer = NotionImporter(
+        "db-1",
+        token="secret",
+        state_path=tmp_path / "notion_state.json",
+        transport=_notion_transport(
+            [NOTION_PAGE, {**NOTION_PAGE, "id": "gone", "archived": True}], requests
+        ),
+    )
+
+    result = await importer.sync(db, "default")
+    assert result.created == 1
+    memory = db.query(Memory).one()
+    assert memory.source == "notion:page-1"
+    assert memory.value == "Kyoto trip\n\n## Plan\n- [x] Book hotel"
+    assert memory.tags_list == ["travel", "japan", "notion"]
+    assert memory.metadata_dict["source_url"] == "https://www.notion.so/page-1"
+    assert requests[0].headers["Authorization"] == "Bearer secret"
+    assert "filter" not in json.loads(requests[0].content)
+
+    requests.clear()
+    again = await importer.sync(db, "default")
+    assert again.unchanged == 1
+    assert "last_edited_time" in json.loads(requests[0].content)["filter"]
+    db.close()
+
+
+def test_apple_notes_export_file(tmp_path):
+    notes = [
+        {
+            "id": "x-coredata://1/ICNote/p1",
+            "name": "Wi-Fi",
+            "body": "Wi-Fi\nRouter is in the hallway",
+            "folder": "Home Stuff",
+            "created": "2024-06-01T00:00:00.000Z",
+        },
+        {
+            "id": "x-coredata://1/ICNote/p2",
+            "name": "Old",
+            "body": "x",
+            "folder": "Recently Deleted",
+        },
+        {"id": "x-coredata://1/ICNote/p3", "name": "Empty", "body": "  ", "folder": "Notes"},
+    ]
+    path = tmp_path / "notes.json"
+    path.write_text(json.dumps(notes))
+
+    [item] = list(apple_notes.parse(path))
+    assert item.source == "apple-notes:x-coredata://1/ICNote/p1"
+    assert item.tags == ["home-stuff", "apple-notes"]
+    assert item.metadata == {"title": "Wi-Fi", "folder": "Home Stuff"}
+    assert item.value == "Wi-Fi\nRouter is in the hallway"
+
+
+def _raw_message(uid: int, subject: str, body: str, subtype: str = "plain") -> bytes:
+    return (
+        f"From: Me <me@example.com>\r\n"
+        f"Subject: {subject}\r\n"
+        f"Date: Sat, 01 Jun 2024 09:00:00 +0900\r\n"
+        f"Message-ID: <msg-{uid}@example.com>\r\n"
+        f"Content-Type: text/{subtype}; charset=utf-8\r\n\r\n{body}\r\n"
+    ).encode()
+
+
+class FakeImap:
+    """Just enough of imaplib.IMAP4 for MailImporter"""
+
+    def __init__(self, messages: dict[int, bytes]):
+        self.messages = messages
+        self.searches = []
+
+    def select(self, mailbox, readonly=False):
+        assert readonly
+        return "OK", [str(len(self.messages)).encode()]
+
+    def response(self, code):
+        return code, [b"42"]
+
+    def uid(self, command, *args):
+        if command == "SEARCH":
+            self.searches.append(args[1:])
+            return "OK", [" ".join(str(uid) for uid in sorted(self.messages)).encode()]
+        uid = int(args[0])
+        return "OK", [(f"{uid} (BODY[] {{0}}".encode(), self.messages[uid]), b")"]
+
+    def logout(self):
+        pass
+
+
+CALENDAR = "\r\n".join(
+    [
+        "BEGIN:VCALENDAR",
+        "X-WR-CALNAME:Work",
+        "BEGIN:VEVENT",
+        "UID:budget@example.com",
+        "SUMMARY:Budget review\\, Q3",
+        "DTSTART;TZID=Asia/Tokyo:20240603T100000",
+        "DTEND;TZID=Asia/Tokyo:20240603T110000",
+        "LOCATION:Room 4",
+        "DESCRIPTION:Agree on the\\nhiring budget",
+        "ATTENDEE;CN=Alice Smith;ROLE=REQ-PARTICIPANT:mailto:alice@example.com",
+        "ATTENDEE:mailto:bob@exam",
+        " ple.com",
+        "BEGIN:VALARM",
+        "DESCRIPTION:Reminder",
+        "END:VALARM",
+        "END:VEVENT",
+        "BEGIN:VEVENT",
+        "UID:offsite@example.com",
+        "SUMMARY:Offsite",
+        "DTSTART;VALUE=DATE:20240610",
+        "RRULE:FREQ=YEARLY",
+        "END:VEVENT",
+        "BEGIN:VEVENT",
+        "UID:cancelled@example.com",
+        "STATUS:CANCELLED",
+        "DTSTART:20240601T000000Z",
+        "END:VEVENT",
+        "END:VCALENDAR",
+    ]
+)
+
+
+def test_ics_events(tmp_path):
+    path = tmp_path / "work.ics"
+    path.write_text(CALENDAR)
+
+    meeting, offsite = ics.parse(path)
+    assert meeting.source == "ics:budget@example.com"
+    assert meeting.value == (
+        "Budget review, Q3\n\nWhen: 2024-06-03 10:00-11:00 JST\nWhere: Room 4\n"
+        "Attendees: Alice Smith, bob@example.com\n\nAgree on the\nhiring budget"
+    )
+    assert meeting.tags == ["work", "2024-06-03", "Alice Smith", "bob@example.com", "ics"]
+    assert meeting.metadata["people"] == ["Alice Smith", "bob@example.com"]
+    assert meeting.metadata["start"] == "2024-06-03T01:00:00"
+    assert meeting.created_at.isoformat() == "2024-06-03T01:00:00"
+
+    assert offsite.tags == ["work", "2024-06-10", "ics"]
+    assert offsite.metadata["all_day"] is True
+    assert offsite.metadata["recurrence"] == "FREQ=YEARLY"
+
+
+def test_joplin_raw_export(tmp_path):
+    files = {
+        "f1": "Home\n\nid: f1\ntype_: 2",
+        "t1": "wifi\n\nid: t1\ntype_: 5",
+        "n1": (
+            "Router\n\nThe router is in the hallway.\nPassword is on the sticker.\n\n"
+            "id: n1\nparent_id: f1\ncreated_time: 2024-06-01T00:00:00.000Z\n"
+            "is_todo: 0\ntype_: 1"
+        ),
+        "l1": "id: l1\nnote_id: n1\ntag_id: t1\ntype_: 6",
+        "n2": "Old\n\nGone\n\nid: n2\ndeleted_time: 1717200000000\ntype_: 1",
+    }
+    for name, text in files.items():
+        (tmp_path / f"{name}.md").write_text(text)
+
+    [note] = list(joplin.parse(tmp_path))
+    assert note.source == "joplin:n1"
+    assert note.value == "Router\n\nThe router is in the hallway.\nPassword is on the sticker."
+    assert note.tags == ["home", "wifi", "joplin"]
+    assert note.metadata == {"title": "Router", "notebook": "Home"}
+    assert note.created_at.isoformat() == "2024-06-01T00:00:00"
+
+
+def test_logseq_graph(tmp_path):
+    (tmp_path / "pages").mkdir()
+    (tmp_path / "journals").mkdir()
+    (tmp_path / "pages" / "projects___mory.md").write_text(
+        "tags:: [[python]], memory\nalias:: mory\n\n"
+        "- Personal memory server\n  id:: 6650a1b2-0000\n"
+        "\t- Uses [[SQLite]] and FTS5\n  collapsed:: true\n-\n"
+    )
+    (tmp_path / "journals" / "2024_06_01.md").write_text("- Met [[Alice]] about the budget\n")
+    (tmp_path / "pages" / "scratch.md").write_text("mory:: ignore\n\n- Not for Mory\n")
+
+    page, journal = logseq.parse(tmp_path)
+    assert page.source == "logseq:pages/projects___mory"
+    assert page.value == "projects/mory\n\n- Personal memory server\n\t- Uses SQLite and FTS5"
+    assert page.tags == ["projects", "python", "memory", "logseq"]
+    assert page.metadata == {"title": "projects/mory", "aliases": ["mory"]}
+
+    assert journal.value == "2024-06-01\n\n- Met Alice about the budget"
+    assert journal.tags == ["journal", "logseq"]
+    assert journal.created_at.isoformat() == "2024-06-01T00:00:00"
+
+
+async def test_unreadable_files_go_to_error_report(db_session, tmp_path, monkeypatch):
+    """A bad file is reported with its reason and first bytes instead of stopping the import"""
+    from app.core.config import settings
+
+    monkeypatch.setattr(settings, "data_dir", str(tmp_path / "data"))
+    graph = tmp_path / "graph"
+    (graph / "pages").mkdir(parents=True)
+    (graph / "pages" / "good.md").write_text("- Readable page\n")
+    (graph / "pages" / "broken.md").write_bytes(b"title:: Broken\n- caf\xe9\n")
+    (graph / "pages" / "empty.md").write_text("- \n")
+
+    db = TestingSessionLocal()
+    items = list(logseq.parse(graph))
+    items.append(ImportedMemory(source="logseq:pages/blank", value="   "))
+    result = await import_memories(db, items, "default")
+    db.close()
+
+    assert (result.created, result.failed, result.skipped) == (1, 1, 1)
+    report = json.loads(open(result.error_report, encoding="utf-8").read())
+    assert report[0]["source"].endswith("broken.md")
+    assert report[0]["reason"].startswith("UnicodeDecodeError")
+    assert report[0]["snippet"].startswith("title:: Broken")
+    assert report[1] == {"source": "logseq:pages/blank", "reason": "Empty content", "snippet": ""}
+
+
+async def test_mail_sync_imports_new_messages(db_session, tmp_path):
+    db = TestingSessionLocal()
+    imap = FakeImap(
+        {
+            7: _raw_message(7, "Dentist", "Appointment on Friday"),
+            9: _raw_message(9, "Recipe", "<p>Miso&nbsp;soup</p><br>Dashi first", "html"),
+        }
+    )
+    importer = MailImporter("Mory", state_path=tmp_path / "imap.json", connect=lambda: imap)
+
+    result = await importer.sync(db, "default")
+    assert result.created == 2
+    memory = db.query(Memory).filter(Memory.source == "email:msg-7@example.com").one()
+    assert memory.value == "Dentist\n\nAppointment on Friday"
+    assert memory.tags_list == ["mory", "email"]
+    assert memory.metadata_dict["from"] == "me@example.com"
+    assert memory.metadata_dict["date"] == "2024-06-01T00:00:00"
+    html_memory = db.query(Memory).filter(Memory.source == "email:msg-9@example.com").one()
+    assert html_memory.value == "Recipe\n\nMiso\xa0soup\n\nDashi first"
+
+    again = await importer.sync(db, "default")
+    assert again.created == again.updated == again.unchanged == 0
+    assert imap.searches[-1][:2] == ("UID", "10:*")
+    db.close()
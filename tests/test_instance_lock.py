@@ -0,0 +1,32 @@
+"""Tests for single-instance data directory locking"""
+
+import os
+
+import pytest
+
+from app.core.instance_lock import InstanceLock, InstanceLockedError
+
+
+def test_second_lock_is_refused(tmp_path):
+    """A second lock on the same data dir fails with the owner's pid"""
+    first = InstanceLock(tmp_path)
+    first.acquire()
+    try:
+        with pytest.raises(InstanceLockedError) as excinfo:
+            InstanceLock(tmp_path).acquire()
+        assert str(os.getpid()) in str(excinfo.value)
+        assert "MORY_ALLOW_MULTIPLE_INSTANCES" in str(excinfo.value)
+    finally:
+        first.release()
+
+
+def test_lock_can_be_reacquired_after_release(tmp_path):
+    """Releasing the lock allows a new server to start"""
+    first = InstanceLock(tmp_path)
+    first.acquire()
+    first.release()
+
+    second = InstanceLock(tmp_path)
+    second.acquire()
+    assert second.held
+    second.release()
@@ -0,0 +1,58 @@
+"""Tests for language detection and per-language search"""
+
+from app.models.memory import Memory
+from app.models.schemas import SearchRequest
+from app.services.language import backfill_languages, bigrams, detect_language
+from app.services.search import SearchService
+from tests.conftest import TestingSessionLocal
+
+
+def test_detect_language():
+    assert detect_language("京都旅行の計画を立てる") == "ja"
+    assert detect_language("Meeting notes for the 会議") == "en"
+    assert detect_language("会議メモ: project kickoff") == "ja"
+    assert detect_language("안녕하세요") == "ko"
+    assert detect_language("中文文本") == "zh"
+    assert detect_language("12:30") is None
+
+
+def test_bigrams():
+    assert bigrams("京都旅行") == ["京都", "都旅", "旅行"]
+    assert bigrams("Python入門") == ["Python", "入門"]
+    assert bigrams("会議") == ["会議"]
+
+
+def test_language_follows_value(db_session):
+    memory = Memory(value="Docker networking")
+    assert memory.language == "en"
+    memory.value = "ドッカーのネットワーク"
+    assert memory.language == "ja"
+
+
+async def test_cjk_bigram_search_and_language_filter(db_session):
+    db = TestingSessionLocal()
+    db.add(Memory(id="mem_ja", value="京都の旅行は五月に行く"))
+    db.add(Memory(id="mem_en", value="Kyoto 旅行 itinerary and train tickets for the trip"))
+    db.add(Memory(id="mem_other", value="大阪で会議"))
+    db.commit()
+
+    async def ids(query, language=None):
+        request = SearchRequest(query=query, search_type="fts5", language=language)
+        response = await SearchService().search_memories(request, db)
+        return sorted(result.memory.id for result in response.results)
+
+    assert await ids("京都旅行") == ["mem_en", "mem_ja"]
+    assert await ids("京都旅行", language="ja") == ["mem_ja"]
+    db.close()
+
+
+def test_backfill_languages(db_session):
+    db = TestingSessionLocal()
+    db.add(Memory(id="mem_old", value="Old note"))
+    db.commit()
+    db.query(Memory).update({Memory.language: None})
+    db.commit()
+
+    assert backfill_languages(db) == 1
+    assert db.get(Memory, "mem_old").language == "en"
+    db.close()
@@ -0,0 +1,48 @@
+"""Tests for graceful shutdown helpers"""
+
+import asyncio
+
+from app.core.lifecycle import InFlightTracker
+
+
+async def test_drain_waits_for_in_flight_operations():
+    """Drain returns once running operations finish"""
+    tracker = InFlightTracker()
+
+    async def operation():
+        with tracker.track():
+            await asyncio.sleep(0.05)
+
+    task = asyncio.create_task(operation())
+    await asyncio.sleep(0)
+    assert tracker.count == 1
+
+    assert await tracker.drain(timeout=1.0) is True
+    assert tracker.draining is True
+    await task
+
+
+async def test_drain_times_out():
+    """Drain reports operations that outlive the timeout"""
+    tracker = InFlightTracker()
+    release = asyncio.Event()
+
+    async def operation():
+        with tracker.track():
+            await release.wait()
+
+    task = asyncio.create_task(operation())
+    await asyncio.sleep(0)
+
+    assert await tracker.drain(timeout=0.01) is False
+
+    release.set()
+    await task
+    assert tracker.count == 0
+
+
+async def test_drain_when_idle_returns_immediately():
+    """An idle tracker drains without waiting"""
+    tracker = InFlightTracker()
+
+    assert await tracker.drain(timeout=0.01) is True
@@ -0,0 +1,44 @@
+"""Tests for write rate limiting and payload size limits"""
+
+import pytest
+
+from app.core.config import settings
+from app.core.limits import PayloadTooLargeError, RateLimiter, RateLimitExceededError, limit_value
+
+
+def test_rate_limiter_blocks_after_limit():
+    """Writes beyond the per-minute limit are rejected per key"""
+    limiter = RateLimiter()
+    limiter.check("agent", 2)
+    limiter.check("agent", 2)
+
+    with pytest.raises(RateLimitExceededError):
+        limiter.check("agent", 2)
+
+    limiter.check("other-agent", 2)
+    limiter.check("agent", 0)  # 0 disables the limit
+
+
+def test_limit_value_policies():
+    """Oversized values are rejected or truncated depending on policy"""
+    assert limit_value("short", 10) == ("short", False)
+    assert limit_value("x" * 20, 10, "truncate") == ("x" * 10, True)
+
+    with pytest.raises(PayloadTooLargeError):
+        limit_value("x" * 20, 10, "reject")
+
+
+def test_api_enforces_limits(client, db_session, monkeypatch):
+    """The save endpoint returns 413/429 and the counters appear in stats"""
+    monkeypatch.setattr(settings, "max_value_length", 10)
+    monkeypatch.setattr(settings, "write_rate_limit_per_minute", 1)
+
+    response = client.post("/api/memories", json={"value": "x" * 50})
+    assert response.status_code == 413
+
+    assert client.post("/api/memories", json={"value": "ok"}).status_code == 201
+    assert client.post("/api/memories", json={"value": "again"}).status_code == 429
+
+    limits = client.get("/api/memories/stats").json()["limits"]
+    assert limits["rate_limited"] >= 1
+    assert limits["rejected_too_large"] >= 1
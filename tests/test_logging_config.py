@@ -0,0 +1,42 @@
+"""Tests for logging configuration"""
+
+import json
+import logging
+
+from app.core.logging_config import JsonFormatter, RequestIdFilter, request_id_var
+
+
+def make_record(message: str = "hello") -> logging.LogRecord:
+    """Create a log record passed through the request ID filter"""
+    record = logging.LogRecord("mory.test", logging.INFO, __file__, 1, message, None, None)
+    RequestIdFilter().filter(record)
+    return record
+
+
+def test_json_formatter_includes_request_id():
+    """JSON output carries the active request ID"""
+    token = request_id_var.set("abc12345")
+    try:
+        record = make_record()
+    finally:
+        request_id_var.reset(token)
+
+    payload = json.loads(JsonFormatter().format(record))
+
+    assert payload["message"] == "hello"
+    assert payload["level"] == "INFO"
+    assert payload["request_id"] == "abc12345"
+
+
+def test_request_id_placeholder_outside_request():
+    """Records outside a request get a placeholder ID"""
+    record = make_record()
+
+    assert record.request_id == "-"
+
+
+def test_api_echoes_request_id_header(client):
+    """Incoming X-Request-ID is returned on the response"""
+    response = client.get("/api/health", headers={"X-Request-ID": "req00001"})
+
+    assert response.headers["X-Request-ID"] == "req00001"
@@ -0,0 +1,86 @@
+"""Tests for JSON Schema validation of MCP tool arguments"""
+
+import json
+
+import httpx
+
+from app.mcp_server import _call_tool, error_code, handle_list_tools, validate_arguments
+
+
+async def _schema(name: str) -> dict:
+    tools = {tool.name: tool for tool in await handle_list_tools()}
+    return tools[name].inputSchema
+
+
+async def test_valid_arguments_pass():
+    schema = await _schema("list_memories")
+    assert validate_arguments(schema, {"limit": 5, "include_archived": True}) == []
+
+
+async def test_wrong_type_optional_argument_is_reported():
+    schema = await _schema("list_memories")
+    errors = validate_arguments(schema, {"limit": "5"})
+    assert errors == [
+        {"field": "$.limit", "rule": "type", "message": "'5' is not of type 'integer'"}
+    ]
+
+
+async def test_missing_required_and_out_of_range_reported_together():
+    schema = await _schema("search_memories")
+    errors = validate_arguments(schema, {"limit": 500})
+    assert {error["rule"] for error in errors} == {"required", "maximum"}
+    assert any("'query' is a required property" in error["message"] for error in errors)
+
+
+async def test_enum_is_enforced():
+    schema = await _schema("search_operations")
+    errors = validate_arguments(schema, {"query": "x", "operation": "removed"})
+    assert len(errors) == 1
+    assert errors[0]["field"] == "$.operation"
+    assert errors[0]["rule"] == "enum"
+
+
+async def test_every_tool_schema_is_valid():
+    for tool in await handle_list_tools():
+        # Empty arguments must not crash the validator, whatever it reports
+        validate_arguments(tool.inputSchema, {})
+
+
+async def test_call_tool_returns_structured_errors():
+    result = await _call_tool("get_memory", {"key": 42})
+    payload = json.loads(result[0].text)
+    assert payload["error"] == "Invalid arguments for get_memory"
+    assert payload["code"] == "invalid_arguments"
+    assert payload["errors"][0]["field"] == "$.key"
+
+
+def _api_error(status: int) -> ValueError:
+    """A handler's readable error raised from the API's HTTP error, as the handlers do"""
+    request = httpx.Request("GET", "http://localhost:8080/api/memories/x")
+    response = httpx.Response(status, request=request)
+    try:
+        try:
+            response.raise_for_status()
+        except httpx.HTTPStatusError as e:
+            raise ValueError("Memory with key 'x' not found") from e
+    except ValueError as e:
+        return e
+
+
+def test_error_codes_follow_the_api_status():
+    assert error_code(_api_error(404)) == "not_found"
+    assert error_code(_api_error(409)) == "conflict"
+    assert error_code(_api_error(503)) == "unavailable"
+    assert error_code(_api_error(500)) == "internal"
+    assert error_code(ValueError("no cause")) == "internal"
+
+    connect_error = httpx.ConnectError("Connection refused")
+    try:
+        raise ValueError("Failed to get memory") from connect_error
+    except ValueError as e:
+        assert error_code(e) == "unavailable"
+
+
+async def test_unknown_tool_has_its_own_code():
+    payload = json.loads((await _call_tool("no_such_tool", {}))[0].text)
+    assert payload["code"] == "unknown_tool"
@@ -228,6 +228,25 @@ class TestUpdateMemory:
         response = client.put("/api/memories/nonexistent_id", json=update_data)
         assert response.status_code == 404
 
+    def test_update_memory_rejects_null_value(self, client, db_session, sample_memory_data):
+        """An explicit null for a field that cannot be cleared is a 422, not a 500"""
+        memory_id = client.post("/api/memories", json=sample_memory_data).json()["id"]
+
+        for update_data in (
+            {"value": None},
+            {"confidence": None},
+            {"value": None, "remind_at": None},
+        ):
+            response = client.put(f"/api/memories/{memory_id}", json=update_data)
+            assert response.status_code == 422, update_data
+
+        memory = client.get(f"/api/memories/{memory_id}").json()
+        assert memory["value"] == sample_memory_data["value"]
+        assert memory["confidence"] == 1.0
+        # Fields that can be cleared still take null
+        response = client.put(f"/api/memories/{memory_id}", json={"remind_at": None})
+        assert response.status_code == 200
+
 
 class TestDeleteMemory:
     """Tests for DELETE /api/memories/{id} - simplified AI-driven schema (Issue #112)"""
@@ -293,6 +312,523 @@ class TestMemoryStats:
         assert "storage_info" in data
 
 
+class TestMemoryNamespaces:
+    """Namespace (profile) isolation tests"""
+
+    def test_memories_are_isolated_by_namespace(self, client, db_session):
+        """Memories saved in one namespace are invisible from another"""
+        response = client.post(
+            "/api/memories",
+            json={"value": "Work project notes"},
+            headers={"X-Mory-Namespace": "work"},
+        )
+        assert response.status_code == 201
+        memory = response.json()
+        assert memory["namespace"] == "work"
+
+        work = client.get("/api/memories", headers={"X-Mory-Namespace": "work"})
+        personal = client.get("/api/memories", headers={"X-Mory-Namespace": "personal"})
+        assert work.json()["total"] == 1
+        assert personal.json()["total"] == 0
+
+        response = client.get(
+            f"/api/memories/{memory['id']}", headers={"X-Mory-Namespace": "personal"}
+        )
+        assert response.status_code == 404
+
+    def test_default_namespace(self, client, db_session):
+        """Requests without the header use the configured namespace"""
+        response = client.post("/api/memories", json={"value": "Default memory"})
+        assert response.json()["namespace"] == "default"
+
+
+class TestMemoryOwnership:
+    """Multi-agent owner scoping tests"""
+
+    def test_owner_recorded_and_filtered(self, client, db_session):
+        """Saved memories record the agent and can be listed per owner"""
+        client.post("/api/memories", json={"value": "From A"}, headers={"X-Mory-Agent": "a"})
+        client.post("/api/memories", json={"value": "From B"}, headers={"X-Mory-Agent": "b"})
+
+        response = client.get("/api/memories", params={"owner": "a"})
+        data = response.json()
+        assert data["total"] == 1
+        assert data["memories"][0]["owner"] == "a"
+
+    def test_other_agent_cannot_modify(self, client, db_session):
+        """Only the owning agent may update or delete a memory"""
+        response = client.post(
+            "/api/memories", json={"value": "Private"}, headers={"X-Mory-Agent": "a"}
+        )
+        memory_id = response.json()["id"]
+
+        response = client.delete(f"/api/memories/{memory_id}", headers={"X-Mory-Agent": "b"})
+        assert response.status_code == 403
+
+        response = client.get(f"/api/memories/{memory_id}", headers={"X-Mory-Agent": "b"})
+        assert response.status_code == 200
+
+        response = client.delete(f"/api/memories/{memory_id}", headers={"X-Mory-Agent": "a"})
+        assert response.status_code == 200
+
+
+class TestMemoryApproval:
+    """Pending/approval staging workflow tests"""
+
+    APPROVER = {"X-Mory-Approval-Token": "secret"}
+
+    def _save_from_tool(self, client, value):
+        response = client.post(
+            "/api/memories", json={"value": value}, headers={"X-Mory-Tool": "save_memory"}
+        )
+        assert response.status_code == 201
+        return response.json()
+
+    def test_tool_saves_are_pending(self, client, db_session, monkeypatch):
+        """Assistant saves stay out of list/search until approved"""
+        from app.core.config import settings
+
+        monkeypatch.setattr(settings, "require_approval", True)
+        monkeypatch.setattr(settings, "approval_token", "secret")
+        memory = self._save_from_tool(client, "Assistant guessed fact")
+        assert memory["review_status"] == "pending"
+
+        assert client.get("/api/memories").json()["total"] == 0
+        pending = client.get("/api/memories/pending").json()
+        assert [m["id"] for m in pending["memories"]] == [memory["id"]]
+
+        response = client.post(f"/api/memories/{memory['id']}/approve", headers=self.APPROVER)
+        assert response.status_code == 200
+        assert response.json()["review_status"] == "approved"
+        assert client.get("/api/memories").json()["total"] == 1
+
+        response = client.post(f"/api/memories/{memory['id']}/approve", headers=self.APPROVER)
+        assert response.status_code == 409
+
+    def test_reject_discards(self, client, db_session, monkeypatch):
+        """Rejected memories are deleted"""
+        from app.core.config import settings
+
+        monkeypatch.setattr(settings, "require_approval", True)
+        memory = self._save_from_tool(client, "Wrong fact")
+
+        response = client.post(f"/api/memories/{memory['id']}/reject")
+        assert response.status_code == 200
+        assert client.get(f"/api/memories/{memory['id']}").status_code == 404
+
+    def test_tools_cannot_approve_by_default(self, client, db_session, monkeypatch):
+        """The assistant cannot approve its own saves unless MORY_TOOL_APPROVAL is set"""
+        from app.core.config import settings
+
+        monkeypatch.setattr(settings, "require_approval", True)
+        memory = self._save_from_tool(client, "Assistant guessed fact")
+        url = f"/api/memories/{memory['id']}/approve"
+        tool = {"X-Mory-Tool": "approve_memory"}
+
+        assert client.post(url, headers=tool).status_code == 403
+        # Leaving out the tool header does not make the caller a human
+        assert client.post(url).status_code == 403
+        # Without MORY_APPROVAL_TOKEN no token is accepted, not even an empty one
+        assert client.post(url, headers={"X-Mory-Approval-Token": ""}).status_code == 403
+        monkeypatch.setattr(settings, "approval_token", "secret")
+        assert client.post(url, headers={"X-Mory-Approval-Token": "guess"}).status_code == 403
+        assert client.get(f"/api/memories/{memory['id']}").json()["review_status"] == "pending"
+
+        monkeypatch.setattr(settings, "tool_approval", True)
+        response = client.post(f"/api/memories/{memory['id']}/approve", headers=tool)
+        assert response.status_code == 200
+
+    def test_review_requires_write_access(self, client, db_session, monkeypatch):
+        """Only agents allowed to modify a pending memory may approve or reject it"""
+        from app.core.config import settings
+
+        monkeypatch.setattr(settings, "require_approval", True)
+        monkeypatch.setattr(settings, "approval_token", "secret")
+        response = client.post(
+            "/api/memories",
+            json={"value": "Owned fact"},
+            headers={"X-Mory-Tool": "save_memory", "X-Mory-Agent": "a"},
+        )
+        memory_id = response.json()["id"]
+
+        other = {"X-Mory-Agent": "b", **self.APPROVER}
+        assert client.post(f"/api/memories/{memory_id}/approve", headers=other).status_code == 403
+        assert client.post(f"/api/memories/{memory_id}/reject", headers=other).status_code == 403
+
+        owner = {"X-Mory-Agent": "a", **self.APPROVER}
+        assert client.post(f"/api/memories/{memory_id}/approve", headers=owner).status_code == 200
+
+
+class TestSummarizeCategory:
+    """Tests for POST /api/memories/summarize"""
+
+    def _seed(self, count):
+        from app.models.memory import Memory
+        from tests.conftest import TestingSessionLocal
+
+        db = TestingSessionLocal()
+        ids = []
+        for i in range(count):
+            memory = Memory(value=f"Homelab note {i}", namespace="default")
+            memory.tags_list = ["homelab"]
+            db.add(memory)
+            db.flush()
+            ids.append(memory.id)
+        db.commit()
+        db.close()
+        return ids
+
+    def test_dry_run_then_condense(self, client, db_session, monkeypatch):
+        """Dry run previews only; a real run saves a summary and archives the originals"""
+        from app.services.summarization import summarization_service
+
+        monkeypatch.setattr(summarization_service, "enabled", False)
+        ids = self._seed(3)
+
+        response = client.post("/api/memories/summarize", json={"tag": "homelab"})
+        assert response.status_code == 200
+        preview = response.json()
+        assert preview["dry_run"] is True
+        assert sorted(preview["source_ids"]) == sorted(ids)
+        assert "Homelab note 2" in preview["summary"]
+        assert preview["memory"] is None
+        assert preview["confirmation_token"]
+        assert client.get(f"/api/memories/{ids[0]}").json()["archived_at"] is None
+
+        confirmed = {
+            "tag": "homelab",
+            "dry_run": False,
+            "confirmation_token": preview["confirmation_token"],
+        }
+        response = client.post("/api/memories/summarize", json=confirmed)
+        assert response.status_code == 200
+        condensed = response.json()["memory"]
+        assert condensed["tags"] == ["homelab", "summary"]
+        for memory_id in ids:
+            assert client.get(f"/api/memories/{memory_id}").json()["archived_at"] is not None
+
+        # Archived originals are not condensed again
+        response = client.post(
+            "/api/memories/summarize", json={"tag": "homelab", "dry_run": False}
+        )
+        assert response.status_code == 404
+
+    def test_requires_confirmation_token(self, client, db_session, monkeypatch):
+        """A real run needs a fresh, single-use token for exactly the previewed memories"""
+        from app.services.summarization import summarization_service
+
+        monkeypatch.setattr(summarization_service, "enabled", False)
+        ids = self._seed(2)
+        confirmed = {"tag": "homelab", "dry_run": False}
+
+        response = client.post("/api/memories/summarize", json=confirmed)
+        assert response.status_code == 428
+
+        confirmed["confirmation_token"] = "not-a-token"
+        assert client.post("/api/memories/summarize", json=confirmed).status_code == 409
+
+        # Another memory joined the category after the preview
+        preview = client.post("/api/memories/summarize", json={"tag": "homelab"}).json()
+        self._seed(1)
+        confirmed["confirmation_token"] = preview["confirmation_token"]
+        assert client.post("/api/memories/summarize", json=confirmed).status_code == 409
+        assert client.get(f"/api/memories/{ids[0]}").json()["archived_at"] is None
+
+        preview = client.post("/api/memories/summarize", json={"tag": "homelab"}).json()
+        confirmed["confirmation_token"] = preview["confirmation_token"]
+        assert client.post("/api/memories/summarize", json=confirmed).status_code == 200
+
+    def test_tag_matches_exactly(self, client, db_session, monkeypatch):
+        """LIKE wildcards in the tag are not wildcards, and other tags don't match"""
+        from app.services.summarization import summarization_service
+
+        monkeypatch.setattr(summarization_service, "enabled", False)
+        self._seed(2)
+
+        for tag in ("home_lab", "home%", "Homelab"):
+            response = client.post("/api/memories/summarize", json={"tag": tag})
+            assert response.status_code == 404
+
+
+class TestMemoryArchive:
+    """Archive tier tests"""
+
+    def test_archive_hides_from_list_and_search(self, client, db_session):
+        """Archived memories are only returned with include_archived"""
+        memory = client.post("/api/memories", json={"value": "Old zeppelin notes"}).json()
+
+        response = client.post(f"/api/memories/{memory['id']}/archive")
+        assert response.status_code == 200
+        assert response.json()["archived_at"] is not None
+
+        assert client.get("/api/memories").json()["total"] == 0
+        assert client.get("/api/memories?include_archived=true").json()["total"] == 1
+        search = {"query": "zeppelin", "search_type": "fts5"}
+        assert client.post("/api/memories/search", json=search).json()["total"] == 0
+        search["include_archived"] = True
+        assert client.post("/api/memories/search", json=search).json()["total"] == 1
+
+        response = client.post(f"/api/memories/{memory['id']}/unarchive")
+        assert response.json()["archived_at"] is None
+        assert client.get("/api/memories").json()["total"] == 1
+
+    def test_reads_are_counted(self, client, db_session):
+        """Reading a memory bumps access_count but not updated_at"""
+        memory = client.post("/api/memories", json={"value": "Counted note"}).json()
+
+        client.get(f"/api/memories/{memory['id']}")
+        fetched = client.get(f"/api/memories/{memory['id']}").json()
+        assert fetched["access_count"] == 2
+        assert fetched["updated_at"] == memory["updated_at"]
+
+    async def test_auto_archive_policy(self, db_session, monkeypatch):
+        """Stale, rarely read memories are archived"""
+        from datetime import datetime, timedelta
+
+        from app.core.config import settings
+        from app.models.memory import Memory
+        from app.services.archive import auto_archive
+        from tests.conftest import TestingSessionLocal
+
+        old = datetime.utcnow() - timedelta(days=400)
+        db = TestingSessionLocal()
+        db.add_all(
+            [
+                Memory(id="mem_stale", value="stale", created_at=old, updated_at=old),
+                Memory(
+                    id="mem_popular",
+                    value="popular",
+                    created_at=old,
+                    updated_at=old,
+                    access_count=10,
+                ),
+                Memory(id="mem_fresh", value="fresh"),
+            ]
+        )
+        db.commit()
+
+        monkeypatch.setattr(settings, "archive_after_days", 365)
+        monkeypatch.setattr(settings, "archive_max_access_count", 2)
+        assert await auto_archive(db) == ["mem_stale"]
+        db.close()
+
+
+class TestReminders:
+    """Reminder (remind_at) tests"""
+
+    def test_due_reminders_and_acknowledge(self, client, db_session):
+        """Past reminders are listed until acknowledged"""
+        from app.models.operation_log import OperationLog
+        from tests.conftest import TestingSessionLocal
+
+        due = client.post(
+            "/api/memories",
+            json={"value": "Renew passport", "remind_at": "2020-01-06T09:00:00+09:00"},
+        ).json()
+        assert due["remind_at"] == "2020-01-06T00:00:00"
+        client.post(
+            "/api/memories",
+            json={"value": "Far future", "remind_at": "2999-01-01T00:00:00Z"},
+        )
+        client.post("/api/memories", json={"value": "No reminder"})
+
+        reminders = client.get("/api/memories/reminders").json()
+        assert [m["id"] for m in reminders["memories"]] == [due["id"]]
+
+        response = client.post(f"/api/memories/{due['id']}/acknowledge")
+        assert response.status_code == 200
+        assert response.json()["remind_at"] is None
+        assert client.get("/api/memories/reminders").json()["total"] == 0
+        assert client.post(f"/api/memories/{due['id']}/acknowledge").status_code == 409
+
+        db = TestingSessionLocal()
+        entry = db.query(OperationLog).filter_by(operation="reminder_acknowledged").one()
+        assert entry.memory_id == due["id"]
+        db.close()
+
+    def test_due_reminders_skip_hidden_memories(self, client, db_session, monkeypatch):
+        """Archived, pending and unreadable memories are not listed as due"""
+        from app.core.config import settings
+        from app.core.permissions import set_permission_hook
+
+        due = {"remind_at": "2020-01-06T00:00:00Z"}
+        archived = client.post("/api/memories", json={"value": "Archived", **due}).json()
+        client.post(f"/api/memories/{archived['id']}/archive")
+        monkeypatch.setattr(settings, "require_approval", True)
+        client.post(
+            "/api/memories",
+            json={"value": "Pending", **due},
+            headers={"X-Mory-Tool": "save_memory"},
+        )
+        monkeypatch.setattr(settings, "require_approval", False)
+        private = client.post(
+            "/api/memories", json={"value": "Private", **due}, headers={"X-Mory-Agent": "a"}
+        ).json()
+        assert client.get("/api/memories/reminders").json()["total"] == 1
+
+        set_permission_hook(lambda agent_id, memory, action: memory.owner == agent_id)
+        try:
+            reminders = client.get("/api/memories/reminders", headers={"X-Mory-Agent": "b"})
+            assert reminders.json()["total"] == 0
+            reminders = client.get("/api/memories/reminders", headers={"X-Mory-Agent": "a"})
+            assert [m["id"] for m in reminders.json()["memories"]] == [private["id"]]
+        finally:
+            set_permission_hook(None)
+
+    def test_update_sets_reminder_without_reprocessing(self, client, db_session):
+        memory = client.post("/api/memories", json={"value": "Call the plumber"}).json()
+
+        response = client.put(
+            f"/api/memories/{memory['id']}", json={"remind_at": "2020-02-01T00:00:00Z"}
+        )
+        assert response.status_code == 200
+        assert response.json()["value"] == "Call the plumber"
+        assert client.get("/api/memories/reminders").json()["total"] == 1
+
+    def test_metadata_only_update_is_logged(self, client, db_session):
+        """A metadata-only PUT publishes an update, recorded with the previous content"""
+        import json
+
+        from app.models.operation_log import OperationLog
+
+        memory = client.post("/api/memories", json={"value": "Call the plumber"}).json()
+        client.put(f"/api/memories/{memory['id']}", json={"metadata": {"phone": "555-0100"}})
+
+        entry = (
+            db_session.query(OperationLog)
+            .filter_by(operation="updated", memory_id=memory["id"])
+            .one()
+        )
+        details = json.loads(entry.details)
+        assert details["before"]["value"] == "Call the plumber"
+
+
+class TestMemoryMetadata:
+    """Structured metadata tests"""
+
+    def test_save_and_filter_by_metadata(self, client, db_session):
+        """Metadata round-trips and filters search; list values match any item"""
+        saved = client.post(
+            "/api/memories",
+            json={
+                "value": "Dinner at the ramen place",
+                "metadata": {"location": "Kyoto", "people": ["Alice", "Bob"]},
+            },
+        ).json()
+        client.post(
+            "/api/memories",
+            json={"value": "Ramen recipe", "metadata": {"source_url": "https://example.com"}},
+        )
+        assert saved["metadata"] == {"location": "Kyoto", "people": ["Alice", "Bob"]}
+
+        for search_type in ("fts5", "hybrid"):
+            search = {
+                "query": "ramen",
+                "search_type": search_type,
+                "metadata": {"people": "Alice"},
+            }
+            results = client.post("/api/memories/search", json=search).json()["results"]
+            assert [r["memory"]["id"] for r in results] == [saved["id"]]
+
+        response = client.put(f"/api/memories/{saved['id']}", json={"metadata": {}})
+        assert response.json()["metadata"] == {}
+
+
+class TestMemorySource:
+    """Source provenance tests"""
+
+    def test_source_recorded_and_filterable(self, client, db_session):
+        """Tool saves record mcp:<tool>; search filters by type or exact source"""
+        from_tool = client.post(
+            "/api/memories",
+            json={"value": "Provenance tool note"},
+            headers={"X-Mory-Tool": "save_memory"},
+        ).json()
+        imported = client.post(
+            "/api/memories",
+            json={"value": "Provenance imported note", "source": "import:notes.csv"},
+        ).json()
+        direct = client.post("/api/memories", json={"value": "Provenance api note"}).json()
+
+        assert from_tool["source"] == "mcp:save_memory"
+        assert imported["source"] == "import:notes.csv"
+        assert direct["source"] == "api"
+
+        def search(source):
+            body = {"query": "provenance", "search_type": "fts5", "source": source}
+            results = client.post("/api/memories/search", json=body).json()["results"]
+            return [r["memory"]["id"] for r in results]
+
+        assert search("mcp") == [from_tool["id"]]
+        assert search("import:notes.csv") == [imported["id"]]
+        assert search("import:other.csv") == []
+
+
+class TestMemoryVerification:
+    """Confidence / verification tests"""
+
+    def test_verify_and_ranking(self, client, db_session):
+        """Verified facts outrank tentative ones for the same match"""
+        tentative = client.post(
+            "/api/memories", json={"value": "Alice likes oolong tea", "confidence": 0.4}
+        ).json()
+        confirmed = client.post(
+            "/api/memories", json={"value": "Alice likes oolong tea a lot"}
+        ).json()
+        assert tentative["confidence"] == 0.4
+        assert tentative["verified"] is False
+
+        response = client.post(f"/api/memories/{confirmed['id']}/verify")
+        assert response.status_code == 200
+        assert response.json()["verified"] is True
+
+        search = {"query": "oolong", "search_type": "fts5"}
+        results = client.post("/api/memories/search", json=search).json()["results"]
+        assert [r["memory"]["id"] for r in results] == [confirmed["id"], tentative["id"]]
+
+    def test_confidence_out_of_range(self, client, db_session):
+        response = client.post("/api/memories", json={"value": "x", "confidence": 1.5})
+        assert response.status_code == 422
+
+
+class TestRecentMemories:
+    """GET /api/memories/recent"""
+
+    def test_recent_memories_grouped_by_category(self, client, db_session):
+        from datetime import datetime, timedelta
+
+        from app.models.memory import Memory
+
+        now = datetime.utcnow()
+        for memory_id, tags, age in [
+            ("mem_py_new", ["python"], timedelta(hours=1)),
+            ("mem_cook", ["cooking"], timedelta(hours=2)),
+            ("mem_py_old", ["python", "async"], timedelta(hours=3)),
+            ("mem_plain", [], timedelta(hours=5)),
+            ("mem_stale", ["python"], timedelta(days=3)),
+        ]:
+            db_session.add(
+                Memory(id=memory_id, value=memory_id, tags=tags, updated_at=now - age)
+            )
+        db_session.commit()
+
+        data = client.get("/api/memories/recent", params={"window": "24h"}).json()
+
+        assert data["total"] == 4
+        assert [
+            (group["category"], [m["id"] for m in group["memories"]]) for group in data["groups"]
+        ] == [
+            ("python", ["mem_py_new", "mem_py_old"]),
+            ("cooking", ["mem_cook"]),
+            ("uncategorized", ["mem_plain"]),
+        ]
+        assert client.get("/api/memories/recent", params={"window": "7d"}).json()["total"] == 5
+
+    def test_invalid_window(self, client, db_session):
+        response = client.get("/api/memories/recent", params={"window": "yesterday"})
+        assert response.status_code == 422
+
+
 class TestAPIPerformance:
     """Performance tests for API endpoints"""
 
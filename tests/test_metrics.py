@@ -0,0 +1,42 @@
+"""Tests for the metrics registry and endpoints"""
+
+from app.core.metrics import MetricsRegistry, metrics
+
+
+def test_registry_renders_prometheus_format():
+    """Counters and summaries render with labels"""
+    registry = MetricsRegistry()
+    registry.counter("calls_total", "Calls")
+    registry.summary("latency_seconds", "Latency")
+
+    registry.inc("calls_total", {"tool": "save_memory"})
+    registry.inc("calls_total", {"tool": "save_memory"})
+    registry.observe("latency_seconds", 0.25, {"search_type": "like"})
+
+    output = registry.render_prometheus({"items": ("Items", 3)})
+
+    assert "# TYPE calls_total counter" in output
+    assert 'calls_total{tool="save_memory"} 2' in output
+    assert 'latency_seconds_count{search_type="like"} 1' in output
+    assert "items 3" in output
+
+
+def test_tool_header_counts_tool_calls(client, db_session):
+    """Requests tagged by the MCP bridge are counted per tool"""
+    metrics.reset()
+
+    client.get("/api/memories", headers={"X-Mory-Tool": "list_memories"})
+
+    assert metrics.get("mory_tool_calls_total", {"tool": "list_memories"}) == 1
+
+
+def test_metrics_endpoints(client, db_session):
+    """Prometheus and JSON endpoints include memory gauges"""
+    client.post("/api/memories", json={"value": "metrics test memory"})
+
+    text_response = client.get("/metrics")
+    json_response = client.get("/api/metrics")
+
+    assert text_response.status_code == 200
+    assert "mory_memories 1" in text_response.text
+    assert json_response.json()["gauges"]["mory_memories"] == 1
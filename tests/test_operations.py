@@ -0,0 +1,58 @@
+"""Tests for operation log snapshots and search"""
+
+import json
+from datetime import datetime, timedelta
+
+from app.models.operation_log import OperationLog
+from app.services.operation_log import record_operation, search_operations
+
+
+def _create(client, value):
+    response = client.post("/api/memories", json={"value": value})
+    assert response.status_code == 201
+    return response.json()["id"]
+
+
+def test_deleted_content_is_searchable(client, db_session):
+    memory_id = _create(client, "Notes from the PyCon conference keynote")
+    _create(client, "Grocery list")
+    assert client.delete(f"/api/memories/{memory_id}").status_code == 200
+
+    response = client.get(
+        "/api/operations/search", params={"q": "conference keynote", "operation": "deleted"}
+    )
+
+    assert response.status_code == 200
+    results = response.json()["results"]
+    assert [entry["memory_id"] for entry in results] == [memory_id]
+    assert results[0]["details"]["before"]["value"] == "Notes from the PyCon conference keynote"
+
+
+def test_update_records_before_and_after(client, db_session):
+    memory_id = _create(client, "Meeting moved to Tuesday")
+    client.put(f"/api/memories/{memory_id}", json={"value": "Meeting moved to Thursday"})
+
+    results = client.get(
+        "/api/operations/search", params={"q": "Tuesday", "operation": "updated"}
+    ).json()["results"]
+
+    assert len(results) == 1
+    assert results[0]["details"]["before"]["value"] == "Meeting moved to Tuesday"
+    assert results[0]["details"]["after"]["value"] == "Meeting moved to Thursday"
+
+
+def test_search_operations_filters(db_session):
+    old = record_operation(db_session, "deleted", "mem_old", details={"before": {"value": "50%"}})
+    record_operation(db_session, "deleted", "mem_new", details={"before": {"value": "500"}})
+    db_session.commit()
+    old.created_at = datetime.utcnow() - timedelta(days=30)
+    db_session.commit()
+
+    assert [e.memory_id for e in search_operations(db_session, "50%")] == ["mem_old"]
+    assert [e.memory_id for e in search_operations(db_session, "mem_")] == ["mem_new", "mem_old"]
+
+    since = datetime.utcnow() - timedelta(days=1)
+    assert [e.memory_id for e in search_operations(db_session, "mem", since=since)] == ["mem_new"]
+    until = datetime.utcnow() - timedelta(days=7)
+    assert [e.memory_id for e in search_operations(db_session, "mem", until=until)] == ["mem_old"]
+    assert json.loads(db_session.get(OperationLog, old.id).details)["before"]["value"] == "50%"
@@ -0,0 +1,102 @@
+"""Tests for the memory permission hook"""
+
+from datetime import datetime, timedelta
+from types import SimpleNamespace
+
+from app.core.permissions import check_access, reads_restricted, set_permission_hook
+from app.models.memory import Memory
+from tests.conftest import TestingSessionLocal
+
+
+def test_default_policy():
+    """Anyone reads; only the owner (or unowned memories) can be modified"""
+    owned = SimpleNamespace(owner="a")
+    shared = SimpleNamespace(owner=None)
+
+    assert check_access("b", owned, "read") is True
+    assert check_access("b", owned, "write") is False
+    assert check_access("a", owned, "write") is True
+    assert check_access("b", shared, "write") is True
+
+
+def test_custom_hook():
+    """A custom hook replaces the default policy until reset"""
+    set_permission_hook(lambda agent_id, memory, action: memory.owner == agent_id)
+    try:
+        assert check_access("b", SimpleNamespace(owner="a"), "read") is False
+        assert reads_restricted()
+    finally:
+        set_permission_hook(None)
+
+    assert check_access("b", SimpleNamespace(owner="a"), "read") is True
+    assert not reads_restricted()
+
+
+def test_hidden_memories_are_not_listed_or_counted(client, db_session):
+    """List and search leave out unreadable memories before paginating"""
+    for agent in ("a", "b", "a", "b"):
+        note = {"value": f"Zeppelin note by {agent}"}
+        client.post("/api/memories", json=note, headers={"X-Mory-Agent": agent})
+
+    set_permission_hook(lambda agent_id, memory, action: memory.owner == agent_id)
+    try:
+        headers = {"X-Mory-Agent": "a"}
+        listed = client.get("/api/memories", params={"limit": 1}, headers=headers).json()
+        assert listed["total"] == 2
+        assert len(listed["memories"]) == 1
+        listed = client.get("/api/memories", params={"offset": 1}, headers=headers).json()
+        assert [memory["owner"] for memory in listed["memories"]] == ["a"]
+
+        search = {"query": "zeppelin", "search_type": "fts5", "limit": 1}
+        found = client.post("/api/memories/search", json=search, headers=headers).json()
+        assert found["total"] == 2
+        assert [result["memory"]["owner"] for result in found["results"]] == ["a"]
+    finally:
+        set_permission_hook(None)
+
+
+def test_deny_all_reads_hides_memories_from_every_read_endpoint(client, db_session):
+    """With reads denied, no read endpoint hands out a memory"""
+    long_ago = datetime.utcnow() - timedelta(days=30)
+    db = TestingSessionLocal()
+    for status in ("approved", "pending"):
+        db.add(
+            Memory(
+                value=f"Zeppelin trip notes ({status})",
+                summary="Zeppelin trip",
+                tags='["zeppelin"]',
+                auto_tags='["zeppelin"]',
+                review_status=status,
+                remind_at=long_ago,
+                last_accessed_at=long_ago,
+            )
+        )
+    db.commit()
+    db.close()
+
+    set_permission_hook(lambda agent_id, memory, action: action != "read")
+    try:
+        listed = client.get("/api/memories").json()
+        assert listed["memories"] == [] and listed["total"] == 0
+        recent = client.get("/api/memories/recent").json()
+        assert recent["groups"] == [] and recent["total"] == 0
+        surfaced = client.post("/api/memories/surface", params={"count": 5}).json()
+        assert surfaced["memories"] == []
+        suggested = client.get("/api/memories/suggest", params={"prefix": "zep"}).json()
+        assert suggested["tags"] == [] and suggested["memories"] == []
+        for path in ("pending", "auto-tagged", "reminders"):
+            result = client.get(f"/api/memories/{path}").json()
+            assert result["memories"] == [] and result["total"] == 0, path
+        canvas = client.get("/api/memories/canvas", params={"tag": "zeppelin"}).json()
+        assert canvas["count"] == 0
+        assert client.get("/api/memories/report").status_code == 403
+        search = {"query": "zeppelin", "search_type": "fts5"}
+        found = client.post("/api/memories/search", json=search).json()
+        assert found["results"] == [] and found["total"] == 0
+    finally:
+        set_permission_hook(None)
+
+    # Without the hook the same memories are there to be found
+    assert client.get("/api/memories/pending").json()["total"] == 1
+    assert client.get("/api/memories/canvas", params={"tag": "zeppelin"}).json()["count"] == 1
+    assert client.get("/api/memories/report").status_code == 200
@@ -0,0 +1,74 @@
+"""Tests for the markdown digest report"""
+
+import json
+from datetime import datetime
+
+from app.cli import main
+from app.models.memory import Memory
+from app.services.report import build_report, category_counts, monthly_growth
+from tests.conftest import TestingSessionLocal
+
+NOW = datetime(2024, 6, 15, 12, 0)
+
+
+def _seed(db):
+    memories = [
+        ("mem_a", "Python tips " * 20, ["python", "tips"], datetime(2024, 4, 2), 0),
+        ("mem_b", "Use uv for virtualenvs", ["python"], datetime(2024, 6, 1), 5),
+        ("mem_c", "Dentist on Friday", ["health"], datetime(2024, 6, 10), 1),
+    ]
+    for memory_id, value, tags, created_at, reads in memories:
+        db.add(
+            Memory(
+                id=memory_id,
+                value=value,
+                tags=json.dumps(tags),
+                created_at=created_at,
+                access_count=reads,
+                embedding=b"\x00" * 8 if memory_id != "mem_c" else None,
+            )
+        )
+    db.commit()
+
+
+def test_report_sections(db_session):
+    db = TestingSessionLocal()
+    _seed(db)
+
+    assert monthly_growth(db, "default") == [("2024-04", 1, 1), ("2024-06", 2, 3)]
+    assert category_counts(db, "default") == [("python", 2), ("health", 1)]
+
+    report = build_report(db, "default", days=30, now=NOW)
+    assert report.startswith("# Mory report: default\n")
+    assert "| Memories | 3 |" in report
+    assert "| Created in the last 30 days | 2 |" in report
+    assert "| With embeddings | 2 (67%) |" in report
+    assert "| 2024-06 | 2 | 3 | ████████████████████ |" in report
+    assert "- python (2)\n- health (1)" in report
+    assert "| mem_a | 240 | Python tips" in report
+    highlights = report.split("## Recent highlights")[1]
+    assert highlights.index("Use uv for virtualenvs (read 5×)") < highlights.index("Dentist")
+    assert "Python tips" not in highlights
+    db.close()
+
+
+def test_report_empty_store(db_session):
+    db = TestingSessionLocal()
+    report = build_report(db, "default", now=NOW)
+    assert "No memories yet." in report
+    assert "Nothing new in the last 30 days." in report
+    db.close()
+
+
+def test_report_api_and_cli(client, db_session, capsys, tmp_path):
+    db = TestingSessionLocal()
+    _seed(db)
+    db.close()
+
+    response = client.get("/api/memories/report", params={"days": 7})
+    assert response.status_code == 200
+    assert response.json()["markdown"].startswith("# Mory report: default")
+
+    output = tmp_path / "report.md"
+    assert main(["report", "-o", str(output)], session_factory=TestingSessionLocal) == 0
+    assert output.read_text().startswith("# Mory report: default")
@@ -0,0 +1,53 @@
+"""Tests for the job scheduler"""
+
+import pytest
+
+from app.core.scheduler import Scheduler, parse_interval
+
+
+def test_parse_interval():
+    assert parse_interval("30m") == 1800
+    assert parse_interval("24h") == 86400
+    assert parse_interval("1w") == 604800
+    with pytest.raises(ValueError):
+        parse_interval("daily")
+
+
+async def test_runs_due_jobs_and_records_status(tmp_path):
+    """Jobs run once per interval; failures are recorded, not raised"""
+    calls = []
+
+    async def ok_job():
+        calls.append("ok")
+        return "done"
+
+    async def broken_job():
+        raise RuntimeError("boom")
+
+    scheduler = Scheduler(state_path=tmp_path / "scheduler.json")
+    scheduler.register("ok", ok_job)
+    scheduler.register("broken", broken_job)
+    scheduler.configure({"ok": "1h", "broken": "1h"})
+
+    assert sorted(await scheduler.run_due()) == ["broken", "ok"]
+    assert await scheduler.run_due() == []
+    assert calls == ["ok"]
+
+    report = scheduler.report()
+    assert report["ok"]["last_status"] == "ok"
+    assert report["ok"]["last_message"] == "done"
+    assert report["broken"]["last_status"] == "error"
+    assert report["broken"]["last_message"] == "boom"
+
+    # Last-run times survive a restart
+    restarted = Scheduler(state_path=tmp_path / "scheduler.json")
+    restarted.register("ok", ok_job)
+    restarted.configure({"ok": "1h"})
+    assert await restarted.run_due() == []
+    assert restarted.report()["ok"]["run_count"] == 1
+
+
+def test_stats_include_job_status(client):
+    response = client.get("/api/memories/stats")
+    assert response.status_code == 200
+    assert "jobs" in response.json()
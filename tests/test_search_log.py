@@ -0,0 +1,46 @@
+"""Tests for the search query log and analytics"""
+
+from app.core.config import settings
+from app.services.search_log import normalize_query
+
+
+def test_normalize_query():
+    assert normalize_query("  Kyoto   TRIP ") == "kyoto trip"
+
+
+def test_query_log_disabled(client, db_session):
+    response = client.post("/api/memories/search", json={"query": "Kyoto"})
+    assert response.status_code == 200
+    assert response.json()["query_id"] is None
+
+    analytics = client.get("/api/memories/search/analytics").json()
+    assert analytics["enabled"] is False
+    assert analytics["searches"] == 0
+
+
+def test_query_log_analytics(client, db_session, monkeypatch):
+    monkeypatch.setattr(settings, "query_log", True)
+    memory_id = client.post("/api/memories", json={"value": "Kyoto trip in May"}).json()["id"]
+
+    first = client.post("/api/memories/search", json={"query": "Kyoto"}).json()
+    client.post("/api/memories/search", json={"query": "kyoto "})
+    client.post("/api/memories/search", json={"query": "Osaka"})
+    assert first["query_id"] is not None
+
+    response = client.post(
+        f"/api/memories/search/{first['query_id']}/chosen", json={"memory_id": memory_id}
+    )
+    assert response.status_code == 200
+    assert response.json()["chosen_id"] == memory_id
+
+    missing = client.post("/api/memories/search/9999/chosen", json={"memory_id": memory_id})
+    assert missing.status_code == 404
+
+    analytics = client.get("/api/memories/search/analytics").json()
+    assert analytics["enabled"] is True
+    assert analytics["searches"] == 3
+    assert analytics["zero_result_searches"] == 1
+    assert analytics["searches_with_choice"] == 1
+    top = analytics["top_queries"][0]
+    assert (top["query"], top["searches"], top["chosen"]) == ("kyoto", 2, 1)
+    assert [q["query"] for q in analytics["zero_result_queries"]] == ["osaka"]
@@ -0,0 +1,377 @@
+"""Tests for search ranking across the FTS5, semantic and keyword engines"""
+
+import json
+from datetime import datetime
+
+import numpy as np
+import pytest
+
+from app.core.config import settings
+from app.core.database import create_fts5_table
+from app.models.memory import Memory
+from app.models.schemas import MemoryResponse, SearchRequest, SearchResult
+from app.services.search import (
+    SearchQueryError,
+    SearchService,
+    bm25_weights,
+    like_score,
+    normalize_scores,
+    parse_fts5_query,
+    query_terms,
+    stem,
+)
+from tests.conftest import TestingSessionLocal, engine
+
+
+@pytest.fixture
+def fts_service(db_session):
+    """Search service with the FTS5 index enabled on the test database"""
+    service = SearchService()
+    if not create_fts5_table(engine):
+        pytest.skip("SQLite build without FTS5")
+    service.fts5_available = True
+    return service
+
+
+def _add(db, memory_id, value, tags=(), summary=None):
+    db.add(Memory(id=memory_id, value=value, summary=summary, tags=json.dumps(list(tags))))
+    db.commit()
+
+
+def test_bm25_weights_follow_settings(monkeypatch):
+    monkeypatch.setattr(settings, "fts_weights", {"tags": 5.0, "value": 0.5})
+    assert bm25_weights() == [0.5, 1.0, 5.0]  # value, summary, tags
+
+
+async def test_tag_matches_outrank_body_matches(fts_service, db_session):
+    _add(db_session, "mem_body", "Notes that mention kubernetes once in passing")
+    _add(db_session, "mem_tag", "Cluster upgrade checklist", tags=["kubernetes"])
+    _add(db_session, "mem_other", "Nothing relevant here")
+
+    response = await fts_service.search_memories(
+        SearchRequest(query="kubernetes", search_type="fts5"), db_session
+    )
+
+    assert [result.memory.id for result in response.results] == ["mem_tag", "mem_body"]
+    assert response.results[0].score == 1.0
+    assert 0 < response.results[1].score < 1.0
+
+
+async def test_index_follows_updates_and_deletes(fts_service, db_session):
+    _add(db_session, "mem_1", "Original wording")
+    memory = db_session.get(Memory, "mem_1")
+    memory.value = "Rewritten wording"
+    db_session.commit()
+
+    async def ids(query):
+        response = await fts_service.search_memories(
+            SearchRequest(query=query, search_type="fts5"), db_session
+        )
+        return [result.memory.id for result in response.results]
+
+    assert await ids("original") == []
+    assert await ids("rewritten") == ["mem_1"]
+
+    db_session.delete(memory)
+    db_session.commit()
+    assert await ids("rewritten") == []
+
+
+def _result(memory_id: str, score: float, search_type: str) -> SearchResult:
+    now = datetime.utcnow()
+    memory = MemoryResponse(
+        id=memory_id,
+        value=memory_id,
+        verified=True,
+        created_at=now,
+        updated_at=now,
+        processing_status="complete",
+    )
+    return SearchResult(memory=memory, score=score, search_type=search_type)
+
+
+@pytest.mark.parametrize("method", ["minmax", "zscore"])
+def test_normalize_scores(method):
+    results = [_result("a", 40.0, "fts5"), _result("b", 10.0, "fts5"), _result("c", 25.0, "fts5")]
+    scores = [result.score for result in normalize_scores(results, method)]
+
+    assert all(0.0 <= score <= 1.0 for score in scores)
+    assert scores[0] > scores[2] > scores[1]
+    if method == "minmax":
+        assert scores == [1.0, 0.0, 0.5]
+
+    single = normalize_scores([_result("a", 0.02, "semantic")], method)
+    assert single[0].score == 1.0
+
+
+@pytest.mark.parametrize("keyword_scale", [0.01, 1.0, 250.0])
+async def test_hybrid_ordering_is_independent_of_engine_scales(
+    db_session, monkeypatch, keyword_scale
+):
+    """Only the hybrid weight decides between engines, not their raw score ranges"""
+    service = SearchService()
+
+    async def keyword(request, db, visible=None):
+        results = [
+            _result("kw_best", 3 * keyword_scale, "fts5"),
+            _result("both", 2 * keyword_scale, "fts5"),
+            _result("kw_worst", 1 * keyword_scale, "fts5"),
+        ]
+        return results, len(results)
+
+    async def semantic(request, db, visible=None):
+        results = [_result("both", 0.83, "semantic"), _result("sem_only", 0.81, "semantic")]
+        return results, len(results)
+
+    monkeypatch.setattr(service, "_search_fts5", keyword)
+    monkeypatch.setattr(service, "_search_semantic", semantic)
+    monkeypatch.setattr(settings, "hybrid_search_weight", 0.7)
+    monkeypatch.setattr(settings, "score_normalization", "minmax")
+
+    results, _ = await service._search_hybrid(SearchRequest(query="x"), db_session)
+
+    # both: 0.3 * 0.5 + 0.7 * 1.0 = 0.85, kw_best: 0.3 * 1.0, whatever the keyword scale
+    assert [result.memory.id for result in results][:2] == ["both", "kw_best"]
+    assert results[0].score == pytest.approx(0.85)
+
+
+async def test_fts_query_operators_are_literal(fts_service, db_session):
+    _add(db_session, "mem_cpp", 'Notes on C++ and "NEAR" semantics: tags OR summary')
+    _add(db_session, "mem_other", "Unrelated text")
+
+    queries = [('C++ "NEAR"', ["mem_cpp"]), ("tags: OR", ["mem_cpp"]), ("(semantics*", ["mem_cpp"])]
+    for query, expected in queries + [("NOT text", []), ('"""', [])]:
+        response = await fts_service.search_memories(
+            SearchRequest(query=query, search_type="fts5"), db_session
+        )
+        assert [result.memory.id for result in response.results] == expected, query
+
+
+async def test_tag_filter_matches_whole_tags(fts_service, db_session):
+    _add(db_session, "mem_exact", "deploy steps", tags=["ops"])
+    _add(db_session, "mem_longer", "deploy steps", tags=["devops", "o_s"])
+    _add(db_session, "mem_untagged", "deploy steps")
+
+    for search_type in ["fts5", "like"]:
+        for tags, expected in [(["ops"], ["mem_exact"]), (["o%"], []), (["o_s"], ["mem_longer"])]:
+            response = await fts_service.search_memories(
+                SearchRequest(query="deploy", search_type=search_type, tags=tags), db_session
+            )
+            assert sorted(result.memory.id for result in response.results) == expected
+
+
+async def test_like_search_matches_wildcards_literally(db_session):
+    _add(db_session, "mem_percent", "Disk at 100% usage")
+    _add(db_session, "mem_plain", "Disk at 1000 blocks")
+
+    response = await SearchService().search_memories(
+        SearchRequest(query="100%", search_type="like"), db_session
+    )
+
+    assert [result.memory.id for result in response.results] == ["mem_percent"]
+
+
+@pytest.mark.parametrize(
+    ("query", "expected"),
+    [
+        ("tags:foo -x", '"tags:foo" "-x"'),
+        ("docker OR kube*", '"docker" OR "kube"*'),
+        ('"compose networking" NOT swarm', '"compose networking" NOT "swarm"'),
+        ("NEAR(docker networking, 2)", 'NEAR ( "docker" "networking" , 2 )'),
+        ('"unterminated', '"unterminated"'),
+    ],
+)
+def test_parse_advanced_query(query, expected):
+    assert parse_fts5_query(query, advanced=True) == expected
+
+
+def test_parse_plain_query_quotes_operators():
+    assert parse_fts5_query('docker OR "kube*"') == '"docker" AND "OR" AND "kube*"'
+    for query in ["(docker", "docker)", "a, b"]:
+        with pytest.raises(SearchQueryError):
+            parse_fts5_query(query, advanced=True)
+
+
+async def test_advanced_search(fts_service, db_session):
+    _add(db_session, "mem_docker", "docker compose networking guide")
+    _add(db_session, "mem_k8s", "kubernetes networking")
+
+    async def ids(query, advanced=True):
+        response = await fts_service.search_memories(
+            SearchRequest(query=query, search_type="fts5", advanced=advanced), db_session
+        )
+        return sorted(result.memory.id for result in response.results)
+
+    assert await ids("docker OR kubernetes") == ["mem_docker", "mem_k8s"]
+    assert await ids("docker OR kubernetes", advanced=False) == []
+    assert await ids("network* NOT dock*") == ["mem_k8s"]
+
+    with pytest.raises(SearchQueryError):
+        await ids("docker AND")
+
+
+def test_invalid_advanced_query_is_a_bad_request(fts_service, client, monkeypatch):
+    from app.services.search import search_service
+
+    monkeypatch.setattr(search_service, "fts5_available", True)
+    response = client.post(
+        "/api/memories/search",
+        json={"query": "docker AND", "search_type": "fts5", "advanced": True},
+    )
+    assert response.status_code == 400
+
+
+def test_like_score_weights_fields_position_and_frequency():
+    def memory(value, summary=None, tags=()):
+        return Memory(value=value, summary=summary, tags=json.dumps(list(tags)))
+
+    tagged = memory("Checklist for the upgrade", tags=["kubernetes"])
+    summarized = memory("Checklist for the upgrade", summary="kubernetes upgrade")
+    early = memory("kubernetes upgrade notes and more notes about other things")
+    late = memory("notes and more notes about other things, then kubernetes")
+    repeated = memory("kubernetes upgrade notes and more kubernetes notes about other things")
+
+    ordered = [tagged, summarized, early, late]
+    scores = [like_score(m, ["Kubernetes"]) for m in ordered]
+    assert scores == sorted(scores, reverse=True)
+    assert len(set(scores)) == len(scores)
+    assert 0 < scores[-1] and scores[0] <= 1.0
+    assert like_score(repeated, ["kubernetes"]) > like_score(early, ["kubernetes"])
+    assert like_score(late, ["kubernetes", "missing"]) < like_score(late, ["kubernetes"])
+
+
+async def test_like_search_ranks_before_paginating(db_session):
+    _add(db_session, "mem_best", "Docker networking", tags=["docker"])
+    _add(db_session, "mem_weak", "Long notes that mention docker only near the end")
+    older = db_session.get(Memory, "mem_best")
+    older.updated_at = datetime(2020, 1, 1)
+    db_session.commit()
+
+    response = await SearchService().search_memories(
+        SearchRequest(query="docker", search_type="like", limit=1), db_session
+    )
+
+    assert response.total == 2
+    assert [result.memory.id for result in response.results] == ["mem_best"]
+
+
+@pytest.mark.parametrize(
+    ("word", "expected"),
+    [
+        ("networking", "network"),
+        ("networks", "network"),
+        ("running", "run"),
+        ("queries", "quer"),
+        ("class", "class"),
+        ("Docker", "docker"),
+        ("会議", "会議"),
+    ],
+)
+def test_stem(word, expected):
+    assert stem(word) == expected
+
+
+def test_query_terms_keep_phrases_whole():
+    terms = query_terms('docker "compose  networking" networking', stemming=True)
+
+    assert [(t.text, t.phrase, t.prefix) for t in terms] == [
+        ("docker", False, False),
+        ("compose networking", True, False),
+        ("network", False, True),
+    ]
+    assert [t.text for t in query_terms("networking", stemming=False)] == ["networking"]
+
+
+async def test_like_search_prefers_documents_with_every_term(db_session):
+    _add(db_session, "mem_all", "Docker compose networking between containers")
+    _add(db_session, "mem_two", "Docker networking basics")
+    _add(db_session, "mem_one", "Docker image layers")
+    _add(db_session, "mem_none", "Kubernetes ingress")
+
+    async def ids(query):
+        response = await SearchService().search_memories(
+            SearchRequest(query=query, search_type="like"), db_session
+        )
+        return [result.memory.id for result in response.results]
+
+    assert await ids("docker compose networking") == ["mem_all", "mem_two", "mem_one"]
+    assert await ids('"docker networking"') == ["mem_two"]
+
+
+async def test_stemming_widens_keyword_and_fts_matches(fts_service, db_session, monkeypatch):
+    _add(db_session, "mem_forms", "Notes on container networks")
+
+    async def ids(search_type):
+        response = await fts_service.search_memories(
+            SearchRequest(query="networking", search_type=search_type), db_session
+        )
+        return [result.memory.id for result in response.results]
+
+    assert await ids("like") == []
+    assert await ids("fts5") == []
+    monkeypatch.setattr(settings, "search_stemming", True)
+    assert await ids("like") == ["mem_forms"]
+    assert await ids("fts5") == ["mem_forms"]
+
+
+def test_stopwords_and_synonyms_shape_the_query(monkeypatch):
+    monkeypatch.setattr(settings, "search_stopwords", ["the", "について"])
+    monkeypatch.setattr(settings, "search_synonyms", {"k8s": "kubernetes", "js": ["javascript"]})
+
+    assert parse_fts5_query("the k8s upgrade") == '("k8s" OR "kubernetes") AND "upgrade"'
+    assert [t.forms for t in query_terms("JavaScript について")] == [["JavaScript", "js"]]
+    # A query of only stopwords still searches for them
+    assert [t.text for t in query_terms("the")] == ["the"]
+
+
+async def test_synonyms_apply_to_keyword_and_fts_search(fts_service, db_session, monkeypatch):
+    monkeypatch.setattr(settings, "search_synonyms", {"k8s": ["kubernetes"]})
+    _add(db_session, "mem_long", "Kubernetes cluster upgrade")
+    _add(db_session, "mem_short", "k8s cluster notes")
+
+    for search_type in ["like", "fts5"]:
+        for query in ["k8s", "kubernetes"]:
+            response = await fts_service.search_memories(
+                SearchRequest(query=query, search_type=search_type), db_session
+            )
+            ids = sorted(result.memory.id for result in response.results)
+            assert ids == ["mem_long", "mem_short"], (search_type, query)
+
+
+async def test_session_context_boosts_matching_categories(db_session):
+    db = TestingSessionLocal()
+    _add(db, "mem_home", "Deploy checklist for the home server", tags=["homelab"])
+    _add(db, "mem_mory", "Release steps: deploy checklist", tags=["mory", "release"])
+
+    async def ids(context=None):
+        response = await SearchService().search_memories(
+            SearchRequest(query="deploy checklist", search_type="like", context=context), db
+        )
+        return [result.memory.id for result in response.results]
+
+    assert await ids() == ["mem_home", "mem_mory"]
+    assert await ids("working on project mory") == ["mem_mory", "mem_home"]
+    db.close()
+
+
+class _FakeEmbedder:
+    """Embeds by keyword, so semantic search runs without an OpenAI key"""
+
+    async def generate_embedding(self, text):
+        return np.array([1.0, 0.0] if "cat" in text else [0.0, 1.0], dtype=np.float32)
+
+
+async def test_semantic_search_uses_injected_embedder(db_session):
+    db = TestingSessionLocal()
+    for memory_id, vector in (("mem_cat", [1.0, 0.0]), ("mem_tax", [0.0, 1.0])):
+        embedding = np.array(vector, dtype=np.float32).tobytes()
+        db.add(Memory(id=memory_id, value=memory_id, embedding=embedding))
+    db.commit()
+
+    service = SearchService(embedder=_FakeEmbedder())
+    service.semantic_available = True
+    results, total = await service._search_semantic(SearchRequest(query="cat"), db)
+    db.close()
+
+    assert total == 1  # The orthogonal memory falls under the similarity threshold
+    assert results[0].memory.id == "mem_cat"
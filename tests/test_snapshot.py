@@ -0,0 +1,106 @@
+"""Tests for data directory snapshots"""
+
+import sqlite3
+import tarfile
+
+import pytest
+
+from app.services.snapshot import (
+    MANIFEST_NAME,
+    SnapshotError,
+    create_snapshot,
+    read_manifest,
+    restore_snapshot,
+)
+
+
+@pytest.fixture
+def data_dir(tmp_path):
+    """A data directory with a database, markdown files and things to leave out"""
+    root = tmp_path / "data"
+    (root / "memories" / "work").mkdir(parents=True)
+    connection = sqlite3.connect(root / "memories.db")
+    connection.execute("CREATE TABLE memories (id TEXT, value TEXT, embedding BLOB)")
+    connection.executemany(
+        "INSERT INTO memories VALUES (?, ?, ?)",
+        [("a", "first", b"\x00\x01"), ("b", "second", None)],
+    )
+    connection.commit()
+    connection.close()
+    (root / "memories" / "work" / "a.md").write_text("---\nid: a\n---\nfirst\n")
+    (root / "mory.lock").write_text("123")
+    (root / "backups").mkdir()
+    (root / "backups" / "memories_1.db").write_bytes(b"old")
+    return root
+
+
+def test_snapshot_contents_and_manifest(data_dir, tmp_path):
+    archive = tmp_path / "snap.tar.gz"
+    manifest = create_snapshot(data_dir, data_dir / "memories.db", archive)
+
+    assert manifest["memories"] == 2
+    assert manifest["embeddings"] == 1
+    paths = [entry["path"] for entry in manifest["files"]]
+    assert paths == ["memories.db", "memories/work/a.md"]
+    with tarfile.open(archive) as tar:
+        assert sorted(tar.getnames()) == sorted([MANIFEST_NAME, *paths])
+    assert read_manifest(archive)["files"] == manifest["files"]
+
+
+def test_restore_round_trip(data_dir, tmp_path):
+    archive = tmp_path / "snap.tar.gz"
+    create_snapshot(data_dir, data_dir / "memories.db", archive)
+
+    target = tmp_path / "restored"
+    restore_snapshot(archive, target)
+
+    connection = sqlite3.connect(target / "memories.db")
+    assert connection.execute("SELECT id FROM memories ORDER BY id").fetchall() == [("a",), ("b",)]
+    connection.close()
+    assert (target / "memories" / "work" / "a.md").read_text().endswith("first\n")
+    assert not (target / "mory.lock").exists()
+
+
+def test_restore_refuses_non_empty_directory(data_dir, tmp_path):
+    archive = tmp_path / "snap.tar.gz"
+    create_snapshot(data_dir, data_dir / "memories.db", archive)
+
+    with pytest.raises(SnapshotError, match="not empty"):
+        restore_snapshot(archive, data_dir)
+
+
+def test_restore_into_existing_empty_directory(data_dir, tmp_path):
+    archive = tmp_path / "snap.tar.gz"
+    create_snapshot(data_dir, data_dir / "memories.db", archive)
+    target = tmp_path / "empty"
+    target.mkdir()
+
+    restore_snapshot(archive, target)
+    assert (target / "memories.db").exists()
+
+
+def test_restore_rejects_tampered_archive(data_dir, tmp_path):
+    archive = tmp_path / "snap.tar.gz"
+    create_snapshot(data_dir, data_dir / "memories.db", archive)
+
+    tampered = tmp_path / "tampered.tar.gz"
+    extracted = tmp_path / "x"
+    with tarfile.open(archive) as tar:
+        tar.extractall(extracted, filter="data")
+    (extracted / "memories" / "work" / "a.md").write_text("changed")
+    with tarfile.open(tampered, "w:gz") as tar:
+        for name in (MANIFEST_NAME, "memories.db", "memories/work/a.md"):
+            tar.add(extracted / name, arcname=name)
+
+    target = tmp_path / "restored"
+    with pytest.raises(SnapshotError, match="Checksum mismatch"):
+        restore_snapshot(tampered, target)
+    assert not target.exists()
+    assert list(tmp_path.glob(".restored.restore-*")) == []
+
+
+def test_not_a_snapshot(tmp_path):
+    bogus = tmp_path / "bogus.tar.gz"
+    bogus.write_bytes(b"not a tarball")
+    with pytest.raises(SnapshotError, match="not a Mory snapshot"):
+        read_manifest(bogus)
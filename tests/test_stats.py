@@ -0,0 +1,94 @@
+"""Tests for the daily statistics time series"""
+
+import json
+from datetime import datetime
+
+from app.models.daily_stats import DailyStats
+from app.models.memory import Memory
+from app.services.stats import (
+    count_activity,
+    record_daily_stats,
+    search_counter,
+    stats_history,
+    today,
+)
+from tests.conftest import TestingSessionLocal
+
+
+def test_record_daily_stats(db_session):
+    db = TestingSessionLocal()
+    db.add(Memory(id="mem_a", value="a", tags=json.dumps(["python", "tips"])))
+    db.add(Memory(id="mem_b", value="b", tags=json.dumps(["python"]), embedding=b"\x00" * 8))
+    db.add(Memory(id="mem_c", value="c", namespace="work"))
+    db.commit()
+
+    count_activity(db, "default", searches=1)
+    count_activity(db, "default", searches=1, embeddings_generated=1)
+    db.commit()
+    [row] = db.query(DailyStats).all()
+    assert (row.searches, row.embeddings_generated, row.total_memories) == (2, 1, None)
+
+    rows = record_daily_stats(db)
+    assert [row.to_dict() for row in rows] == [
+        {
+            "day": today(),
+            "namespace": "default",
+            "total_memories": 2,
+            "memories_with_embeddings": 1,
+            "categories": {"python": 2},
+            "searches": 2,
+            "embeddings_generated": 1,
+        },
+        {
+            "day": today(),
+            "namespace": "work",
+            "total_memories": 1,
+            "memories_with_embeddings": 0,
+            "categories": {"uncategorized": 1},
+            "searches": 0,
+            "embeddings_generated": 0,
+        },
+    ]
+
+    # Running again the same day updates the row instead of adding one
+    record_daily_stats(db)
+    assert db.query(DailyStats).count() == 2
+    db.close()
+
+
+def test_stats_history_window(db_session):
+    db = TestingSessionLocal()
+    for day in ("2024-05-01", "2024-06-10", "2024-06-15"):
+        db.add(DailyStats(day=day, namespace="default", searches=1, embeddings_generated=0))
+    db.commit()
+
+    rows = stats_history(db, "default", days=7, now=datetime(2024, 6, 15, 12, 0))
+    assert [row.day for row in rows] == ["2024-06-10", "2024-06-15"]
+    db.close()
+
+
+def test_searches_are_counted(client, db_session):
+    client.post("/api/memories", json={"value": "Kyoto trip in May"})
+    client.post("/api/memories/search", json={"query": "Kyoto"})
+    client.post("/api/memories/search", json={"query": "Osaka"})
+
+    response = client.get("/api/memories/stats/history")
+    assert response.status_code == 200
+    [day] = response.json()["days"]
+    assert day["day"] == today()
+    assert day["searches"] == 2
+
+
+def test_search_counts_wait_for_a_flush(db_session):
+    """Searches are written to daily_stats only when flushed, then counted once"""
+    db = TestingSessionLocal()
+    search_counter.add("default")
+    search_counter.add("default")
+    search_counter.add("work")
+    assert db.query(DailyStats).count() == 0
+
+    assert search_counter.flush(db) == 3
+    assert search_counter.flush(db) == 0
+    searches = {row.namespace: row.searches for row in db.query(DailyStats).all()}
+    assert searches == {"default": 2, "work": 1}
+    db.close()
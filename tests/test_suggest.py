@@ -0,0 +1,50 @@
+"""Tests for tag and summary completions"""
+
+import json
+
+from sqlalchemy import text
+
+from app.models.memory import Memory
+from app.services.suggest import suggest, suggest_memories, suggest_tags
+
+
+def _add(db, memory_id, summary, tags, **fields):
+    db.add(Memory(id=memory_id, value=memory_id, summary=summary, tags=json.dumps(tags), **fields))
+
+
+def test_suggest_tags_and_summaries(db_session):
+    _add(db_session, "mem_a", "Docker networking notes", ["docker", "network"])
+    _add(db_session, "mem_b", "docker compose cheatsheet", ["docker", "do_it"])
+    _add(db_session, "mem_c", "Dentist appointment", ["doctor"])
+    _add(db_session, "mem_d", "Docker secrets", ["docker"], review_status="pending")
+    db_session.commit()
+
+    assert suggest_tags(db_session, "do") == [("docker", 2), ("do_it", 1), ("doctor", 1)]
+    assert suggest_tags(db_session, "do_") == [("do_it", 1)]
+    assert suggest_tags(db_session, "DOC", limit=1) == [("docker", 2)]
+
+    ids = sorted(memory.id for memory in suggest_memories(db_session, "DOCKER"))
+    assert ids == ["mem_a", "mem_b"]
+    assert suggest_memories(db_session, "docker", namespace="other") == []
+
+    result = suggest(db_session, "dent")
+    assert result["memories"] == [{"id": "mem_c", "summary": "Dentist appointment"}]
+    assert result["tags"] == []
+
+
+def test_summary_prefix_uses_index(db_session):
+    plan = db_session.execute(
+        text("EXPLAIN QUERY PLAN SELECT id FROM memories WHERE lower(summary) >= 'a'")
+    ).all()
+    assert any("idx_summary_lower" in row[-1] for row in plan)
+
+
+def test_suggest_endpoint(client, db_session):
+    _add(db_session, "mem_a", "Kubernetes upgrade", ["kubernetes"])
+    db_session.commit()
+
+    response = client.get("/api/memories/suggest", params={"prefix": "kube"})
+
+    assert response.status_code == 200
+    assert response.json()["tags"] == [{"tag": "kubernetes", "count": 1}]
+    assert response.json()["memories"][0]["id"] == "mem_a"
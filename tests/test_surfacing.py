@@ -0,0 +1,63 @@
+"""Tests for spaced-repetition surfacing"""
+
+import random
+from datetime import datetime, timedelta
+
+from app.models.memory import Memory
+from app.services.surfacing import pick_memories_to_surface, review_weight
+
+
+def _add(db, memory_id, idle_days, access_count=0, tags=()):
+    seen = datetime.utcnow() - timedelta(days=idle_days)
+    db.add(
+        Memory(
+            id=memory_id,
+            value=memory_id,
+            tags=list(tags),
+            access_count=access_count,
+            updated_at=seen,
+            last_accessed_at=seen if access_count else None,
+        )
+    )
+
+
+def test_review_weight_prefers_unseen_and_rarely_read():
+    now = datetime.utcnow()
+    old = Memory(updated_at=now - timedelta(days=30), access_count=0)
+    read_often = Memory(last_accessed_at=now - timedelta(days=30), access_count=4)
+    recent = Memory(updated_at=now - timedelta(days=2), access_count=0)
+
+    assert review_weight(old, now) == 30
+    assert review_weight(old, now) > review_weight(read_often, now) > 0
+    assert review_weight(old, now) > review_weight(recent, now)
+
+
+def test_pick_skips_recent_and_favours_overdue(db_session):
+    _add(db_session, "mem_overdue", idle_days=60)
+    _add(db_session, "mem_mild", idle_days=3, access_count=3)
+    _add(db_session, "mem_fresh", idle_days=0.1)
+    db_session.commit()
+
+    picks = [
+        pick_memories_to_surface(db_session, "default", count=1, rng=random.Random(seed))[0].id
+        for seed in range(50)
+    ]
+
+    assert "mem_fresh" not in picks
+    assert picks.count("mem_overdue") > picks.count("mem_mild")
+
+
+def test_surface_endpoint_records_the_read(client, db_session):
+    _add(db_session, "mem_a", idle_days=10, tags=["english"])
+    _add(db_session, "mem_b", idle_days=10, tags=["cooking"])
+    db_session.commit()
+
+    wildcard = client.post("/api/memories/surface", params={"tag": "englis_"})
+    assert wildcard.json()["memories"] == []
+    response = client.post("/api/memories/surface", params={"count": 5, "tag": "english"})
+
+    assert response.status_code == 200
+    assert [m["id"] for m in response.json()["memories"]] == ["mem_a"]
+    assert response.json()["memories"][0]["access_count"] == 1
+    # Just read, so not due again yet
+    assert client.post("/api/memories/surface").json()["memories"][0]["id"] == "mem_b"
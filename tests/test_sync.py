@@ -0,0 +1,107 @@
+"""Tests for two-way sync between Mory instances"""
+
+from datetime import datetime, timedelta
+
+import httpx
+import numpy as np
+import pytest
+from sqlalchemy import create_engine
+from sqlalchemy.orm import sessionmaker
+
+from app.core.database import Base
+from app.main import app
+from app.models.memory import Memory
+from app.services.embedding import embedding_service
+from app.services.sync import SyncClient
+
+
+@pytest.fixture
+def local_db(tmp_path):
+    """A second, independent database standing in for the other machine"""
+    engine = create_engine(f"sqlite:///{tmp_path / 'local.db'}")
+    Base.metadata.create_all(bind=engine)
+    db = sessionmaker(bind=engine)()
+    yield db
+    db.close()
+    engine.dispose()
+
+
+def _client(tmp_path) -> SyncClient:
+    return SyncClient(
+        "http://peer",
+        state_path=tmp_path / "sync_state.json",
+        transport=httpx.ASGITransport(app=app),
+    )
+
+
+async def test_sync_converges(client, db_session, local_db, tmp_path):
+    """Both sides end up with the same memories, edits and deletions"""
+    peer_memory = client.post("/api/memories", json={"value": "Written on the desktop"}).json()
+    local_memory = Memory(id="mem_laptop01", value="Written on the laptop")
+    local_db.add(local_memory)
+    local_db.commit()
+
+    sync = _client(tmp_path)
+    result = await sync.sync(local_db)
+    assert result["pulled"]["created"] == 1
+    assert result["pushed"]["created"] == 1
+    assert local_db.get(Memory, peer_memory["id"]).value == "Written on the desktop"
+    assert client.get("/api/memories/mem_laptop01").json()["value"] == "Written on the laptop"
+
+    # Both sides edit the same memory; the later edit (peer) wins and is logged
+    local_memory.value = "Laptop edit"
+    local_memory.updated_at = datetime.utcnow() - timedelta(seconds=30)
+    local_db.commit()
+    client.put("/api/memories/mem_laptop01", json={"value": "Desktop edit"})
+
+    result = await sync.sync(local_db)
+    assert result["pulled"]["conflicts"] == ["mem_laptop01"]
+    local_db.expire_all()
+    assert local_db.get(Memory, "mem_laptop01").value == "Desktop edit"
+
+    # Deletions propagate through the operation log
+    client.delete(f"/api/memories/{peer_memory['id']}")
+    result = await sync.sync(local_db)
+    assert result["pulled"]["deleted"] == 1
+    assert local_db.get(Memory, peer_memory["id"]) is None
+
+
+async def test_sync_settles_with_embeddings_and_auto_tags(
+    client, db_session, local_db, tmp_path, monkeypatch
+):
+    """Embedding and tagging synced memories keeps the peer's updated_at, so a second sync
+    finds nothing to do"""
+    from app.core.config import settings
+
+    async def fake_embedding(text):
+        return np.array([1.0, 0.0], dtype=np.float32)
+
+    monkeypatch.setattr(embedding_service, "enabled", True)
+    monkeypatch.setattr(embedding_service, "generate_embedding", fake_embedding)
+    monkeypatch.setattr(settings, "auto_tag", True)
+
+    peer_memory = client.post("/api/memories", json={"value": "Kyoto temple notes"}).json()
+    local_db.add(Memory(id="mem_laptop01", value="Osaka food notes"))
+    local_db.commit()
+
+    sync = _client(tmp_path)
+    result = await sync.sync(local_db)
+    assert (result["pulled"]["created"], result["pushed"]["created"]) == (1, 1)
+    local_db.expire_all()
+    pulled = local_db.get(Memory, peer_memory["id"])
+    assert pulled.has_embedding
+    peer_copy = client.get(f"/api/memories/{peer_memory['id']}").json()
+    assert pulled.updated_at == datetime.fromisoformat(peer_copy["updated_at"])
+
+    result = await sync.sync(local_db)
+    for side in ("pulled", "pushed"):
+        assert result[side]["created"] == result[side]["updated"] == 0, side
+        assert result[side]["conflicts"] == [], side
+
+
+async def test_sync_token_required(client, db_session, local_db, tmp_path, monkeypatch):
+    from app.core.config import settings
+
+    monkeypatch.setattr(settings, "sync_token", "secret")
+    with pytest.raises(httpx.HTTPStatusError):
+        await _client(tmp_path).sync(local_db)
@@ -0,0 +1,81 @@
+"""Tests for memory templates"""
+
+import pytest
+
+from app.core.config import settings
+from app.core.diagnostics import check_memory_templates
+from app.services.templates import TemplateError, render, validate_fields
+
+
+def test_validate_fields_normalises_and_defaults():
+    fields = validate_fields(
+        "decision", {"decision": "Use SQLite", "decided_on": "2025-03-01T10:00:00", "extra": 1}
+    )
+    assert fields == {
+        "decision": "Use SQLite",
+        "decided_on": "2025-03-01",
+        "status": "accepted",
+        "extra": 1,
+    }
+
+
+@pytest.mark.parametrize(
+    ("template", "metadata", "message"),
+    [
+        ("contact", {"email": "a@example.com"}, "need 'name'"),
+        ("contact", {"name": "Alice", "email": "not-an-email"}, "email address"),
+        ("bookmark", {"url": "example.com"}, "URL"),
+        ("decision", {"decision": "x", "status": "maybe"}, "one of"),
+        ("credential_reference", {"service": "AWS", "location": "1P", "password": "x"}, "secrets"),
+        ("nope", {}, "Unknown template"),
+    ],
+)
+def test_validate_fields_rejects(template, metadata, message):
+    with pytest.raises(TemplateError, match=message):
+        validate_fields(template, metadata)
+
+
+def test_render():
+    card = render("contact", {"name": "Alice", "email": "alice@example.com", "x": 1})
+    assert card == "[contact] Alice\nemail: alice@example.com"
+    assert render(None, {"name": "Alice"}) is None
+
+
+def test_custom_templates(monkeypatch):
+    monkeypatch.setattr(
+        settings, "memory_templates", {"recipe": {"fields": {"dish": {"required": True}}}}
+    )
+    assert validate_fields("recipe", {"dish": "Curry"}) == {"dish": "Curry"}
+    assert check_memory_templates(settings).ok
+
+    monkeypatch.setattr(settings, "memory_templates", {"bad": {"fields": {"x": {"type": "blob"}}}})
+    assert not check_memory_templates(settings).ok
+
+
+def test_templated_memory_api(client, db_session):
+    response = client.post(
+        "/api/memories",
+        json={
+            "value": "Alice from the meetup",
+            "template": "contact",
+            "metadata": {"name": "Alice", "email": "alice@example.com"},
+        },
+    )
+    assert response.status_code == 201
+    memory = response.json()
+    assert memory["template"] == "contact"
+    assert memory["rendered"] == "[contact] Alice\nemail: alice@example.com"
+
+    bad = client.put(f"/api/memories/{memory['id']}", json={"metadata": {"email": "x@y.z"}})
+    assert bad.status_code == 422
+
+    listed = client.get("/api/memories").json()["memories"]
+    assert listed[0]["rendered"].startswith("[contact] Alice")
+
+    names = [t["name"] for t in client.get("/api/templates").json()["templates"]]
+    assert {"contact", "decision", "bookmark", "credential_reference"} <= set(names)
+
+
+def test_unknown_template_is_rejected(client, db_session):
+    response = client.post("/api/memories", json={"value": "x", "template": "nope"})
+    assert response.status_code == 422
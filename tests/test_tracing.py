@@ -0,0 +1,41 @@
+"""Tests for debug-mode request tracing"""
+
+from app.core.config import settings
+from app.core.tracing import TraceRecorder, trace_recorder, trace_span
+
+
+def test_trace_span_records_within_trace():
+    """Spans accumulate into the active trace"""
+    recorder = TraceRecorder()
+    token = recorder.start("req1", "POST", "/api/memories/search")
+    with trace_span("db_query"):
+        pass
+    with trace_span("db_query"):
+        pass
+    trace = recorder.finish(token, 200)
+
+    assert "db_query" in trace.spans
+    assert recorder.recent()[0]["request_id"] == "req1"
+    assert recorder.summary()["total"]["count"] == 1
+
+
+def test_trace_span_is_noop_without_trace():
+    """Spans outside a traced request do nothing"""
+    with trace_span("db_query"):
+        value = 1
+
+    assert value == 1
+
+
+def test_diagnostics_endpoint_reports_debug_traces(client, monkeypatch):
+    """Requests are traced and reported when debug mode is on"""
+    monkeypatch.setattr(settings, "debug", True)
+    trace_recorder.clear()
+
+    client.get("/api/health", headers={"X-Request-ID": "trace001"})
+    response = client.get("/api/diagnostics")
+
+    assert response.status_code == 200
+    data = response.json()
+    assert data["debug_enabled"] is True
+    assert any(t["request_id"] == "trace001" for t in data["recent_requests"])
@@ -0,0 +1,50 @@
+"""Tests for the TUI browser state (no terminal required)"""
+
+import pytest
+
+from app.models.memory import Memory
+from tests.conftest import TestingSessionLocal
+
+pytest.importorskip("curses")
+
+from app.tui import MemoryBrowser  # noqa: E402
+
+
+@pytest.fixture
+def browser(db_session):
+    db = TestingSessionLocal()
+    samples = [("Python tips", ["python"]), ("Rust notes", ["rust"]), ("More Python", ["python"])]
+    for value, tags in samples:
+        memory = Memory(value=value, namespace="default")
+        memory.tags_list = tags
+        db.add(memory)
+    db.commit()
+
+    browser = MemoryBrowser(db, "default")
+    browser.load()
+    yield browser
+    db.close()
+
+
+def test_keyword_filter_and_tags(browser):
+    """Incremental keyword filter and tag cycling narrow the list"""
+    assert len(browser.visible) == 3
+    assert browser.tag_counts()[0] == ("python", 2)
+
+    browser.set_query("rust")
+    assert [m.value for m in browser.visible] == ["Rust notes"]
+
+    browser.set_query("")
+    browser.cycle_tag()
+    assert browser.tag == "python"
+    assert len(browser.visible) == 2
+
+
+def test_edit_and_delete(browser):
+    """Editing updates the selected memory; delete removes it"""
+    browser.set_query("rust")
+    assert browser.update_selected("Rust ownership notes") is True
+    assert browser.selected.value == "Rust ownership notes"
+
+    browser.delete_selected()
+    assert len(browser.memories) == 2
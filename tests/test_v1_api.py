@@ -0,0 +1,32 @@
+"""Tests for the versioned /v1 REST API"""
+
+
+def test_v1_memory_crud(client, db_session):
+    """/v1/memories shares storage with /api/memories"""
+    response = client.post("/v1/memories", json={"value": "Saved from a shell script"})
+    assert response.status_code == 201
+    memory_id = response.json()["id"]
+
+    assert client.get(f"/api/memories/{memory_id}").status_code == 200
+    assert client.get("/v1/memories").json()["total"] == 1
+
+    assert client.delete(f"/v1/memories/{memory_id}").status_code == 200
+    assert client.get(f"/v1/memories/{memory_id}").status_code == 404
+
+
+def test_v1_search_get_and_post(client, db_session):
+    """/v1/search accepts query parameters and JSON bodies"""
+    client.post("/v1/memories", json={"value": "Python shell scripting notes"})
+
+    response = client.get("/v1/search", params={"q": "Python", "search_type": "fts5"})
+    assert response.status_code == 200
+    assert response.json()["total"] >= 1
+
+    response = client.post("/v1/search", json={"query": "Python", "search_type": "fts5"})
+    assert response.status_code == 200
+    assert response.json()["total"] >= 1
+
+
+def test_v1_validation(client, db_session):
+    """Validation errors match the /api endpoints"""
+    assert client.post("/v1/memories", json={"value": ""}).status_code == 422
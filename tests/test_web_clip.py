@@ -0,0 +1,230 @@
+"""Tests for saving web pages as bookmark memories"""
+
+import httpx
+import pytest
+
+from app.core.config import settings
+from app.services.summarization import summarization_service
+from app.services.web_clip import (
+    WebClipError,
+    WebClipper,
+    extract_article,
+    is_public_address,
+    web_clipper,
+)
+
+PAGE = """<html><head>
+<title>Miso soup | Kitchen Notes</title>
+<meta property="og:site_name" content="Kitchen Notes">
+<meta name="description" content="How to make miso soup at home">
+</head><body>
+<header><p>Kitchen Notes: recipes, tips and stories from home</p></header>
+<nav><ul><li><a href="/">Home</a></li><li><a href="/recipes">Recipes</a></li></ul></nav>
+<article>
+  <h1>Miso soup</h1>
+  <p>Start with dashi made from kombu &amp; katsuobushi, simmered gently.</p>
+  <ul><li>Add tofu and wakame just before the miso goes in.</li>
+  <li><a href="/tags/tofu">More recipes with tofu and wakame</a></li></ul>
+  <script>track()</script>
+</article>
+<footer><p>Copyright Kitchen Notes, all rights reserved</p></footer>
+</body></html>"""
+
+
+def fake_dns(addresses):
+    """Resolver answering from a host -> address table instead of the network"""
+
+    async def resolve(host):
+        return [addresses[host]] if host in addresses else []
+
+    return resolve
+
+
+PUBLIC_DNS = fake_dns({"example.com": "93.184.215.14", "example.net": "93.184.215.15"})
+
+
+@pytest.fixture(autouse=True)
+def public_dns(monkeypatch):
+    monkeypatch.setattr(web_clipper, "resolve", PUBLIC_DNS)
+
+
+def test_extract_article_keeps_main_text():
+    article = extract_article(PAGE, "https://example.com/miso")
+
+    assert article.title == "Miso soup | Kitchen Notes"
+    assert article.site_name == "Kitchen Notes"
+    assert article.description == "How to make miso soup at home"
+    assert article.text == (
+        "# Miso soup\n\n"
+        "Start with dashi made from kombu & katsuobushi, simmered gently.\n\n"
+        "Add tofu and wakame just before the miso goes in."
+    )
+
+
+def test_extract_article_without_article_element():
+    html = "<body><div><p>A plain page with a single paragraph of text.</p></div></body>"
+    article = extract_article(html, "https://example.com/plain")
+
+    assert article.title == "https://example.com/plain"
+    assert article.text == "A plain page with a single paragraph of text."
+
+
+async def test_fetch_rejects_non_html():
+    transport = httpx.MockTransport(
+        lambda request: httpx.Response(200, headers={"Content-Type": "application/pdf"})
+    )
+    with pytest.raises(WebClipError, match="not a web page"):
+        clipper = WebClipper(transport=transport, resolve=PUBLIC_DNS)
+        await clipper.fetch("https://example.com/paper.pdf")
+
+
+def test_public_addresses():
+    assert is_public_address("93.184.215.14")
+    assert is_public_address("2606:2800:21f:cb07:6820:80da:af6b:8b2c")
+    for address in (
+        "127.0.0.1",
+        "10.0.0.8",
+        "192.168.1.20",
+        "172.16.0.1",
+        "169.254.169.254",
+        "100.64.0.1",
+        "0.0.0.0",
+        "224.0.0.1",
+        "::1",
+        "fe80::1%eth0",
+        "fc00::1",
+        "::ffff:127.0.0.1",
+    ):
+        assert not is_public_address(address), address
+
+
+async def test_fetch_refuses_private_hosts(monkeypatch):
+    requests = []
+
+    def handler(request):
+        requests.append(request)
+        return httpx.Response(200, headers={"Content-Type": "text/html"}, text=PAGE)
+
+    dns = fake_dns({"example.com": "93.184.215.14", "intranet.example": "10.0.0.8"})
+    clipper = WebClipper(transport=httpx.MockTransport(handler), resolve=dns)
+    for url in (
+        "http://127.0.0.1:8080/api/memories",
+        "http://169.254.169.254/latest/meta-data/",
+        "http://[::1]/",
+        "http://intranet.example/wiki",
+        "http://unknown.example/",
+    ):
+        with pytest.raises(WebClipError):
+            await clipper.fetch(url)
+    assert requests == []
+
+    # Unless private addresses are allowed
+    monkeypatch.setattr(settings, "web_clip_allow_private", True)
+    article = await clipper.fetch("http://intranet.example/wiki")
+    assert article.title == "Miso soup | Kitchen Notes"
+
+
+async def test_fetch_checks_every_redirect():
+    requests = []
+
+    def handler(request):
+        requests.append(f"{request.url.scheme}://{request.headers['Host']}{request.url.path}")
+        if request.headers["Host"] == "example.com":
+            return httpx.Response(302, headers={"Location": "https://example.net/miso"})
+        if request.url.path == "/miso":
+            return httpx.Response(301, headers={"Location": "http://169.254.169.254/"})
+        return httpx.Response(200, headers={"Content-Type": "text/html"}, text=PAGE)
+
+    clipper = WebClipper(transport=httpx.MockTransport(handler), resolve=PUBLIC_DNS)
+    with pytest.raises(WebClipError, match="private or local"):
+        await clipper.fetch("https://example.com/short")
+    assert requests == ["https://example.com/short", "https://example.net/miso"]
+
+    article = await clipper.fetch("https://example.net/other")
+    assert article.url == "https://example.net/other"
+
+
+async def test_fetch_connects_to_the_checked_address():
+    """A host that resolves differently on a second lookup still gets the vetted address"""
+    answers = iter(["93.184.215.14", "127.0.0.1"])
+
+    async def rebinding_dns(host):
+        return [next(answers)]
+
+    requests = []
+
+    def handler(request):
+        requests.append(request)
+        if request.url.path == "/short":
+            return httpx.Response(302, headers={"Location": "/miso"})
+        return httpx.Response(200, headers={"Content-Type": "text/html"}, text=PAGE)
+
+    clipper = WebClipper(transport=httpx.MockTransport(handler), resolve=rebinding_dns)
+    with pytest.raises(WebClipError, match="private or local"):
+        await clipper.fetch("https://example.com:8443/short")
+
+    # The first hop went to the checked address; the redirect was refused on its new answer
+    assert len(requests) == 1
+    assert requests[0].url.host == "93.184.215.14"
+    assert requests[0].url.port == 8443
+    assert requests[0].headers["Host"] == "example.com:8443"
+    assert requests[0].extensions["sni_hostname"] == "example.com"
+
+
+async def test_fetch_limits_redirects():
+    transport = httpx.MockTransport(
+        lambda request: httpx.Response(302, headers={"Location": "/again"})
+    )
+    clipper = WebClipper(transport=transport, resolve=PUBLIC_DNS)
+    with pytest.raises(WebClipError, match="redirected more than"):
+        await clipper.fetch("https://example.com/loop")
+
+
+async def test_fetch_limits_page_size(monkeypatch):
+    from app.services import web_clip
+
+    monkeypatch.setattr(web_clip, "MAX_PAGE_BYTES", 100)
+    transport = httpx.MockTransport(
+        lambda request: httpx.Response(200, headers={"Content-Type": "text/html"}, text=PAGE)
+    )
+    clipper = WebClipper(transport=transport, resolve=PUBLIC_DNS)
+    with pytest.raises(WebClipError, match="larger than 100 bytes"):
+        await clipper.fetch("https://example.com/miso")
+
+
+def test_save_url_creates_bookmark(client, db_session, monkeypatch):
+    requests = []
+
+    def handler(request):
+        requests.append(request)
+        return httpx.Response(200, headers={"Content-Type": "text/html"}, text=PAGE)
+
+    monkeypatch.setattr(web_clipper, "transport", httpx.MockTransport(handler))
+    monkeypatch.setattr(summarization_service, "enabled", False)
+
+    response = client.post("/api/memories/url", json={"url": "https://example.com/miso#top"})
+    assert response.status_code == 201
+    memory = response.json()
+    assert memory["value"].startswith("Miso soup | Kitchen Notes\n\n# Miso soup")
+    assert memory["value"].endswith("\n\nhttps://example.com/miso")
+    assert memory["source"] == "url:https://example.com/miso"
+    assert memory["template"] == "bookmark"
+    assert memory["metadata"]["url"] == "https://example.com/miso"
+    assert memory["metadata"]["site_name"] == "Kitchen Notes"
+
+    # The same link again returns the existing bookmark without fetching
+    again = client.post("/api/memories/url", json={"url": "https://example.com/miso"})
+    assert again.json()["id"] == memory["id"]
+    assert len(requests) == 1
+
+
+def test_save_url_reports_fetch_errors(client, db_session, monkeypatch):
+    transport = httpx.MockTransport(lambda request: httpx.Response(404))
+    monkeypatch.setattr(web_clipper, "transport", transport)
+
+    response = client.post("/api/memories/url", json={"url": "https://example.com/gone"})
+    assert response.status_code == 422
+    assert "HTTP 404" in response.json()["detail"]
+
+    response = client.post("/api/memories/url", json={"url": "ftp://example.com/file"})
+    assert response.status_code == 422
@@ -0,0 +1,62 @@
+"""Tests for webhook notifications"""
+
+import hashlib
+import hmac
+import json
+from types import SimpleNamespace
+
+import httpx
+
+from app.core.config import settings
+from app.services.webhooks import SIGNATURE_HEADER, WebhookDispatcher
+
+MEMORY = SimpleNamespace(
+    id="mem_12345678",
+    namespace="default",
+    owner=None,
+    summary="Summary",
+    tags_list=["tag"],
+    review_status="approved",
+    created_at=None,
+    updated_at=None,
+)
+
+
+async def test_signed_delivery(monkeypatch):
+    """Events are POSTed to every URL with an HMAC signature"""
+    received = []
+
+    def handler(request: httpx.Request) -> httpx.Response:
+        received.append(request)
+        return httpx.Response(200)
+
+    monkeypatch.setattr(settings, "webhook_urls", ["https://a.test/hook", "https://b.test/hook"])
+    monkeypatch.setattr(settings, "webhook_secret", "s3cret")
+    dispatcher = WebhookDispatcher(transport=httpx.MockTransport(handler))
+
+    results = await dispatcher.send(dispatcher.build_event("saved", MEMORY))
+
+    assert results == [True, True]
+    body = received[0].content
+    assert json.loads(body)["event"] == "memory.saved"
+    expected = "sha256=" + hmac.new(b"s3cret", body, hashlib.sha256).hexdigest()
+    assert received[0].headers[SIGNATURE_HEADER] == expected
+
+
+async def test_retries_server_errors(monkeypatch):
+    """5xx responses are retried; 4xx responses are not"""
+    calls = {"count": 0}
+
+    def flaky(request: httpx.Request) -> httpx.Response:
+        calls["count"] += 1
+        return httpx.Response(503 if calls["count"] < 3 else 200)
+
+    monkeypatch.setattr(settings, "webhook_urls", ["https://a.test/hook"])
+    monkeypatch.setattr(settings, "webhook_retry_backoff", 0)
+    dispatcher = WebhookDispatcher(transport=httpx.MockTransport(flaky))
+
+    assert await dispatcher.send(dispatcher.build_event("updated", MEMORY)) == [True]
+    assert calls["count"] == 3
+
+    dispatcher = WebhookDispatcher(transport=httpx.MockTransport(lambda r: httpx.Response(404)))
+    assert await dispatcher.send(dispatcher.build_event("deleted", MEMORY)) == [False]
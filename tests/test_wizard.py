@@ -0,0 +1,69 @@
+"""Tests for the interactive setup wizard"""
+
+import json
+from pathlib import Path
+
+from app.core.claude_desktop import claude_desktop_snippet
+from app.core.wizard import SetupWizard
+
+
+def make_wizard(tmp_path, answers):
+    """Create a wizard fed with scripted answers"""
+    replies = iter(answers)
+    output = []
+    wizard = SetupWizard(
+        env_path=tmp_path / ".env",
+        input_fn=lambda _prompt: next(replies),
+        output_fn=output.append,
+    )
+    return wizard, output
+
+
+def test_wizard_writes_env_file(tmp_path):
+    """Answers are written to .env and the Claude snippet is printed"""
+    vault = tmp_path / "vault"
+    vault.mkdir()
+    answers = ["mydata", "y", "", "9000", str(vault), "y", "sk-test"]
+    wizard, output = make_wizard(tmp_path, answers)
+
+    assert wizard.run() == 0
+
+    env = (tmp_path / ".env").read_text()
+    assert "MORY_DATA_DIR=mydata" in env
+    assert "MORY_STORAGE_BACKEND=files" in env
+    assert f"MORY_FILES_DIR={Path('mydata') / 'memories'}" in env
+    assert "MORY_PORT=9000" in env
+    assert f"MORY_OBSIDIAN_VAULT_PATH={vault}" in env
+    assert "OPENAI_API_KEY=sk-test" in env
+    assert any("http://localhost:9000" in line for line in output)
+
+
+def test_wizard_defaults_and_semantic_disabled(tmp_path):
+    """Empty answers use defaults and semantic search can be turned off"""
+    wizard, _ = make_wizard(tmp_path, ["", "", "", "", "n"])
+
+    assert wizard.run() == 0
+
+    env = (tmp_path / ".env").read_text()
+    assert "MORY_DATA_DIR=data" in env
+    assert "MORY_STORAGE_BACKEND=sqlite" in env
+    assert "MORY_SEMANTIC_SEARCH_ENABLED=false" in env
+    assert "OPENAI_API_KEY" not in env
+
+
+def test_wizard_keeps_existing_env_when_declined(tmp_path):
+    """Existing .env is not overwritten without confirmation"""
+    (tmp_path / ".env").write_text("MORY_PORT=1234\n")
+    wizard, _ = make_wizard(tmp_path, ["n"])
+
+    assert wizard.run() == 1
+    assert (tmp_path / ".env").read_text() == "MORY_PORT=1234\n"
+
+
+def test_claude_desktop_snippet_structure():
+    """Snippet is valid JSON with a mory server entry"""
+    snippet = json.loads(claude_desktop_snippet(8080))
+
+    server = snippet["mcpServers"]["mory"]
+    assert server["args"][0].endswith("mcp_main.py")
+    assert server["env"]["MORY_API_URL"] == "http://localhost:8080"
@@ -0,0 +1,43 @@
+"""Tests for session working memory"""
+
+import json
+from datetime import datetime, timedelta
+
+from app.core.working_memory import WorkingMemory
+from app.mcp_server import _call_tool, working_memories
+
+
+def test_capacity_drops_oldest():
+    memory = WorkingMemory(capacity=2)
+    first = memory.add("one")
+    memory.add("two", ["draft"])
+    memory.add("three")
+
+    assert [item.value for item in memory.items()] == ["two", "three"]
+    assert memory.get(first.id) is None
+    assert memory.items()[0].tags == ["draft"]
+
+
+def test_ttl_expires_notes():
+    memory = WorkingMemory(ttl=30)
+    old = memory.add("stale")
+    old.created_at = datetime.utcnow() - timedelta(minutes=31)
+    memory.add("fresh")
+
+    assert [item.value for item in memory.items()] == ["fresh"]
+
+
+async def test_working_memory_tools():
+    working_memories.clear()
+    result = await _call_tool("save_working_memory", {"value": "Try port 8081"})
+    saved = json.loads(result[0].text)
+    assert saved["id"] == "wm_1"
+
+    listed = json.loads((await _call_tool("list_working_memory", {}))[0].text)
+    assert [item["value"] for item in listed["items"]] == ["Try port 8081"]
+
+    result = await _call_tool("promote_to_long_term", {"id": "wm_9", "category": "notes"})
+    error = json.loads(result[0].text)
+    assert error["code"] == "not_found"
+    assert "wm_9" in error["error"]
+    working_memories.clear()